@@ -4,8 +4,11 @@ import (
 	"boonkosang/internal/adapters/postgres"
 	"boonkosang/internal/adapters/rest"
 	"boonkosang/internal/infrastructure/database"
+	"boonkosang/internal/infrastructure/document"
 	"boonkosang/internal/infrastructure/server"
 	"boonkosang/internal/usecase"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"log"
 	"os"
@@ -69,15 +72,40 @@ func main() {
 	ProjectHandler.ProjectRoutes(app)
 
 	materialRepo := postgres.NewMaterialRepository(db)
-	materialUseCase := usecase.NewMaterialUsecase(materialRepo, supplierRepo)
+	unitRepo := postgres.NewUnitRepository(db)
+	unitUseCase := usecase.NewUnitUsecase(unitRepo, materialRepo)
+	UnitHandler := rest.NewUnitHandler(unitUseCase)
+	UnitHandler.UnitRoutes(app)
+
+	supplierPriceRepo := postgres.NewSupplierPriceRepository(db)
+	materialUseCase := usecase.NewMaterialUsecase(materialRepo, supplierRepo, supplierPriceRepo)
 	MaterialHandler := rest.NewMaterialHandler(materialUseCase)
 	MaterialHandler.MaterialRoutes(app)
 
+	supplierPriceUseCase := usecase.NewSupplierPriceUsecase(supplierPriceRepo, supplierRepo, materialRepo, unitUseCase)
+	SupplierPriceHandler := rest.NewSupplierPriceHandler(supplierPriceUseCase)
+	SupplierPriceHandler.SupplierPriceRoutes(app)
+
+	purchaseOrderRepo := postgres.NewPurchaseOrderRepository(db)
+	purchaseOrderUseCase := usecase.NewPurchaseOrderUsecase(purchaseOrderRepo, materialRepo, supplierRepo, unitUseCase)
+	PurchaseOrderHandler := rest.NewPurchaseOrderHandler(purchaseOrderUseCase)
+	PurchaseOrderHandler.PurchaseOrderRoutes(app)
+
+	rfqRepo := postgres.NewRFQRepository(db)
+	rfqUseCase := usecase.NewRFQUsecase(rfqRepo, purchaseOrderRepo, supplierRepo, purchaseOrderUseCase, materialUseCase)
+	RFQHandler := rest.NewRFQHandler(rfqUseCase)
+	RFQHandler.RFQRoutes(app)
+
 	jobRepo := postgres.NewJobRepository(db)
-	jobUseCase := usecase.NewJobUseCase(jobRepo)
+	jobUseCase := usecase.NewJobUseCase(jobRepo, unitUseCase)
 	JobHandler := rest.NewJobHandler(jobUseCase)
 	JobHandler.JobRoutes(app)
 
+	stockRepo := postgres.NewStockRepository(db)
+	inventoryUseCase := usecase.NewInventoryUsecase(stockRepo, projectRepo, materialRepo, supplierRepo, jobRepo)
+	InventoryHandler := rest.NewInventoryHandler(inventoryUseCase)
+	InventoryHandler.InventoryRoutes(app)
+
 	boqRepo := postgres.NewBOQRepository(db)
 	boqUseCase := usecase.NewBOQUsecase(boqRepo, projectRepo)
 	BOQHandler := rest.NewBOQHandler(boqUseCase)
@@ -93,6 +121,11 @@ func main() {
 	CompanyHandler := rest.NewCompanyHandler(companyUseCase)
 	CompanyHandler.CompanyRoutes(app)
 
+	documentNumberRepo := postgres.NewDocumentNumberRepository(db)
+	documentNumberUseCase := usecase.NewDocumentNumberUsecase(documentNumberRepo, companyRepo)
+	DocumentNumberHandler := rest.NewDocumentNumberHandler(documentNumberUseCase)
+	DocumentNumberHandler.DocumentNumberRoutes(app)
+
 	contractRepo := postgres.NewContractRepository(db)
 	quotationRepo := postgres.NewQuotationRepository(db)
 	periodRepo := postgres.NewPeriodRepository(db)
@@ -101,14 +134,74 @@ func main() {
 	InvoiceHandler := rest.NewInvoiceHandler(invoiceUseCase)
 	InvoiceHandler.InvoiceRoutes(app)
 
+	paymentRepo := postgres.NewPaymentRepository(db)
+	paymentUseCase := usecase.NewPaymentUsecase(paymentRepo, invoiceRepo, contractRepo)
+	PaymentHandler := rest.NewPaymentHandler(paymentUseCase)
+	PaymentHandler.PaymentRoutes(app)
+
+	adjustmentNoteRepo := postgres.NewAdjustmentNoteRepository(db)
+	adjustmentNoteUseCase := usecase.NewAdjustmentNoteUsecase(adjustmentNoteRepo, invoiceRepo)
+	AdjustmentNoteHandler := rest.NewAdjustmentNoteHandler(adjustmentNoteUseCase)
+	AdjustmentNoteHandler.AdjustmentNoteRoutes(app)
+
 	contractUseCase := usecase.NewContractUsecase(contractRepo, periodRepo, projectRepo, quotationRepo, jobRepo)
-	ContractHandler := rest.NewContractHandler(contractUseCase, invoiceUseCase)
+	ContractHandler := rest.NewContractHandler(contractUseCase)
 	ContractHandler.ContractRoutes(app)
 
 	quotationUseCase := usecase.NewQuotationUsecase(quotationRepo)
 	QuotationHandler := rest.NewQuotationHandler(quotationUseCase, contractUseCase)
 	QuotationHandler.QuotationRoutes(app)
 
+	// Share links are signed with their own key so that they can never be
+	// confused with login tokens.
+	quotationShareSecret := getEnv("QUOTATION_SHARE_SECRET", "")
+	if quotationShareSecret == "" {
+		quotationShareSecret = randomSecret()
+		log.Println("Warning: QUOTATION_SHARE_SECRET is not set; quotation share links will stop working when the server restarts")
+	} else if quotationShareSecret == jwtSecret {
+		log.Fatal("QUOTATION_SHARE_SECRET must differ from JWT_SECRET")
+	}
+
+	quotationShareRepo := postgres.NewQuotationShareRepository(db)
+	quotationShareUseCase := usecase.NewQuotationShareUsecase(quotationShareRepo, quotationRepo, projectRepo, quotationUseCase, quotationShareSecret)
+	QuotationShareHandler := rest.NewQuotationShareHandler(quotationShareUseCase)
+	QuotationShareHandler.QuotationShareRoutes(app)
+
+	// Contracts, receipts and tax invoices are printed with this font. The
+	// built-in PDF font cannot show Thai, so PDFs with Thai text are refused
+	// until a Thai-capable TrueType font is configured.
+	var contractFont []byte
+	if fontPath := getEnv("CONTRACT_PDF_FONT", ""); fontPath != "" {
+		contractFont, err = os.ReadFile(fontPath)
+		if err != nil {
+			log.Fatalf("Failed to read contract PDF font: %v", err)
+		}
+		if err := document.ValidateFont(contractFont, thaiFontSample); err != nil {
+			log.Fatalf("Contract PDF font %s cannot be used for Thai documents: %v", fontPath, err)
+		}
+	} else {
+		log.Println("Warning: CONTRACT_PDF_FONT is not set; receipts and PDFs containing Thai text cannot be generated")
+	}
+
+	contractTemplateRepo := postgres.NewContractTemplateRepository(db)
+	contractTemplateUseCase := usecase.NewContractTemplateUsecase(contractTemplateRepo, contractRepo, periodRepo, projectRepo, quotationRepo, companyRepo, contractFont)
+	ContractTemplateHandler := rest.NewContractTemplateHandler(contractTemplateUseCase)
+	ContractTemplateHandler.ContractTemplateRoutes(app)
+
+	receiptRepo := postgres.NewReceiptRepository(db)
+	receiptUseCase := usecase.NewReceiptUsecase(receiptRepo, paymentRepo, invoiceRepo, projectRepo, companyRepo, contractFont)
+	ReceiptHandler := rest.NewReceiptHandler(receiptUseCase)
+	ReceiptHandler.ReceiptRoutes(app)
+
+	eTaxUseCase := usecase.NewETaxUsecase(invoiceRepo, receiptRepo, adjustmentNoteRepo, projectRepo, companyRepo)
+	ETaxHandler := rest.NewETaxHandler(eTaxUseCase)
+	ETaxHandler.ETaxRoutes(app)
+
+	changeOrderRepo := postgres.NewChangeOrderRepository(db)
+	changeOrderUseCase := usecase.NewChangeOrderUsecase(changeOrderRepo, contractRepo, periodRepo, quotationRepo, jobRepo)
+	ChangeOrderHandler := rest.NewChangeOrderHandler(changeOrderUseCase)
+	ChangeOrderHandler.ChangeOrderRoutes(app)
+
 	port := getEnv("PORT", "8004")
 	if err := app.Listen(":" + port); err != nil {
 		log.Fatalf("Failed to start server: %v", err)
@@ -122,6 +215,20 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// thaiFontSample holds the Thai consonants, vowels, tone marks and digits
+// that the contract PDF font must be able to show.
+const thaiFontSample = "กขฃคฅฆงจฉชซฌญฎฏฐฑฒณดตถทธนบปผฝพฟภมยรฤลฦวศษสหฬอฮ" +
+	"ะัาำิีึืุู฿เแโใไๆ็่้๊๋์ํ๐๑๒๓๔๕๖๗๘๙"
+
+// randomSecret returns a signing key that only lives as long as the process.
+func randomSecret() string {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		log.Fatalf("Failed to generate secret: %v", err)
+	}
+	return hex.EncodeToString(key)
+}
+
 func getEnvAsInt(key string, defaultValue int) int {
 	valueStr := getEnv(key, "")
 	if value, err := strconv.Atoi(valueStr); err == nil {
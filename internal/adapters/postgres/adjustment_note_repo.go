@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"boonkosang/internal/domain/models"
+	"boonkosang/internal/repositories"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type adjustmentNoteRepository struct {
+	db *sqlx.DB
+}
+
+func NewAdjustmentNoteRepository(db *sqlx.DB) repositories.AdjustmentNoteRepository {
+	return &adjustmentNoteRepository{db: db}
+}
+
+func (r *adjustmentNoteRepository) Create(ctx context.Context, note *models.AdjustmentNote, update models.InvoiceBalanceUpdate) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	invoice, err := lockInvoice(ctx, tx, note.InvoiceID)
+	if err != nil {
+		return err
+	}
+
+	noteNumber, err := nextDocumentNumber(ctx, tx, note.ProjectID, note.NoteType.DocumentType(), note.NoteDate)
+	if err != nil {
+		return err
+	}
+
+	note.NoteID = uuid.New()
+	note.NoteNumber = noteNumber
+	note.CreatedAt = time.Now()
+
+	query := `
+        INSERT INTO adjustment_note (
+            note_id, note_number, note_type, invoice_id, project_id, note_date, reason,
+            sub_total, tax_percentage, tax_amount, total_amount, created_at
+        ) VALUES (
+            :note_id, :note_number, :note_type, :invoice_id, :project_id, :note_date, :reason,
+            :sub_total, :tax_percentage, :tax_amount, :total_amount, :created_at
+        )`
+
+	if _, err := tx.NamedExecContext(ctx, query, note); err != nil {
+		return fmt.Errorf("failed to create %s note: %w", note.NoteType, err)
+	}
+
+	if err := updateInvoiceBalance(ctx, tx, invoice, update); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *adjustmentNoteRepository) GetByID(ctx context.Context, noteID uuid.UUID) (*models.AdjustmentNote, error) {
+	var note models.AdjustmentNote
+	query := `SELECT * FROM adjustment_note WHERE note_id = $1`
+	err := r.db.GetContext(ctx, &note, query, noteID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("note not found")
+		}
+		return nil, fmt.Errorf("failed to get note: %w", err)
+	}
+
+	return &note, nil
+}
+
+func (r *adjustmentNoteRepository) ListByInvoiceID(ctx context.Context, invoiceID uuid.UUID) ([]models.AdjustmentNote, error) {
+	notes := []models.AdjustmentNote{}
+	query := `SELECT * FROM adjustment_note WHERE invoice_id = $1 ORDER BY note_date, created_at`
+	if err := r.db.SelectContext(ctx, &notes, query, invoiceID); err != nil {
+		return nil, fmt.Errorf("failed to get notes: %w", err)
+	}
+
+	return notes, nil
+}
+
+func (r *adjustmentNoteRepository) Void(ctx context.Context, note *models.AdjustmentNote, update models.InvoiceBalanceUpdate) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	invoice, err := lockInvoice(ctx, tx, note.InvoiceID)
+	if err != nil {
+		return err
+	}
+
+	note.VoidedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	query := `
+        UPDATE adjustment_note SET
+            voided_at = $1,
+            void_reason = $2
+        WHERE note_id = $3 AND voided_at IS NULL`
+
+	result, err := tx.ExecContext(ctx, query, note.VoidedAt, note.VoidReason, note.NoteID)
+	if err != nil {
+		return fmt.Errorf("failed to void note: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return errors.New("note is already voided")
+	}
+
+	if err := updateInvoiceBalance(ctx, tx, invoice, update); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
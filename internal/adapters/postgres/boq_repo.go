@@ -243,13 +243,18 @@ func (r *boqRepository) AddBOQJob(ctx context.Context, boqID uuid.UUID, req requ
 
 	// Get all materials for the job
 	materialQuery := `
-        SELECT material_id, quantity 
-        FROM job_material 
-        WHERE job_id = $1`
+        SELECT 
+            jm.material_id, 
+            jm.quantity,
+            COALESCE(jm.waste_percent, m.waste_percent) AS waste_percent
+        FROM job_material jm
+        JOIN material m ON m.material_id = jm.material_id
+        WHERE jm.job_id = $1`
 
 	type JobMaterial struct {
-		MaterialID string  `db:"material_id"`
-		Quantity   float64 `db:"quantity"`
+		MaterialID   string  `db:"material_id"`
+		Quantity     float64 `db:"quantity"`
+		WastePercent float64 `db:"waste_percent"`
 	}
 	var materials []JobMaterial
 
@@ -293,9 +298,9 @@ func (r *boqRepository) AddBOQJob(ctx context.Context, boqID uuid.UUID, req requ
 	for _, material := range materials {
 		insertPriceLogQuery := `
             INSERT INTO material_price_log (
-                material_id, boq_id, job_id, quantity, estimated_price, updated_at
+                material_id, boq_id, job_id, quantity, waste_percent, estimated_price, updated_at
             ) VALUES (
-                $1, $2, $3, $4, $5, CURRENT_TIMESTAMP
+                $1, $2, $3, $4, $5, $6, CURRENT_TIMESTAMP
             )`
 
 		estimatedPrice := estimatedPrices[material.MaterialID]
@@ -305,6 +310,7 @@ func (r *boqRepository) AddBOQJob(ctx context.Context, boqID uuid.UUID, req requ
 			boqID,
 			req.JobID,
 			material.Quantity,
+			material.WastePercent,
 			estimatedPrice,
 		)
 		if err != nil {
@@ -448,7 +454,7 @@ func (r *boqRepository) GetBOQDetails(ctx context.Context, projectID uuid.UUID)
             SELECT 
                 job_id, 
                 boq_id, 
-                COALESCE(SUM(COALESCE(estimated_price, 0) * COALESCE(quantity, 0)), 0) as total_material_price
+                COALESCE(SUM(COALESCE(estimated_price, 0) * COALESCE(gross_quantity, 0)), 0) as total_material_price
             FROM material_price_log
             GROUP BY job_id, boq_id
         )
@@ -492,9 +498,11 @@ func (r *boqRepository) GetBOQMaterialDetails(ctx context.Context, projectID uui
             j.name, 
             m.name as material_name,
             mpl.quantity, 
+            COALESCE(mpl.waste_percent, 0) as waste_percent,
+            mpl.gross_quantity,
             m.unit, 
             mpl.estimated_price, 
-            COALESCE(mpl.quantity, 0) * COALESCE(mpl.estimated_price, 0) as total
+            COALESCE(mpl.gross_quantity, 0) * COALESCE(mpl.estimated_price, 0) as total
         FROM project p 
         JOIN boq b ON b.project_id = p.project_id 
         LEFT JOIN client c ON c.client_id = p.project_id 
@@ -0,0 +1,446 @@
+package postgres
+
+import (
+	"boonkosang/internal/domain/models"
+	"boonkosang/internal/repositories"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type changeOrderRepository struct {
+	db *sqlx.DB
+}
+
+func NewChangeOrderRepository(db *sqlx.DB) repositories.ChangeOrderRepository {
+	return &changeOrderRepository{db: db}
+}
+
+func (r *changeOrderRepository) Create(ctx context.Context, changeOrder *models.ChangeOrder) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the contract row so concurrent drafts get distinct numbers
+	var contractID uuid.UUID
+	err = tx.GetContext(ctx, &contractID, `SELECT contract_id FROM contract WHERE contract_id = $1 FOR UPDATE`, changeOrder.ContractID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errors.New("contract not found")
+		}
+		return fmt.Errorf("failed to lock contract: %w", err)
+	}
+
+	numberQuery := `SELECT COALESCE(MAX(change_order_number), 0) + 1 FROM change_order WHERE contract_id = $1`
+	if err := tx.GetContext(ctx, &changeOrder.ChangeOrderNumber, numberQuery, changeOrder.ContractID); err != nil {
+		return fmt.Errorf("failed to get change order number: %w", err)
+	}
+
+	query := `
+        INSERT INTO change_order (
+            change_order_id, contract_id, project_id, change_order_number, title,
+            reason, status, tax_percentage, value_change, created_at
+        ) VALUES (
+            :change_order_id, :contract_id, :project_id, :change_order_number, :title,
+            :reason, :status, :tax_percentage, :value_change, :created_at
+        )`
+
+	if _, err := tx.NamedExecContext(ctx, query, changeOrder); err != nil {
+		return fmt.Errorf("failed to create change order: %w", err)
+	}
+
+	if err := r.insertDetails(ctx, tx, changeOrder); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *changeOrderRepository) Update(ctx context.Context, changeOrder *models.ChangeOrder) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+        UPDATE change_order SET
+            title = :title,
+            reason = :reason,
+            tax_percentage = :tax_percentage,
+            value_change = :value_change,
+            updated_at = CURRENT_TIMESTAMP
+        WHERE change_order_id = :change_order_id AND status = 'draft'`
+
+	result, err := tx.NamedExecContext(ctx, query, changeOrder)
+	if err != nil {
+		return fmt.Errorf("failed to update change order: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return errors.New("only draft change orders can be edited")
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM change_order_item WHERE change_order_id = $1`, changeOrder.ChangeOrderID); err != nil {
+		return fmt.Errorf("failed to delete change order items: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM change_order_period WHERE change_order_id = $1`, changeOrder.ChangeOrderID); err != nil {
+		return fmt.Errorf("failed to delete change order periods: %w", err)
+	}
+
+	if err := r.insertDetails(ctx, tx, changeOrder); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *changeOrderRepository) insertDetails(ctx context.Context, tx *sqlx.Tx, changeOrder *models.ChangeOrder) error {
+	itemQuery := `
+        INSERT INTO change_order_item (
+            item_id, change_order_id, job_id, change_type, original_quantity, new_quantity,
+            original_selling_price, new_selling_price, labor_cost, amount_change
+        ) VALUES (
+            :item_id, :change_order_id, :job_id, :change_type, :original_quantity, :new_quantity,
+            :original_selling_price, :new_selling_price, :labor_cost, :amount_change
+        )`
+
+	for i := range changeOrder.Items {
+		item := &changeOrder.Items[i]
+		item.ItemID = uuid.New()
+		item.ChangeOrderID = changeOrder.ChangeOrderID
+		if _, err := tx.NamedExecContext(ctx, itemQuery, item); err != nil {
+			return fmt.Errorf("failed to create change order item: %w", err)
+		}
+	}
+
+	periodQuery := `
+        INSERT INTO change_order_period (
+            change_order_period_id, change_order_id, period_id, period_number,
+            delivered_within, original_amount, amount_change
+        ) VALUES (
+            :change_order_period_id, :change_order_id, :period_id, :period_number,
+            :delivered_within, :original_amount, :amount_change
+        )`
+
+	for i := range changeOrder.Periods {
+		period := &changeOrder.Periods[i]
+		period.ChangeOrderPeriodID = uuid.New()
+		period.ChangeOrderID = changeOrder.ChangeOrderID
+		if _, err := tx.NamedExecContext(ctx, periodQuery, period); err != nil {
+			return fmt.Errorf("failed to create change order period: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (r *changeOrderRepository) Delete(ctx context.Context, changeOrderID uuid.UUID) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM change_order_item WHERE change_order_id = $1`, changeOrderID); err != nil {
+		return fmt.Errorf("failed to delete change order items: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM change_order_period WHERE change_order_id = $1`, changeOrderID); err != nil {
+		return fmt.Errorf("failed to delete change order periods: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM change_order WHERE change_order_id = $1 AND status = 'draft'`, changeOrderID)
+	if err != nil {
+		return fmt.Errorf("failed to delete change order: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return errors.New("only draft change orders can be deleted")
+	}
+
+	return tx.Commit()
+}
+
+func (r *changeOrderRepository) GetByID(ctx context.Context, changeOrderID uuid.UUID) (*models.ChangeOrder, error) {
+	var changeOrder models.ChangeOrder
+	err := r.db.GetContext(ctx, &changeOrder, `SELECT * FROM change_order WHERE change_order_id = $1`, changeOrderID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("change order not found")
+		}
+		return nil, fmt.Errorf("failed to get change order: %w", err)
+	}
+
+	if err := r.loadDetails(ctx, &changeOrder); err != nil {
+		return nil, err
+	}
+
+	return &changeOrder, nil
+}
+
+func (r *changeOrderRepository) ListByContractID(ctx context.Context, contractID uuid.UUID) ([]models.ChangeOrder, error) {
+	changeOrders := []models.ChangeOrder{}
+	query := `SELECT * FROM change_order WHERE contract_id = $1 ORDER BY change_order_number`
+	if err := r.db.SelectContext(ctx, &changeOrders, query, contractID); err != nil {
+		return nil, fmt.Errorf("failed to get change orders: %w", err)
+	}
+
+	for i := range changeOrders {
+		if err := r.loadDetails(ctx, &changeOrders[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return changeOrders, nil
+}
+
+func (r *changeOrderRepository) loadDetails(ctx context.Context, changeOrder *models.ChangeOrder) error {
+	itemQuery := `
+        SELECT coi.*, j.name as job_name
+        FROM change_order_item coi
+        JOIN job j ON j.job_id = coi.job_id
+        WHERE coi.change_order_id = $1
+        ORDER BY j.name`
+	changeOrder.Items = []models.ChangeOrderItem{}
+	if err := r.db.SelectContext(ctx, &changeOrder.Items, itemQuery, changeOrder.ChangeOrderID); err != nil {
+		return fmt.Errorf("failed to get change order items: %w", err)
+	}
+
+	periodQuery := `SELECT * FROM change_order_period WHERE change_order_id = $1 ORDER BY period_number`
+	changeOrder.Periods = []models.ChangeOrderPeriod{}
+	if err := r.db.SelectContext(ctx, &changeOrder.Periods, periodQuery, changeOrder.ChangeOrderID); err != nil {
+		return fmt.Errorf("failed to get change order periods: %w", err)
+	}
+
+	return nil
+}
+
+func (r *changeOrderRepository) UpdateStatus(ctx context.Context, changeOrderID uuid.UUID, status models.ChangeOrderStatus, rejectReason string) error {
+	query := `
+        UPDATE change_order SET
+            status = $1,
+            reject_reason = NULLIF($2, ''),
+            submitted_at = CASE WHEN $1 = 'submitted' THEN CURRENT_TIMESTAMP ELSE submitted_at END,
+            rejected_at = CASE WHEN $1 = 'rejected' THEN CURRENT_TIMESTAMP ELSE rejected_at END,
+            updated_at = CURRENT_TIMESTAMP
+        WHERE change_order_id = $3`
+
+	if _, err := r.db.ExecContext(ctx, query, status, rejectReason, changeOrderID); err != nil {
+		return fmt.Errorf("failed to update change order status: %w", err)
+	}
+
+	return nil
+}
+
+// Approve updates the BOQ jobs and payment periods in one transaction. It
+// fails if the BOQ or a period no longer matches the values recorded on the
+// change order, which happens when another change order was approved first.
+func (r *changeOrderRepository) Approve(ctx context.Context, changeOrder *models.ChangeOrder) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var boqID uuid.UUID
+	err = tx.GetContext(ctx, &boqID, `SELECT boq_id FROM boq WHERE project_id = $1`, changeOrder.ProjectID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errors.New("BOQ not found")
+		}
+		return fmt.Errorf("failed to get BOQ: %w", err)
+	}
+
+	for _, item := range changeOrder.Items {
+		var current struct {
+			Quantity     float64         `db:"quantity"`
+			SellingPrice sql.NullFloat64 `db:"selling_price"`
+		}
+		err := tx.GetContext(ctx, &current,
+			`SELECT quantity, selling_price FROM boq_job WHERE boq_id = $1 AND job_id = $2 FOR UPDATE`,
+			boqID, item.JobID)
+		exists := err == nil
+		if err != nil && err != sql.ErrNoRows {
+			return fmt.Errorf("failed to get BOQ job: %w", err)
+		}
+
+		switch item.ChangeType {
+		case models.ChangeTypeAdd:
+			if exists && current.Quantity > 0 {
+				return errors.New("change order is out of date with the current BOQ")
+			}
+			if exists {
+				_, err = tx.ExecContext(ctx,
+					`UPDATE boq_job SET quantity = $1, labor_cost = $2, selling_price = $3 WHERE boq_id = $4 AND job_id = $5`,
+					item.NewQuantity, item.LaborCost, item.NewSellingPrice, boqID, item.JobID)
+			} else {
+				_, err = tx.ExecContext(ctx,
+					`INSERT INTO boq_job (boq_id, job_id, quantity, labor_cost, selling_price) VALUES ($1, $2, $3, $4, $5)`,
+					boqID, item.JobID, item.NewQuantity, item.LaborCost, item.NewSellingPrice)
+				if err == nil {
+					_, err = tx.ExecContext(ctx, `
+                        INSERT INTO material_price_log (
+                            material_id, boq_id, job_id, quantity, waste_percent, estimated_price, updated_at
+                        )
+                        SELECT jm.material_id, $1, $2, jm.quantity, COALESCE(jm.waste_percent, m.waste_percent),
+                            (SELECT mpl.estimated_price FROM material_price_log mpl
+                             WHERE mpl.boq_id = $1 AND mpl.material_id = jm.material_id
+                             AND mpl.estimated_price IS NOT NULL LIMIT 1),
+                            CURRENT_TIMESTAMP
+                        FROM job_material jm
+                        JOIN material m ON m.material_id = jm.material_id
+                        WHERE jm.job_id = $2`, boqID, item.JobID)
+				}
+			}
+		case models.ChangeTypeModify, models.ChangeTypeRemove:
+			if !exists || !sameAmount(current.Quantity, item.OriginalQuantity) ||
+				!sameAmount(current.SellingPrice.Float64, item.OriginalSellingPrice) {
+				return errors.New("change order is out of date with the current BOQ")
+			}
+			// Removed jobs keep their BOQ row with a zero quantity so that
+			// period and material history still refer to them.
+			_, err = tx.ExecContext(ctx,
+				`UPDATE boq_job SET quantity = $1, selling_price = $2 WHERE boq_id = $3 AND job_id = $4`,
+				item.NewQuantity, item.NewSellingPrice, boqID, item.JobID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to update BOQ job: %w", err)
+		}
+	}
+
+	for _, period := range changeOrder.Periods {
+		if period.PeriodID.Valid {
+			var amount float64
+			err := tx.GetContext(ctx, &amount,
+				`SELECT amount_period FROM period WHERE period_id = $1 AND contract_id = $2 FOR UPDATE`,
+				period.PeriodID.UUID, changeOrder.ContractID)
+			if err != nil {
+				if err == sql.ErrNoRows {
+					return errors.New("period not found")
+				}
+				return fmt.Errorf("failed to get period: %w", err)
+			}
+			if !sameAmount(amount, period.OriginalAmount) {
+				return errors.New("change order is out of date with the current payment periods")
+			}
+
+			_, err = tx.ExecContext(ctx,
+				`UPDATE period SET amount_period = $1, delivered_within = $2 WHERE period_id = $3`,
+				math.Round((amount+period.AmountChange)*100)/100, period.DeliveredWithin, period.PeriodID.UUID)
+			if err != nil {
+				return fmt.Errorf("failed to update period: %w", err)
+			}
+			continue
+		}
+
+		// New periods are numbered at approval so that change orders approved
+		// in a different order than they were drafted do not collide.
+		var periodNumber int
+		err := tx.GetContext(ctx, &periodNumber,
+			`SELECT COALESCE(MAX(period_number), 0) + 1 FROM period WHERE contract_id = $1`, changeOrder.ContractID)
+		if err != nil {
+			return fmt.Errorf("failed to get period number: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+            INSERT INTO period (period_id, contract_id, period_number, amount_period, delivered_within)
+            VALUES ($1, $2, $3, $4, $5)`,
+			uuid.New(), changeOrder.ContractID, periodNumber, period.AmountChange, period.DeliveredWithin)
+		if err != nil {
+			return fmt.Errorf("failed to create period: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx,
+			`UPDATE change_order_period SET period_number = $1 WHERE change_order_period_id = $2`,
+			periodNumber, period.ChangeOrderPeriodID)
+		if err != nil {
+			return fmt.Errorf("failed to update change order period: %w", err)
+		}
+	}
+
+	// New periods need invoices the same way the original contract periods
+	// got them at approval.
+	periods, err := uninvoicedPeriods(ctx, tx, changeOrder.ContractID)
+	if err != nil {
+		return err
+	}
+	if err := insertPeriodInvoices(ctx, tx, changeOrder.ProjectID, periods, sql.NullString{}); err != nil {
+		return err
+	}
+
+	// The periods now add up to the old contract value plus the change, so
+	// the quotation's final amount moves by the same value.
+	_, err = tx.ExecContext(ctx, `
+        UPDATE quotation SET final_amount = ROUND((COALESCE(final_amount, 0) + $1)::numeric, 2)
+        WHERE project_id = $2`, changeOrder.ValueChange, changeOrder.ProjectID)
+	if err != nil {
+		return fmt.Errorf("failed to update quotation final amount: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, `
+        UPDATE change_order SET
+            status = 'approved',
+            approved_at = CURRENT_TIMESTAMP,
+            updated_at = CURRENT_TIMESTAMP
+        WHERE change_order_id = $1 AND status = 'submitted'`, changeOrder.ChangeOrderID)
+	if err != nil {
+		return fmt.Errorf("failed to approve change order: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return errors.New("only submitted change orders can be approved")
+	}
+
+	return tx.Commit()
+}
+
+func (r *changeOrderRepository) GetPeriodInvoiceStatuses(ctx context.Context, contractID uuid.UUID) (map[uuid.UUID]string, error) {
+	query := `
+        SELECT p.period_id, COALESCE(i.status, '') as status
+        FROM period p
+        LEFT JOIN invoice i ON i.period_id = p.period_id
+        WHERE p.contract_id = $1`
+
+	var rows []struct {
+		PeriodID uuid.UUID `db:"period_id"`
+		Status   string    `db:"status"`
+	}
+	if err := r.db.SelectContext(ctx, &rows, query, contractID); err != nil {
+		return nil, fmt.Errorf("failed to get period invoice statuses: %w", err)
+	}
+
+	statuses := make(map[uuid.UUID]string, len(rows))
+	for _, row := range rows {
+		statuses[row.PeriodID] = row.Status
+	}
+
+	return statuses, nil
+}
+
+func sameAmount(a, b float64) bool {
+	return math.Abs(a-b) < 0.005
+}
@@ -32,14 +32,18 @@ func (r *clientRepository) Create(ctx context.Context, req requests.CreateClient
 		Tel:      req.Tel,
 		Address:  req.Address,
 		TaxID:    req.TaxID,
+		Branch:   req.Branch,
+
+		WithholdingTaxPercentage: sql.NullFloat64{Float64: req.WithholdingTaxPercentage, Valid: true},
 	}
 
 	query := `
         INSERT INTO Client (
-            client_id, name, email, tel, address, tax_id
-          
+            client_id, name, email, tel, address, tax_id, branch,
+            withholding_tax_percentage
         ) VALUES (
-            :client_id, :name, :email, :tel, :address, :tax_id
+            :client_id, :name, :email, :tel, :address, :tax_id, :branch,
+            :withholding_tax_percentage
         ) RETURNING *`
 
 	rows, err := r.db.NamedQueryContext(ctx, query, client)
@@ -68,7 +72,9 @@ func (r *clientRepository) Update(ctx context.Context, id uuid.UUID, req request
             email = :email,
             tel = :tel,
             address = :address,
-            tax_id = :tax_id
+            tax_id = :tax_id,
+            branch = :branch,
+            withholding_tax_percentage = COALESCE(:withholding_tax_percentage, withholding_tax_percentage)
         WHERE client_id = :client_id`
 
 	params := map[string]interface{}{
@@ -78,6 +84,9 @@ func (r *clientRepository) Update(ctx context.Context, id uuid.UUID, req request
 		"tel":       req.Tel,
 		"address":   req.Address,
 		"tax_id":    req.TaxID,
+		"branch":    req.Branch,
+
+		"withholding_tax_percentage": req.WithholdingTaxPercentage,
 	}
 
 	result, err := r.db.NamedExecContext(ctx, query, params)
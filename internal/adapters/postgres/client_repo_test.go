@@ -0,0 +1,54 @@
+package postgres_test
+
+import (
+	"boonkosang/internal/adapters/postgres"
+	"boonkosang/internal/requests"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateClientWithholdingTax(t *testing.T) {
+	clientID := uuid.New()
+	rate := 3.0
+
+	tests := []struct {
+		name string
+		rate *float64
+		want interface{}
+	}{
+		{name: "new rate", rate: &rate, want: 3.0},
+		{name: "omitted rate keeps the stored one", rate: nil, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectExec(`withholding_tax_percentage = COALESCE\(\$\d+, withholding_tax_percentage\)`).
+				WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), tt.want, clientID).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			repo := postgres.NewClientRepository(sqlx.NewDb(db, "postgres"))
+			err = repo.Update(context.Background(), clientID, requests.UpdateClientRequest{
+				Name:    "Siam Builders",
+				Email:   "office@siambuilders.co.th",
+				Tel:     "0812345678",
+				Address: json.RawMessage(`{}`),
+				TaxID:   "0105551234567",
+
+				WithholdingTaxPercentage: tt.rate,
+			})
+			require.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
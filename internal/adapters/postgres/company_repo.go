@@ -93,12 +93,13 @@ func (r *companyRepository) GetOrCreateCompanyByUserID(ctx context.Context, user
 		Tel:       "",
 		Address:   addressJSON,
 		TaxID:     "",
+		Branch:    models.HeadOfficeBranch,
 	}
 
 	// Insert new company
 	insertCompanyQuery := `
-        INSERT INTO company (company_id, name, email, tel, address, tax_id)
-        VALUES (:company_id, :name, :email, :tel, :address, :tax_id)
+        INSERT INTO company (company_id, name, email, tel, address, tax_id, branch)
+        VALUES (:company_id, :name, :email, :tel, :address, :tax_id, :branch)
         RETURNING *`
 
 	rows, err := r.db.NamedQueryContext(ctx, insertCompanyQuery, newCompany)
@@ -136,7 +137,8 @@ func (r *companyRepository) UpdateCompany(ctx context.Context, company *models.C
             email = :email,
             tel = :tel,
             address = :address,
-            tax_id = :tax_id
+            tax_id = :tax_id,
+            branch = :branch
         WHERE company_id = :company_id`
 
 	result, err := r.db.NamedExecContext(ctx, query, company)
@@ -155,3 +157,17 @@ func (r *companyRepository) UpdateCompany(ctx context.Context, company *models.C
 
 	return nil
 }
+
+func (r *companyRepository) GetByID(ctx context.Context, companyID uuid.UUID) (*models.Company, error) {
+	var company models.Company
+	query := `SELECT * FROM company WHERE company_id = $1`
+	err := r.db.GetContext(ctx, &company, query, companyID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("company not found")
+		}
+		return nil, fmt.Errorf("failed to get company: %w", err)
+	}
+
+	return &company, nil
+}
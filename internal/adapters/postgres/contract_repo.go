@@ -28,6 +28,7 @@ func (r *contractRepository) Create(ctx context.Context, contract *models.Contra
 	query := `
 		INSERT INTO contract (
 			contract_id, 
+			contract_number,
 			project_id, 
 			project_description,
 			area_size,
@@ -47,6 +48,7 @@ func (r *contractRepository) Create(ctx context.Context, contract *models.Contra
 			created_at
 		) VALUES (
 			:contract_id, 
+			:contract_number,
 			:project_id, 
 			:project_description,
 			:area_size,
@@ -71,8 +73,21 @@ func (r *contractRepository) Create(ctx context.Context, contract *models.Contra
 		return fmt.Errorf("failed to marshal format: %w", err)
 	}
 
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	createdAt := time.Now()
+	contractNumber, err := nextDocumentNumber(ctx, tx, contract.ProjectID, models.DocumentTypeContract, createdAt)
+	if err != nil {
+		return err
+	}
+
 	params := map[string]interface{}{
 		"contract_id":             uuid.New(),
+		"contract_number":         contractNumber,
 		"project_id":              contract.ProjectID,
 		"project_description":     contract.ProjectDescription.String,
 		"area_size":               contract.AreaSize.Float64,
@@ -89,14 +104,18 @@ func (r *contractRepository) Create(ctx context.Context, contract *models.Contra
 		"validate_within":         contract.ValidateWithin.Int32,
 		"format":                  string(formatJSON),
 		"status":                  "draft",
-		"created_at":              time.Now(),
+		"created_at":              createdAt,
 	}
 
-	_, err = r.db.NamedExecContext(ctx, query, params)
+	_, err = tx.NamedExecContext(ctx, query, params)
 	if err != nil {
 		return fmt.Errorf("failed to create contract: %w", err)
 	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	return nil
 }
 
@@ -244,11 +263,78 @@ func (r *contractRepository) ValidateProjectStatus(ctx context.Context, projectI
 	return nil
 }
 
-func (r *contractRepository) ChangeStatus(ctx context.Context, projectID uuid.UUID, status string) error {
-	query := `UPDATE contract SET status = $1 WHERE project_id = $2`
-	_, err := r.db.ExecContext(ctx, query, status, projectID)
+func (r *contractRepository) ChangeStatus(ctx context.Context, history *models.ContractStatusHistory) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Contracts created before statuses were tracked have a NULL status
+	query := `
+        UPDATE contract SET status = $1, updated_at = CURRENT_TIMESTAMP
+        WHERE contract_id = $2 AND COALESCE(status, 'draft') = $3`
+	result, err := tx.ExecContext(ctx, query, history.ToStatus, history.ContractID, history.FromStatus)
 	if err != nil {
 		return fmt.Errorf("failed to update contract status: %w", err)
 	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return errors.New("contract status was changed by another request")
+	}
+
+	if history.HistoryID == uuid.Nil {
+		history.HistoryID = uuid.New()
+	}
+	history.ChangedAt = time.Now()
+
+	historyQuery := `
+        INSERT INTO contract_status_history (
+            history_id, contract_id, from_status, to_status,
+            termination_reason, remarks, changed_at
+        ) VALUES (
+            :history_id, :contract_id, :from_status, :to_status,
+            :termination_reason, :remarks, :changed_at
+        )`
+	if _, err := tx.NamedExecContext(ctx, historyQuery, history); err != nil {
+		return fmt.Errorf("failed to record contract status history: %w", err)
+	}
+
+	// Periods are invoiced when the contract is first agreed, in the same
+	// transaction so that a failure leaves the contract unapproved.
+	if !history.FromStatus.IsBinding() && history.ToStatus.IsBinding() {
+		var projectID uuid.UUID
+		err := tx.GetContext(ctx, &projectID, `SELECT project_id FROM contract WHERE contract_id = $1`, history.ContractID)
+		if err != nil {
+			return fmt.Errorf("failed to get contract project: %w", err)
+		}
+
+		periods, err := uninvoicedPeriods(ctx, tx, history.ContractID)
+		if err != nil {
+			return err
+		}
+		if err := insertPeriodInvoices(ctx, tx, projectID, periods, sql.NullString{}); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	return nil
 }
+
+func (r *contractRepository) GetStatusHistory(ctx context.Context, contractID uuid.UUID) ([]models.ContractStatusHistory, error) {
+	history := []models.ContractStatusHistory{}
+	query := `SELECT * FROM contract_status_history WHERE contract_id = $1 ORDER BY changed_at`
+	if err := r.db.SelectContext(ctx, &history, query, contractID); err != nil {
+		return nil, fmt.Errorf("failed to get contract status history: %w", err)
+	}
+
+	return history, nil
+}
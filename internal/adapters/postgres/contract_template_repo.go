@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"boonkosang/internal/domain/models"
+	"boonkosang/internal/repositories"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type contractTemplateRepository struct {
+	db *sqlx.DB
+}
+
+func NewContractTemplateRepository(db *sqlx.DB) repositories.ContractTemplateRepository {
+	return &contractTemplateRepository{db: db}
+}
+
+func (r *contractTemplateRepository) Create(ctx context.Context, template *models.ContractClauseTemplate) error {
+	query := `
+        INSERT INTO contract_clause_template (
+            template_id, company_id, clause_type, title, body, sort_order, created_at
+        ) VALUES (
+            :template_id, :company_id, :clause_type, :title, :body, :sort_order, :created_at
+        )`
+
+	_, err := r.db.NamedExecContext(ctx, query, template)
+	if err != nil {
+		return fmt.Errorf("failed to create clause template: %w", err)
+	}
+
+	return nil
+}
+
+func (r *contractTemplateRepository) Update(ctx context.Context, template *models.ContractClauseTemplate) error {
+	query := `
+        UPDATE contract_clause_template SET
+            clause_type = :clause_type,
+            title = :title,
+            body = :body,
+            sort_order = :sort_order,
+            updated_at = CURRENT_TIMESTAMP
+        WHERE template_id = :template_id AND company_id = :company_id`
+
+	result, err := r.db.NamedExecContext(ctx, query, template)
+	if err != nil {
+		return fmt.Errorf("failed to update clause template: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return errors.New("clause template not found")
+	}
+
+	return nil
+}
+
+func (r *contractTemplateRepository) Delete(ctx context.Context, companyID uuid.UUID, templateID uuid.UUID) error {
+	query := `DELETE FROM contract_clause_template WHERE template_id = $1 AND company_id = $2`
+	result, err := r.db.ExecContext(ctx, query, templateID, companyID)
+	if err != nil {
+		return fmt.Errorf("failed to delete clause template: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return errors.New("clause template not found")
+	}
+
+	return nil
+}
+
+func (r *contractTemplateRepository) GetByID(ctx context.Context, companyID uuid.UUID, templateID uuid.UUID) (*models.ContractClauseTemplate, error) {
+	var template models.ContractClauseTemplate
+	query := `SELECT * FROM contract_clause_template WHERE template_id = $1 AND company_id = $2`
+	err := r.db.GetContext(ctx, &template, query, templateID, companyID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("clause template not found")
+		}
+		return nil, fmt.Errorf("failed to get clause template: %w", err)
+	}
+
+	return &template, nil
+}
+
+func (r *contractTemplateRepository) ListByCompanyID(ctx context.Context, companyID uuid.UUID) ([]models.ContractClauseTemplate, error) {
+	templates := []models.ContractClauseTemplate{}
+	query := `
+        SELECT * FROM contract_clause_template 
+        WHERE company_id = $1 
+        ORDER BY sort_order, created_at`
+	err := r.db.SelectContext(ctx, &templates, query, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clause templates: %w", err)
+	}
+
+	return templates, nil
+}
@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"boonkosang/internal/domain/models"
+	"boonkosang/internal/repositories"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type documentNumberRepository struct {
+	db *sqlx.DB
+}
+
+func NewDocumentNumberRepository(db *sqlx.DB) repositories.DocumentNumberRepository {
+	return &documentNumberRepository{db: db}
+}
+
+func (r *documentNumberRepository) ListFormats(ctx context.Context, companyID uuid.UUID) ([]models.DocumentNumberFormat, error) {
+	var formats []models.DocumentNumberFormat
+	query := `SELECT * FROM document_number_format WHERE company_id = $1 ORDER BY document_type`
+	err := r.db.SelectContext(ctx, &formats, query, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document number formats: %w", err)
+	}
+
+	return formats, nil
+}
+
+func (r *documentNumberRepository) UpsertFormat(ctx context.Context, format *models.DocumentNumberFormat) error {
+	query := `
+        INSERT INTO document_number_format (
+            company_id, document_type, pattern, created_at
+        ) VALUES (
+            :company_id, :document_type, :pattern, CURRENT_TIMESTAMP
+        )
+        ON CONFLICT (company_id, document_type) DO UPDATE SET
+            pattern = EXCLUDED.pattern,
+            updated_at = CURRENT_TIMESTAMP`
+
+	_, err := r.db.NamedExecContext(ctx, query, format)
+	if err != nil {
+		return fmt.Errorf("failed to save document number format: %w", err)
+	}
+
+	return nil
+}
+
+func (r *documentNumberRepository) ListSequences(ctx context.Context, companyID uuid.UUID) ([]models.DocumentSequence, error) {
+	var sequences []models.DocumentSequence
+	query := `
+        SELECT company_id, document_type, fiscal_year, last_number
+        FROM document_sequence
+        WHERE company_id = $1
+        ORDER BY fiscal_year DESC, document_type`
+	err := r.db.SelectContext(ctx, &sequences, query, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document sequences: %w", err)
+	}
+
+	return sequences, nil
+}
+
+// nextDocumentNumber allocates the next number for a project's company within
+// the caller's transaction. The sequence row stays locked until the transaction
+// ends and a rollback releases the number, so sequences never have gaps.
+// Sequences restart every calendar year (January to December) of the document
+// date unless the pattern has no year (see models.DocumentSequenceYear).
+// Projects without a company use the default patterns and a sequence shared by
+// all such projects, kept in default_document_sequence.
+func nextDocumentNumber(ctx context.Context, tx *sqlx.Tx, projectID uuid.UUID, documentType models.DocumentType, date time.Time) (string, error) {
+	var companyID uuid.NullUUID
+	err := tx.GetContext(ctx, &companyID, `SELECT company_id FROM project WHERE project_id = $1`, projectID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", errors.New("project not found")
+		}
+		return "", fmt.Errorf("failed to get project company: %w", err)
+	}
+
+	pattern := models.DefaultDocumentNumberPatterns[documentType]
+	var lastNumber int
+	if companyID.Valid {
+		var saved string
+		err = tx.GetContext(ctx, &saved, `
+            SELECT pattern FROM document_number_format
+            WHERE company_id = $1 AND document_type = $2`, companyID.UUID, documentType)
+		if err != nil && err != sql.ErrNoRows {
+			return "", fmt.Errorf("failed to get %s number format: %w", documentType, err)
+		}
+		if saved != "" {
+			pattern = saved
+		}
+
+		err = tx.GetContext(ctx, &lastNumber, `
+            INSERT INTO document_sequence (company_id, document_type, fiscal_year, last_number)
+            VALUES ($1, $2, $3, 1)
+            ON CONFLICT (company_id, document_type, fiscal_year) DO UPDATE SET
+                last_number = document_sequence.last_number + 1
+            RETURNING last_number`, companyID.UUID, documentType, models.DocumentSequenceYear(pattern, date))
+	} else {
+		err = tx.GetContext(ctx, &lastNumber, `
+            INSERT INTO default_document_sequence (document_type, fiscal_year, last_number)
+            VALUES ($1, $2, 1)
+            ON CONFLICT (document_type, fiscal_year) DO UPDATE SET
+                last_number = default_document_sequence.last_number + 1
+            RETURNING last_number`, documentType, models.DocumentSequenceYear(pattern, date))
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to allocate %s number: %w", documentType, err)
+	}
+
+	return models.FormatDocumentNumber(pattern, date, lastNumber), nil
+}
@@ -0,0 +1,123 @@
+package postgres_test
+
+import (
+	"boonkosang/internal/adapters/postgres"
+	"boonkosang/internal/domain/models"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextDocumentNumber(t *testing.T) {
+	projectID := uuid.New()
+	companyID := uuid.New()
+	date := time.Date(2026, time.March, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		expect func(mock sqlmock.Sqlmock)
+		want   string
+	}{
+		{
+			name: "company with its own pattern",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT company_id FROM project`).
+					WithArgs(projectID).
+					WillReturnRows(sqlmock.NewRows([]string{"company_id"}).AddRow(companyID))
+				mock.ExpectQuery(`SELECT pattern FROM document_number_format`).
+					WithArgs(companyID, models.DocumentTypeInvoice).
+					WillReturnRows(sqlmock.NewRows([]string{"pattern"}).AddRow("IV{YYYY}/{00000}"))
+				mock.ExpectQuery(`INSERT INTO document_sequence`).
+					WithArgs(companyID, models.DocumentTypeInvoice, 2026).
+					WillReturnRows(sqlmock.NewRows([]string{"last_number"}).AddRow(42))
+			},
+			want: "IV2026/00042",
+		},
+		{
+			name: "company with the default pattern",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT company_id FROM project`).
+					WithArgs(projectID).
+					WillReturnRows(sqlmock.NewRows([]string{"company_id"}).AddRow(companyID))
+				mock.ExpectQuery(`SELECT pattern FROM document_number_format`).
+					WithArgs(companyID, models.DocumentTypeInvoice).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectQuery(`INSERT INTO document_sequence`).
+					WithArgs(companyID, models.DocumentTypeInvoice, 2026).
+					WillReturnRows(sqlmock.NewRows([]string{"last_number"}).AddRow(7))
+			},
+			want: "INV-2603-007",
+		},
+		{
+			name: "saved pattern without a year never restarts",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT company_id FROM project`).
+					WithArgs(projectID).
+					WillReturnRows(sqlmock.NewRows([]string{"company_id"}).AddRow(companyID))
+				mock.ExpectQuery(`SELECT pattern FROM document_number_format`).
+					WithArgs(companyID, models.DocumentTypeInvoice).
+					WillReturnRows(sqlmock.NewRows([]string{"pattern"}).AddRow("INV-{0000}"))
+				mock.ExpectQuery(`INSERT INTO document_sequence`).
+					WithArgs(companyID, models.DocumentTypeInvoice, 0).
+					WillReturnRows(sqlmock.NewRows([]string{"last_number"}).AddRow(1203))
+			},
+			want: "INV-1203",
+		},
+		{
+			name: "project without a company",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT company_id FROM project`).
+					WithArgs(projectID).
+					WillReturnRows(sqlmock.NewRows([]string{"company_id"}).AddRow(nil))
+				mock.ExpectQuery(`INSERT INTO default_document_sequence`).
+					WithArgs(models.DocumentTypeInvoice, 2026).
+					WillReturnRows(sqlmock.NewRows([]string{"last_number"}).AddRow(3))
+			},
+			want: "INV-2603-003",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectBegin()
+			tt.expect(mock)
+			mock.ExpectRollback()
+
+			tx, err := sqlx.NewDb(db, "sqlmock").Beginx()
+			require.NoError(t, err)
+			number, err := postgres.NextDocumentNumber(context.Background(), tx, projectID, models.DocumentTypeInvoice, date)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, number)
+
+			require.NoError(t, tx.Rollback())
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Run("unknown project", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT company_id FROM project`).
+			WithArgs(projectID).
+			WillReturnError(sql.ErrNoRows)
+
+		tx, err := sqlx.NewDb(db, "sqlmock").Beginx()
+		require.NoError(t, err)
+		_, err = postgres.NextDocumentNumber(context.Background(), tx, projectID, models.DocumentTypeInvoice, date)
+		assert.EqualError(t, err, "project not found")
+	})
+}
@@ -0,0 +1,6 @@
+package postgres
+
+// Exported aliases of unexported helpers for the postgres_test package.
+var (
+	NextDocumentNumber = nextDocumentNumber
+)
@@ -7,6 +7,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -88,7 +89,43 @@ func (r *invoiceRepository) CreateForAllPeriods(ctx context.Context, projectID u
 		return fmt.Errorf("failed to validate contract: %w", err)
 	}
 
-	// Get all periods for this contract that don't have invoices yet
+	periods, err := uninvoicedPeriods(ctx, r.db, contractID)
+	if err != nil {
+		return err
+	}
+
+	if len(periods) == 0 {
+		return errors.New("no available periods found for invoicing in this contract")
+	}
+
+	// Begin a transaction for creating multiple invoices
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	term := sql.NullString{String: paymentTerm, Valid: paymentTerm != ""}
+	if err := insertPeriodInvoices(ctx, tx, projectID, periods, term); err != nil {
+		return err
+	}
+
+	// Commit the transaction
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+type uninvoicedPeriod struct {
+	PeriodID     uuid.UUID `db:"period_id"`
+	PeriodNumber int       `db:"period_number"`
+	PayWithin    int       `db:"pay_within"`
+}
+
+// uninvoicedPeriods returns the contract's periods that don't have invoices yet.
+func uninvoicedPeriods(ctx context.Context, q sqlx.QueryerContext, contractID uuid.UUID) ([]uninvoicedPeriod, error) {
 	periodsQuery := `
 		SELECT p.period_id, p.period_number, c.pay_within
 		FROM period p
@@ -100,34 +137,25 @@ func (r *invoiceRepository) CreateForAllPeriods(ctx context.Context, projectID u
 		ORDER BY p.period_number
 	`
 
-	type PeriodInfo struct {
-		PeriodID     uuid.UUID `db:"period_id"`
-		PeriodNumber int       `db:"period_number"`
-		PayWithin    int       `db:"pay_within"`
+	var periods []uninvoicedPeriod
+	if err := sqlx.SelectContext(ctx, q, &periods, periodsQuery, contractID); err != nil {
+		return nil, fmt.Errorf("failed to get contract periods: %w", err)
 	}
 
-	var periods []PeriodInfo
-	err = r.db.SelectContext(ctx, &periods, periodsQuery, contractID)
-	if err != nil {
-		return fmt.Errorf("failed to get contract periods: %w", err)
-	}
-
-	if len(periods) == 0 {
-		return errors.New("no available periods found for invoicing in this contract")
-	}
-
-	// Begin a transaction for creating multiple invoices
-	tx, err := r.db.BeginTxx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
-	}
+	return periods, nil
+}
 
-	// Insert invoice for each period
+// insertPeriodInvoices creates a numbered draft invoice for each period
+// within the caller's transaction. Invoices created without a payment term
+// leave it NULL until the invoice is edited.
+func insertPeriodInvoices(ctx context.Context, tx *sqlx.Tx, projectID uuid.UUID, periods []uninvoicedPeriod, paymentTerm sql.NullString) error {
 	insertQuery := `
         INSERT INTO invoice (
             invoice_id,
+            invoice_number,
             project_id,
             period_id,
+            invoice_type,
             invoice_date,
             payment_due_date,
             payment_term,
@@ -135,7 +163,7 @@ func (r *invoiceRepository) CreateForAllPeriods(ctx context.Context, projectID u
             created_at,
             updated_at
         ) VALUES (
-            $1, $2, $3, CURRENT_DATE, 
+            $1, $6, $2, $3, 'period', CURRENT_DATE, 
             CURRENT_DATE + INTERVAL '1 day' * $4, 
             $5, 'draft', 
             CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
@@ -143,20 +171,18 @@ func (r *invoiceRepository) CreateForAllPeriods(ctx context.Context, projectID u
 
 	for _, period := range periods {
 		invoiceID := uuid.New()
-		_, err = tx.ExecContext(ctx, insertQuery,
-			invoiceID, projectID, period.PeriodID, period.PayWithin, paymentTerm)
+		invoiceNumber, err := nextDocumentNumber(ctx, tx, projectID, models.DocumentTypeInvoice, time.Now())
+		if err != nil {
+			return err
+		}
 
+		_, err = tx.ExecContext(ctx, insertQuery,
+			invoiceID, projectID, period.PeriodID, period.PayWithin, paymentTerm, invoiceNumber)
 		if err != nil {
-			tx.Rollback()
 			return fmt.Errorf("failed to create invoice for period %d: %w", period.PeriodNumber, err)
 		}
 	}
 
-	// Commit the transaction
-	if err = tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
-	}
-
 	return nil
 }
 
@@ -198,12 +224,103 @@ func (r *invoiceRepository) GetByProjectID(ctx context.Context, projectID uuid.U
 		}
 	}
 
+	// Load the withholding tax certificates of all the project's invoices at once
+	var certificates []models.WithholdingTaxCertificate
+	certificateQuery := `
+        SELECT c.*
+        FROM withholding_tax_certificate c
+        JOIN invoice i ON i.invoice_id = c.invoice_id
+        WHERE i.project_id = $1`
+	if err := r.db.SelectContext(ctx, &certificates, certificateQuery, projectID); err != nil {
+		return nil, fmt.Errorf("failed to get withholding tax certificates: %w", err)
+	}
+
+	certificatesByInvoice := make(map[uuid.UUID]*models.WithholdingTaxCertificate, len(certificates))
+	for i := range certificates {
+		certificatesByInvoice[certificates[i].InvoiceID] = &certificates[i]
+	}
+	for i := range invoices {
+		invoices[i].WithholdingCertificate = certificatesByInvoice[invoices[i].InvoiceID]
+	}
+
 	return invoices, nil
 }
 
-func (r *invoiceRepository) UpdateStatus(ctx context.Context, invoiceID uuid.UUID, status string) error {
-	query := `UPDATE invoice SET status = $1, updated_at = CURRENT_TIMESTAMP WHERE invoice_id = $2`
-	result, err := r.db.ExecContext(ctx, query, status, invoiceID)
+func (r *invoiceRepository) ListReceivable(ctx context.Context, asOf time.Time) ([]models.ReceivableInvoice, error) {
+	invoices := []models.ReceivableInvoice{}
+	// Payments and notes count until they are reversed or voided, and void
+	// invoices count until the day they were voided.
+	query := `
+        SELECT
+            i.*,
+            p.name AS project_name,
+            c.client_id,
+            c.name AS client_name,
+            pe.period_number,
+            pe.amount_period,
+            (
+                SELECT COALESCE(SUM(pm.amount), 0) FROM payment pm
+                WHERE pm.invoice_id = i.invoice_id
+                    AND pm.payment_date <= $1
+                    AND (pm.reversed_at IS NULL OR pm.reversed_at >= CAST($1 AS date) + 1)
+            ) AS paid_as_of,
+            (
+                SELECT COALESCE(SUM(CASE n.note_type WHEN 'credit' THEN -n.total_amount ELSE n.total_amount END), 0)
+                FROM adjustment_note n
+                WHERE n.invoice_id = i.invoice_id
+                    AND n.note_date <= $1
+                    AND (n.voided_at IS NULL OR n.voided_at >= CAST($1 AS date) + 1)
+            ) AS adjustment_as_of
+        FROM invoice i
+        JOIN project p ON p.project_id = i.project_id
+        LEFT JOIN client c ON c.client_id = p.client_id
+        LEFT JOIN period pe ON pe.period_id = i.period_id
+        WHERE i.invoice_date <= $1
+            AND (
+                i.status IN ('issued', 'partially_paid', 'paid', 'approved')
+                OR (i.status = 'void' AND EXISTS (
+                    SELECT 1 FROM invoice_status_history h
+                    WHERE h.invoice_id = i.invoice_id
+                        AND h.to_status = 'void'
+                        AND h.changed_at >= CAST($1 AS date) + 1
+                ))
+            )
+        ORDER BY i.payment_due_date NULLS LAST, i.invoice_number`
+	if err := r.db.SelectContext(ctx, &invoices, query, asOf); err != nil {
+		return nil, fmt.Errorf("failed to get receivable invoices: %w", err)
+	}
+
+	for i := range invoices {
+		invoice := &invoices[i]
+		invoice.Period.PeriodID = invoice.PeriodID
+		invoice.Period.PeriodNumber = int(invoice.PeriodNumber.Int32)
+		invoice.Period.AmountPeriod = invoice.AmountPeriod.Float64
+
+		invoice.PaidAmount = invoice.PaidAsOf
+		invoice.AdjustmentAmount = invoice.AdjustmentAsOf
+		status := models.InvoiceStatusIssued
+		if invoice.PaidAsOf > 0 {
+			status = models.InvoiceStatusPartiallyPaid
+		}
+		invoice.Status = sql.NullString{String: string(status), Valid: true}
+	}
+
+	return invoices, nil
+}
+
+func (r *invoiceRepository) ChangeStatus(ctx context.Context, history *models.InvoiceStatusHistory) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Invoices approved before the issued status existed count as issued
+	query := `
+        UPDATE invoice SET status = $1, updated_at = CURRENT_TIMESTAMP
+        WHERE invoice_id = $2
+            AND CASE COALESCE(status, 'draft') WHEN 'approved' THEN 'issued' ELSE COALESCE(status, 'draft') END = $3`
+	result, err := tx.ExecContext(ctx, query, history.ToStatus, history.InvoiceID, history.FromStatus)
 	if err != nil {
 		return fmt.Errorf("failed to update invoice status: %w", err)
 	}
@@ -212,9 +329,47 @@ func (r *invoiceRepository) UpdateStatus(ctx context.Context, invoiceID uuid.UUI
 	if err != nil {
 		return fmt.Errorf("failed to get affected rows: %w", err)
 	}
-
 	if rows == 0 {
-		return errors.New("invoice not found")
+		return errors.New("invoice status was changed by another request")
+	}
+
+	if err := insertInvoiceStatusHistory(ctx, tx, history); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *invoiceRepository) GetStatusHistory(ctx context.Context, invoiceID uuid.UUID) ([]models.InvoiceStatusHistory, error) {
+	history := []models.InvoiceStatusHistory{}
+	query := `SELECT * FROM invoice_status_history WHERE invoice_id = $1 ORDER BY changed_at`
+	if err := r.db.SelectContext(ctx, &history, query, invoiceID); err != nil {
+		return nil, fmt.Errorf("failed to get invoice status history: %w", err)
+	}
+
+	return history, nil
+}
+
+func insertInvoiceStatusHistory(ctx context.Context, tx *sqlx.Tx, history *models.InvoiceStatusHistory) error {
+	if history.HistoryID == uuid.Nil {
+		history.HistoryID = uuid.New()
+	}
+	history.ChangedAt = time.Now()
+
+	query := `
+        INSERT INTO invoice_status_history (
+            history_id, invoice_id, from_status, to_status,
+            reason, changed_by, changed_at
+        ) VALUES (
+            :history_id, :invoice_id, :from_status, :to_status,
+            :reason, :changed_by, :changed_at
+        )`
+	if _, err := tx.NamedExecContext(ctx, query, history); err != nil {
+		return fmt.Errorf("failed to record invoice status history: %w", err)
 	}
 
 	return nil
@@ -230,9 +385,16 @@ func (r *invoiceRepository) Update(ctx context.Context, invoiceID uuid.UUID, upd
 	values := []interface{}{}
 	paramCount := 1
 
-	for field, value := range updates {
+	// Sort the columns so the generated statement is the same on every call
+	columns := make([]string, 0, len(updates))
+	for field := range updates {
+		columns = append(columns, field)
+	}
+	sort.Strings(columns)
+
+	for _, field := range columns {
 		fields = append(fields, fmt.Sprintf("%s = $%d", field, paramCount))
-		values = append(values, value)
+		values = append(values, updates[field])
 		paramCount++
 	}
 
@@ -262,3 +424,112 @@ func (r *invoiceRepository) Update(ctx context.Context, invoiceID uuid.UUID, upd
 
 	return nil
 }
+
+func (r *invoiceRepository) GetTaxSettings(ctx context.Context, projectID uuid.UUID) (*models.InvoiceTaxSettings, error) {
+	query := `
+        SELECT 
+            COALESCE(q.tax_percentage, 0) as tax_percentage,
+            q.withholding_tax_percentage as quotation_withholding_tax,
+            COALESCE(c.withholding_tax_percentage, 0) as client_withholding_tax
+        FROM project p
+        LEFT JOIN quotation q ON q.project_id = p.project_id
+        LEFT JOIN client c ON c.client_id = p.client_id
+        WHERE p.project_id = $1`
+
+	var settings models.InvoiceTaxSettings
+	err := r.db.GetContext(ctx, &settings, query, projectID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("project not found")
+		}
+		return nil, fmt.Errorf("failed to get tax settings: %w", err)
+	}
+
+	return &settings, nil
+}
+
+func (r *invoiceRepository) GetWithholdingCertificate(ctx context.Context, invoiceID uuid.UUID) (*models.WithholdingTaxCertificate, error) {
+	var certificate models.WithholdingTaxCertificate
+	query := `SELECT * FROM withholding_tax_certificate WHERE invoice_id = $1`
+	err := r.db.GetContext(ctx, &certificate, query, invoiceID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get withholding tax certificate: %w", err)
+	}
+
+	return &certificate, nil
+}
+
+func (r *invoiceRepository) SaveWithholdingCertificate(ctx context.Context, certificate *models.WithholdingTaxCertificate) error {
+	if certificate.CertificateID == uuid.Nil {
+		certificate.CertificateID = uuid.New()
+	}
+
+	query := `
+        INSERT INTO withholding_tax_certificate (
+            certificate_id, invoice_id, certificate_number, certificate_date,
+            received_date, amount, remarks, created_at
+        ) VALUES (
+            :certificate_id, :invoice_id, :certificate_number, :certificate_date,
+            :received_date, :amount, :remarks, CURRENT_TIMESTAMP
+        )
+        ON CONFLICT (invoice_id) DO UPDATE SET
+            certificate_number = EXCLUDED.certificate_number,
+            certificate_date = EXCLUDED.certificate_date,
+            received_date = EXCLUDED.received_date,
+            amount = EXCLUDED.amount,
+            remarks = EXCLUDED.remarks,
+            updated_at = CURRENT_TIMESTAMP`
+
+	_, err := r.db.NamedExecContext(ctx, query, certificate)
+	if err != nil {
+		if strings.Contains(err.Error(), "unique constraint") {
+			return errors.New("certificate number already recorded for another invoice")
+		}
+		return fmt.Errorf("failed to save withholding tax certificate: %w", err)
+	}
+
+	return nil
+}
+
+func (r *invoiceRepository) CreateRetentionRelease(ctx context.Context, invoice *models.Invoice) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	invoiceNumber, err := nextDocumentNumber(ctx, tx, invoice.ProjectID, models.DocumentTypeInvoice, time.Now())
+	if err != nil {
+		return err
+	}
+
+	invoice.InvoiceID = uuid.New()
+	invoice.InvoiceNumber = sql.NullString{String: invoiceNumber, Valid: true}
+	invoice.InvoiceType = sql.NullString{String: models.InvoiceTypeRetentionRelease, Valid: true}
+	invoice.Status = sql.NullString{String: "draft", Valid: true}
+	invoice.CreatedAt = time.Now()
+
+	query := `
+        INSERT INTO invoice (
+            invoice_id, invoice_number, project_id, invoice_type, amount,
+            invoice_date, payment_due_date, payment_term, remarks, status,
+            created_at, updated_at
+        ) VALUES (
+            :invoice_id, :invoice_number, :project_id, :invoice_type, :amount,
+            :invoice_date, :payment_due_date, :payment_term, :remarks, :status,
+            :created_at, :created_at
+        )`
+
+	if _, err := tx.NamedExecContext(ctx, query, invoice); err != nil {
+		return fmt.Errorf("failed to create retention release invoice: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
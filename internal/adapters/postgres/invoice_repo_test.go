@@ -2,6 +2,7 @@ package postgres_test
 
 import (
 	"boonkosang/internal/adapters/postgres"
+	"boonkosang/internal/domain/models"
 	"context"
 	"database/sql"
 	"testing"
@@ -113,9 +114,30 @@ func TestInvoiceRepository(t *testing.T) {
 			// Expect transaction begin
 			mock.ExpectBegin()
 
-			// Expect two invoice inserts
-			mock.ExpectExec(`INSERT INTO invoice`).WillReturnResult(sqlmock.NewResult(1, 1))
-			mock.ExpectExec(`INSERT INTO invoice`).WillReturnResult(sqlmock.NewResult(1, 1))
+			// Expect a number allocation and insert for each invoice
+			companyID := uuid.New()
+			mock.ExpectQuery(`SELECT company_id FROM project`).
+				WithArgs(projectID).
+				WillReturnRows(sqlmock.NewRows([]string{"company_id"}).AddRow(companyID))
+			mock.ExpectQuery(`SELECT pattern FROM document_number_format`).
+				WithArgs(companyID, models.DocumentTypeInvoice).
+				WillReturnError(sql.ErrNoRows)
+			mock.ExpectQuery(`INSERT INTO document_sequence`).
+				WillReturnRows(sqlmock.NewRows([]string{"last_number"}).AddRow(1))
+			mock.ExpectExec(`INSERT INTO invoice`).
+				WithArgs(sqlmock.AnyArg(), projectID, sqlmock.AnyArg(), 30, "NET30", sqlmock.AnyArg()).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			mock.ExpectQuery(`SELECT company_id FROM project`).
+				WithArgs(projectID).
+				WillReturnRows(sqlmock.NewRows([]string{"company_id"}).AddRow(companyID))
+			mock.ExpectQuery(`SELECT pattern FROM document_number_format`).
+				WithArgs(companyID, models.DocumentTypeInvoice).
+				WillReturnError(sql.ErrNoRows)
+			mock.ExpectQuery(`INSERT INTO document_sequence`).
+				WillReturnRows(sqlmock.NewRows([]string{"last_number"}).AddRow(2))
+			mock.ExpectExec(`INSERT INTO invoice`).
+				WithArgs(sqlmock.AnyArg(), projectID, sqlmock.AnyArg(), 30, "NET30", sqlmock.AnyArg()).
+				WillReturnResult(sqlmock.NewResult(1, 1))
 
 			// Expect transaction commit
 			mock.ExpectCommit()
@@ -178,25 +200,44 @@ func TestInvoiceRepository(t *testing.T) {
 		})
 	})
 
-	t.Run("UpdateStatus", func(t *testing.T) {
+	t.Run("ChangeStatus", func(t *testing.T) {
 		invoiceID := uuid.New()
 
-		t.Run("Success - Update status", func(t *testing.T) {
+		t.Run("Success - Change status and record history", func(t *testing.T) {
+			history := &models.InvoiceStatusHistory{
+				InvoiceID:  invoiceID,
+				FromStatus: models.InvoiceStatusDraft,
+				ToStatus:   models.InvoiceStatusIssued,
+			}
+
+			mock.ExpectBegin()
 			mock.ExpectExec(`UPDATE invoice`).
-				WithArgs("approved", invoiceID).
+				WithArgs(models.InvoiceStatusIssued, invoiceID, models.InvoiceStatusDraft).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			mock.ExpectExec(`INSERT INTO invoice_status_history`).
 				WillReturnResult(sqlmock.NewResult(1, 1))
+			mock.ExpectCommit()
 
-			err := repo.UpdateStatus(context.Background(), invoiceID, "approved")
+			err := repo.ChangeStatus(context.Background(), history)
 			assert.NoError(t, err)
+			assert.NotEqual(t, uuid.Nil, history.HistoryID)
 		})
 
-		t.Run("Failure - Invoice not found", func(t *testing.T) {
+		t.Run("Failure - Status changed by another request", func(t *testing.T) {
+			history := &models.InvoiceStatusHistory{
+				InvoiceID:  invoiceID,
+				FromStatus: models.InvoiceStatusDraft,
+				ToStatus:   models.InvoiceStatusIssued,
+			}
+
+			mock.ExpectBegin()
 			mock.ExpectExec(`UPDATE invoice`).
-				WithArgs("approved", invoiceID).
+				WithArgs(models.InvoiceStatusIssued, invoiceID, models.InvoiceStatusDraft).
 				WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectRollback()
 
-			err := repo.UpdateStatus(context.Background(), invoiceID, "approved")
-			assert.EqualError(t, err, "invoice not found")
+			err := repo.ChangeStatus(context.Background(), history)
+			assert.EqualError(t, err, "invoice status was changed by another request")
 		})
 	})
 
@@ -221,11 +262,61 @@ func TestInvoiceRepository(t *testing.T) {
 			assert.NoError(t, err)
 		})
 
+		t.Run("Success - Columns in a stable order", func(t *testing.T) {
+			updates := map[string]interface{}{
+				"retention":    500.0,
+				"remarks":      "Revised",
+				"payment_term": "NET30",
+				"invoice_date": time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+			}
+
+			// Map iteration order is random; the statement must not be
+			for i := 0; i < 10; i++ {
+				mock.ExpectExec(`UPDATE invoice SET invoice_date = \$1, payment_term = \$2, remarks = \$3, retention = \$4, updated_at = \$5 WHERE invoice_id = \$6`).
+					WithArgs(updates["invoice_date"], "NET30", "Revised", 500.0, sqlmock.AnyArg(), invoiceID).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
+				assert.NoError(t, repo.Update(context.Background(), invoiceID, updates))
+			}
+		})
+
 		t.Run("Failure - No fields to update", func(t *testing.T) {
 			err := repo.Update(context.Background(), invoiceID, map[string]interface{}{})
 			assert.EqualError(t, err, "no fields to update")
 		})
 	})
+
+	t.Run("ListReceivable", func(t *testing.T) {
+		asOf := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+		columns := []string{
+			"invoice_id", "project_id", "status", "paid_amount", "adjustment_amount",
+			"project_name", "client_id", "client_name", "period_number", "amount_period",
+			"paid_as_of", "adjustment_as_of",
+		}
+		paidLater := uuid.New()
+		voidedLater := uuid.New()
+
+		mock.ExpectQuery(`WHERE i.invoice_date <= \$1`).
+			WithArgs(asOf).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(paidLater, uuid.New(), "paid", 1000.0, -100.0, "House", nil, nil, 1, 1000.0, 400.0, 0.0).
+				AddRow(voidedLater, uuid.New(), "void", 0.0, 0.0, "Shop", nil, nil, 2, 500.0, 0.0, 0.0))
+
+		invoices, err := repo.ListReceivable(context.Background(), asOf)
+		assert.NoError(t, err)
+		if assert.Len(t, invoices, 2) {
+			// Balances and status are the ones the invoice had on asOf
+			assert.Equal(t, paidLater, invoices[0].InvoiceID)
+			assert.Equal(t, models.InvoiceStatusPartiallyPaid, invoices[0].CurrentStatus())
+			assert.Equal(t, 400.0, invoices[0].PaidAmount)
+			assert.Equal(t, 0.0, invoices[0].AdjustmentAmount)
+			assert.Equal(t, 1000.0, invoices[0].Period.AmountPeriod)
+
+			assert.Equal(t, voidedLater, invoices[1].InvoiceID)
+			assert.Equal(t, models.InvoiceStatusIssued, invoices[1].CurrentStatus())
+		}
+	})
+
 	// Make sure all expectations were met
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -14,6 +14,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type jobRepository struct {
@@ -88,7 +89,10 @@ func (r *jobRepository) GetJobMaterialByID(ctx context.Context, id uuid.UUID) (r
             m.material_id,
             m.name,
             m.unit,
-            jm.quantity
+            jm.quantity,
+            COALESCE(jm.waste_percent, m.waste_percent) AS waste_percent,
+            jm.waste_percent IS NOT NULL AS waste_overridden,
+            jm.quantity * (1 + COALESCE(jm.waste_percent, m.waste_percent) / 100) AS gross_quantity
         FROM Material m
         JOIN Job_material jm ON m.material_id = jm.material_id
         WHERE jm.job_id = $1`
@@ -107,10 +111,13 @@ func (r *jobRepository) GetJobMaterialByID(ctx context.Context, id uuid.UUID) (r
 	var materialsForResponse []responses.JobMaterialItem
 	for _, material := range materials {
 		materialsForResponse = append(materialsForResponse, responses.JobMaterialItem{
-			MaterialID: material.MaterialID,
-			Name:       material.Name,
-			Unit:       material.Unit,
-			Quantity:   material.Quantity,
+			MaterialID:      material.MaterialID,
+			Name:            material.Name,
+			Unit:            material.Unit,
+			Quantity:        material.Quantity,
+			WastePercent:    material.WastePercent,
+			WasteOverridden: material.WasteOverridden,
+			GrossQuantity:   material.GrossQuantity,
 		})
 	}
 
@@ -298,11 +305,13 @@ func (r *jobRepository) AddJobMaterial(ctx context.Context, jobID uuid.UUID, req
 
 	insertJobMaterialQuery := `
 		INSERT INTO Job_material (
-			job_id, material_id, quantity
+			job_id, material_id, quantity, waste_percent
 		) VALUES (
-			:job_id, :material_id, :quantity
+			:job_id, :material_id, :quantity, :waste_percent
 		) ON CONFLICT (job_id, material_id) 
-		DO UPDATE SET quantity = Job_material.quantity + EXCLUDED.quantity`
+		DO UPDATE SET 
+			quantity = Job_material.quantity + EXCLUDED.quantity,
+			waste_percent = COALESCE(EXCLUDED.waste_percent, Job_material.waste_percent)`
 
 	getProjectsQuery := `
 		SELECT DISTINCT b.boq_id, b.status
@@ -322,12 +331,35 @@ func (r *jobRepository) AddJobMaterial(ctx context.Context, jobID uuid.UUID, req
 		return fmt.Errorf("failed to get associated projects: %w", err)
 	}
 
+	materialIDs := make([]string, len(req.Materials))
+	for i, material := range req.Materials {
+		materialIDs[i] = material.MaterialID
+	}
+
+	var deprecated []string
+	err = tx.SelectContext(ctx, &deprecated, `
+		SELECT name FROM Material
+		WHERE material_id = ANY($1) AND is_deprecated
+		ORDER BY name`, pq.Array(materialIDs))
+	if err != nil {
+		return fmt.Errorf("failed to check deprecated materials: %w", err)
+	}
+	if len(deprecated) > 0 {
+		return fmt.Errorf("deprecated materials cannot be added to a job: %s", strings.Join(deprecated, ", "))
+	}
+
 	// Insert job materials
 	for _, material := range req.Materials {
+		wastePercent := sql.NullFloat64{}
+		if material.WastePercent != nil {
+			wastePercent = sql.NullFloat64{Float64: *material.WastePercent, Valid: true}
+		}
+
 		params := map[string]interface{}{
-			"job_id":      jobID,
-			"material_id": material.MaterialID,
-			"quantity":    material.Quantity,
+			"job_id":        jobID,
+			"material_id":   material.MaterialID,
+			"quantity":      material.Quantity,
+			"waste_percent": wastePercent,
 		}
 
 		_, err := tx.NamedExecContext(ctx, insertJobMaterialQuery, params)
@@ -343,10 +375,12 @@ func (r *jobRepository) AddJobMaterial(ctx context.Context, jobID uuid.UUID, req
 			if boq.Status == "draft" {
 				insertPriceLogQuery := `
 					INSERT INTO Material_price_log (
-						material_id, boq_id, supplier_id, actual_price, estimated_price, job_id, quantity, updated_at
-					) VALUES (
-						$1, $2, NULL, NULL, NULL, $3, $4, CURRENT_TIMESTAMP
-					)`
+						material_id, boq_id, supplier_id, actual_price, estimated_price, job_id, quantity, waste_percent, updated_at
+					) 
+					SELECT $1, $2, NULL, NULL, NULL, $3, $4, COALESCE(jm.waste_percent, m.waste_percent), CURRENT_TIMESTAMP
+					FROM Job_material jm
+					JOIN Material m ON m.material_id = jm.material_id
+					WHERE jm.job_id = $3 AND jm.material_id = $1`
 
 				_, err = tx.ExecContext(ctx, insertPriceLogQuery, material.MaterialID, boq.BOQID, jobID, material.Quantity)
 				if err != nil {
@@ -462,6 +496,50 @@ func (r *jobRepository) UpdateJobMaterialQuantity(ctx context.Context, jobID uui
 	return nil
 }
 
+// UpdateJobMaterialWaste sets or, when wastePercent is NULL, clears the
+// job's own waste allowance for a material. Draft BOQs follow the change.
+func (r *jobRepository) UpdateJobMaterialWaste(ctx context.Context, jobID uuid.UUID, materialID string, wastePercent sql.NullFloat64) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE job_material SET waste_percent = $3
+		WHERE job_id = $1 AND material_id = $2`, jobID, materialID, wastePercent)
+	if err != nil {
+		return fmt.Errorf("failed to update material waste: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return errors.New("job material not found")
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE material_price_log mpl 
+		SET waste_percent = COALESCE($3, m.waste_percent)
+		FROM material m, boq b
+		WHERE m.material_id = mpl.material_id
+		AND b.boq_id = mpl.boq_id
+		AND mpl.job_id = $1 
+		AND mpl.material_id = $2
+		AND b.status = 'draft'`, jobID, materialID, wastePercent)
+	if err != nil {
+		return fmt.Errorf("failed to update material price log: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
 type UpdateJobMaterialQuantityRequest struct {
 	MaterialID uuid.UUID `json:"material_id" validate:"required"`
 	Quantity   int       `json:"quantity" validate:"required,gt=0"`
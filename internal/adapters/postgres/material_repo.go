@@ -12,6 +12,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type materialRepository struct {
@@ -26,21 +27,33 @@ func NewMaterialRepository(db *sqlx.DB) repositories.MaterialRepository {
 
 func (r *materialRepository) Create(ctx context.Context, req requests.CreateMaterialRequest) (*models.Material, error) {
 	material := &models.Material{
-		MaterialID: uuid.New().String(),
-		Name:       req.Name,
-		Unit:       req.Unit,
+		MaterialID:    uuid.New().String(),
+		Name:          req.Name,
+		Unit:          req.Unit,
+		Category:      models.MaterialCategory(req.Category),
+		Brand:         sql.NullString{String: req.Brand, Valid: req.Brand != ""},
+		Specification: sql.NullString{String: req.Specification, Valid: req.Specification != ""},
+		SKU:           sql.NullString{String: req.SKU, Valid: req.SKU != ""},
+		Tags:          pq.StringArray(req.Tags),
+		WastePercent:  req.WastePercent,
+	}
+	if material.Tags == nil {
+		material.Tags = pq.StringArray{}
 	}
 
 	query := `
         INSERT INTO Material (
-            material_id, name, unit
+            material_id, name, unit, category, brand, specification, sku, tags, waste_percent
         ) VALUES (
-            :material_id, :name, :unit
+            :material_id, :name, :unit, :category, :brand, :specification, :sku, :tags, :waste_percent
         ) RETURNING *`
 
 	rows, err := r.db.NamedQueryContext(ctx, query, material)
 	if err != nil {
 		if strings.Contains(err.Error(), "unique constraint") {
+			if strings.Contains(err.Error(), "sku") {
+				return nil, errors.New("material SKU already exists")
+			}
 			return nil, errors.New("material ID already exists")
 		}
 		return nil, fmt.Errorf("failed to create material: %w", err)
@@ -58,20 +71,59 @@ func (r *materialRepository) Create(ctx context.Context, req requests.CreateMate
 }
 
 func (r *materialRepository) Update(ctx context.Context, materialID string, req requests.UpdateMaterialRequest) error {
+	// Fields left out of the request (NULL parameters) keep their values; an
+	// empty brand, specification or SKU clears it.
 	query := `
         UPDATE Material SET 
             name = :name,
-            unit = :unit
+            unit = :unit,
+            category = COALESCE(:category, category),
+            brand = CASE WHEN CAST(:brand_set AS boolean) THEN NULLIF(:brand, '') ELSE brand END,
+            specification = CASE WHEN CAST(:specification_set AS boolean) THEN NULLIF(:specification, '') ELSE specification END,
+            sku = CASE WHEN CAST(:sku_set AS boolean) THEN NULLIF(:sku, '') ELSE sku END,
+            tags = COALESCE(:tags, tags),
+            waste_percent = COALESCE(:waste_percent, waste_percent)
         WHERE material_id = :material_id`
 
+	var wastePercent sql.NullFloat64
+	if req.WastePercent != nil {
+		wastePercent = sql.NullFloat64{Float64: *req.WastePercent, Valid: true}
+	}
+
+	var tags pq.StringArray
+	if req.Tags != nil {
+		tags = pq.StringArray(*req.Tags)
+		if tags == nil {
+			tags = pq.StringArray{}
+		}
+	}
+
 	params := map[string]interface{}{
-		"material_id": materialID,
-		"name":        req.Name,
-		"unit":        req.Unit,
+		"material_id":       materialID,
+		"name":              req.Name,
+		"unit":              req.Unit,
+		"category":          optionalString(req.Category),
+		"brand_set":         req.Brand != nil,
+		"brand":             optionalString(req.Brand).String,
+		"specification_set": req.Specification != nil,
+		"specification":     optionalString(req.Specification).String,
+		"sku_set":           req.SKU != nil,
+		"sku":               optionalString(req.SKU).String,
+		"tags":              tags,
+		"waste_percent":     wastePercent,
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	result, err := r.db.NamedExecContext(ctx, query, params)
+	result, err := tx.NamedExecContext(ctx, query, params)
 	if err != nil {
+		if strings.Contains(err.Error(), "unique constraint") {
+			return errors.New("material SKU already exists")
+		}
 		return fmt.Errorf("failed to update material: %w", err)
 	}
 
@@ -84,6 +136,84 @@ func (r *materialRepository) Update(ctx context.Context, materialID string, req
 		return errors.New("material not found")
 	}
 
+	// Draft BOQs follow a new default waste, except where a job overrides
+	// it.
+	if req.WastePercent != nil {
+		_, err = tx.ExecContext(ctx, `
+            UPDATE material_price_log mpl
+            SET waste_percent = $2
+            FROM job_material jm, boq b
+            WHERE jm.job_id = mpl.job_id
+            AND jm.material_id = mpl.material_id
+            AND b.boq_id = mpl.boq_id
+            AND mpl.material_id = $1
+            AND jm.waste_percent IS NULL
+            AND b.status = 'draft'`, materialID, *req.WastePercent)
+		if err != nil {
+			return fmt.Errorf("failed to update material price log: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// optionalString is NULL for a request field that was left out.
+func optionalString(value *string) sql.NullString {
+	if value == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *value, Valid: true}
+}
+
+func (r *materialRepository) Deprecate(ctx context.Context, materialID string, replacedBy sql.NullString) error {
+	query := `
+        UPDATE Material SET
+            is_deprecated = true,
+            deprecated_at = CURRENT_TIMESTAMP,
+            replaced_by = $2
+        WHERE material_id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, materialID, replacedBy)
+	if err != nil {
+		return fmt.Errorf("failed to deprecate material: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return errors.New("material not found")
+	}
+
+	return nil
+}
+
+func (r *materialRepository) Restore(ctx context.Context, materialID string) error {
+	query := `
+        UPDATE Material SET
+            is_deprecated = false,
+            deprecated_at = NULL,
+            replaced_by = NULL
+        WHERE material_id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, materialID)
+	if err != nil {
+		return fmt.Errorf("failed to restore material: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return errors.New("material not found")
+	}
+
 	return nil
 }
 
@@ -190,20 +320,74 @@ func (r *materialRepository) GetByID(ctx context.Context, materialID string) (*m
 	return material, nil
 }
 
-func (r *materialRepository) List(ctx context.Context) ([]models.Material, error) {
-	var materials []models.Material
+func (r *materialRepository) List(ctx context.Context, filter models.MaterialFilter) ([]models.Material, int64, error) {
+	var conditions []string
 	var args []interface{}
 
-	query := `
-		SELECT * FROM Material
-	   `
+	if !filter.IncludeDeprecated {
+		conditions = append(conditions, "NOT m.is_deprecated")
+	}
+	if filter.Category != "" {
+		args = append(args, filter.Category)
+		conditions = append(conditions, fmt.Sprintf("m.category = $%d", len(args)))
+	}
+	if filter.Tag != "" {
+		args = append(args, filter.Tag)
+		conditions = append(conditions, fmt.Sprintf("$%d = ANY(m.tags)", len(args)))
+	}
 
+	// English words go through full-text search so "pipes" finds "pipe";
+	// Thai has no word boundaries for the parser, so every term must also be
+	// accepted as a plain substring.
+	rank := "0"
+	if search := strings.TrimSpace(filter.Search); search != "" {
+		args = append(args, search)
+		searchArg := len(args)
+		document := `to_tsvector('english', m.name || ' ' || COALESCE(m.brand, '') || ' ' || COALESCE(m.specification, ''))`
+		rank = fmt.Sprintf("ts_rank(%s, websearch_to_tsquery('english', $%d))", document, searchArg)
+
+		var terms []string
+		for _, term := range strings.Fields(search) {
+			args = append(args, "%"+escapeLike(term)+"%")
+			terms = append(terms, fmt.Sprintf(`(m.name || ' ' || COALESCE(m.brand, '') || ' ' || COALESCE(m.specification, '') || ' ' || COALESCE(m.sku, '') || ' ' || array_to_string(m.tags, ' ')) ILIKE $%d`, len(args)))
+		}
+
+		conditions = append(conditions, fmt.Sprintf("(%s @@ websearch_to_tsquery('english', $%d) OR (%s))",
+			document, searchArg, strings.Join(terms, " AND ")))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM Material m ` + where
+	if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to count materials: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT m.* FROM Material m
+		%s
+		ORDER BY %s DESC, m.name`, where, rank)
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit, filter.Offset)
+		query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+	}
+
+	var materials []models.Material
 	err := r.db.SelectContext(ctx, &materials, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list materials: %w", err)
+		return nil, 0, fmt.Errorf("failed to list materials: %w", err)
 	}
 
-	return materials, nil
+	return materials, total, nil
+}
+
+// escapeLike escapes the LIKE wildcards in a user-supplied search term.
+func escapeLike(term string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(term)
 }
 
 func (r *materialRepository) GetMaterialPricesByProjectID(ctx context.Context, projectID uuid.UUID) ([]models.MaterialPriceInfo, error) {
@@ -236,13 +420,16 @@ func (r *materialRepository) GetMaterialPricesByProjectID(ctx context.Context, p
         SELECT 
             m.material_id, 
             m.name, 
-            SUM(mpl.quantity) * SUM(bj.quantity) as qty_all_material_in_all_job,
+            SUM(mpl.gross_quantity * bj.quantity) as qty_all_material_in_all_job,
+            SUM(mpl.quantity * bj.quantity) as net_quantity,
             m.unit, 
             mpl.estimated_price,
             fa.avg_actual_price,
             mpl.actual_price,
 			s.supplier_id,
-            s.name as supplier_name
+            s.name as supplier_name,
+            COALESCE(ordered.ordered_quantity, 0) as ordered_quantity,
+            COALESCE(ordered.received_quantity, 0) as received_quantity
         FROM project p 
         JOIN boq b ON b.project_id = p.project_id 
         JOIN material_price_log mpl ON mpl.boq_id = b.boq_id
@@ -251,6 +438,8 @@ func (r *materialRepository) GetMaterialPricesByProjectID(ctx context.Context, p
         JOIN FinalAvg fa ON fa.material_id = m.material_id
         JOIN boq_job bj ON bj.boq_id = mpl.boq_id AND bj.job_id = mpl.job_id 
         LEFT JOIN supplier s ON s.supplier_id = mpl.supplier_id
+        LEFT JOIN (` + purchaseOrderQuantitiesQuery + `) ordered 
+            ON ordered.boq_id = b.boq_id AND ordered.material_id = m.material_id
         WHERE p.project_id = $1
         GROUP BY 
             mpl.material_id, 
@@ -260,7 +449,9 @@ func (r *materialRepository) GetMaterialPricesByProjectID(ctx context.Context, p
             mpl.actual_price, 
             fa.avg_actual_price, 
 			s.supplier_id,
-            s.name`
+            s.name,
+            ordered.ordered_quantity,
+            ordered.received_quantity`
 
 	var materials []models.MaterialPriceInfo
 	err := r.db.SelectContext(ctx, &materials, query, projectID)
@@ -272,12 +463,38 @@ func (r *materialRepository) GetMaterialPricesByProjectID(ctx context.Context, p
 }
 
 func (r *materialRepository) UpdateEstimatedPrices(ctx context.Context, boqID uuid.UUID, materialID string, estimatedPrice float64) error {
+	return updateEstimatedPrice(ctx, r.db, boqID, materialID, estimatedPrice)
+}
+
+func (r *materialRepository) UpdateEstimatedPriceList(ctx context.Context, boqID uuid.UUID, prices map[string]float64) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for materialID, estimatedPrice := range prices {
+		if err := updateEstimatedPrice(ctx, tx, boqID, materialID, estimatedPrice); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// updateEstimatedPrice sets the estimated price of a BOQ material, on its own
+// or as part of a transaction.
+func updateEstimatedPrice(ctx context.Context, e sqlx.ExecerContext, boqID uuid.UUID, materialID string, estimatedPrice float64) error {
 	query := `
         UPDATE material_price_log 
         SET estimated_price = $1
         WHERE material_id = $2 AND boq_id = $3`
 
-	result, err := r.db.ExecContext(ctx, query, estimatedPrice, materialID, boqID)
+	result, err := e.ExecContext(ctx, query, estimatedPrice, materialID, boqID)
 	if err != nil {
 		return fmt.Errorf("failed to update estimated prices: %w", err)
 	}
@@ -294,6 +511,124 @@ func (r *materialRepository) UpdateEstimatedPrices(ctx context.Context, boqID uu
 	return nil
 }
 
+// GetPriceHistory returns a price point per material and BOQ, oldest first,
+// optionally for one material and between from and to.
+func (r *materialRepository) GetPriceHistory(ctx context.Context, materialID string, from, to sql.NullTime) ([]models.MaterialPricePoint, error) {
+	query := `
+        SELECT 
+            m.material_id,
+            m.name,
+            m.unit,
+            p.project_id,
+            p.name AS project_name,
+            b.boq_id,
+            (ARRAY_AGG(mpl.supplier_id ORDER BY mpl.updated_at DESC) 
+                FILTER (WHERE mpl.supplier_id IS NOT NULL))[1] AS supplier_id,
+            (ARRAY_AGG(s.name ORDER BY mpl.updated_at DESC) 
+                FILTER (WHERE s.name IS NOT NULL))[1] AS supplier_name,
+            SUM(mpl.quantity * bj.quantity) AS quantity,
+            MAX(mpl.estimated_price) AS estimated_price,
+            AVG(mpl.actual_price) AS actual_price,
+            MAX(mpl.updated_at) AS price_date
+        FROM material_price_log mpl
+        JOIN boq b ON b.boq_id = mpl.boq_id
+        JOIN project p ON p.project_id = b.project_id
+        JOIN boq_job bj ON bj.boq_id = mpl.boq_id AND bj.job_id = mpl.job_id
+        JOIN material m ON m.material_id = mpl.material_id
+        LEFT JOIN supplier s ON s.supplier_id = mpl.supplier_id
+        WHERE ($1 = '' OR mpl.material_id = $1)
+        AND (mpl.estimated_price IS NOT NULL OR mpl.actual_price IS NOT NULL)
+        GROUP BY m.material_id, m.name, m.unit, p.project_id, p.name, b.boq_id
+        HAVING MAX(mpl.updated_at) IS NOT NULL
+        AND ($2::timestamp IS NULL OR MAX(mpl.updated_at) >= $2)
+        AND ($3::timestamp IS NULL OR MAX(mpl.updated_at) < $3::timestamp + INTERVAL '1 day')
+        ORDER BY m.name, price_date`
+
+	points := []models.MaterialPricePoint{}
+	if err := r.db.SelectContext(ctx, &points, query, materialID, from, to); err != nil {
+		return nil, fmt.Errorf("failed to get material price history: %w", err)
+	}
+
+	return points, nil
+}
+
+func (r *materialRepository) GetRecentActualPrices(ctx context.Context, boqID uuid.UUID, materialIDs []string, limit int) ([]models.MaterialPricePoint, error) {
+	query := `
+        WITH actual AS (
+            SELECT 
+                m.material_id,
+                m.name,
+                m.unit,
+                p.project_id,
+                p.name AS project_name,
+                b.boq_id,
+                (ARRAY_AGG(mpl.supplier_id ORDER BY mpl.updated_at DESC) 
+                    FILTER (WHERE mpl.supplier_id IS NOT NULL))[1] AS supplier_id,
+                (ARRAY_AGG(s.name ORDER BY mpl.updated_at DESC) 
+                    FILTER (WHERE s.name IS NOT NULL))[1] AS supplier_name,
+                SUM(mpl.quantity * bj.quantity) AS quantity,
+                MAX(mpl.estimated_price) AS estimated_price,
+                AVG(mpl.actual_price) AS actual_price,
+                MAX(mpl.updated_at) AS price_date
+            FROM material_price_log mpl
+            JOIN boq b ON b.boq_id = mpl.boq_id
+            JOIN project p ON p.project_id = b.project_id
+            JOIN boq_job bj ON bj.boq_id = mpl.boq_id AND bj.job_id = mpl.job_id
+            JOIN material m ON m.material_id = mpl.material_id
+            LEFT JOIN supplier s ON s.supplier_id = mpl.supplier_id
+            WHERE mpl.boq_id <> $1
+            AND mpl.material_id = ANY($2)
+            AND mpl.actual_price IS NOT NULL
+            AND mpl.updated_at IS NOT NULL
+            GROUP BY m.material_id, m.name, m.unit, p.project_id, p.name, b.boq_id
+        ), ranked AS (
+            SELECT 
+                actual.*,
+                ROW_NUMBER() OVER (PARTITION BY material_id ORDER BY price_date DESC) AS row_num
+            FROM actual
+        )
+        SELECT 
+            material_id, name, unit, project_id, project_name, boq_id,
+            supplier_id, supplier_name, quantity, estimated_price, actual_price, price_date
+        FROM ranked
+        WHERE row_num <= $3
+        ORDER BY material_id, price_date DESC`
+
+	points := []models.MaterialPricePoint{}
+	if err := r.db.SelectContext(ctx, &points, query, boqID, pq.Array(materialIDs), limit); err != nil {
+		return nil, fmt.Errorf("failed to get recent actual prices: %w", err)
+	}
+
+	return points, nil
+}
+
+// GetBOQMaterials returns the total quantity of each material over the jobs
+// of a BOQ, including waste, with its estimated price.
+func (r *materialRepository) GetBOQMaterials(ctx context.Context, boqID uuid.UUID) ([]models.BOQMaterialRequirement, error) {
+	query := `
+        SELECT 
+            mpl.boq_id,
+            m.material_id,
+            m.name,
+            m.unit,
+            SUM(mpl.gross_quantity * bj.quantity) AS required_quantity,
+            SUM(mpl.quantity * bj.quantity) AS net_quantity,
+            MAX(mpl.estimated_price) AS estimated_price
+        FROM material_price_log mpl
+        JOIN boq_job bj ON bj.boq_id = mpl.boq_id AND bj.job_id = mpl.job_id
+        JOIN material m ON m.material_id = mpl.material_id
+        WHERE mpl.boq_id = $1
+        GROUP BY mpl.boq_id, m.material_id, m.name, m.unit
+        ORDER BY m.name`
+
+	materials := []models.BOQMaterialRequirement{}
+	if err := r.db.SelectContext(ctx, &materials, query, boqID); err != nil {
+		return nil, fmt.Errorf("failed to get BOQ materials: %w", err)
+	}
+
+	return materials, nil
+}
+
 func (r *materialRepository) GetBOQStatus(ctx context.Context, boqID uuid.UUID) (string, error) {
 	var status string
 	query := `SELECT status FROM boq WHERE boq_id = $1`
@@ -310,6 +645,12 @@ func (r *materialRepository) GetBOQStatus(ctx context.Context, boqID uuid.UUID)
 }
 
 func (r *materialRepository) UpdateActualPrice(ctx context.Context, boqID uuid.UUID, req requests.UpdateMaterialActualPriceRequest) error {
+	return updateActualPrice(ctx, r.db, boqID, req)
+}
+
+// updateActualPrice sets the actual price and supplier of a BOQ material,
+// on its own or as part of a transaction.
+func updateActualPrice(ctx context.Context, e sqlx.ExtContext, boqID uuid.UUID, req requests.UpdateMaterialActualPriceRequest) error {
 	query := `
         UPDATE material_price_log 
         SET actual_price = :actual_price, 
@@ -325,7 +666,7 @@ func (r *materialRepository) UpdateActualPrice(ctx context.Context, boqID uuid.U
 		"supplier_id":  req.SupplierID,
 	}
 
-	result, err := r.db.NamedExecContext(ctx, query, params)
+	result, err := sqlx.NamedExecContext(ctx, e, query, params)
 	if err != nil {
 		return fmt.Errorf("failed to update actual price: %w", err)
 	}
@@ -0,0 +1,203 @@
+package postgres
+
+import (
+	"boonkosang/internal/domain/models"
+	"boonkosang/internal/repositories"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type paymentRepository struct {
+	db *sqlx.DB
+}
+
+func NewPaymentRepository(db *sqlx.DB) repositories.PaymentRepository {
+	return &paymentRepository{db: db}
+}
+
+func (r *paymentRepository) Create(ctx context.Context, payment *models.Payment, update models.InvoiceBalanceUpdate) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	invoice, err := lockInvoice(ctx, tx, payment.InvoiceID)
+	if err != nil {
+		return err
+	}
+
+	payment.PaymentID = uuid.New()
+	payment.CreatedAt = time.Now()
+
+	query := `
+        INSERT INTO payment (
+            payment_id, invoice_id, amount, payment_date, method,
+            reference, bank_name, remarks, created_at
+        ) VALUES (
+            :payment_id, :invoice_id, :amount, :payment_date, :method,
+            :reference, :bank_name, :remarks, :created_at
+        )`
+
+	if _, err := tx.NamedExecContext(ctx, query, payment); err != nil {
+		return fmt.Errorf("failed to create payment: %w", err)
+	}
+
+	if err := updateInvoiceBalance(ctx, tx, invoice, update); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *paymentRepository) GetByID(ctx context.Context, paymentID uuid.UUID) (*models.Payment, error) {
+	var payment models.Payment
+	query := `SELECT * FROM payment WHERE payment_id = $1`
+	err := r.db.GetContext(ctx, &payment, query, paymentID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("payment not found")
+		}
+		return nil, fmt.Errorf("failed to get payment: %w", err)
+	}
+
+	return &payment, nil
+}
+
+func (r *paymentRepository) ListByInvoiceID(ctx context.Context, invoiceID uuid.UUID) ([]models.Payment, error) {
+	payments := []models.Payment{}
+	query := `SELECT * FROM payment WHERE invoice_id = $1 ORDER BY payment_date, created_at`
+	if err := r.db.SelectContext(ctx, &payments, query, invoiceID); err != nil {
+		return nil, fmt.Errorf("failed to get payments: %w", err)
+	}
+
+	return payments, nil
+}
+
+func (r *paymentRepository) Reverse(ctx context.Context, payment *models.Payment, update models.InvoiceBalanceUpdate) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	invoice, err := lockInvoice(ctx, tx, payment.InvoiceID)
+	if err != nil {
+		return err
+	}
+
+	payment.ReversedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	query := `
+        UPDATE payment SET
+            reversed_at = $1,
+            reversal_reason = $2
+        WHERE payment_id = $3 AND reversed_at IS NULL`
+
+	result, err := tx.ExecContext(ctx, query, payment.ReversedAt, payment.ReversalReason, payment.PaymentID)
+	if err != nil {
+		return fmt.Errorf("failed to reverse payment: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return errors.New("payment is already reversed")
+	}
+
+	// A receipt already handed to the client keeps its number but is voided
+	voidQuery := `
+        UPDATE receipt SET
+            voided_at = $1,
+            void_reason = $2
+        WHERE payment_id = $3 AND voided_at IS NULL`
+
+	if _, err := tx.ExecContext(ctx, voidQuery, payment.ReversedAt, payment.ReversalReason, payment.PaymentID); err != nil {
+		return fmt.Errorf("failed to void receipt: %w", err)
+	}
+
+	if err := updateInvoiceBalance(ctx, tx, invoice, update); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// lockInvoice reads the invoice and locks its row until the transaction ends,
+// so payments and notes against the same invoice are applied one at a time.
+func lockInvoice(ctx context.Context, tx *sqlx.Tx, invoiceID uuid.UUID) (*models.Invoice, error) {
+	var invoice models.Invoice
+	query := `SELECT * FROM invoice WHERE invoice_id = $1 FOR UPDATE`
+	if err := tx.GetContext(ctx, &invoice, query, invoiceID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("invoice not found")
+		}
+		return nil, fmt.Errorf("failed to lock invoice: %w", err)
+	}
+
+	return &invoice, nil
+}
+
+// updateInvoiceBalance recalculates the paid and adjustment amounts of an
+// invoice locked by lockInvoice from its payments and credit/debit notes, so
+// they can never drift from those records, and stores the status update
+// derives from them along with the retention it fixed. Because the row stays
+// locked, a concurrent payment waits and then sees this one, and update can
+// reject it before it overpays.
+func updateInvoiceBalance(ctx context.Context, tx *sqlx.Tx, invoice *models.Invoice, update models.InvoiceBalanceUpdate) error {
+	balanceQuery := `
+        SELECT
+            (
+                SELECT COALESCE(SUM(amount), 0) FROM payment
+                WHERE invoice_id = $1 AND reversed_at IS NULL
+            ) AS paid_amount,
+            (
+                SELECT COALESCE(SUM(CASE note_type WHEN 'credit' THEN -total_amount ELSE total_amount END), 0)
+                FROM adjustment_note
+                WHERE invoice_id = $1 AND voided_at IS NULL
+            ) AS adjustment_amount`
+
+	if err := tx.QueryRowxContext(ctx, balanceQuery, invoice.InvoiceID).Scan(&invoice.PaidAmount, &invoice.AdjustmentAmount); err != nil {
+		return fmt.Errorf("failed to calculate invoice balance: %w", err)
+	}
+
+	statusChange, paidDate, err := update(invoice)
+	if err != nil {
+		return err
+	}
+
+	query := `
+        UPDATE invoice SET
+            paid_amount = $2,
+            adjustment_amount = $3,
+            status = $4,
+            paid_date = $5,
+            retention = $6,
+            updated_at = CURRENT_TIMESTAMP
+        WHERE invoice_id = $1`
+
+	if _, err := tx.ExecContext(ctx, query, invoice.InvoiceID, invoice.PaidAmount, invoice.AdjustmentAmount, statusChange.ToStatus, paidDate, invoice.Retention); err != nil {
+		return fmt.Errorf("failed to update invoice balance: %w", err)
+	}
+
+	if statusChange.FromStatus == statusChange.ToStatus {
+		return nil
+	}
+
+	return insertInvoiceStatusHistory(ctx, tx, statusChange)
+}
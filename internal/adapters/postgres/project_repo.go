@@ -33,15 +33,16 @@ func (r *projectRepository) Create(ctx context.Context, req requests.CreateProje
 		Status:      models.ProjectStatusPlanning,
 		ClientID:    req.ClientID,
 		CreatedAt:   time.Now(),
+		CompanyID:   req.CompanyID,
 	}
 
 	query := `
         INSERT INTO Project (
             project_id, name, description, address, status, 
-            client_id, created_at
+            client_id, created_at, company_id
         ) VALUES (
             :project_id, :name, :description, :address, :status,
-            :client_id, :created_at
+            :client_id, :created_at, :company_id
         ) RETURNING *`
 
 	rows, err := r.db.NamedQueryContext(ctx, query, project)
@@ -67,6 +68,7 @@ func (r *projectRepository) Update(ctx context.Context, id uuid.UUID, req reques
             description = :description,
             address = :address,
 			client_id = :client_id,
+            company_id = COALESCE(:company_id, company_id),
             updated_at = :updated_at
         WHERE project_id = :project_id`
 
@@ -76,6 +78,7 @@ func (r *projectRepository) Update(ctx context.Context, id uuid.UUID, req reques
 		"description": req.Description,
 		"address":     req.Address,
 		"client_id":   req.ClientID,
+		"company_id":  req.CompanyID,
 		"updated_at":  time.Now(),
 	}
 
@@ -133,13 +136,16 @@ func (r *projectRepository) GetByIDWithClient(ctx context.Context, id uuid.UUID)
 
 	query := `
         SELECT 
-            p.*,
+            p.project_id, p.name, p.description, p.address, p.status,
+            p.client_id, p.created_at, p.updated_at, p.company_id,
             c.client_id as "client.client_id",
             c.name as "client.name",
             c.email as "client.email",
             c.tel as "client.tel",
             c.address as "client.address",
-            c.tax_id as "client.tax_id"
+            c.tax_id as "client.tax_id",
+            COALESCE(c.branch, '00000') as "client.branch",
+            COALESCE(c.withholding_tax_percentage, 0) as "client.withholding_tax_percentage"
         FROM Project p
         LEFT JOIN Client c ON p.client_id = c.client_id
         WHERE p.project_id = $1`
@@ -147,9 +153,10 @@ func (r *projectRepository) GetByIDWithClient(ctx context.Context, id uuid.UUID)
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&project.ProjectID, &project.Name, &project.Description,
 		&project.Address, &project.Status, &project.ClientID,
-		&project.CreatedAt, &project.UpdatedAt,
+		&project.CreatedAt, &project.UpdatedAt, &project.CompanyID,
 		&client.ClientID, &client.Name, &client.Email,
 		&client.Tel, &client.Address, &client.TaxID,
+		&client.Branch, &client.WithholdingTaxPercentage,
 	)
 
 	if err != nil {
@@ -311,7 +318,7 @@ func (r *projectRepository) GetProjectOverview(ctx context.Context, projectID uu
             SELECT 
                 job_id,
                 boq_id,
-                SUM(estimated_price * quantity) as total_material_price
+                SUM(estimated_price * gross_quantity) as total_material_price
             FROM material_price_log 
             GROUP BY job_id, boq_id
         ), GeneralCost AS (
@@ -335,7 +342,7 @@ func (r *projectRepository) GetProjectOverview(ctx context.Context, projectID uu
             SELECT 
                 job_id,
                 boq_id,
-                SUM(actual_price * quantity) as total_actual_price
+                SUM(actual_price * gross_quantity) as total_actual_price
             FROM material_price_log 
             GROUP BY job_id, boq_id
         )
@@ -412,8 +419,8 @@ func (r *projectRepository) getJobDetails(ctx context.Context, projectID uuid.UU
             SELECT 
                 job_id,  
                 boq_id, 
-                COALESCE(SUM(estimated_price * quantity), 0) as total_material_price, 
-                COALESCE(SUM(actual_price * quantity), 0) as total_actual_price 
+                COALESCE(SUM(estimated_price * gross_quantity), 0) as total_material_price, 
+                COALESCE(SUM(actual_price * gross_quantity), 0) as total_actual_price 
             FROM material_price_log 
             GROUP BY job_id, boq_id
         )
@@ -0,0 +1,461 @@
+package postgres
+
+import (
+	"boonkosang/internal/domain/models"
+	"boonkosang/internal/repositories"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type purchaseOrderRepository struct {
+	db *sqlx.DB
+}
+
+func NewPurchaseOrderRepository(db *sqlx.DB) repositories.PurchaseOrderRepository {
+	return &purchaseOrderRepository{db: db}
+}
+
+const purchaseOrderConflict = "purchase order status was changed by another request"
+
+// purchaseOrderQuantitiesQuery totals the ordered, received and rejected
+// quantities of each BOQ material over purchase orders that are not
+// cancelled.
+const purchaseOrderQuantitiesQuery = `
+            SELECT 
+                po.boq_id,
+                l.material_id,
+                SUM(l.quantity) AS ordered_quantity,
+                SUM(l.received_quantity) AS received_quantity,
+                SUM(l.rejected_quantity) AS rejected_quantity
+            FROM purchase_order_line l
+            JOIN purchase_order po ON po.po_id = l.po_id
+            WHERE po.status <> 'cancelled'
+            GROUP BY po.boq_id, l.material_id`
+
+func (r *purchaseOrderRepository) ListBOQMaterials(ctx context.Context, projectID uuid.UUID) ([]models.BOQMaterialRequirement, error) {
+	query := `
+        SELECT 
+            b.boq_id,
+            m.material_id,
+            m.name,
+            m.unit,
+            SUM(mpl.gross_quantity * bj.quantity) AS required_quantity,
+            SUM(mpl.quantity * bj.quantity) AS net_quantity,
+            MAX(mpl.estimated_price) AS estimated_price,
+            COALESCE(ordered.ordered_quantity, 0) AS ordered_quantity,
+            COALESCE(ordered.received_quantity, 0) AS received_quantity,
+            COALESCE(ordered.rejected_quantity, 0) AS rejected_quantity
+        FROM boq b
+        JOIN material_price_log mpl ON mpl.boq_id = b.boq_id
+        JOIN boq_job bj ON bj.boq_id = mpl.boq_id AND bj.job_id = mpl.job_id
+        JOIN material m ON m.material_id = mpl.material_id
+        LEFT JOIN (` + purchaseOrderQuantitiesQuery + `) ordered 
+            ON ordered.boq_id = b.boq_id AND ordered.material_id = m.material_id
+        WHERE b.project_id = $1
+        GROUP BY 
+            b.boq_id, m.material_id, m.name, m.unit,
+            ordered.ordered_quantity, ordered.received_quantity, ordered.rejected_quantity
+        ORDER BY m.name`
+
+	materials := []models.BOQMaterialRequirement{}
+	if err := r.db.SelectContext(ctx, &materials, query, projectID); err != nil {
+		return nil, fmt.Errorf("failed to get BOQ materials: %w", err)
+	}
+
+	return materials, nil
+}
+
+func (r *purchaseOrderRepository) Create(ctx context.Context, orders []*models.PurchaseOrder) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, po := range orders {
+		if err := insertPurchaseOrder(ctx, tx, po); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *purchaseOrderRepository) GetByID(ctx context.Context, poID uuid.UUID) (*models.PurchaseOrder, error) {
+	var po models.PurchaseOrder
+	query := `
+        SELECT po.*, s.name AS supplier_name
+        FROM purchase_order po
+        JOIN supplier s ON s.supplier_id = po.supplier_id
+        WHERE po.po_id = $1`
+
+	err := r.db.GetContext(ctx, &po, query, poID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("purchase order not found")
+		}
+		return nil, fmt.Errorf("failed to get purchase order: %w", err)
+	}
+
+	lines := []models.PurchaseOrderLine{}
+	linesQuery := `
+        SELECT l.*, m.name AS material_name, m.unit
+        FROM purchase_order_line l
+        JOIN material m ON m.material_id = l.material_id
+        WHERE l.po_id = $1
+        ORDER BY m.name`
+
+	if err := r.db.SelectContext(ctx, &lines, linesQuery, poID); err != nil {
+		return nil, fmt.Errorf("failed to get purchase order lines: %w", err)
+	}
+	po.Lines = lines
+
+	return &po, nil
+}
+
+func (r *purchaseOrderRepository) ListByProjectID(ctx context.Context, projectID uuid.UUID) ([]models.PurchaseOrder, error) {
+	orders := []models.PurchaseOrder{}
+	query := `
+        SELECT po.*, s.name AS supplier_name
+        FROM purchase_order po
+        JOIN supplier s ON s.supplier_id = po.supplier_id
+        WHERE po.project_id = $1
+        ORDER BY po.order_date DESC, po.po_number DESC`
+
+	if err := r.db.SelectContext(ctx, &orders, query, projectID); err != nil {
+		return nil, fmt.Errorf("failed to list purchase orders: %w", err)
+	}
+
+	var lines []models.PurchaseOrderLine
+	linesQuery := `
+        SELECT l.*, m.name AS material_name, m.unit
+        FROM purchase_order_line l
+        JOIN purchase_order po ON po.po_id = l.po_id
+        JOIN material m ON m.material_id = l.material_id
+        WHERE po.project_id = $1
+        ORDER BY m.name`
+
+	if err := r.db.SelectContext(ctx, &lines, linesQuery, projectID); err != nil {
+		return nil, fmt.Errorf("failed to get purchase order lines: %w", err)
+	}
+
+	index := make(map[uuid.UUID]int, len(orders))
+	for i := range orders {
+		orders[i].Lines = []models.PurchaseOrderLine{}
+		index[orders[i].POID] = i
+	}
+	for _, line := range lines {
+		if i, ok := index[line.POID]; ok {
+			orders[i].Lines = append(orders[i].Lines, line)
+		}
+	}
+
+	return orders, nil
+}
+
+// Update replaces the details and lines of a draft purchase order.
+func (r *purchaseOrderRepository) Update(ctx context.Context, po *models.PurchaseOrder) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+        UPDATE purchase_order SET
+            delivery_date = $1,
+            remarks = $2,
+            updated_at = CURRENT_TIMESTAMP
+        WHERE po_id = $3 AND status = $4`
+
+	result, err := tx.ExecContext(ctx, query, po.DeliveryDate, po.Remarks, po.POID, models.PurchaseOrderStatusDraft)
+	if err != nil {
+		return fmt.Errorf("failed to update purchase order: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return errors.New(purchaseOrderConflict)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM purchase_order_line WHERE po_id = $1`, po.POID); err != nil {
+		return fmt.Errorf("failed to delete purchase order lines: %w", err)
+	}
+
+	if err := insertPurchaseOrderLines(ctx, tx, po); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *purchaseOrderRepository) ChangeStatus(ctx context.Context, po *models.PurchaseOrder, from models.PurchaseOrderStatus) error {
+	query := `
+        UPDATE purchase_order SET
+            status = $1,
+            sent_at = CASE WHEN $1 = 'sent' THEN CURRENT_TIMESTAMP ELSE sent_at END,
+            updated_at = CURRENT_TIMESTAMP
+        WHERE po_id = $2 AND status = $3`
+
+	result, err := r.db.ExecContext(ctx, query, po.Status, po.POID, from)
+	if err != nil {
+		return fmt.Errorf("failed to update purchase order status: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return errors.New(purchaseOrderConflict)
+	}
+
+	return nil
+}
+
+func (r *purchaseOrderRepository) Receive(ctx context.Context, po *models.PurchaseOrder, receipt *models.GoodsReceipt, status models.PurchaseOrderStatus) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	statusQuery := `
+        UPDATE purchase_order SET
+            status = $1,
+            updated_at = CURRENT_TIMESTAMP
+        WHERE po_id = $2 AND status = $3`
+
+	result, err := tx.ExecContext(ctx, statusQuery, status, po.POID, po.Status)
+	if err != nil {
+		return fmt.Errorf("failed to update purchase order status: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return errors.New(purchaseOrderConflict)
+	}
+
+	receipt.GRID = uuid.New()
+	receipt.POID = po.POID
+	receipt.CreatedAt = time.Now()
+
+	receiptQuery := `
+        INSERT INTO goods_receipt (
+            gr_id, po_id, received_date, received_by, notes, created_at
+        ) VALUES (
+            :gr_id, :po_id, :received_date, :received_by, :notes, :created_at
+        )`
+
+	if _, err := tx.NamedExecContext(ctx, receiptQuery, receipt); err != nil {
+		return fmt.Errorf("failed to create goods receipt: %w", err)
+	}
+
+	receiptLineQuery := `
+        INSERT INTO goods_receipt_line (
+            gr_line_id, gr_id, po_line_id, quantity_received, quantity_rejected, notes
+        ) VALUES (
+            :gr_line_id, :gr_id, :po_line_id, :quantity_received, :quantity_rejected, :notes
+        )`
+
+	orderLineQuery := `
+        UPDATE purchase_order_line SET
+            received_quantity = received_quantity + $1,
+            rejected_quantity = rejected_quantity + $2
+        WHERE po_line_id = $3 AND po_id = $4
+        AND received_quantity + $1 <= quantity`
+
+	materialIDs := make([]string, 0, len(receipt.Lines))
+	for i := range receipt.Lines {
+		line := &receipt.Lines[i]
+		line.GRLineID = uuid.New()
+		line.GRID = receipt.GRID
+
+		if _, err := tx.NamedExecContext(ctx, receiptLineQuery, line); err != nil {
+			return fmt.Errorf("failed to create goods receipt line: %w", err)
+		}
+
+		result, err := tx.ExecContext(ctx, orderLineQuery, line.AcceptedQuantity(), line.QuantityRejected, line.POLineID, po.POID)
+		if err != nil {
+			return fmt.Errorf("failed to receive purchase order line: %w", err)
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get affected rows: %w", err)
+		}
+		if rows == 0 {
+			return errors.New(purchaseOrderConflict)
+		}
+
+		if line.AcceptedQuantity() > 0 {
+			materialIDs = append(materialIDs, line.MaterialID)
+
+			movement := &models.StockMovement{
+				ProjectID:    po.ProjectID,
+				MaterialID:   line.MaterialID,
+				MovementType: models.StockMovementReceipt,
+				Quantity:     line.AcceptedQuantity(),
+				MovementDate: receipt.ReceivedDate,
+				GRID:         uuid.NullUUID{UUID: receipt.GRID, Valid: true},
+				SupplierID:   uuid.NullUUID{UUID: po.SupplierID, Valid: true},
+				Notes:        sql.NullString{String: po.PONumber, Valid: true},
+				RecordedBy:   receipt.ReceivedBy,
+			}
+			if err := insertStockMovement(ctx, tx, movement); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := updateActualPricesFromPurchaseOrders(ctx, tx, po.BOQID, materialIDs); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *purchaseOrderRepository) ListGoodsReceipts(ctx context.Context, poID uuid.UUID) ([]models.GoodsReceipt, error) {
+	receipts := []models.GoodsReceipt{}
+	query := `
+        SELECT * FROM goods_receipt 
+        WHERE po_id = $1 
+        ORDER BY received_date, created_at`
+
+	if err := r.db.SelectContext(ctx, &receipts, query, poID); err != nil {
+		return nil, fmt.Errorf("failed to list goods receipts: %w", err)
+	}
+
+	var lines []models.GoodsReceiptLine
+	linesQuery := `
+        SELECT grl.*, l.material_id, m.name AS material_name, m.unit
+        FROM goods_receipt_line grl
+        JOIN goods_receipt gr ON gr.gr_id = grl.gr_id
+        JOIN purchase_order_line l ON l.po_line_id = grl.po_line_id
+        JOIN material m ON m.material_id = l.material_id
+        WHERE gr.po_id = $1
+        ORDER BY m.name`
+
+	if err := r.db.SelectContext(ctx, &lines, linesQuery, poID); err != nil {
+		return nil, fmt.Errorf("failed to get goods receipt lines: %w", err)
+	}
+
+	index := make(map[uuid.UUID]int, len(receipts))
+	for i := range receipts {
+		receipts[i].Lines = []models.GoodsReceiptLine{}
+		index[receipts[i].GRID] = i
+	}
+	for _, line := range lines {
+		if i, ok := index[line.GRID]; ok {
+			receipts[i].Lines = append(receipts[i].Lines, line)
+		}
+	}
+
+	return receipts, nil
+}
+
+// insertPurchaseOrder allocates the PO number and stores a new draft
+// purchase order with its lines.
+func insertPurchaseOrder(ctx context.Context, tx *sqlx.Tx, po *models.PurchaseOrder) error {
+	poNumber, err := nextDocumentNumber(ctx, tx, po.ProjectID, models.DocumentTypePurchaseOrder, po.OrderDate)
+	if err != nil {
+		return err
+	}
+
+	po.POID = uuid.New()
+	po.PONumber = poNumber
+	po.Status = models.PurchaseOrderStatusDraft
+	po.CreatedAt = time.Now()
+
+	query := `
+        INSERT INTO purchase_order (
+            po_id, po_number, project_id, boq_id, supplier_id, status,
+            order_date, delivery_date, remarks, created_at
+        ) VALUES (
+            :po_id, :po_number, :project_id, :boq_id, :supplier_id, :status,
+            :order_date, :delivery_date, :remarks, :created_at
+        )`
+
+	if _, err := tx.NamedExecContext(ctx, query, po); err != nil {
+		return fmt.Errorf("failed to create purchase order: %w", err)
+	}
+
+	return insertPurchaseOrderLines(ctx, tx, po)
+}
+
+func insertPurchaseOrderLines(ctx context.Context, tx *sqlx.Tx, po *models.PurchaseOrder) error {
+	query := `
+        INSERT INTO purchase_order_line (
+            po_line_id, po_id, material_id, quantity, unit_price, delivery_date,
+            received_quantity, rejected_quantity
+        ) VALUES (
+            :po_line_id, :po_id, :material_id, :quantity, :unit_price, :delivery_date,
+            :received_quantity, :rejected_quantity
+        )`
+
+	for i := range po.Lines {
+		po.Lines[i].LineID = uuid.New()
+		po.Lines[i].POID = po.POID
+		if _, err := tx.NamedExecContext(ctx, query, &po.Lines[i]); err != nil {
+			return fmt.Errorf("failed to create purchase order line: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// updateActualPricesFromPurchaseOrders sets the BOQ actual price of each
+// material to the average unit price of what has been received on its
+// purchase orders, weighted by quantity, and the supplier to the one most
+// recently received from.
+func updateActualPricesFromPurchaseOrders(ctx context.Context, tx *sqlx.Tx, boqID uuid.UUID, materialIDs []string) error {
+	if len(materialIDs) == 0 {
+		return nil
+	}
+
+	query := `
+        UPDATE material_price_log mpl SET
+            actual_price = received.unit_price,
+            supplier_id = received.supplier_id,
+            updated_at = CURRENT_TIMESTAMP
+        FROM (
+            SELECT 
+                l.material_id,
+                SUM(l.received_quantity * l.unit_price) / SUM(l.received_quantity) AS unit_price,
+                (ARRAY_AGG(po.supplier_id ORDER BY po.updated_at DESC NULLS LAST))[1] AS supplier_id
+            FROM purchase_order_line l
+            JOIN purchase_order po ON po.po_id = l.po_id
+            WHERE po.boq_id = $1 
+            AND l.material_id = ANY($2)
+            AND l.received_quantity > 0
+            GROUP BY l.material_id
+        ) received
+        WHERE mpl.boq_id = $1 AND mpl.material_id = received.material_id`
+
+	if _, err := tx.ExecContext(ctx, query, boqID, pq.Array(materialIDs)); err != nil {
+		return fmt.Errorf("failed to update actual prices: %w", err)
+	}
+
+	return nil
+}
@@ -41,6 +41,25 @@ func (r *quotationRepository) CheckBOQStatus(ctx context.Context, projectID uuid
 	return status, nil
 }
 
+func (r *quotationRepository) GetClientWithholdingTax(ctx context.Context, projectID uuid.UUID) (float64, error) {
+	var percentage float64
+	query := `
+        SELECT COALESCE(c.withholding_tax_percentage, 0)
+        FROM project p
+        LEFT JOIN client c ON c.client_id = p.client_id
+        WHERE p.project_id = $1`
+
+	err := r.db.GetContext(ctx, &percentage, query, projectID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, errors.New("project not found")
+		}
+		return 0, fmt.Errorf("failed to get client withholding tax: %w", err)
+	}
+
+	return percentage, nil
+}
+
 func (r *quotationRepository) GetByProjectID(ctx context.Context, projectID uuid.UUID) (*models.Quotation, error) {
 	var quotation models.Quotation
 	query := `SELECT * FROM quotation WHERE project_id = $1`
@@ -71,12 +90,18 @@ func (r *quotationRepository) Create(ctx context.Context, projectID uuid.UUID) (
 		TaxPercentage: sql.NullFloat64{Float64: 7, Valid: true},                     // Default tax percentage
 	}
 
+	number, err := nextDocumentNumber(ctx, tx, projectID, models.DocumentTypeQuotation, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	quotation.QuotationNumber = sql.NullString{String: number, Valid: true}
+
 	query := `
         INSERT INTO quotation (
-            quotation_id, project_id, status, valid_date, 
+            quotation_id, quotation_number, project_id, status, valid_date, 
             final_amount, tax_percentage
         ) VALUES (
-            :quotation_id, :project_id, :status, :valid_date, 
+            :quotation_id, :quotation_number, :project_id, :status, :valid_date, 
             :final_amount, :tax_percentage
         ) RETURNING *`
 
@@ -102,7 +127,7 @@ WITH MaterialTotals AS (
     SELECT 
         job_id, 
         boq_id, 
-        SUM(estimated_price * quantity) as total_material_price 
+        SUM(estimated_price * gross_quantity) as total_material_price 
     FROM material_price_log 
     GROUP BY job_id, boq_id
 )
@@ -275,7 +300,8 @@ func (r *quotationRepository) GetExportData(ctx context.Context, projectID uuid.
             q.valid_date,
             q.tax_percentage,
             q.final_amount,
-            q.status
+            q.status,
+            COALESCE(q.withholding_tax_percentage, c.withholding_tax_percentage, 0) as withholding_tax_percentage
         FROM project p
         LEFT JOIN client c ON c.client_id = p.client_id
         LEFT JOIN quotation q ON q.project_id = p.project_id
@@ -295,7 +321,7 @@ func (r *quotationRepository) GetExportData(ctx context.Context, projectID uuid.
 	// Get detailed job and cost information using the new query structure
 	detailQuery := `
         WITH MaterialTotals AS (
-            SELECT job_id, boq_id, SUM(estimated_price * quantity) as total_material_price 
+            SELECT job_id, boq_id, SUM(estimated_price * gross_quantity) as total_material_price 
             FROM material_price_log 
             GROUP BY job_id, boq_id
         )
@@ -371,6 +397,10 @@ func (r *quotationRepository) GetExportData(ctx context.Context, projectID uuid.
 		}
 	}
 
+	// Withholding tax is deducted from the pre-VAT subtotal
+	data.WithholdingTaxAmount = data.SubTotal * data.WithholdingTaxPercentage / 100
+	data.NetReceivable = data.FinalAmount.Float64 - data.WithholdingTaxAmount
+
 	// Format all nullable fields
 	data.FormatFinalAmount()
 	for i := range data.JobDetails {
@@ -405,6 +435,14 @@ func (r *quotationRepository) UpdateProjectSellingPrice(ctx context.Context, req
 		return fmt.Errorf("failed to update tax percentage: %w", err)
 	}
 
+	if req.WithholdingTaxPercentage != nil {
+		query = `UPDATE quotation SET withholding_tax_percentage = $1 WHERE project_id = $2`
+		_, err = tx.ExecContext(ctx, query, *req.WithholdingTaxPercentage, req.ProjectID)
+		if err != nil {
+			return fmt.Errorf("failed to update withholding tax percentage: %w", err)
+		}
+	}
+
 	query = `UPDATE boq SET selling_general_cost = $1 WHERE project_id = $2`
 	_, err = tx.ExecContext(ctx, query, req.SellingGeneralCost, req.ProjectID)
 	if err != nil {
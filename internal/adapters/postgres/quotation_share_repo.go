@@ -0,0 +1,139 @@
+package postgres
+
+import (
+	"boonkosang/internal/domain/models"
+	"boonkosang/internal/repositories"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type quotationShareRepository struct {
+	db *sqlx.DB
+}
+
+func NewQuotationShareRepository(db *sqlx.DB) repositories.QuotationShareRepository {
+	return &quotationShareRepository{db: db}
+}
+
+func (r *quotationShareRepository) CreateShare(ctx context.Context, share *models.QuotationShare) error {
+	query := `
+        INSERT INTO quotation_share (
+            share_id, quotation_id, project_id, expires_at, created_at
+        ) VALUES (
+            :share_id, :quotation_id, :project_id, :expires_at, :created_at
+        )`
+
+	_, err := r.db.NamedExecContext(ctx, query, share)
+	if err != nil {
+		return fmt.Errorf("failed to create quotation share: %w", err)
+	}
+
+	return nil
+}
+
+func (r *quotationShareRepository) GetShareByID(ctx context.Context, shareID uuid.UUID) (*models.QuotationShare, error) {
+	var share models.QuotationShare
+	query := `SELECT * FROM quotation_share WHERE share_id = $1`
+	err := r.db.GetContext(ctx, &share, query, shareID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get quotation share: %w", err)
+	}
+
+	return &share, nil
+}
+
+func (r *quotationShareRepository) GetClientResponse(ctx context.Context, quotationID uuid.UUID) (*models.QuotationClientResponse, error) {
+	var response models.QuotationClientResponse
+	query := `SELECT * FROM quotation_client_response WHERE quotation_id = $1`
+	err := r.db.GetContext(ctx, &response, query, quotationID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get client response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// RecordClientResponse stores the client's decision and records it on the
+// quotation. An acceptance moves the project from planning to in progress in
+// the same transaction; a rejection leaves the quotation and project status
+// unchanged.
+func (r *quotationShareRepository) RecordClientResponse(ctx context.Context, projectID uuid.UUID, response *models.QuotationClientResponse) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+        INSERT INTO quotation_client_response (
+            response_id, quotation_id, share_id, decision,
+            client_name, remarks, ip_address, responded_at
+        ) VALUES (
+            :response_id, :quotation_id, :share_id, :decision,
+            :client_name, :remarks, :ip_address, :responded_at
+        )`
+
+	_, err = tx.NamedExecContext(ctx, query, response)
+	if err != nil {
+		if strings.Contains(err.Error(), "unique constraint") {
+			return errors.New("quotation has already been responded to")
+		}
+		return fmt.Errorf("failed to record client response: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, `
+        UPDATE quotation
+        SET client_decision = $1, client_responded_at = $2
+        WHERE quotation_id = $3 AND status = $4`,
+		response.Decision, response.RespondedAt, response.QuotationID, models.QuotationStatusApproved)
+	if err != nil {
+		return fmt.Errorf("failed to record client decision: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return errors.New("quotation is no longer available")
+	}
+
+	// The usecase checked the transition; the status condition catches a
+	// project that moved on since.
+	if response.Decision == models.QuotationDecisionAccepted {
+		projectQuery := `
+            UPDATE project 
+            SET status = $1, updated_at = CURRENT_TIMESTAMP
+            WHERE project_id = $2 AND status = $3`
+
+		result, err := tx.ExecContext(ctx, projectQuery,
+			models.ProjectStatusInProgress, projectID, models.ProjectStatusPlanning)
+		if err != nil {
+			return fmt.Errorf("failed to update project status: %w", err)
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get affected rows: %w", err)
+		}
+		if rows == 0 {
+			return errors.New("project must be in planning status to move to in_progress")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
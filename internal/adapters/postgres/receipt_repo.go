@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"boonkosang/internal/domain/models"
+	"boonkosang/internal/repositories"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type receiptRepository struct {
+	db *sqlx.DB
+}
+
+func NewReceiptRepository(db *sqlx.DB) repositories.ReceiptRepository {
+	return &receiptRepository{db: db}
+}
+
+func (r *receiptRepository) Create(ctx context.Context, receipt *models.Receipt) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	receiptNumber, err := nextDocumentNumber(ctx, tx, receipt.ProjectID, models.DocumentTypeReceipt, receipt.ReceiptDate)
+	if err != nil {
+		return err
+	}
+
+	receipt.ReceiptID = uuid.New()
+	receipt.ReceiptNumber = receiptNumber
+	receipt.CreatedAt = time.Now()
+
+	query := `
+        INSERT INTO receipt (
+            receipt_id, receipt_number, payment_id, invoice_id, project_id, receipt_date,
+            sub_total, tax_percentage, tax_amount, total_amount,
+            withholding_tax_amount, retention_amount, amount_received,
+            seller_name, seller_tax_id, seller_branch, seller_address,
+            buyer_name, buyer_tax_id, buyer_branch, buyer_address, created_at
+        ) VALUES (
+            :receipt_id, :receipt_number, :payment_id, :invoice_id, :project_id, :receipt_date,
+            :sub_total, :tax_percentage, :tax_amount, :total_amount,
+            :withholding_tax_amount, :retention_amount, :amount_received,
+            :seller_name, :seller_tax_id, :seller_branch, :seller_address,
+            :buyer_name, :buyer_tax_id, :buyer_branch, :buyer_address, :created_at
+        )
+        ON CONFLICT (payment_id) DO NOTHING`
+
+	result, err := tx.NamedExecContext(ctx, query, receipt)
+	if err != nil {
+		return fmt.Errorf("failed to create receipt: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		// Rolling back releases the allocated number
+		return errors.New("receipt already issued for this payment")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *receiptRepository) GetByPaymentID(ctx context.Context, paymentID uuid.UUID) (*models.Receipt, error) {
+	var receipt models.Receipt
+	query := `SELECT * FROM receipt WHERE payment_id = $1`
+	err := r.db.GetContext(ctx, &receipt, query, paymentID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("receipt not found")
+		}
+		return nil, fmt.Errorf("failed to get receipt: %w", err)
+	}
+
+	return &receipt, nil
+}
+
+func (r *receiptRepository) ListByInvoiceID(ctx context.Context, invoiceID uuid.UUID) ([]models.Receipt, error) {
+	receipts := []models.Receipt{}
+	query := `SELECT * FROM receipt WHERE invoice_id = $1 ORDER BY receipt_date, created_at`
+	if err := r.db.SelectContext(ctx, &receipts, query, invoiceID); err != nil {
+		return nil, fmt.Errorf("failed to get receipts: %w", err)
+	}
+
+	return receipts, nil
+}
@@ -0,0 +1,306 @@
+package postgres
+
+import (
+	"boonkosang/internal/domain/models"
+	"boonkosang/internal/repositories"
+	"boonkosang/internal/requests"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type rfqRepository struct {
+	db *sqlx.DB
+}
+
+func NewRFQRepository(db *sqlx.DB) repositories.RFQRepository {
+	return &rfqRepository{db: db}
+}
+
+const rfqConflict = "RFQ status was changed by another request"
+
+func (r *rfqRepository) Create(ctx context.Context, rfq *models.RFQ) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rfqNumber, err := nextDocumentNumber(ctx, tx, rfq.ProjectID, models.DocumentTypeRequestForQuotation, rfq.IssueDate)
+	if err != nil {
+		return err
+	}
+
+	rfq.RFQID = uuid.New()
+	rfq.RFQNumber = rfqNumber
+	rfq.Status = models.RFQStatusDraft
+	rfq.CreatedAt = time.Now()
+
+	query := `
+        INSERT INTO rfq (
+            rfq_id, rfq_number, project_id, boq_id, status,
+            issue_date, response_due_date, notes, created_at
+        ) VALUES (
+            :rfq_id, :rfq_number, :project_id, :boq_id, :status,
+            :issue_date, :response_due_date, :notes, :created_at
+        )`
+
+	if _, err := tx.NamedExecContext(ctx, query, rfq); err != nil {
+		return fmt.Errorf("failed to create RFQ: %w", err)
+	}
+
+	lineQuery := `
+        INSERT INTO rfq_line (
+            rfq_line_id, rfq_id, material_id, quantity
+        ) VALUES (
+            :rfq_line_id, :rfq_id, :material_id, :quantity
+        )`
+
+	for i := range rfq.Lines {
+		rfq.Lines[i].RFQLineID = uuid.New()
+		rfq.Lines[i].RFQID = rfq.RFQID
+		if _, err := tx.NamedExecContext(ctx, lineQuery, &rfq.Lines[i]); err != nil {
+			return fmt.Errorf("failed to create RFQ line: %w", err)
+		}
+	}
+
+	supplierQuery := `INSERT INTO rfq_supplier (rfq_id, supplier_id) VALUES ($1, $2)`
+
+	for i := range rfq.Suppliers {
+		rfq.Suppliers[i].RFQID = rfq.RFQID
+		if _, err := tx.ExecContext(ctx, supplierQuery, rfq.RFQID, rfq.Suppliers[i].SupplierID); err != nil {
+			return fmt.Errorf("failed to add RFQ supplier: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *rfqRepository) GetByID(ctx context.Context, rfqID uuid.UUID) (*models.RFQ, error) {
+	var rfq models.RFQ
+	err := r.db.GetContext(ctx, &rfq, `SELECT * FROM rfq WHERE rfq_id = $1`, rfqID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("RFQ not found")
+		}
+		return nil, fmt.Errorf("failed to get RFQ: %w", err)
+	}
+
+	rfqs := []models.RFQ{rfq}
+	if err := r.loadDetails(ctx, rfqs, `rfq_id = $1`, rfqID); err != nil {
+		return nil, err
+	}
+
+	rfq = rfqs[0]
+	rfq.Quotes = []models.RFQQuote{}
+	quotesQuery := `SELECT * FROM rfq_quote WHERE rfq_id = $1 ORDER BY quoted_at`
+	if err := r.db.SelectContext(ctx, &rfq.Quotes, quotesQuery, rfqID); err != nil {
+		return nil, fmt.Errorf("failed to get RFQ quotes: %w", err)
+	}
+
+	return &rfq, nil
+}
+
+func (r *rfqRepository) ListByProjectID(ctx context.Context, projectID uuid.UUID) ([]models.RFQ, error) {
+	rfqs := []models.RFQ{}
+	query := `
+        SELECT * FROM rfq
+        WHERE project_id = $1
+        ORDER BY issue_date DESC, rfq_number DESC`
+
+	if err := r.db.SelectContext(ctx, &rfqs, query, projectID); err != nil {
+		return nil, fmt.Errorf("failed to list RFQs: %w", err)
+	}
+
+	if err := r.loadDetails(ctx, rfqs, `rfq_id IN (SELECT rfq_id FROM rfq WHERE project_id = $1)`, projectID); err != nil {
+		return nil, err
+	}
+
+	return rfqs, nil
+}
+
+// loadDetails fills the lines and invited suppliers of rfqs, selecting the
+// rows matching condition on rfq_id.
+func (r *rfqRepository) loadDetails(ctx context.Context, rfqs []models.RFQ, condition string, arg interface{}) error {
+	var lines []models.RFQLine
+	linesQuery := `
+        SELECT l.*, m.name AS material_name, m.unit
+        FROM rfq_line l
+        JOIN material m ON m.material_id = l.material_id
+        WHERE l.` + condition + `
+        ORDER BY m.name`
+
+	if err := r.db.SelectContext(ctx, &lines, linesQuery, arg); err != nil {
+		return fmt.Errorf("failed to get RFQ lines: %w", err)
+	}
+
+	var suppliers []models.RFQSupplier
+	suppliersQuery := `
+        SELECT rs.rfq_id, rs.supplier_id, s.name AS supplier_name
+        FROM rfq_supplier rs
+        JOIN supplier s ON s.supplier_id = rs.supplier_id
+        WHERE rs.` + condition + `
+        ORDER BY s.name`
+
+	if err := r.db.SelectContext(ctx, &suppliers, suppliersQuery, arg); err != nil {
+		return fmt.Errorf("failed to get RFQ suppliers: %w", err)
+	}
+
+	index := make(map[uuid.UUID]int, len(rfqs))
+	for i := range rfqs {
+		rfqs[i].Lines = []models.RFQLine{}
+		rfqs[i].Suppliers = []models.RFQSupplier{}
+		index[rfqs[i].RFQID] = i
+	}
+	for _, line := range lines {
+		if i, ok := index[line.RFQID]; ok {
+			rfqs[i].Lines = append(rfqs[i].Lines, line)
+		}
+	}
+	for _, supplier := range suppliers {
+		if i, ok := index[supplier.RFQID]; ok {
+			rfqs[i].Suppliers = append(rfqs[i].Suppliers, supplier)
+		}
+	}
+
+	return nil
+}
+
+func (r *rfqRepository) ChangeStatus(ctx context.Context, rfq *models.RFQ, from models.RFQStatus) error {
+	query := `
+        UPDATE rfq SET
+            status = $1,
+            sent_at = CASE WHEN $1 = 'sent' THEN CURRENT_TIMESTAMP ELSE sent_at END,
+            updated_at = CURRENT_TIMESTAMP
+        WHERE rfq_id = $2 AND status = $3`
+
+	result, err := r.db.ExecContext(ctx, query, rfq.Status, rfq.RFQID, from)
+	if err != nil {
+		return fmt.Errorf("failed to update RFQ status: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return errors.New(rfqConflict)
+	}
+
+	return nil
+}
+
+func (r *rfqRepository) SaveQuotes(ctx context.Context, rfqID, supplierID uuid.UUID, quotes []models.RFQQuote) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the RFQ so quotes cannot change while it is being awarded.
+	var status models.RFQStatus
+	err = tx.GetContext(ctx, &status, `SELECT status FROM rfq WHERE rfq_id = $1 FOR UPDATE`, rfqID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errors.New("RFQ not found")
+		}
+		return fmt.Errorf("failed to get RFQ: %w", err)
+	}
+	if status != models.RFQStatusSent {
+		return errors.New(rfqConflict)
+	}
+
+	deleteQuery := `DELETE FROM rfq_quote WHERE rfq_id = $1 AND supplier_id = $2`
+	if _, err := tx.ExecContext(ctx, deleteQuery, rfqID, supplierID); err != nil {
+		return fmt.Errorf("failed to delete RFQ quotes: %w", err)
+	}
+
+	query := `
+        INSERT INTO rfq_quote (
+            rfq_line_id, supplier_id, rfq_id, unit_price, lead_time_days, notes, quoted_at
+        ) VALUES (
+            :rfq_line_id, :supplier_id, :rfq_id, :unit_price, :lead_time_days, :notes, :quoted_at
+        )`
+
+	now := time.Now()
+	for i := range quotes {
+		quotes[i].RFQID = rfqID
+		quotes[i].SupplierID = supplierID
+		quotes[i].QuotedAt = now
+		if _, err := tx.NamedExecContext(ctx, query, &quotes[i]); err != nil {
+			return fmt.Errorf("failed to save RFQ quote: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *rfqRepository) Award(ctx context.Context, rfq *models.RFQ, orders []*models.PurchaseOrder, actualPrices []requests.UpdateMaterialActualPriceRequest) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+        UPDATE rfq SET
+            status = $1,
+            awarded_at = CURRENT_TIMESTAMP,
+            updated_at = CURRENT_TIMESTAMP
+        WHERE rfq_id = $2 AND status = $3`
+
+	result, err := tx.ExecContext(ctx, query, models.RFQStatusAwarded, rfq.RFQID, models.RFQStatusSent)
+	if err != nil {
+		return fmt.Errorf("failed to award RFQ: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return errors.New(rfqConflict)
+	}
+
+	lineQuery := `
+        UPDATE rfq_line SET
+            awarded_supplier_id = $1,
+            awarded_unit_price = $2
+        WHERE rfq_line_id = $3 AND rfq_id = $4`
+
+	for _, line := range rfq.Lines {
+		if _, err := tx.ExecContext(ctx, lineQuery, line.AwardedSupplierID, line.AwardedUnitPrice, line.RFQLineID, rfq.RFQID); err != nil {
+			return fmt.Errorf("failed to award RFQ line: %w", err)
+		}
+	}
+
+	for _, po := range orders {
+		if err := insertPurchaseOrder(ctx, tx, po); err != nil {
+			return err
+		}
+	}
+
+	for _, price := range actualPrices {
+		if err := updateActualPrice(ctx, tx, rfq.BOQID, price); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	rfq.Status = models.RFQStatusAwarded
+	return nil
+}
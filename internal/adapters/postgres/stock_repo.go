@@ -0,0 +1,231 @@
+package postgres
+
+import (
+	"boonkosang/internal/domain/models"
+	"boonkosang/internal/repositories"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type stockRepository struct {
+	db *sqlx.DB
+}
+
+func NewStockRepository(db *sqlx.DB) repositories.StockRepository {
+	return &stockRepository{db: db}
+}
+
+func (r *stockRepository) CreateMovements(ctx context.Context, movements []models.StockMovement) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the projects in a fixed order so concurrent movements on the same
+	// site are serialized and cannot both draw down the same stock.
+	projectIDs := []uuid.UUID{}
+	seen := map[uuid.UUID]bool{}
+	for _, m := range movements {
+		if !seen[m.ProjectID] {
+			seen[m.ProjectID] = true
+			projectIDs = append(projectIDs, m.ProjectID)
+		}
+	}
+	sort.Slice(projectIDs, func(i, j int) bool {
+		return projectIDs[i].String() < projectIDs[j].String()
+	})
+	for _, projectID := range projectIDs {
+		var locked uuid.UUID
+		err := tx.GetContext(ctx, &locked, `SELECT project_id FROM project WHERE project_id = $1 FOR UPDATE`, projectID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return errors.New("project not found")
+			}
+			return fmt.Errorf("failed to lock project: %w", err)
+		}
+	}
+
+	for i := range movements {
+		if err := insertStockMovement(ctx, tx, &movements[i]); err != nil {
+			return err
+		}
+	}
+
+	for _, m := range movements {
+		if !m.MovementType.IsOutgoing() {
+			continue
+		}
+		onHand, err := stockOnHand(ctx, tx, m.ProjectID, m.MaterialID)
+		if err != nil {
+			return err
+		}
+		// Allow for the rounding of summed quantities, as ReceiveGoods does
+		if onHand < -0.0001 {
+			return fmt.Errorf("insufficient stock of material %s: on-hand balance would be %.2f", m.MaterialID, onHand)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *stockRepository) ListMovements(ctx context.Context, projectID uuid.UUID, materialID string) ([]models.StockMovementDetail, error) {
+	query := `
+        SELECT 
+            sm.*,
+            m.name AS material_name,
+            m.unit,
+            j.name AS job_name,
+            s.name AS supplier_name,
+            cp.name AS counterpart_project_name
+        FROM stock_movement sm
+        JOIN material m ON m.material_id = sm.material_id
+        LEFT JOIN job j ON j.job_id = sm.job_id
+        LEFT JOIN supplier s ON s.supplier_id = sm.supplier_id
+        LEFT JOIN project cp ON cp.project_id = sm.counterpart_project_id
+        WHERE sm.project_id = $1
+        AND ($2 = '' OR sm.material_id = $2)
+        ORDER BY sm.movement_date DESC, sm.created_at DESC`
+
+	movements := []models.StockMovementDetail{}
+	if err := r.db.SelectContext(ctx, &movements, query, projectID, materialID); err != nil {
+		return nil, fmt.Errorf("failed to list stock movements: %w", err)
+	}
+
+	return movements, nil
+}
+
+func (r *stockRepository) GetBalances(ctx context.Context, projectID uuid.UUID) ([]models.StockBalance, error) {
+	query := `
+        SELECT 
+            m.material_id,
+            m.name,
+            m.unit,
+            COALESCE(SUM(sm.quantity) FILTER (WHERE sm.movement_type = 'receipt'), 0) AS received,
+            COALESCE(SUM(sm.quantity) FILTER (WHERE sm.movement_type = 'issue'), 0) AS issued,
+            COALESCE(SUM(sm.quantity) FILTER (WHERE sm.movement_type = 'transfer_in'), 0) AS transferred_in,
+            COALESCE(SUM(sm.quantity) FILTER (WHERE sm.movement_type = 'transfer_out'), 0) AS transferred_out,
+            COALESCE(SUM(sm.quantity) FILTER (WHERE sm.movement_type = 'return'), 0) AS returned
+        FROM stock_movement sm
+        JOIN material m ON m.material_id = sm.material_id
+        WHERE sm.project_id = $1
+        GROUP BY m.material_id, m.name, m.unit
+        ORDER BY m.name`
+
+	balances := []models.StockBalance{}
+	if err := r.db.SelectContext(ctx, &balances, query, projectID); err != nil {
+		return nil, fmt.Errorf("failed to get stock balances: %w", err)
+	}
+
+	return balances, nil
+}
+
+func (r *stockRepository) GetConsumption(ctx context.Context, projectID uuid.UUID) ([]models.MaterialConsumption, error) {
+	query := `
+        WITH estimated AS (
+            SELECT 
+                mpl.material_id,
+                mpl.job_id,
+                SUM(mpl.gross_quantity * bj.quantity) AS quantity
+            FROM boq b
+            JOIN material_price_log mpl ON mpl.boq_id = b.boq_id
+            JOIN boq_job bj ON bj.boq_id = mpl.boq_id AND bj.job_id = mpl.job_id
+            WHERE b.project_id = $1
+            GROUP BY mpl.material_id, mpl.job_id
+        ), issued AS (
+            SELECT 
+                material_id,
+                job_id,
+                SUM(quantity) AS quantity
+            FROM stock_movement
+            WHERE project_id = $1 AND movement_type = 'issue'
+            GROUP BY material_id, job_id
+        )
+        SELECT 
+            m.material_id,
+            m.name AS material_name,
+            m.unit,
+            j.job_id,
+            j.name AS job_name,
+            COALESCE(e.quantity, 0) AS estimated_quantity,
+            COALESCE(i.quantity, 0) AS issued_quantity
+        FROM estimated e
+        FULL OUTER JOIN issued i ON i.material_id = e.material_id AND i.job_id = e.job_id
+        JOIN material m ON m.material_id = COALESCE(e.material_id, i.material_id)
+        JOIN job j ON j.job_id = COALESCE(e.job_id, i.job_id)
+        ORDER BY m.name, j.name`
+
+	consumption := []models.MaterialConsumption{}
+	if err := r.db.SelectContext(ctx, &consumption, query, projectID); err != nil {
+		return nil, fmt.Errorf("failed to get material consumption: %w", err)
+	}
+
+	return consumption, nil
+}
+
+func (r *stockRepository) IsJobInBOQ(ctx context.Context, projectID, jobID uuid.UUID) (bool, error) {
+	query := `
+        SELECT EXISTS (
+            SELECT 1 
+            FROM boq b
+            JOIN boq_job bj ON bj.boq_id = b.boq_id
+            WHERE b.project_id = $1 AND bj.job_id = $2
+        )`
+
+	var exists bool
+	if err := r.db.GetContext(ctx, &exists, query, projectID, jobID); err != nil {
+		return false, fmt.Errorf("failed to check BOQ job: %w", err)
+	}
+
+	return exists, nil
+}
+
+func insertStockMovement(ctx context.Context, tx *sqlx.Tx, m *models.StockMovement) error {
+	m.MovementID = uuid.New()
+	m.CreatedAt = time.Now()
+
+	query := `
+        INSERT INTO stock_movement (
+            movement_id, project_id, material_id, movement_type, quantity,
+            movement_date, job_id, gr_id, supplier_id, transfer_id,
+            counterpart_project_id, notes, recorded_by, created_at
+        ) VALUES (
+            :movement_id, :project_id, :material_id, :movement_type, :quantity,
+            :movement_date, :job_id, :gr_id, :supplier_id, :transfer_id,
+            :counterpart_project_id, :notes, :recorded_by, :created_at
+        )`
+
+	if _, err := tx.NamedExecContext(ctx, query, m); err != nil {
+		return fmt.Errorf("failed to create stock movement: %w", err)
+	}
+
+	return nil
+}
+
+func stockOnHand(ctx context.Context, tx *sqlx.Tx, projectID uuid.UUID, materialID string) (float64, error) {
+	query := `
+        SELECT COALESCE(SUM(
+            CASE WHEN movement_type IN ('issue', 'transfer_out', 'return') 
+                THEN -quantity ELSE quantity END
+        ), 0)
+        FROM stock_movement
+        WHERE project_id = $1 AND material_id = $2`
+
+	var onHand float64
+	if err := tx.GetContext(ctx, &onHand, query, projectID, materialID); err != nil {
+		return 0, fmt.Errorf("failed to get stock on hand: %w", err)
+	}
+
+	return onHand, nil
+}
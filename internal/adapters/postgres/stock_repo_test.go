@@ -0,0 +1,69 @@
+package postgres_test
+
+import (
+	"boonkosang/internal/adapters/postgres"
+	"boonkosang/internal/domain/models"
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateMovementsChecksOnHand(t *testing.T) {
+	projectID := uuid.New()
+
+	tests := []struct {
+		name    string
+		onHand  float64
+		wantErr string
+	}{
+		{name: "issue everything on hand", onHand: 0},
+		{name: "rounding left over from summed quantities", onHand: -0.00004},
+		{
+			name:    "more than on hand",
+			onHand:  -0.5,
+			wantErr: "insufficient stock of material CEM-01: on-hand balance would be -0.50",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectBegin()
+			mock.ExpectQuery(`SELECT project_id FROM project`).
+				WithArgs(projectID).
+				WillReturnRows(sqlmock.NewRows([]string{"project_id"}).AddRow(projectID))
+			mock.ExpectExec(`INSERT INTO stock_movement`).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			mock.ExpectQuery(`FROM stock_movement`).
+				WithArgs(projectID, "CEM-01").
+				WillReturnRows(sqlmock.NewRows([]string{"on_hand"}).AddRow(tt.onHand))
+			if tt.wantErr != "" {
+				mock.ExpectRollback()
+			} else {
+				mock.ExpectCommit()
+			}
+
+			repo := postgres.NewStockRepository(sqlx.NewDb(db, "postgres"))
+			err = repo.CreateMovements(context.Background(), []models.StockMovement{{
+				ProjectID:    projectID,
+				MaterialID:   "CEM-01",
+				MovementType: models.StockMovementIssue,
+				Quantity:     10.3,
+			}})
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package postgres
+
+import (
+	"boonkosang/internal/domain/models"
+	"boonkosang/internal/repositories"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type supplierPriceRepository struct {
+	db *sqlx.DB
+}
+
+func NewSupplierPriceRepository(db *sqlx.DB) repositories.SupplierPriceRepository {
+	return &supplierPriceRepository{db: db}
+}
+
+const supplierPriceSelect = `
+        SELECT sp.*, s.name AS supplier_name, m.name AS material_name, m.unit
+        FROM supplier_price sp
+        JOIN supplier s ON s.supplier_id = sp.supplier_id
+        JOIN material m ON m.material_id = sp.material_id`
+
+func (r *supplierPriceRepository) Create(ctx context.Context, prices []models.SupplierPrice) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	closeQuery := `
+        UPDATE supplier_price SET
+            valid_to = $1::date - 1,
+            updated_at = CURRENT_TIMESTAMP
+        WHERE supplier_id = $2 AND material_id = $3 AND min_quantity = $4
+        AND valid_from < $1
+        AND (valid_to IS NULL OR valid_to >= $1)`
+
+	query := `
+        INSERT INTO supplier_price (
+            price_id, supplier_id, material_id, unit_price, min_quantity,
+            valid_from, valid_to, created_at
+        ) VALUES (
+            :price_id, :supplier_id, :material_id, :unit_price, :min_quantity,
+            :valid_from, :valid_to, :created_at
+        )`
+
+	now := time.Now()
+	for i := range prices {
+		price := &prices[i]
+
+		if _, err := tx.ExecContext(ctx, closeQuery, price.ValidFrom, price.SupplierID, price.MaterialID, price.MinQuantity); err != nil {
+			return fmt.Errorf("failed to close previous supplier price: %w", err)
+		}
+
+		if err := checkSupplierPriceOverlap(ctx, tx, price); err != nil {
+			return err
+		}
+
+		price.PriceID = uuid.New()
+		price.CreatedAt = now
+		if _, err := tx.NamedExecContext(ctx, query, price); err != nil {
+			return fmt.Errorf("failed to create supplier price: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *supplierPriceRepository) GetByID(ctx context.Context, priceID uuid.UUID) (*models.SupplierPrice, error) {
+	var price models.SupplierPrice
+	query := supplierPriceSelect + `
+        WHERE sp.price_id = $1`
+
+	err := r.db.GetContext(ctx, &price, query, priceID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("supplier price not found")
+		}
+		return nil, fmt.Errorf("failed to get supplier price: %w", err)
+	}
+
+	return &price, nil
+}
+
+func (r *supplierPriceRepository) Update(ctx context.Context, price *models.SupplierPrice) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := checkSupplierPriceOverlap(ctx, tx, price); err != nil {
+		return err
+	}
+
+	query := `
+        UPDATE supplier_price SET
+            unit_price = :unit_price,
+            min_quantity = :min_quantity,
+            valid_from = :valid_from,
+            valid_to = :valid_to,
+            updated_at = CURRENT_TIMESTAMP
+        WHERE price_id = :price_id`
+
+	result, err := tx.NamedExecContext(ctx, query, price)
+	if err != nil {
+		return fmt.Errorf("failed to update supplier price: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return errors.New("supplier price not found")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *supplierPriceRepository) Delete(ctx context.Context, priceID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM supplier_price WHERE price_id = $1`, priceID)
+	if err != nil {
+		return fmt.Errorf("failed to delete supplier price: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return errors.New("supplier price not found")
+	}
+
+	return nil
+}
+
+func (r *supplierPriceRepository) ListBySupplierID(ctx context.Context, supplierID uuid.UUID) ([]models.SupplierPrice, error) {
+	prices := []models.SupplierPrice{}
+	query := supplierPriceSelect + `
+        WHERE sp.supplier_id = $1
+        ORDER BY m.name, sp.min_quantity, sp.valid_from DESC`
+
+	if err := r.db.SelectContext(ctx, &prices, query, supplierID); err != nil {
+		return nil, fmt.Errorf("failed to list supplier prices: %w", err)
+	}
+
+	return prices, nil
+}
+
+func (r *supplierPriceRepository) ListValidPrices(ctx context.Context, date time.Time, materialIDs []string) ([]models.SupplierPrice, error) {
+	prices := []models.SupplierPrice{}
+	query := supplierPriceSelect + `
+        WHERE sp.valid_from <= $1
+        AND (sp.valid_to IS NULL OR sp.valid_to >= $1)
+        AND (cardinality($2::text[]) = 0 OR sp.material_id = ANY($2))
+        ORDER BY sp.material_id, sp.unit_price, sp.min_quantity, s.name`
+
+	if err := r.db.SelectContext(ctx, &prices, query, date, pq.Array(materialIDs)); err != nil {
+		return nil, fmt.Errorf("failed to list valid supplier prices: %w", err)
+	}
+
+	return prices, nil
+}
+
+// checkSupplierPriceOverlap fails when another entry for the same supplier,
+// material and minimum quantity is valid on any day price is.
+func checkSupplierPriceOverlap(ctx context.Context, tx *sqlx.Tx, price *models.SupplierPrice) error {
+	query := `
+        SELECT EXISTS (
+            SELECT 1 FROM supplier_price
+            WHERE supplier_id = $1 AND material_id = $2 AND min_quantity = $3
+            AND price_id <> $4
+            AND ($6::date IS NULL OR valid_from <= $6)
+            AND (valid_to IS NULL OR valid_to >= $5)
+        )`
+
+	var overlaps bool
+	err := tx.GetContext(ctx, &overlaps, query,
+		price.SupplierID, price.MaterialID, price.MinQuantity, price.PriceID, price.ValidFrom, price.ValidTo)
+	if err != nil {
+		return fmt.Errorf("failed to check supplier price overlap: %w", err)
+	}
+	if overlaps {
+		return fmt.Errorf("price of material %s overlaps an existing price list entry", price.MaterialID)
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"boonkosang/internal/domain/models"
+	"boonkosang/internal/repositories"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type unitRepository struct {
+	db *sqlx.DB
+}
+
+func NewUnitRepository(db *sqlx.DB) repositories.UnitRepository {
+	return &unitRepository{db: db}
+}
+
+func (r *unitRepository) CreateConversion(ctx context.Context, conversion *models.UnitConversion) error {
+	conversion.ConversionID = uuid.New()
+	conversion.CreatedAt = time.Now()
+
+	query := `
+        INSERT INTO unit_conversion (
+            conversion_id, material_id, from_unit, to_unit, factor, created_at
+        ) VALUES (
+            :conversion_id, :material_id, :from_unit, :to_unit, :factor, :created_at
+        )`
+
+	if _, err := r.db.NamedExecContext(ctx, query, conversion); err != nil {
+		if strings.Contains(err.Error(), "unique constraint") {
+			return errors.New("unit conversion already exists")
+		}
+		if strings.Contains(err.Error(), "foreign key constraint") {
+			return errors.New("material not found")
+		}
+		return fmt.Errorf("failed to create unit conversion: %w", err)
+	}
+
+	return nil
+}
+
+func (r *unitRepository) DeleteConversion(ctx context.Context, conversionID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM unit_conversion WHERE conversion_id = $1`, conversionID)
+	if err != nil {
+		return fmt.Errorf("failed to delete unit conversion: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return errors.New("unit conversion not found")
+	}
+
+	return nil
+}
+
+func (r *unitRepository) ListConversions(ctx context.Context, materialID string) ([]models.UnitConversion, error) {
+	conversions := []models.UnitConversion{}
+	query := `
+        SELECT uc.*, m.name AS material_name
+        FROM unit_conversion uc
+        LEFT JOIN material m ON m.material_id = uc.material_id
+        WHERE uc.material_id IS NULL OR uc.material_id = $1
+        ORDER BY uc.material_id NULLS FIRST, uc.from_unit, uc.to_unit`
+
+	if err := r.db.SelectContext(ctx, &conversions, query, materialID); err != nil {
+		return nil, fmt.Errorf("failed to list unit conversions: %w", err)
+	}
+
+	return conversions, nil
+}
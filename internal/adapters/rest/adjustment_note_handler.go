@@ -0,0 +1,104 @@
+package rest
+
+import (
+	"boonkosang/internal/requests"
+	"boonkosang/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type AdjustmentNoteHandler struct {
+	noteUsecase usecase.AdjustmentNoteUsecase
+}
+
+func NewAdjustmentNoteHandler(noteUsecase usecase.AdjustmentNoteUsecase) *AdjustmentNoteHandler {
+	return &AdjustmentNoteHandler{
+		noteUsecase: noteUsecase,
+	}
+}
+
+func (h *AdjustmentNoteHandler) AdjustmentNoteRoutes(app *fiber.App) {
+	notes := app.Group("/invoice/:invoiceId/notes")
+	notes.Post("/", h.CreateNote)
+	notes.Get("/", h.ListNotes)
+	notes.Put("/:noteId/void", h.VoidNote)
+}
+
+func (h *AdjustmentNoteHandler) CreateNote(c *fiber.Ctx) error {
+	invoiceID, err := uuid.Parse(c.Params("invoiceId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid invoice ID",
+		})
+	}
+
+	var req requests.CreateAdjustmentNoteRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	note, err := h.noteUsecase.CreateNote(c.Context(), invoiceID, req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Note issued successfully",
+		"data":    note,
+	})
+}
+
+func (h *AdjustmentNoteHandler) ListNotes(c *fiber.Ctx) error {
+	invoiceID, err := uuid.Parse(c.Params("invoiceId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid invoice ID",
+		})
+	}
+
+	notes, err := h.noteUsecase.ListNotes(c.Context(), invoiceID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Notes retrieved successfully",
+		"data":    notes,
+	})
+}
+
+func (h *AdjustmentNoteHandler) VoidNote(c *fiber.Ctx) error {
+	invoiceID, err := uuid.Parse(c.Params("invoiceId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid invoice ID",
+		})
+	}
+
+	noteID, err := uuid.Parse(c.Params("noteId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid note ID",
+		})
+	}
+
+	var req requests.VoidAdjustmentNoteRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	note, err := h.noteUsecase.VoidNote(c.Context(), invoiceID, noteID, req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Note voided successfully",
+		"data":    note,
+	})
+}
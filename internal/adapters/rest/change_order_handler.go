@@ -0,0 +1,222 @@
+package rest
+
+import (
+	"boonkosang/internal/requests"
+	"boonkosang/internal/usecase"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type ChangeOrderHandler struct {
+	changeOrderUsecase usecase.ChangeOrderUsecase
+}
+
+func NewChangeOrderHandler(changeOrderUsecase usecase.ChangeOrderUsecase) *ChangeOrderHandler {
+	return &ChangeOrderHandler{
+		changeOrderUsecase: changeOrderUsecase,
+	}
+}
+
+func (h *ChangeOrderHandler) ChangeOrderRoutes(app *fiber.App) {
+	changeOrder := app.Group("/contracts/:project_id/change-orders")
+	changeOrder.Post("/", h.CreateChangeOrder)
+	changeOrder.Get("/", h.ListChangeOrders)
+	changeOrder.Get("/:changeOrderId", h.GetChangeOrder)
+	changeOrder.Put("/:changeOrderId", h.UpdateChangeOrder)
+	changeOrder.Delete("/:changeOrderId", h.DeleteChangeOrder)
+
+	changeOrder.Put("/:changeOrderId/submit", h.SubmitChangeOrder)
+	changeOrder.Put("/:changeOrderId/approve", h.ApproveChangeOrder)
+	changeOrder.Put("/:changeOrderId/reject", h.RejectChangeOrder)
+}
+
+func (h *ChangeOrderHandler) CreateChangeOrder(c *fiber.Ctx) error {
+	projectID, err := uuid.Parse(c.Params("project_id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid project ID",
+		})
+	}
+
+	var req requests.ChangeOrderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+		})
+	}
+
+	changeOrder, err := h.changeOrderUsecase.Create(c.Context(), projectID, req)
+	if err != nil {
+		return changeOrderError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Change order created successfully",
+		"data":    changeOrder,
+	})
+}
+
+func (h *ChangeOrderHandler) ListChangeOrders(c *fiber.Ctx) error {
+	projectID, err := uuid.Parse(c.Params("project_id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid project ID",
+		})
+	}
+
+	summary, err := h.changeOrderUsecase.List(c.Context(), projectID)
+	if err != nil {
+		return changeOrderError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Change orders retrieved successfully",
+		"data":    summary,
+	})
+}
+
+func (h *ChangeOrderHandler) GetChangeOrder(c *fiber.Ctx) error {
+	projectID, changeOrderID, err := parseChangeOrderParams(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	changeOrder, err := h.changeOrderUsecase.GetByID(c.Context(), projectID, changeOrderID)
+	if err != nil {
+		return changeOrderError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Change order retrieved successfully",
+		"data":    changeOrder,
+	})
+}
+
+func (h *ChangeOrderHandler) UpdateChangeOrder(c *fiber.Ctx) error {
+	projectID, changeOrderID, err := parseChangeOrderParams(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	var req requests.ChangeOrderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+		})
+	}
+
+	changeOrder, err := h.changeOrderUsecase.Update(c.Context(), projectID, changeOrderID, req)
+	if err != nil {
+		return changeOrderError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Change order updated successfully",
+		"data":    changeOrder,
+	})
+}
+
+func (h *ChangeOrderHandler) DeleteChangeOrder(c *fiber.Ctx) error {
+	projectID, changeOrderID, err := parseChangeOrderParams(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	if err := h.changeOrderUsecase.Delete(c.Context(), projectID, changeOrderID); err != nil {
+		return changeOrderError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Change order deleted successfully",
+	})
+}
+
+func (h *ChangeOrderHandler) SubmitChangeOrder(c *fiber.Ctx) error {
+	projectID, changeOrderID, err := parseChangeOrderParams(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	if err := h.changeOrderUsecase.Submit(c.Context(), projectID, changeOrderID); err != nil {
+		return changeOrderError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Change order submitted successfully",
+	})
+}
+
+func (h *ChangeOrderHandler) ApproveChangeOrder(c *fiber.Ctx) error {
+	projectID, changeOrderID, err := parseChangeOrderParams(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	changeOrder, err := h.changeOrderUsecase.Approve(c.Context(), projectID, changeOrderID)
+	if err != nil {
+		return changeOrderError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Change order approved successfully",
+		"data":    changeOrder,
+	})
+}
+
+func (h *ChangeOrderHandler) RejectChangeOrder(c *fiber.Ctx) error {
+	projectID, changeOrderID, err := parseChangeOrderParams(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	var req requests.RejectChangeOrderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+		})
+	}
+
+	if err := h.changeOrderUsecase.Reject(c.Context(), projectID, changeOrderID, req); err != nil {
+		return changeOrderError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Change order rejected successfully",
+	})
+}
+
+func parseChangeOrderParams(c *fiber.Ctx) (uuid.UUID, uuid.UUID, error) {
+	projectID, err := uuid.Parse(c.Params("project_id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, errors.New("Invalid project ID")
+	}
+
+	changeOrderID, err := uuid.Parse(c.Params("changeOrderId"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, errors.New("Invalid change order ID")
+	}
+
+	return projectID, changeOrderID, nil
+}
+
+// changeOrderError reports errors under the message key, like the contract
+// routes.
+func changeOrderError(c *fiber.Ctx, err error) error {
+	return c.Status(errorStatus(err)).JSON(fiber.Map{
+		"message": err.Error(),
+	})
+}
@@ -52,6 +52,10 @@ func (h *ClientHandler) Create(c *fiber.Ctx) error {
 			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
 				"error": "Client with this email already exists",
 			})
+		case "withholding tax percentage must be one of 0, 1, 1.5, 2, 3 or 5":
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
 		default:
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 				"error": "Failed to create client",
@@ -147,6 +151,10 @@ func (h *ClientHandler) Update(c *fiber.Ctx) error {
 			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
 				"error": "Client with this email already exists",
 			})
+		case "withholding tax percentage must be one of 0, 1, 1.5, 2, 3 or 5":
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
 		default:
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 				"error": "Failed to update client",
@@ -1,8 +1,10 @@
 package rest
 
 import (
+	"boonkosang/internal/domain/models"
 	"boonkosang/internal/requests"
 	"boonkosang/internal/usecase"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -10,13 +12,11 @@ import (
 
 type ContractHandler struct {
 	contractUseCase usecase.ContractUseCase
-	invoiceUseCase  usecase.InvoiceUseCase
 }
 
-func NewContractHandler(contractUseCase usecase.ContractUseCase, invoiceUseCase usecase.InvoiceUseCase) *ContractHandler {
+func NewContractHandler(contractUseCase usecase.ContractUseCase) *ContractHandler {
 	return &ContractHandler{
 		contractUseCase: contractUseCase,
-		invoiceUseCase:  invoiceUseCase,
 	}
 }
 
@@ -28,6 +28,7 @@ func (h *ContractHandler) ContractRoutes(app *fiber.App) {
 	//change status of contract
 
 	contract.Put("/:project_id/status", h.ChangeStatus)
+	contract.Post("/:project_id/periods/proposal", h.ProposePeriods)
 	contract.Get("/:project_id", h.GetContractByProjectID)
 }
 
@@ -121,19 +122,63 @@ func (h *ContractHandler) ChangeStatus(c *fiber.Ctx) error {
 		})
 	}
 
-	if err := h.contractUseCase.ChangeStatus(c.Context(), projectID, "approved"); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+	// An empty body approves the contract, as before statuses were tracked
+	req := requests.ChangeContractStatusRequest{Status: string(models.ContractStatusApproved)}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"message": "Invalid request body",
+			})
+		}
+	}
+
+	transition, err := h.contractUseCase.ChangeStatus(c.Context(), projectID, &req)
+	if err != nil {
+		status := fiber.StatusBadRequest
+		if err.Error() == "contract not found" {
+			status = fiber.StatusNotFound
+		} else if strings.HasPrefix(err.Error(), "failed to") {
+			status = fiber.StatusInternalServerError
+		}
+		return c.Status(status).JSON(fiber.Map{
 			"message": err.Error(),
 		})
 	}
 
-	if err := h.invoiceUseCase.CreateInvoicesForAllPeriods(c.Context(), projectID); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Contract status updated successfully",
+		"data":    transition,
+	})
+}
+
+func (h *ContractHandler) ProposePeriods(c *fiber.Ctx) error {
+	projectID, err := uuid.Parse(c.Params("project_id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid project ID",
+		})
+	}
+
+	var req requests.ProposePeriodsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+		})
+	}
+
+	proposal, err := h.contractUseCase.ProposePeriods(c.Context(), projectID, &req)
+	if err != nil {
+		status := fiber.StatusBadRequest
+		if strings.HasPrefix(err.Error(), "failed to") {
+			status = fiber.StatusInternalServerError
+		}
+		return c.Status(status).JSON(fiber.Map{
 			"message": err.Error(),
 		})
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"message": "Contract status updated successfully",
+		"message": "Payment periods proposed successfully",
+		"data":    proposal,
 	})
 }
@@ -0,0 +1,171 @@
+package rest
+
+import (
+	"boonkosang/internal/requests"
+	"boonkosang/internal/usecase"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type ContractTemplateHandler struct {
+	templateUsecase usecase.ContractTemplateUsecase
+}
+
+func NewContractTemplateHandler(templateUsecase usecase.ContractTemplateUsecase) *ContractTemplateHandler {
+	return &ContractTemplateHandler{
+		templateUsecase: templateUsecase,
+	}
+}
+
+func (h *ContractTemplateHandler) ContractTemplateRoutes(app *fiber.App) {
+	templates := app.Group("/contract-templates")
+	templates.Get("/:companyId", h.ListTemplates)
+	templates.Post("/:companyId", h.CreateTemplate)
+	templates.Put("/:companyId/:templateId", h.UpdateTemplate)
+	templates.Delete("/:companyId/:templateId", h.DeleteTemplate)
+
+	app.Get("/contracts/:project_id/document", h.RenderContract)
+}
+
+func (h *ContractTemplateHandler) ListTemplates(c *fiber.Ctx) error {
+	companyID, err := uuid.Parse(c.Params("companyId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid company ID",
+		})
+	}
+
+	templates, err := h.templateUsecase.ListTemplates(c.Context(), companyID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Clause templates retrieved successfully",
+		"data":    templates,
+	})
+}
+
+func (h *ContractTemplateHandler) CreateTemplate(c *fiber.Ctx) error {
+	companyID, err := uuid.Parse(c.Params("companyId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid company ID",
+		})
+	}
+
+	var req requests.ContractClauseTemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+		})
+	}
+
+	template, err := h.templateUsecase.CreateTemplate(c.Context(), companyID, req)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Clause template created successfully",
+		"data":    template,
+	})
+}
+
+func (h *ContractTemplateHandler) UpdateTemplate(c *fiber.Ctx) error {
+	companyID, err := uuid.Parse(c.Params("companyId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid company ID",
+		})
+	}
+
+	templateID, err := uuid.Parse(c.Params("templateId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid template ID",
+		})
+	}
+
+	var req requests.ContractClauseTemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+		})
+	}
+
+	template, err := h.templateUsecase.UpdateTemplate(c.Context(), companyID, templateID, req)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Clause template updated successfully",
+		"data":    template,
+	})
+}
+
+func (h *ContractTemplateHandler) DeleteTemplate(c *fiber.Ctx) error {
+	companyID, err := uuid.Parse(c.Params("companyId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid company ID",
+		})
+	}
+
+	templateID, err := uuid.Parse(c.Params("templateId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid template ID",
+		})
+	}
+
+	if err := h.templateUsecase.DeleteTemplate(c.Context(), companyID, templateID); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Clause template deleted successfully",
+	})
+}
+
+// RenderContract returns the contract document as a file download.
+// The format query parameter selects pdf (default) or docx.
+func (h *ContractTemplateHandler) RenderContract(c *fiber.Ctx) error {
+	projectID, err := uuid.Parse(c.Params("project_id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid project ID",
+		})
+	}
+
+	file, err := h.templateUsecase.RenderContract(c.Context(), projectID, c.Query("format", "pdf"))
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, file.ContentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", file.FileName))
+	return c.Send(file.Content)
+}
+
+func (h *ContractTemplateHandler) handleError(c *fiber.Ctx, err error) error {
+	switch err.Error() {
+	case "clause template not found", "company not found", "contract not found", "project not found":
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	case "invalid clause type", "title and body are required", "format must be pdf or docx":
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+}
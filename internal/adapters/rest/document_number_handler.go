@@ -0,0 +1,93 @@
+package rest
+
+import (
+	"boonkosang/internal/domain/models"
+	"boonkosang/internal/requests"
+	"boonkosang/internal/usecase"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type DocumentNumberHandler struct {
+	documentNumberUsecase usecase.DocumentNumberUsecase
+}
+
+func NewDocumentNumberHandler(documentNumberUsecase usecase.DocumentNumberUsecase) *DocumentNumberHandler {
+	return &DocumentNumberHandler{
+		documentNumberUsecase: documentNumberUsecase,
+	}
+}
+
+func (h *DocumentNumberHandler) DocumentNumberRoutes(app *fiber.App) {
+	numbering := app.Group("/document-numbering")
+
+	numbering.Get("/:companyId", h.GetFormats)
+	numbering.Put("/:companyId/:documentType", h.UpdateFormat)
+}
+
+func (h *DocumentNumberHandler) GetFormats(c *fiber.Ctx) error {
+	companyID, err := uuid.Parse(c.Params("companyId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid company ID",
+		})
+	}
+
+	formats, err := h.documentNumberUsecase.GetFormats(c.Context(), companyID)
+	if err != nil {
+		if err.Error() == "company not found" {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Document number formats retrieved successfully",
+		"data":    formats,
+	})
+}
+
+func (h *DocumentNumberHandler) UpdateFormat(c *fiber.Ctx) error {
+	companyID, err := uuid.Parse(c.Params("companyId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid company ID",
+		})
+	}
+
+	var req requests.UpdateDocumentNumberFormatRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	documentType := models.DocumentType(c.Params("documentType"))
+	format, err := h.documentNumberUsecase.UpdateFormat(c.Context(), companyID, documentType, req)
+	if err != nil {
+		if err.Error() == "company not found" {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		if err.Error() == "invalid document type" || strings.HasPrefix(err.Error(), "invalid pattern") {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Document number format updated successfully",
+		"data":    format,
+	})
+}
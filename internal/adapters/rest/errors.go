@@ -0,0 +1,40 @@
+package rest
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// errorStatus maps a usecase error to an HTTP status by the wording the
+// usecases and repositories use: "... not found" is 404, "... already
+// exists" and "... changed by another request" are 409, "failed to ..." is
+// an internal error and anything else is a validation error. Messages listed
+// in conflicts are also reported as 409.
+func errorStatus(err error, conflicts ...string) int {
+	message := err.Error()
+	for _, conflict := range conflicts {
+		if message == conflict {
+			return fiber.StatusConflict
+		}
+	}
+
+	switch {
+	case strings.HasSuffix(message, "not found"):
+		return fiber.StatusNotFound
+	case strings.HasSuffix(message, "already exists"),
+		strings.HasSuffix(message, "changed by another request"):
+		return fiber.StatusConflict
+	case strings.HasPrefix(message, "failed to"):
+		return fiber.StatusInternalServerError
+	default:
+		return fiber.StatusBadRequest
+	}
+}
+
+// errorResponse writes a usecase error with the status from errorStatus.
+func errorResponse(c *fiber.Ctx, err error, conflicts ...string) error {
+	return c.Status(errorStatus(err, conflicts...)).JSON(fiber.Map{
+		"error": err.Error(),
+	})
+}
@@ -0,0 +1,96 @@
+package rest
+
+import (
+	"boonkosang/internal/responses"
+	"boonkosang/internal/usecase"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type ETaxHandler struct {
+	eTaxUsecase usecase.ETaxUsecase
+}
+
+func NewETaxHandler(eTaxUsecase usecase.ETaxUsecase) *ETaxHandler {
+	return &ETaxHandler{
+		eTaxUsecase: eTaxUsecase,
+	}
+}
+
+// ETaxRoutes serves e-Tax Invoice XML for submission to the e-Tax service
+// provider.
+func (h *ETaxHandler) ETaxRoutes(app *fiber.App) {
+	app.Get("/invoice/:invoiceId/etax", h.ExportInvoice)
+	app.Get("/invoice/:invoiceId/payments/:paymentId/receipt/etax", h.ExportReceipt)
+	app.Get("/invoice/:invoiceId/notes/:noteId/etax", h.ExportNote)
+}
+
+func (h *ETaxHandler) ExportInvoice(c *fiber.Ctx) error {
+	invoiceID, err := uuid.Parse(c.Params("invoiceId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid invoice ID",
+		})
+	}
+
+	file, err := h.eTaxUsecase.ExportInvoice(c.Context(), invoiceID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return sendETaxDocument(c, file)
+}
+
+func (h *ETaxHandler) ExportReceipt(c *fiber.Ctx) error {
+	invoiceID, err := uuid.Parse(c.Params("invoiceId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid invoice ID",
+		})
+	}
+
+	paymentID, err := uuid.Parse(c.Params("paymentId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid payment ID",
+		})
+	}
+
+	file, err := h.eTaxUsecase.ExportReceipt(c.Context(), invoiceID, paymentID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return sendETaxDocument(c, file)
+}
+
+func (h *ETaxHandler) ExportNote(c *fiber.Ctx) error {
+	invoiceID, err := uuid.Parse(c.Params("invoiceId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid invoice ID",
+		})
+	}
+
+	noteID, err := uuid.Parse(c.Params("noteId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid note ID",
+		})
+	}
+
+	file, err := h.eTaxUsecase.ExportNote(c.Context(), invoiceID, noteID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return sendETaxDocument(c, file)
+}
+
+func sendETaxDocument(c *fiber.Ctx, file *responses.ETaxDocumentResponse) error {
+	c.Set(fiber.HeaderContentType, file.ContentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", file.FileName))
+	return c.Send(file.Content)
+}
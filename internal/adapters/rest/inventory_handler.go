@@ -0,0 +1,137 @@
+package rest
+
+import (
+	"boonkosang/internal/requests"
+	"boonkosang/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type InventoryHandler struct {
+	inventoryUsecase usecase.InventoryUsecase
+}
+
+func NewInventoryHandler(inventoryUsecase usecase.InventoryUsecase) *InventoryHandler {
+	return &InventoryHandler{
+		inventoryUsecase: inventoryUsecase,
+	}
+}
+
+func (h *InventoryHandler) InventoryRoutes(app *fiber.App) {
+	inventory := app.Group("/inventory/:projectId")
+	inventory.Get("/balances", h.GetBalances)
+	inventory.Get("/movements", h.ListMovements)
+	inventory.Post("/movements", h.RecordMovement)
+	inventory.Post("/transfers", h.TransferStock)
+	inventory.Get("/consumption", h.GetConsumption)
+}
+
+func (h *InventoryHandler) GetBalances(c *fiber.Ctx) error {
+	projectID, err := uuid.Parse(c.Params("projectId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid project ID",
+		})
+	}
+
+	balances, err := h.inventoryUsecase.GetBalances(c.Context(), projectID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Stock balances retrieved successfully",
+		"data":    balances,
+	})
+}
+
+func (h *InventoryHandler) ListMovements(c *fiber.Ctx) error {
+	projectID, err := uuid.Parse(c.Params("projectId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid project ID",
+		})
+	}
+
+	movements, err := h.inventoryUsecase.ListMovements(c.Context(), projectID, c.Query("material_id"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Stock movements retrieved successfully",
+		"data":    movements,
+	})
+}
+
+func (h *InventoryHandler) RecordMovement(c *fiber.Ctx) error {
+	projectID, err := uuid.Parse(c.Params("projectId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid project ID",
+		})
+	}
+
+	var req requests.CreateStockMovementRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	movement, err := h.inventoryUsecase.RecordMovement(c.Context(), projectID, req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Stock movement recorded successfully",
+		"data":    movement,
+	})
+}
+
+func (h *InventoryHandler) TransferStock(c *fiber.Ctx) error {
+	projectID, err := uuid.Parse(c.Params("projectId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid project ID",
+		})
+	}
+
+	var req requests.TransferStockRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	movements, err := h.inventoryUsecase.TransferStock(c.Context(), projectID, req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Stock transferred successfully",
+		"data":    movements,
+	})
+}
+
+func (h *InventoryHandler) GetConsumption(c *fiber.Ctx) error {
+	projectID, err := uuid.Parse(c.Params("projectId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid project ID",
+		})
+	}
+
+	consumption, err := h.inventoryUsecase.GetConsumption(c.Context(), projectID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Material consumption retrieved successfully",
+		"data":    consumption,
+	})
+}
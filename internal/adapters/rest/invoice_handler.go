@@ -3,6 +3,9 @@ package rest
 import (
 	"boonkosang/internal/requests"
 	"boonkosang/internal/usecase"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -23,11 +26,18 @@ func (h *InvoiceHandler) InvoiceRoutes(app *fiber.App) {
 	invoice := app.Group("/invoices/:projectId")
 	invoice.Post("/all-periods", h.CreateInvoicesForAllPeriods)
 	invoice.Get("/", h.GetProjectInvoices)
+	invoice.Get("/retention", h.GetRetentionSummary)
+	invoice.Post("/retention/release", h.CreateRetentionReleaseInvoice)
 
 	invoiceDetail := app.Group("/invoice")
 	invoiceDetail.Get("/:invoiceId", h.GetInvoiceByID)
 	invoiceDetail.Put("/:invoiceId/status", h.UpdateInvoiceStatus)
 	invoiceDetail.Put("/:invoiceId", h.UpdateInvoice)
+	invoiceDetail.Put("/:invoiceId/withholding-certificate", h.RecordWithholdingCertificate)
+
+	receivables := app.Group("/receivables")
+	receivables.Get("/overdue", h.ListOverdueInvoices)
+	receivables.Get("/aging", h.GetAgingReport)
 
 }
 
@@ -89,9 +99,24 @@ func (h *InvoiceHandler) UpdateInvoiceStatus(c *fiber.Ctx) error {
 	}
 
 	if err := h.invoiceUseCase.UpdateInvoiceStatus(c.Context(), invoiceID, req); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		switch {
+		case err.Error() == "invoice not found":
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case err.Error() == "invoice status was changed by another request":
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case strings.HasPrefix(err.Error(), "failed to"):
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
 	}
 
 	return c.JSON(fiber.Map{
@@ -143,3 +168,170 @@ func (h *InvoiceHandler) UpdateInvoice(c *fiber.Ctx) error {
 		"message": "Invoice updated successfully",
 	})
 }
+
+func (h *InvoiceHandler) RecordWithholdingCertificate(c *fiber.Ctx) error {
+	invoiceID, err := uuid.Parse(c.Params("invoiceId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid invoice ID",
+		})
+	}
+
+	var req requests.RecordWithholdingCertificateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	invoice, err := h.invoiceUseCase.RecordWithholdingCertificate(c.Context(), invoiceID, req)
+	if err != nil {
+		switch err.Error() {
+		case "invoice not found":
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case "withholding tax certificate can only be recorded for paid invoices",
+			"invoice is not subject to withholding tax",
+			"certificate number is required":
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Withholding tax certificate recorded successfully",
+		"data":    invoice,
+	})
+}
+
+func (h *InvoiceHandler) GetRetentionSummary(c *fiber.Ctx) error {
+	projectID, err := uuid.Parse(c.Params("projectId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid project ID",
+		})
+	}
+
+	summary, err := h.invoiceUseCase.GetRetentionSummary(c.Context(), projectID)
+	if err != nil {
+		switch err.Error() {
+		case "project not found", "contract not found":
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Retention summary retrieved successfully",
+		"data":    summary,
+	})
+}
+
+func (h *InvoiceHandler) CreateRetentionReleaseInvoice(c *fiber.Ctx) error {
+	projectID, err := uuid.Parse(c.Params("projectId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid project ID",
+		})
+	}
+
+	invoice, err := h.invoiceUseCase.CreateRetentionReleaseInvoice(c.Context(), projectID)
+	if err != nil {
+		switch {
+		case err.Error() == "project not found", err.Error() == "contract not found":
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case strings.HasPrefix(err.Error(), "failed to"):
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Retention release invoice created successfully",
+		"data":    invoice,
+	})
+}
+
+func (h *InvoiceHandler) ListOverdueInvoices(c *fiber.Ctx) error {
+	asOf, err := parseAsOf(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid as_of date, expected YYYY-MM-DD",
+		})
+	}
+
+	invoices, err := h.invoiceUseCase.ListOverdueInvoices(c.Context(), asOf)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Overdue invoices retrieved successfully",
+		"data":    invoices,
+	})
+}
+
+// GetAgingReport returns the accounts receivable aging report. With
+// format=csv the report is returned as a file download.
+func (h *InvoiceHandler) GetAgingReport(c *fiber.Ctx) error {
+	asOf, err := parseAsOf(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid as_of date, expected YYYY-MM-DD",
+		})
+	}
+
+	if c.Query("format") == "csv" {
+		content, err := h.invoiceUseCase.ExportAgingReportCSV(c.Context(), asOf)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+		c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", "ar-aging-"+asOf.Format("2006-01-02")+".csv"))
+		return c.Send(content)
+	}
+
+	report, err := h.invoiceUseCase.GetAgingReport(c.Context(), asOf)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Aging report retrieved successfully",
+		"data":    report,
+	})
+}
+
+// parseAsOf reads the as_of query parameter, defaulting to today.
+func parseAsOf(c *fiber.Ctx) (time.Time, error) {
+	if value := c.Query("as_of"); value != "" {
+		return time.Parse("2006-01-02", value)
+	}
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC), nil
+}
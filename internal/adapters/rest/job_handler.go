@@ -3,6 +3,7 @@ package rest
 import (
 	"boonkosang/internal/requests"
 	"boonkosang/internal/usecase"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -32,6 +33,7 @@ func (h *JobHandler) JobRoutes(app *fiber.App) {
 	job.Post("/:id/materials", h.AddMaterial)
 	job.Delete("/:id/materials/:materialId", h.DeleteMaterial)
 	job.Put("/:id/materials/:materialId/quantity", h.UpdateMaterialQuantity)
+	job.Put("/:id/materials/:materialId/waste", h.UpdateMaterialWaste)
 
 }
 
@@ -52,6 +54,11 @@ func (h *JobHandler) Create(c *fiber.Ctx) error {
 
 	job, err := h.jobUsecase.Create(c.Context(), req)
 	if err != nil {
+		if strings.HasPrefix(err.Error(), "unknown unit") {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to create job",
 		})
@@ -173,6 +180,11 @@ func (h *JobHandler) Update(c *fiber.Ctx) error {
 				"error": "Job not found",
 			})
 		}
+		if strings.HasPrefix(err.Error(), "unknown unit") {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to update job",
 		})
@@ -211,6 +223,12 @@ func (h *JobHandler) AddMaterial(c *fiber.Ctx) error {
 				"error": "Material already exists for this job",
 			})
 		default:
+			if strings.HasPrefix(err.Error(), "deprecated materials") || strings.HasPrefix(err.Error(), "cannot convert") ||
+				strings.HasPrefix(err.Error(), "waste percent") {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": err.Error(),
+				})
+			}
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 				"error": "Failed to add material",
 			})
@@ -281,6 +299,11 @@ func (h *JobHandler) UpdateMaterialQuantity(c *fiber.Ctx) error {
 				"error": "Job material not found",
 			})
 		}
+		if strings.HasPrefix(err.Error(), "cannot convert") {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to update material quantity",
 		})
@@ -290,3 +313,41 @@ func (h *JobHandler) UpdateMaterialQuantity(c *fiber.Ctx) error {
 		"message": "Material quantity updated successfully",
 	})
 }
+
+func (h *JobHandler) UpdateMaterialWaste(c *fiber.Ctx) error {
+	jobID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid job ID",
+		})
+	}
+
+	var req requests.UpdateJobMaterialWasteRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	err = h.jobUsecase.UpdateMaterialWaste(c.Context(), jobID, c.Params("materialId"), req)
+	if err != nil {
+		switch {
+		case strings.HasSuffix(err.Error(), "not found"):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case strings.HasPrefix(err.Error(), "failed to"):
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Material waste updated successfully",
+	})
+}
@@ -4,6 +4,7 @@ package rest
 import (
 	"boonkosang/internal/requests"
 	"boonkosang/internal/usecase"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -24,14 +25,19 @@ func (h *MaterialHandler) MaterialRoutes(app *fiber.App) {
 
 	material.Post("/", h.Create)
 	material.Get("/", h.List)
+	material.Get("/categories", h.ListCategories)
+	material.Get("/price-history", h.GetPriceHistory)
 
 	material.Get("/:projectId/prices", h.GetMaterialPrices)
 	material.Put("/:boqId/estimated-price", h.UpdateEstimatedPrice)
 	material.Put("/:boqId/actual-price", h.UpdateActualPrice)
+	material.Post("/:boqId/estimated-price/auto-fill", h.AutoFillEstimatedPrices)
 
 	material.Get("/:id", h.GetByID)
 	material.Put("/:id", h.Update)
 	material.Delete("/:id", h.Delete)
+	material.Put("/:id/deprecate", h.Deprecate)
+	material.Put("/:id/restore", h.Restore)
 
 }
 
@@ -45,11 +51,17 @@ func (h *MaterialHandler) Create(c *fiber.Ctx) error {
 	}
 	material, err := h.materialUsecase.Create(c.Context(), req)
 	if err != nil {
-		switch err.Error() {
-		case "material ID already exists":
+		switch {
+		case strings.HasSuffix(err.Error(), "already exists"):
 			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
 				"error": err.Error(),
 			})
+		case strings.HasPrefix(err.Error(), "invalid material category"),
+			strings.HasPrefix(err.Error(), "unknown unit"),
+			strings.HasPrefix(err.Error(), "waste percent"):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
 		default:
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 				"error": "Failed to create material",
@@ -64,9 +76,22 @@ func (h *MaterialHandler) Create(c *fiber.Ctx) error {
 }
 
 func (h *MaterialHandler) List(c *fiber.Ctx) error {
+	req := requests.ListMaterialsRequest{
+		Search:            c.Query("q"),
+		Category:          c.Query("category"),
+		Tag:               c.Query("tag"),
+		IncludeDeprecated: c.QueryBool("include_deprecated"),
+		Page:              c.QueryInt("page"),
+		PageSize:          c.QueryInt("page_size", 10),
+	}
 
-	response, err := h.materialUsecase.List(c.Context())
+	response, err := h.materialUsecase.List(c.Context(), req)
 	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid material category") {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to retrieve materials",
 		})
@@ -129,11 +154,21 @@ func (h *MaterialHandler) Update(c *fiber.Ctx) error {
 
 	err := h.materialUsecase.Update(c.Context(), materialID, req)
 	if err != nil {
-		switch err.Error() {
-		case "material not found":
+		switch {
+		case err.Error() == "material not found":
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 				"error": "Material not found",
 			})
+		case strings.HasSuffix(err.Error(), "already exists"):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case strings.HasPrefix(err.Error(), "invalid material category"),
+			strings.HasPrefix(err.Error(), "unknown unit"),
+			strings.HasPrefix(err.Error(), "waste percent"):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
 		default:
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 				"error": "Failed to update material",
@@ -177,6 +212,42 @@ func (h *MaterialHandler) Delete(c *fiber.Ctx) error {
 	})
 }
 
+func (h *MaterialHandler) ListCategories(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"message": "Material categories retrieved successfully",
+		"data":    h.materialUsecase.ListCategories(),
+	})
+}
+
+func (h *MaterialHandler) Deprecate(c *fiber.Ctx) error {
+	var req requests.DeprecateMaterialRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid request body",
+			})
+		}
+	}
+
+	if err := h.materialUsecase.Deprecate(c.Context(), c.Params("id"), req); err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Material deprecated successfully",
+	})
+}
+
+func (h *MaterialHandler) Restore(c *fiber.Ctx) error {
+	if err := h.materialUsecase.Restore(c.Context(), c.Params("id")); err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Material restored successfully",
+	})
+}
+
 func (h *MaterialHandler) GetMaterialPrices(c *fiber.Ctx) error {
 	projectID, err := uuid.Parse(c.Params("projectId"))
 	if err != nil {
@@ -198,6 +269,45 @@ func (h *MaterialHandler) GetMaterialPrices(c *fiber.Ctx) error {
 	})
 }
 
+func (h *MaterialHandler) GetPriceHistory(c *fiber.Ctx) error {
+	history, err := h.materialUsecase.GetPriceHistory(c.Context(),
+		c.Query("material_id"), c.Query("from"), c.Query("to"), c.Query("period"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Material price history retrieved successfully",
+		"data":    history,
+	})
+}
+
+func (h *MaterialHandler) AutoFillEstimatedPrices(c *fiber.Ctx) error {
+	boqID, err := uuid.Parse(c.Params("boqId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid BOQ ID",
+		})
+	}
+
+	var req requests.AutoFillEstimatedPricesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	result, err := h.materialUsecase.AutoFillEstimatedPrices(c.Context(), boqID, req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Estimated prices filled successfully",
+		"data":    result,
+	})
+}
+
 func (h *MaterialHandler) UpdateEstimatedPrice(c *fiber.Ctx) error {
 	boqID, err := uuid.Parse(c.Params("boqId"))
 	if err != nil {
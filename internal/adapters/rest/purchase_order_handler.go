@@ -0,0 +1,214 @@
+package rest
+
+import (
+	"boonkosang/internal/requests"
+	"boonkosang/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type PurchaseOrderHandler struct {
+	poUsecase usecase.PurchaseOrderUsecase
+}
+
+func NewPurchaseOrderHandler(poUsecase usecase.PurchaseOrderUsecase) *PurchaseOrderHandler {
+	return &PurchaseOrderHandler{
+		poUsecase: poUsecase,
+	}
+}
+
+func (h *PurchaseOrderHandler) PurchaseOrderRoutes(app *fiber.App) {
+	projectOrders := app.Group("/purchase-orders/:projectId")
+	projectOrders.Post("/", h.CreateFromBOQ)
+	projectOrders.Get("/", h.ListPurchaseOrders)
+	projectOrders.Get("/materials", h.GetMaterialStatus)
+
+	order := app.Group("/purchase-order/:poId")
+	order.Get("/", h.GetPurchaseOrder)
+	order.Put("/", h.UpdatePurchaseOrder)
+	order.Put("/status", h.UpdateStatus)
+	order.Post("/receive", h.ReceiveGoods)
+	order.Get("/receipts", h.ListGoodsReceipts)
+}
+
+func (h *PurchaseOrderHandler) CreateFromBOQ(c *fiber.Ctx) error {
+	projectID, err := uuid.Parse(c.Params("projectId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid project ID",
+		})
+	}
+
+	var req requests.CreatePurchaseOrdersRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	orders, err := h.poUsecase.CreateFromBOQ(c.Context(), projectID, req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Purchase orders created successfully",
+		"data":    orders,
+	})
+}
+
+func (h *PurchaseOrderHandler) ListPurchaseOrders(c *fiber.Ctx) error {
+	projectID, err := uuid.Parse(c.Params("projectId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid project ID",
+		})
+	}
+
+	orders, err := h.poUsecase.ListPurchaseOrders(c.Context(), projectID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Purchase orders retrieved successfully",
+		"data":    orders,
+	})
+}
+
+func (h *PurchaseOrderHandler) GetPurchaseOrder(c *fiber.Ctx) error {
+	poID, err := uuid.Parse(c.Params("poId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid purchase order ID",
+		})
+	}
+
+	order, err := h.poUsecase.GetPurchaseOrder(c.Context(), poID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Purchase order retrieved successfully",
+		"data":    order,
+	})
+}
+
+func (h *PurchaseOrderHandler) UpdatePurchaseOrder(c *fiber.Ctx) error {
+	poID, err := uuid.Parse(c.Params("poId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid purchase order ID",
+		})
+	}
+
+	var req requests.UpdatePurchaseOrderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	order, err := h.poUsecase.UpdatePurchaseOrder(c.Context(), poID, req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Purchase order updated successfully",
+		"data":    order,
+	})
+}
+
+func (h *PurchaseOrderHandler) UpdateStatus(c *fiber.Ctx) error {
+	poID, err := uuid.Parse(c.Params("poId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid purchase order ID",
+		})
+	}
+
+	var req requests.UpdatePurchaseOrderStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	order, err := h.poUsecase.UpdateStatus(c.Context(), poID, req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Purchase order status updated successfully",
+		"data":    order,
+	})
+}
+
+func (h *PurchaseOrderHandler) ReceiveGoods(c *fiber.Ctx) error {
+	poID, err := uuid.Parse(c.Params("poId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid purchase order ID",
+		})
+	}
+
+	var req requests.ReceivePurchaseOrderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	receipt, err := h.poUsecase.ReceiveGoods(c.Context(), poID, req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Goods received successfully",
+		"data":    receipt,
+	})
+}
+
+func (h *PurchaseOrderHandler) ListGoodsReceipts(c *fiber.Ctx) error {
+	poID, err := uuid.Parse(c.Params("poId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid purchase order ID",
+		})
+	}
+
+	receipts, err := h.poUsecase.ListGoodsReceipts(c.Context(), poID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Goods receipts retrieved successfully",
+		"data":    receipts,
+	})
+}
+
+// GetMaterialStatus returns ordered and received quantities per BOQ material.
+func (h *PurchaseOrderHandler) GetMaterialStatus(c *fiber.Ctx) error {
+	projectID, err := uuid.Parse(c.Params("projectId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid project ID",
+		})
+	}
+
+	materials, err := h.poUsecase.GetMaterialStatus(c.Context(), projectID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Material order status retrieved successfully",
+		"data":    materials,
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"boonkosang/internal/requests"
 	"boonkosang/internal/usecase"
 	"fmt"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -29,6 +30,7 @@ func (h *QuotationHandler) QuotationRoutes(app *fiber.App) {
 	quotation.Get("/projects/:projectId/export", h.ExportQuotation)
 
 	quotation.Put("/projects/:projectId/selling-price", h.UpdateProjectSellingPrice)
+	quotation.Post("/projects/:projectId/selling-price/markup", h.CalculateSellingPrice)
 
 	quotation.Post("/projects/:projectId", h.CreateOrGetQuotation)
 	quotation.Put("/projects/:projectId/approve", h.ApproveQuotation)
@@ -206,3 +208,53 @@ func (h *QuotationHandler) UpdateProjectSellingPrice(c *fiber.Ctx) error {
 		"message": "Project selling prices updated successfully",
 	})
 }
+
+func (h *QuotationHandler) CalculateSellingPrice(c *fiber.Ctx) error {
+	var req requests.CalculateSellingPriceRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	projectID, err := uuid.Parse(c.Params("projectId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid project ID",
+		})
+	}
+	req.ProjectID = projectID
+
+	response, err := h.quotationUsecase.CalculateSellingPrice(c.Context(), req)
+	if err != nil {
+		switch err.Error() {
+		case "BOQ not found", "quotation not found":
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		default:
+			// Everything the usecase reports besides repository failures is
+			// a problem with the request: markup validation, BOQ or quotation
+			// state, or a calculated price that fails selling price checks.
+			if strings.HasPrefix(err.Error(), "failed to") {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error": err.Error(),
+				})
+			}
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+	}
+
+	message := "Project selling prices updated successfully"
+	if req.Preview {
+		message = "Project selling prices calculated successfully"
+	}
+
+	return c.JSON(fiber.Map{
+		"message": message,
+		"data":    response,
+	})
+}
@@ -0,0 +1,139 @@
+package rest
+
+import (
+	"boonkosang/internal/domain/models"
+	"boonkosang/internal/requests"
+	"boonkosang/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type QuotationShareHandler struct {
+	shareUsecase usecase.QuotationShareUsecase
+}
+
+func NewQuotationShareHandler(shareUsecase usecase.QuotationShareUsecase) *QuotationShareHandler {
+	return &QuotationShareHandler{
+		shareUsecase: shareUsecase,
+	}
+}
+
+func (h *QuotationShareHandler) QuotationShareRoutes(app *fiber.App) {
+	app.Post("/quotations/projects/:projectId/share", h.CreateShareLink)
+
+	// Public routes used by the client, authorised by the share token only
+	public := app.Group("/public/quotations")
+	public.Get("/:token", h.GetSharedQuotation)
+	public.Post("/:token/accept", h.AcceptQuotation)
+	public.Post("/:token/reject", h.RejectQuotation)
+}
+
+func (h *QuotationShareHandler) CreateShareLink(c *fiber.Ctx) error {
+	projectID, err := uuid.Parse(c.Params("projectId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid project ID format",
+		})
+	}
+
+	var req requests.CreateQuotationShareRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid request body",
+			})
+		}
+	}
+
+	share, err := h.shareUsecase.CreateShareLink(c.Context(), projectID, req)
+	if err != nil {
+		switch err.Error() {
+		case "quotation not found":
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case "only approved quotations can be shared",
+			"expires_in_days must be between 1 and 90":
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to create share link",
+			})
+		}
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Share link created successfully",
+		"data":    share,
+	})
+}
+
+func (h *QuotationShareHandler) GetSharedQuotation(c *fiber.Ctx) error {
+	quotation, err := h.shareUsecase.GetSharedQuotation(c.Context(), c.Params("token"))
+	if err != nil {
+		if err == usecase.ErrInvalidShareLink {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve quotation",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Quotation retrieved successfully",
+		"data":    quotation,
+	})
+}
+
+func (h *QuotationShareHandler) AcceptQuotation(c *fiber.Ctx) error {
+	return h.respond(c, models.QuotationDecisionAccepted)
+}
+
+func (h *QuotationShareHandler) RejectQuotation(c *fiber.Ctx) error {
+	return h.respond(c, models.QuotationDecisionRejected)
+}
+
+func (h *QuotationShareHandler) respond(c *fiber.Ctx, decision models.QuotationDecision) error {
+	var req requests.RespondToQuotationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	response, err := h.shareUsecase.RespondToQuotation(c.Context(), c.Params("token"), decision, req, c.IP())
+	if err != nil {
+		switch err.Error() {
+		case usecase.ErrInvalidShareLink.Error():
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case "client name is required":
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case "quotation has already been responded to",
+			"quotation is no longer available",
+			"project must be in planning status to move to in_progress",
+			"BOQ must be approved",
+			"quotation must be approved":
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to record response",
+			})
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Quotation " + string(decision) + " successfully",
+		"data":    response,
+	})
+}
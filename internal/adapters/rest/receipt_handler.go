@@ -0,0 +1,111 @@
+package rest
+
+import (
+	"boonkosang/internal/usecase"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type ReceiptHandler struct {
+	receiptUsecase usecase.ReceiptUsecase
+}
+
+func NewReceiptHandler(receiptUsecase usecase.ReceiptUsecase) *ReceiptHandler {
+	return &ReceiptHandler{
+		receiptUsecase: receiptUsecase,
+	}
+}
+
+func (h *ReceiptHandler) ReceiptRoutes(app *fiber.App) {
+	app.Get("/invoice/:invoiceId/receipts", h.ListReceipts)
+
+	receipt := app.Group("/invoice/:invoiceId/payments/:paymentId/receipt")
+	receipt.Post("/", h.IssueReceipt)
+	receipt.Get("/", h.GetReceipt)
+}
+
+func (h *ReceiptHandler) IssueReceipt(c *fiber.Ctx) error {
+	invoiceID, paymentID, errMessage := parseReceiptParams(c)
+	if errMessage != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": errMessage,
+		})
+	}
+
+	receipt, err := h.receiptUsecase.IssueReceipt(c.Context(), invoiceID, paymentID)
+	if err != nil {
+		return errorResponse(c, err, "receipt already issued for this payment")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Receipt issued successfully",
+		"data":    receipt,
+	})
+}
+
+// GetReceipt returns the receipt of a payment. With format=pdf the receipt
+// is returned as a file download.
+func (h *ReceiptHandler) GetReceipt(c *fiber.Ctx) error {
+	invoiceID, paymentID, errMessage := parseReceiptParams(c)
+	if errMessage != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": errMessage,
+		})
+	}
+
+	if c.Query("format") == "pdf" {
+		file, err := h.receiptUsecase.RenderReceipt(c.Context(), invoiceID, paymentID)
+		if err != nil {
+			return errorResponse(c, err, "receipt already issued for this payment")
+		}
+
+		c.Set(fiber.HeaderContentType, file.ContentType)
+		c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", file.FileName))
+		return c.Send(file.Content)
+	}
+
+	receipt, err := h.receiptUsecase.GetReceipt(c.Context(), invoiceID, paymentID)
+	if err != nil {
+		return errorResponse(c, err, "receipt already issued for this payment")
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Receipt retrieved successfully",
+		"data":    receipt,
+	})
+}
+
+func (h *ReceiptHandler) ListReceipts(c *fiber.Ctx) error {
+	invoiceID, err := uuid.Parse(c.Params("invoiceId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid invoice ID",
+		})
+	}
+
+	receipts, err := h.receiptUsecase.ListReceipts(c.Context(), invoiceID)
+	if err != nil {
+		return errorResponse(c, err, "receipt already issued for this payment")
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Receipts retrieved successfully",
+		"data":    receipts,
+	})
+}
+
+func parseReceiptParams(c *fiber.Ctx) (uuid.UUID, uuid.UUID, string) {
+	invoiceID, err := uuid.Parse(c.Params("invoiceId"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, "Invalid invoice ID"
+	}
+
+	paymentID, err := uuid.Parse(c.Params("paymentId"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, "Invalid payment ID"
+	}
+
+	return invoiceID, paymentID, ""
+}
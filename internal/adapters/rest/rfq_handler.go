@@ -0,0 +1,200 @@
+package rest
+
+import (
+	"boonkosang/internal/requests"
+	"boonkosang/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type RFQHandler struct {
+	rfqUsecase usecase.RFQUsecase
+}
+
+func NewRFQHandler(rfqUsecase usecase.RFQUsecase) *RFQHandler {
+	return &RFQHandler{
+		rfqUsecase: rfqUsecase,
+	}
+}
+
+func (h *RFQHandler) RFQRoutes(app *fiber.App) {
+	projectRFQs := app.Group("/rfqs/:projectId")
+	projectRFQs.Post("/", h.CreateRFQ)
+	projectRFQs.Get("/", h.ListRFQs)
+
+	rfq := app.Group("/rfq/:rfqId")
+	rfq.Get("/", h.GetRFQ)
+	rfq.Put("/status", h.UpdateStatus)
+	rfq.Put("/quotes/:supplierId", h.RecordQuote)
+	rfq.Get("/comparison", h.GetComparison)
+	rfq.Post("/award", h.Award)
+}
+
+func (h *RFQHandler) CreateRFQ(c *fiber.Ctx) error {
+	projectID, err := uuid.Parse(c.Params("projectId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid project ID",
+		})
+	}
+
+	var req requests.CreateRFQRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	rfq, err := h.rfqUsecase.CreateRFQ(c.Context(), projectID, req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "RFQ created successfully",
+		"data":    rfq,
+	})
+}
+
+func (h *RFQHandler) ListRFQs(c *fiber.Ctx) error {
+	projectID, err := uuid.Parse(c.Params("projectId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid project ID",
+		})
+	}
+
+	rfqs, err := h.rfqUsecase.ListRFQs(c.Context(), projectID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "RFQs retrieved successfully",
+		"data":    rfqs,
+	})
+}
+
+func (h *RFQHandler) GetRFQ(c *fiber.Ctx) error {
+	rfqID, err := uuid.Parse(c.Params("rfqId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid RFQ ID",
+		})
+	}
+
+	rfq, err := h.rfqUsecase.GetRFQ(c.Context(), rfqID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "RFQ retrieved successfully",
+		"data":    rfq,
+	})
+}
+
+func (h *RFQHandler) UpdateStatus(c *fiber.Ctx) error {
+	rfqID, err := uuid.Parse(c.Params("rfqId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid RFQ ID",
+		})
+	}
+
+	var req requests.UpdateRFQStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	rfq, err := h.rfqUsecase.UpdateStatus(c.Context(), rfqID, req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "RFQ status updated successfully",
+		"data":    rfq,
+	})
+}
+
+func (h *RFQHandler) RecordQuote(c *fiber.Ctx) error {
+	rfqID, err := uuid.Parse(c.Params("rfqId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid RFQ ID",
+		})
+	}
+
+	supplierID, err := uuid.Parse(c.Params("supplierId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid supplier ID",
+		})
+	}
+
+	var req requests.RecordRFQQuoteRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	comparison, err := h.rfqUsecase.RecordQuote(c.Context(), rfqID, supplierID, req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Quote recorded successfully",
+		"data":    comparison,
+	})
+}
+
+func (h *RFQHandler) GetComparison(c *fiber.Ctx) error {
+	rfqID, err := uuid.Parse(c.Params("rfqId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid RFQ ID",
+		})
+	}
+
+	comparison, err := h.rfqUsecase.GetComparison(c.Context(), rfqID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Quote comparison retrieved successfully",
+		"data":    comparison,
+	})
+}
+
+func (h *RFQHandler) Award(c *fiber.Ctx) error {
+	rfqID, err := uuid.Parse(c.Params("rfqId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid RFQ ID",
+		})
+	}
+
+	var req requests.AwardRFQRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	award, err := h.rfqUsecase.Award(c.Context(), rfqID, req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "RFQ awarded successfully",
+		"data":    award,
+	})
+}
@@ -0,0 +1,172 @@
+package rest
+
+import (
+	"boonkosang/internal/requests"
+	"boonkosang/internal/usecase"
+	"io"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type SupplierPriceHandler struct {
+	priceUsecase usecase.SupplierPriceUsecase
+}
+
+func NewSupplierPriceHandler(priceUsecase usecase.SupplierPriceUsecase) *SupplierPriceHandler {
+	return &SupplierPriceHandler{
+		priceUsecase: priceUsecase,
+	}
+}
+
+func (h *SupplierPriceHandler) SupplierPriceRoutes(app *fiber.App) {
+	supplierPrices := app.Group("/suppliers/:supplierId/prices")
+	supplierPrices.Post("/", h.CreatePrice)
+	supplierPrices.Get("/", h.ListPrices)
+	supplierPrices.Post("/import", h.ImportPrices)
+
+	prices := app.Group("/supplier-prices")
+	prices.Get("/best", h.GetBestPrices)
+	prices.Put("/:priceId", h.UpdatePrice)
+	prices.Delete("/:priceId", h.DeletePrice)
+}
+
+func (h *SupplierPriceHandler) CreatePrice(c *fiber.Ctx) error {
+	supplierID, err := uuid.Parse(c.Params("supplierId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid supplier ID",
+		})
+	}
+
+	var req requests.SupplierPriceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	price, err := h.priceUsecase.CreatePrice(c.Context(), supplierID, req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Supplier price created successfully",
+		"data":    price,
+	})
+}
+
+func (h *SupplierPriceHandler) ListPrices(c *fiber.Ctx) error {
+	supplierID, err := uuid.Parse(c.Params("supplierId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid supplier ID",
+		})
+	}
+
+	prices, err := h.priceUsecase.ListPrices(c.Context(), supplierID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Supplier prices retrieved successfully",
+		"data":    prices,
+	})
+}
+
+// ImportPrices accepts the CSV price list as a multipart "file" field or as
+// the request body.
+func (h *SupplierPriceHandler) ImportPrices(c *fiber.Ctx) error {
+	supplierID, err := uuid.Parse(c.Params("supplierId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid supplier ID",
+		})
+	}
+
+	data := c.Body()
+	if file, err := c.FormFile("file"); err == nil {
+		f, err := file.Open()
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid price list file",
+			})
+		}
+		defer f.Close()
+
+		if data, err = io.ReadAll(f); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid price list file",
+			})
+		}
+	}
+
+	result, err := h.priceUsecase.ImportPrices(c.Context(), supplierID, data)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Supplier prices imported successfully",
+		"data":    result,
+	})
+}
+
+func (h *SupplierPriceHandler) UpdatePrice(c *fiber.Ctx) error {
+	priceID, err := uuid.Parse(c.Params("priceId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid price ID",
+		})
+	}
+
+	var req requests.SupplierPriceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	price, err := h.priceUsecase.UpdatePrice(c.Context(), priceID, req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Supplier price updated successfully",
+		"data":    price,
+	})
+}
+
+func (h *SupplierPriceHandler) DeletePrice(c *fiber.Ctx) error {
+	priceID, err := uuid.Parse(c.Params("priceId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid price ID",
+		})
+	}
+
+	if err := h.priceUsecase.DeletePrice(c.Context(), priceID); err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Supplier price deleted successfully",
+	})
+}
+
+func (h *SupplierPriceHandler) GetBestPrices(c *fiber.Ctx) error {
+	quantity := c.QueryFloat("quantity", 0)
+
+	prices, err := h.priceUsecase.GetBestPrices(c.Context(), c.Query("material_id"), c.Query("date"), quantity)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Best supplier prices retrieved successfully",
+		"data":    prices,
+	})
+}
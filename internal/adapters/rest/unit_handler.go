@@ -0,0 +1,103 @@
+package rest
+
+import (
+	"boonkosang/internal/requests"
+	"boonkosang/internal/usecase"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type UnitHandler struct {
+	unitUsecase usecase.UnitUsecase
+}
+
+func NewUnitHandler(unitUsecase usecase.UnitUsecase) *UnitHandler {
+	return &UnitHandler{
+		unitUsecase: unitUsecase,
+	}
+}
+
+func (h *UnitHandler) UnitRoutes(app *fiber.App) {
+	units := app.Group("/units")
+	units.Get("/", h.ListUnits)
+	units.Get("/convert", h.Convert)
+	units.Get("/conversions", h.ListConversions)
+	units.Post("/conversions", h.CreateConversion)
+	units.Delete("/conversions/:conversionId", h.DeleteConversion)
+}
+
+func (h *UnitHandler) ListUnits(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"message": "Units retrieved successfully",
+		"data":    h.unitUsecase.ListUnits(),
+	})
+}
+
+func (h *UnitHandler) Convert(c *fiber.Ctx) error {
+	quantity, err := strconv.ParseFloat(c.Query("quantity", "1"), 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid quantity",
+		})
+	}
+
+	result, err := h.unitUsecase.Convert(c.Context(), c.Query("material_id"), quantity, c.Query("from"), c.Query("to"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Quantity converted successfully",
+		"data":    result,
+	})
+}
+
+func (h *UnitHandler) ListConversions(c *fiber.Ctx) error {
+	conversions, err := h.unitUsecase.ListConversions(c.Context(), c.Query("material_id"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Unit conversions retrieved successfully",
+		"data":    conversions,
+	})
+}
+
+func (h *UnitHandler) CreateConversion(c *fiber.Ctx) error {
+	var req requests.CreateUnitConversionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	conversion, err := h.unitUsecase.CreateConversion(c.Context(), req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Unit conversion created successfully",
+		"data":    conversion,
+	})
+}
+
+func (h *UnitHandler) DeleteConversion(c *fiber.Ctx) error {
+	conversionID, err := uuid.Parse(c.Params("conversionId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid conversion ID",
+		})
+	}
+
+	if err := h.unitUsecase.DeleteConversion(c.Context(), conversionID); err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Unit conversion deleted successfully",
+	})
+}
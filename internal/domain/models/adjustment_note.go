@@ -0,0 +1,57 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AdjustmentNoteType string
+
+const (
+	// AdjustmentNoteTypeCredit lowers the invoice value (ใบลดหนี้)
+	AdjustmentNoteTypeCredit AdjustmentNoteType = "credit"
+	// AdjustmentNoteTypeDebit raises the invoice value (ใบเพิ่มหนี้)
+	AdjustmentNoteTypeDebit AdjustmentNoteType = "debit"
+)
+
+func (t AdjustmentNoteType) IsValid() bool {
+	return t == AdjustmentNoteTypeCredit || t == AdjustmentNoteTypeDebit
+}
+
+// DocumentType returns the numbering sequence used by the note type.
+func (t AdjustmentNoteType) DocumentType() DocumentType {
+	if t == AdjustmentNoteTypeDebit {
+		return DocumentTypeDebitNote
+	}
+	return DocumentTypeCreditNote
+}
+
+// AdjustmentNote is a credit or debit note correcting an issued invoice. The
+// amounts are always positive; NoteType decides the direction. Notes are
+// voided, never deleted, so their numbers stay accounted for.
+type AdjustmentNote struct {
+	NoteID        uuid.UUID          `db:"note_id"`
+	NoteNumber    string             `db:"note_number"`
+	NoteType      AdjustmentNoteType `db:"note_type"`
+	InvoiceID     uuid.UUID          `db:"invoice_id"`
+	ProjectID     uuid.UUID          `db:"project_id"`
+	NoteDate      time.Time          `db:"note_date"`
+	Reason        string             `db:"reason"`
+	SubTotal      float64            `db:"sub_total"`
+	TaxPercentage float64            `db:"tax_percentage"`
+	TaxAmount     float64            `db:"tax_amount"`
+	TotalAmount   float64            `db:"total_amount"`
+	VoidedAt      sql.NullTime       `db:"voided_at"`
+	VoidReason    sql.NullString     `db:"void_reason"`
+	CreatedAt     time.Time          `db:"created_at"`
+}
+
+// SignedTotal returns the change the note makes to the invoice total.
+func (n *AdjustmentNote) SignedTotal() float64 {
+	if n.NoteType == AdjustmentNoteTypeCredit {
+		return -n.TotalAmount
+	}
+	return n.TotalAmount
+}
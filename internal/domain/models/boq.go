@@ -40,6 +40,8 @@ type BOQMaterialDetails struct {
 	JobName        string          `db:"name"`
 	MaterialName   string          `db:"material_name"`
 	Quantity       sql.NullFloat64 `db:"quantity"` // Changed to handle NULL
+	WastePercent   float64         `db:"waste_percent"`
+	GrossQuantity  sql.NullFloat64 `db:"gross_quantity"`
 	Unit           string          `db:"unit"`
 	EstimatedPrice sql.NullFloat64 `db:"estimated_price"` // Changed to handle NULL
 	Total          sql.NullFloat64 `db:"total"`           // Changed to handle NULL
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"errors"
+	"strings"
+)
+
+// HeadOfficeBranch is the Revenue Department branch code for a head office.
+const HeadOfficeBranch = "00000"
+
+// NormalizeBranch returns the 5-digit branch code used on tax documents,
+// defaulting an empty code to the head office.
+func NormalizeBranch(code string) (string, error) {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return HeadOfficeBranch, nil
+	}
+	if len(code) != 5 {
+		return "", errors.New("branch code must be 5 digits")
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return "", errors.New("branch code must be 5 digits")
+		}
+	}
+	return code, nil
+}
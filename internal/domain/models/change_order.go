@@ -0,0 +1,78 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ChangeOrderStatus string
+
+const (
+	ChangeOrderStatusDraft     ChangeOrderStatus = "draft"
+	ChangeOrderStatusSubmitted ChangeOrderStatus = "submitted"
+	ChangeOrderStatusApproved  ChangeOrderStatus = "approved"
+	ChangeOrderStatusRejected  ChangeOrderStatus = "rejected"
+)
+
+type ChangeType string
+
+const (
+	ChangeTypeAdd    ChangeType = "add"
+	ChangeTypeRemove ChangeType = "remove"
+	ChangeTypeModify ChangeType = "modify"
+)
+
+// ChangeOrder is a variation to an approved contract. The original contract
+// and quotation are never modified; an approved change order adjusts the BOQ
+// scope and payment periods and records the values it replaced.
+type ChangeOrder struct {
+	ChangeOrderID     uuid.UUID         `db:"change_order_id"`
+	ContractID        uuid.UUID         `db:"contract_id"`
+	ProjectID         uuid.UUID         `db:"project_id"`
+	ChangeOrderNumber int               `db:"change_order_number"`
+	Title             string            `db:"title"`
+	Reason            sql.NullString    `db:"reason"`
+	Status            ChangeOrderStatus `db:"status"`
+	TaxPercentage     float64           `db:"tax_percentage"`
+	ValueChange       float64           `db:"value_change"`
+	RejectReason      sql.NullString    `db:"reject_reason"`
+	SubmittedAt       sql.NullTime      `db:"submitted_at"`
+	ApprovedAt        sql.NullTime      `db:"approved_at"`
+	RejectedAt        sql.NullTime      `db:"rejected_at"`
+	CreatedAt         time.Time         `db:"created_at"`
+	UpdatedAt         sql.NullTime      `db:"updated_at"`
+
+	// Related data (not stored in database)
+	Items   []ChangeOrderItem   `db:"-"`
+	Periods []ChangeOrderPeriod `db:"-"`
+}
+
+// ChangeOrderItem is a change to a single BOQ job. Original values are the
+// BOQ values at the time the change order was drafted.
+type ChangeOrderItem struct {
+	ItemID               uuid.UUID  `db:"item_id"`
+	ChangeOrderID        uuid.UUID  `db:"change_order_id"`
+	JobID                uuid.UUID  `db:"job_id"`
+	JobName              string     `db:"job_name"`
+	ChangeType           ChangeType `db:"change_type"`
+	OriginalQuantity     float64    `db:"original_quantity"`
+	NewQuantity          float64    `db:"new_quantity"`
+	OriginalSellingPrice float64    `db:"original_selling_price"`
+	NewSellingPrice      float64    `db:"new_selling_price"`
+	LaborCost            float64    `db:"labor_cost"`
+	AmountChange         float64    `db:"amount_change"`
+}
+
+// ChangeOrderPeriod adjusts an existing payment period (PeriodID set) or adds
+// a new one. OriginalAmount keeps the period amount before the adjustment.
+type ChangeOrderPeriod struct {
+	ChangeOrderPeriodID uuid.UUID     `db:"change_order_period_id"`
+	ChangeOrderID       uuid.UUID     `db:"change_order_id"`
+	PeriodID            uuid.NullUUID `db:"period_id"`
+	PeriodNumber        int           `db:"period_number"`
+	DeliveredWithin     int           `db:"delivered_within"`
+	OriginalAmount      float64       `db:"original_amount"`
+	AmountChange        float64       `db:"amount_change"`
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"database/sql"
 	"encoding/json"
 
 	"github.com/google/uuid"
@@ -13,4 +14,9 @@ type Client struct {
 	Tel      string          `db:"tel"`
 	Address  json.RawMessage `db:"address"`
 	TaxID    string          `db:"tax_id"`
+	Branch   string          `db:"branch"`
+
+	// WithholdingTaxPercentage is NULL for clients created before the
+	// column existed; those default to no withholding.
+	WithholdingTaxPercentage sql.NullFloat64 `db:"withholding_tax_percentage"`
 }
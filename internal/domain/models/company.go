@@ -13,4 +13,5 @@ type Company struct {
 	Tel       string          `db:"tel" json:"tel" validate:"required,len=10"`
 	Address   json.RawMessage `db:"address" json:"address"`
 	TaxID     string          `db:"tax_id" json:"tax_id" validate:"required,len=13,numeric"`
+	Branch    string          `db:"branch" json:"branch" validate:"omitempty,len=5,numeric"`
 }
@@ -37,6 +37,7 @@ func (a *StringArray) Scan(value interface{}) error {
 
 type Contract struct {
 	ContractID          uuid.UUID       `db:"contract_id"`
+	ContractNumber      sql.NullString  `db:"contract_number"`
 	ProjectID           uuid.UUID       `db:"project_id"`
 	ProjectDescription  sql.NullString  `db:"project_description"`
 	AreaSize            sql.NullFloat64 `db:"area_size"`
@@ -57,6 +58,16 @@ type Contract struct {
 	UpdatedAt           sql.NullTime    `db:"updated_at"`
 	Periods             []Period        `db:"-"`
 }
+
+// CurrentStatus returns the contract status, treating contracts created
+// before statuses were tracked as drafts.
+func (c *Contract) CurrentStatus() ContractStatus {
+	if !c.Status.Valid || c.Status.String == "" {
+		return ContractStatusDraft
+	}
+	return ContractStatus(c.Status.String)
+}
+
 type JobPeriod struct {
 	JobID     uuid.UUID `db:"job_id"`
 	PeriodID  uuid.UUID `db:"period_id"`
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ContractStatus string
+
+const (
+	ContractStatusDraft            ContractStatus = "draft"
+	ContractStatusPendingSignature ContractStatus = "pending_signature"
+	ContractStatusApproved         ContractStatus = "approved"
+	ContractStatusSigned           ContractStatus = "signed"
+	ContractStatusActive           ContractStatus = "active"
+	ContractStatusCompleted        ContractStatus = "completed"
+	ContractStatusTerminated       ContractStatus = "terminated"
+)
+
+// contractTransitions lists the statuses a contract may move to from each
+// status. Draft contracts can still be approved directly without a separate
+// signature round.
+var contractTransitions = map[ContractStatus][]ContractStatus{
+	ContractStatusDraft:            {ContractStatusPendingSignature, ContractStatusApproved},
+	ContractStatusPendingSignature: {ContractStatusDraft, ContractStatusApproved, ContractStatusSigned},
+	ContractStatusApproved:         {ContractStatusSigned, ContractStatusActive, ContractStatusTerminated},
+	ContractStatusSigned:           {ContractStatusActive, ContractStatusTerminated},
+	ContractStatusActive:           {ContractStatusCompleted, ContractStatusTerminated},
+}
+
+func (s ContractStatus) IsValid() bool {
+	switch s {
+	case ContractStatusDraft, ContractStatusPendingSignature, ContractStatusApproved,
+		ContractStatusSigned, ContractStatusActive, ContractStatusCompleted, ContractStatusTerminated:
+		return true
+	}
+	return false
+}
+
+func (s ContractStatus) CanTransitionTo(next ContractStatus) bool {
+	for _, allowed := range contractTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsEditable reports whether the contract terms and payment periods can
+// still be changed. A contract sent for signature has to go back to draft to
+// be edited, and once approved its periods are invoiced and can only be
+// adjusted through change orders.
+func (s ContractStatus) IsEditable() bool {
+	return s == ContractStatusDraft
+}
+
+// IsBinding reports whether the contract has been agreed and is not yet closed.
+func (s ContractStatus) IsBinding() bool {
+	return s == ContractStatusApproved || s == ContractStatusSigned || s == ContractStatusActive
+}
+
+type TerminationReason string
+
+const (
+	TerminationReasonClientBreach     TerminationReason = "client_breach"
+	TerminationReasonContractorBreach TerminationReason = "contractor_breach"
+	TerminationReasonMutualAgreement  TerminationReason = "mutual_agreement"
+	TerminationReasonForceMajeure     TerminationReason = "force_majeure"
+	TerminationReasonOther            TerminationReason = "other"
+)
+
+func (r TerminationReason) IsValid() bool {
+	switch r {
+	case TerminationReasonClientBreach, TerminationReasonContractorBreach,
+		TerminationReasonMutualAgreement, TerminationReasonForceMajeure, TerminationReasonOther:
+		return true
+	}
+	return false
+}
+
+type ContractStatusHistory struct {
+	HistoryID         uuid.UUID      `db:"history_id"`
+	ContractID        uuid.UUID      `db:"contract_id"`
+	FromStatus        ContractStatus `db:"from_status"`
+	ToStatus          ContractStatus `db:"to_status"`
+	TerminationReason sql.NullString `db:"termination_reason"`
+	Remarks           sql.NullString `db:"remarks"`
+	ChangedAt         time.Time      `db:"changed_at"`
+}
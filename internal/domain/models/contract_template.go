@@ -0,0 +1,33 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ClauseType string
+
+const (
+	ClauseTypeForceMajeure        ClauseType = "force_majeure"
+	ClauseTypeBreachOfContract    ClauseType = "breach_of_contract"
+	ClauseTypeEndOfContract       ClauseType = "end_of_contract"
+	ClauseTypeTerminationContract ClauseType = "termination_of_contract"
+	ClauseTypeAmendment           ClauseType = "amendment"
+	ClauseTypeCustom              ClauseType = "custom"
+)
+
+// ContractClauseTemplate is a reusable clause in a company's library. The body
+// may contain placeholders such as {{client_name}} or {{period_table}} that are
+// filled in when a contract document is rendered.
+type ContractClauseTemplate struct {
+	TemplateID uuid.UUID    `db:"template_id"`
+	CompanyID  uuid.UUID    `db:"company_id"`
+	ClauseType ClauseType   `db:"clause_type"`
+	Title      string       `db:"title"`
+	Body       string       `db:"body"`
+	SortOrder  int          `db:"sort_order"`
+	CreatedAt  time.Time    `db:"created_at"`
+	UpdatedAt  sql.NullTime `db:"updated_at"`
+}
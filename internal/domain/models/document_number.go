@@ -0,0 +1,127 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DocumentType string
+
+const (
+	DocumentTypeQuotation  DocumentType = "quotation"
+	DocumentTypeContract   DocumentType = "contract"
+	DocumentTypeInvoice    DocumentType = "invoice"
+	DocumentTypeReceipt    DocumentType = "receipt"
+	DocumentTypeCreditNote DocumentType = "credit_note"
+	DocumentTypeDebitNote  DocumentType = "debit_note"
+
+	DocumentTypePurchaseOrder       DocumentType = "purchase_order"
+	DocumentTypeRequestForQuotation DocumentType = "rfq"
+)
+
+// DefaultDocumentNumberPatterns are used when a company has not configured its own pattern.
+var DefaultDocumentNumberPatterns = map[DocumentType]string{
+	DocumentTypeQuotation:  "QT-{YYYY}-{0000}",
+	DocumentTypeContract:   "CT-{YYYY}-{0000}",
+	DocumentTypeInvoice:    "INV-{YY}{MM}-{000}",
+	DocumentTypeReceipt:    "RC-{YY}{MM}-{000}",
+	DocumentTypeCreditNote: "CN-{YY}{MM}-{000}",
+	DocumentTypeDebitNote:  "DN-{YY}{MM}-{000}",
+
+	DocumentTypePurchaseOrder:       "PO-{YY}{MM}-{000}",
+	DocumentTypeRequestForQuotation: "RFQ-{YY}{MM}-{000}",
+}
+
+type DocumentNumberFormat struct {
+	CompanyID    uuid.UUID    `db:"company_id"`
+	DocumentType DocumentType `db:"document_type"`
+	Pattern      string       `db:"pattern"`
+	CreatedAt    time.Time    `db:"created_at"`
+	UpdatedAt    *time.Time   `db:"updated_at"`
+}
+
+// DocumentSequence is the last number issued for a document type in a year.
+// FiscalYear is the calendar year of the document date, or 0 for a pattern
+// that never restarts (see DocumentSequenceYear).
+type DocumentSequence struct {
+	CompanyID    uuid.UUID    `db:"company_id"`
+	DocumentType DocumentType `db:"document_type"`
+	FiscalYear   int          `db:"fiscal_year"`
+	LastNumber   int          `db:"last_number"`
+}
+
+// DocumentSequenceYear returns the year whose sequence numbers a document
+// dated date. Sequences follow the calendar year, so numbering restarts on
+// 1 January regardless of the company's accounting year. A pattern without a
+// year token would repeat its numbers after a restart, so its sequence never
+// restarts and the year is 0.
+func DocumentSequenceYear(pattern string, date time.Time) int {
+	if !hasYearToken(pattern) {
+		return 0
+	}
+	return date.Year()
+}
+
+var documentNumberToken = regexp.MustCompile(`\{([^}]*)\}`)
+
+func hasYearToken(pattern string) bool {
+	for _, match := range documentNumberToken.FindAllStringSubmatch(pattern, -1) {
+		if match[1] == "YYYY" || match[1] == "YY" {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateDocumentNumberPattern checks that a pattern only uses the supported
+// tokens ({YYYY}, {YY}, {MM} and a zero-padded counter such as {0000}),
+// contains exactly one counter and contains a year, since the counter
+// restarts every year.
+func ValidateDocumentNumberPattern(pattern string) error {
+	if strings.TrimSpace(pattern) == "" {
+		return errors.New("pattern is required")
+	}
+
+	counters := 0
+	for _, match := range documentNumberToken.FindAllStringSubmatch(pattern, -1) {
+		token := match[1]
+		switch {
+		case token == "YYYY", token == "YY", token == "MM":
+		case token != "" && strings.Trim(token, "0") == "":
+			counters++
+		default:
+			return fmt.Errorf("unsupported pattern token {%s}", token)
+		}
+	}
+
+	if counters != 1 {
+		return errors.New("pattern must contain exactly one counter token such as {0000}")
+	}
+	if !hasYearToken(pattern) {
+		return errors.New("pattern must contain a year token {YYYY} or {YY}")
+	}
+
+	return nil
+}
+
+// FormatDocumentNumber renders a pattern for the given document date and sequence number.
+func FormatDocumentNumber(pattern string, date time.Time, sequence int) string {
+	return documentNumberToken.ReplaceAllStringFunc(pattern, func(match string) string {
+		token := match[1 : len(match)-1]
+		switch token {
+		case "YYYY":
+			return fmt.Sprintf("%04d", date.Year())
+		case "YY":
+			return fmt.Sprintf("%02d", date.Year()%100)
+		case "MM":
+			return fmt.Sprintf("%02d", int(date.Month()))
+		default:
+			return fmt.Sprintf("%0*d", len(token), sequence)
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package models_test
+
+import (
+	"boonkosang/internal/domain/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateDocumentNumberPattern(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		wantErr string
+	}{
+		{name: "full year", pattern: "QT-{YYYY}-{0000}"},
+		{name: "short year and month", pattern: "INV-{YY}{MM}-{000}"},
+		{name: "year after the counter", pattern: "{00000}/{YYYY}"},
+		{name: "empty", pattern: "  ", wantErr: "pattern is required"},
+		{name: "unsupported token", pattern: "QT-{YYYY}-{DD}-{000}", wantErr: "unsupported pattern token {DD}"},
+		{name: "empty token", pattern: "QT-{YYYY}-{}-{000}", wantErr: "unsupported pattern token {}"},
+		{name: "no counter", pattern: "QT-{YYYY}", wantErr: "pattern must contain exactly one counter token such as {0000}"},
+		{name: "two counters", pattern: "QT-{YYYY}-{00}-{000}", wantErr: "pattern must contain exactly one counter token such as {0000}"},
+		{name: "no year", pattern: "INV-{0000}", wantErr: "pattern must contain a year token {YYYY} or {YY}"},
+		{name: "month without a year", pattern: "INV-{MM}-{0000}", wantErr: "pattern must contain a year token {YYYY} or {YY}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := models.ValidateDocumentNumberPattern(tt.pattern)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+
+	for documentType, pattern := range models.DefaultDocumentNumberPatterns {
+		assert.NoError(t, models.ValidateDocumentNumberPattern(pattern), "default %s pattern", documentType)
+	}
+}
+
+func TestFormatDocumentNumber(t *testing.T) {
+	date := time.Date(2026, time.January, 5, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		pattern  string
+		sequence int
+		want     string
+	}{
+		{name: "full year", pattern: "QT-{YYYY}-{0000}", sequence: 12, want: "QT-2026-0012"},
+		{name: "short year and month", pattern: "INV-{YY}{MM}-{000}", sequence: 7, want: "INV-2601-007"},
+		{name: "counter wider than its padding", pattern: "RC-{YY}-{00}", sequence: 1234, want: "RC-26-1234"},
+		{name: "text around tokens", pattern: "{YYYY}/{0000} (copy)", sequence: 1, want: "2026/0001 (copy)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, models.FormatDocumentNumber(tt.pattern, date, tt.sequence))
+		})
+	}
+}
+
+func TestDocumentSequenceYear(t *testing.T) {
+	date := time.Date(2026, time.December, 31, 23, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 2026, models.DocumentSequenceYear("QT-{YYYY}-{0000}", date))
+	assert.Equal(t, 2026, models.DocumentSequenceYear("INV-{YY}{MM}-{000}", date))
+	assert.Equal(t, 0, models.DocumentSequenceYear("INV-{0000}", date))
+}
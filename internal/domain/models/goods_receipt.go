@@ -0,0 +1,43 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GoodsReceipt records one delivery of materials against a purchase order.
+type GoodsReceipt struct {
+	GRID         uuid.UUID      `db:"gr_id"`
+	POID         uuid.UUID      `db:"po_id"`
+	ReceivedDate time.Time      `db:"received_date"`
+	ReceivedBy   string         `db:"received_by"`
+	Notes        sql.NullString `db:"notes"`
+	CreatedAt    time.Time      `db:"created_at"`
+
+	// Related data (not stored in database)
+	Lines []GoodsReceiptLine `db:"-"`
+}
+
+// GoodsReceiptLine is the quantity of a purchase order line delivered in a
+// goods receipt. Rejected goods are part of QuantityReceived but are not
+// accepted into the order's received quantity.
+type GoodsReceiptLine struct {
+	GRLineID         uuid.UUID      `db:"gr_line_id"`
+	GRID             uuid.UUID      `db:"gr_id"`
+	POLineID         uuid.UUID      `db:"po_line_id"`
+	QuantityReceived float64        `db:"quantity_received"`
+	QuantityRejected float64        `db:"quantity_rejected"`
+	Notes            sql.NullString `db:"notes"`
+
+	// Related data (joined, not stored in goods_receipt_line)
+	MaterialID   string `db:"material_id"`
+	MaterialName string `db:"material_name"`
+	Unit         string `db:"unit"`
+}
+
+// AcceptedQuantity is the delivered quantity that was not rejected.
+func (l *GoodsReceiptLine) AcceptedQuantity() float64 {
+	return l.QuantityReceived - l.QuantityRejected
+}
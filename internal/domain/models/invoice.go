@@ -7,8 +7,14 @@ import (
 	"github.com/google/uuid"
 )
 
+const (
+	InvoiceTypePeriod           = "period"
+	InvoiceTypeRetentionRelease = "retention_release"
+)
+
 type Invoice struct {
 	InvoiceID      uuid.UUID       `db:"invoice_id"`
+	InvoiceNumber  sql.NullString  `db:"invoice_number"`
 	ProjectID      uuid.UUID       `db:"project_id"`
 	PeriodID       uuid.UUID       `db:"period_id"`
 	InvoiceDate    sql.NullTime    `db:"invoice_date"`
@@ -21,8 +27,117 @@ type Invoice struct {
 	UpdatedAt      sql.NullTime    `db:"updated_at"`
 	Retention      sql.NullFloat64 `db:"retention"`
 
+	// InvoiceType is InvoiceTypePeriod for contract period invoices. Other
+	// invoice types have no period and bill a fixed Amount instead. The
+	// invoice_type column defaults to 'period' and is NULL on invoices created
+	// before it existed; use Type to read it.
+	InvoiceType sql.NullString  `db:"invoice_type"`
+	Amount      sql.NullFloat64 `db:"amount"`
+
+	// PaidAmount is the sum of payments that have not been reversed.
+	PaidAmount float64 `db:"paid_amount"`
+
+	// AdjustmentAmount is the VAT-inclusive total of debit notes less credit
+	// notes issued against the invoice, excluding voided notes.
+	AdjustmentAmount float64 `db:"adjustment_amount"`
+
+	// WithholdingTaxPercentage overrides the quotation or client rate when set.
+	WithholdingTaxPercentage sql.NullFloat64 `db:"withholding_tax_percentage"`
+
 	// Related data (not stored in database)
-	Period Period `db:"-"`
+	Period                 Period                     `db:"-"`
+	TaxSettings            InvoiceTaxSettings         `db:"-"`
+	WithholdingCertificate *WithholdingTaxCertificate `db:"-"`
+}
+
+// InvoiceBalanceUpdate works out the status change of an invoice whose
+// payments or credit/debit notes changed. The repository calls it inside the
+// transaction with the invoice row locked and its PaidAmount and
+// AdjustmentAmount recalculated, so it sees every committed change. It may set
+// the invoice Retention, which is stored with the new balance. An error rolls
+// the whole change back.
+type InvoiceBalanceUpdate func(invoice *Invoice) (*InvoiceStatusHistory, sql.NullTime, error)
+
+// Type returns the invoice type, treating invoices without one as period
+// invoices.
+func (i *Invoice) Type() string {
+	if !i.InvoiceType.Valid || i.InvoiceType.String == "" {
+		return InvoiceTypePeriod
+	}
+	return i.InvoiceType.String
+}
+
+// CurrentStatus returns the invoice status, treating invoices without a status
+// as drafts and invoices approved before the issued status existed as issued.
+func (i *Invoice) CurrentStatus() InvoiceStatus {
+	switch {
+	case !i.Status.Valid || i.Status.String == "":
+		return InvoiceStatusDraft
+	case i.Status.String == "approved":
+		return InvoiceStatusIssued
+	}
+	return InvoiceStatus(i.Status.String)
+}
+
+// IsOpen reports whether the invoice has been issued and still has money
+// outstanding.
+func (i *Invoice) IsOpen() bool {
+	status := i.CurrentStatus()
+	return status == InvoiceStatusIssued || status == InvoiceStatusPartiallyPaid
+}
+
+// DaysOverdue returns how many days an open invoice is past its payment due
+// date on asOf, or 0 when it is not overdue.
+func (i *Invoice) DaysOverdue(asOf time.Time) int {
+	if !i.IsOpen() || !i.PaymentDueDate.Valid {
+		return 0
+	}
+
+	due := i.PaymentDueDate.Time
+	dueDate := time.Date(due.Year(), due.Month(), due.Day(), 0, 0, 0, 0, time.UTC)
+	asOfDate := time.Date(asOf.Year(), asOf.Month(), asOf.Day(), 0, 0, 0, 0, time.UTC)
+	if !asOfDate.After(dueDate) {
+		return 0
+	}
+	return int(asOfDate.Sub(dueDate).Hours() / 24)
+}
+
+// ReceivableInvoice is an open invoice together with the project and client
+// it is billed to, as used by the receivables reports.
+type ReceivableInvoice struct {
+	Invoice
+	ProjectName  string          `db:"project_name"`
+	ClientID     uuid.NullUUID   `db:"client_id"`
+	ClientName   sql.NullString  `db:"client_name"`
+	PeriodNumber sql.NullInt32   `db:"period_number"`
+	AmountPeriod sql.NullFloat64 `db:"amount_period"`
+
+	// PaidAsOf and AdjustmentAsOf are the payments and credit/debit notes
+	// recorded up to the report date.
+	PaidAsOf       float64 `db:"paid_as_of"`
+	AdjustmentAsOf float64 `db:"adjustment_as_of"`
+}
+
+// InvoiceTaxSettings holds the VAT and withholding tax rates inherited from
+// the project's quotation and client.
+type InvoiceTaxSettings struct {
+	TaxPercentage           float64         `db:"tax_percentage"`
+	QuotationWithholdingTax sql.NullFloat64 `db:"quotation_withholding_tax"`
+	ClientWithholdingTax    float64         `db:"client_withholding_tax"`
+}
+
+// WithholdingTaxCertificate records the withholding tax certificate (50 ทวิ)
+// received from the client for a paid invoice.
+type WithholdingTaxCertificate struct {
+	CertificateID     uuid.UUID      `db:"certificate_id"`
+	InvoiceID         uuid.UUID      `db:"invoice_id"`
+	CertificateNumber string         `db:"certificate_number"`
+	CertificateDate   time.Time      `db:"certificate_date"`
+	ReceivedDate      time.Time      `db:"received_date"`
+	Amount            float64        `db:"amount"`
+	Remarks           sql.NullString `db:"remarks"`
+	CreatedAt         time.Time      `db:"created_at"`
+	UpdatedAt         sql.NullTime   `db:"updated_at"`
 }
 
 // PeriodInvoiceStatus provides status information about a period's invoice
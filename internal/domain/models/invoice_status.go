@@ -0,0 +1,70 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type InvoiceStatus string
+
+const (
+	InvoiceStatusDraft         InvoiceStatus = "draft"
+	InvoiceStatusIssued        InvoiceStatus = "issued"
+	InvoiceStatusPartiallyPaid InvoiceStatus = "partially_paid"
+	InvoiceStatusPaid          InvoiceStatus = "paid"
+	InvoiceStatusVoid          InvoiceStatus = "void"
+	InvoiceStatusCanceled      InvoiceStatus = "canceled"
+)
+
+// invoiceTransitions lists the statuses an invoice may move to from each
+// status. A draft that was never sent is canceled, an issued invoice keeps its
+// number and is voided instead. Reversing payments moves a paid invoice back.
+var invoiceTransitions = map[InvoiceStatus][]InvoiceStatus{
+	InvoiceStatusDraft:         {InvoiceStatusIssued, InvoiceStatusCanceled},
+	InvoiceStatusIssued:        {InvoiceStatusPartiallyPaid, InvoiceStatusPaid, InvoiceStatusVoid},
+	InvoiceStatusPartiallyPaid: {InvoiceStatusPaid, InvoiceStatusIssued},
+	InvoiceStatusPaid:          {InvoiceStatusPartiallyPaid, InvoiceStatusIssued},
+}
+
+func (s InvoiceStatus) IsValid() bool {
+	switch s {
+	case InvoiceStatusDraft, InvoiceStatusIssued, InvoiceStatusPartiallyPaid,
+		InvoiceStatusPaid, InvoiceStatusVoid, InvoiceStatusCanceled:
+		return true
+	}
+	return false
+}
+
+func (s InvoiceStatus) CanTransitionTo(next InvoiceStatus) bool {
+	for _, allowed := range invoiceTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPaymentStatus reports whether the status follows from the payments
+// recorded against the invoice rather than being set by hand.
+func (s InvoiceStatus) IsPaymentStatus() bool {
+	return s == InvoiceStatusPartiallyPaid || s == InvoiceStatusPaid
+}
+
+// RequiresReason reports whether moving to the status needs a reason.
+func (s InvoiceStatus) RequiresReason() bool {
+	return s == InvoiceStatusVoid || s == InvoiceStatusCanceled
+}
+
+// InvoiceStatusHistory records a status change. ChangedBy is the name given
+// by the caller since requests are not authenticated.
+type InvoiceStatusHistory struct {
+	HistoryID  uuid.UUID      `db:"history_id"`
+	InvoiceID  uuid.UUID      `db:"invoice_id"`
+	FromStatus InvoiceStatus  `db:"from_status"`
+	ToStatus   InvoiceStatus  `db:"to_status"`
+	Reason     sql.NullString `db:"reason"`
+	ChangedBy  sql.NullString `db:"changed_by"`
+	ChangedAt  time.Time      `db:"changed_at"`
+}
@@ -1,21 +1,108 @@
 package models
 
-import "database/sql"
+import (
+	"database/sql"
+	"time"
 
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type MaterialCategory string
+
+const (
+	MaterialCategoryStructural MaterialCategory = "structural"
+	MaterialCategoryFinishing  MaterialCategory = "finishing"
+	MaterialCategoryElectrical MaterialCategory = "electrical"
+	MaterialCategoryPlumbing   MaterialCategory = "plumbing"
+	MaterialCategoryMechanical MaterialCategory = "mechanical"
+	MaterialCategoryRoofing    MaterialCategory = "roofing"
+	MaterialCategoryOther      MaterialCategory = "other"
+)
+
+// MaterialCategories lists the catalog categories in display order.
+var MaterialCategories = []MaterialCategory{
+	MaterialCategoryStructural,
+	MaterialCategoryFinishing,
+	MaterialCategoryElectrical,
+	MaterialCategoryPlumbing,
+	MaterialCategoryMechanical,
+	MaterialCategoryRoofing,
+	MaterialCategoryOther,
+}
+
+func (c MaterialCategory) IsValid() bool {
+	for _, category := range MaterialCategories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// Material is a catalog entry. Deprecated materials stay on the BOQs that
+// already use them but are hidden from the catalog and cannot be added to
+// jobs; ReplacedBy points to the material to use instead. WastePercent is
+// the default allowance for waste and offcuts added on top of the net
+// quantity a job needs.
 type Material struct {
-	MaterialID string `db:"material_id"`
-	Name       string `db:"name"`
-	Unit       string `db:"unit"`
+	MaterialID    string           `db:"material_id"`
+	Name          string           `db:"name"`
+	Unit          string           `db:"unit"`
+	Category      MaterialCategory `db:"category"`
+	Brand         sql.NullString   `db:"brand"`
+	Specification sql.NullString   `db:"specification"`
+	SKU           sql.NullString   `db:"sku"`
+	Tags          pq.StringArray   `db:"tags"`
+	WastePercent  float64          `db:"waste_percent"`
+	IsDeprecated  bool             `db:"is_deprecated"`
+	DeprecatedAt  sql.NullTime     `db:"deprecated_at"`
+	ReplacedBy    sql.NullString   `db:"replaced_by"`
+}
+
+// MaterialFilter narrows the material catalog. Search matches English words
+// in the name, brand and specification, or any text (including Thai) as a
+// substring of the name, brand, specification, SKU or tags. A zero Limit
+// returns every match.
+type MaterialFilter struct {
+	Search            string
+	Category          MaterialCategory
+	Tag               string
+	IncludeDeprecated bool
+	Limit             int
+	Offset            int
 }
 
 type MaterialPriceInfo struct {
 	MaterialID     string          `db:"material_id"`
 	Name           string          `db:"name"`
-	TotalQuantity  float64         `db:"qty_all_material_in_all_job"`
+	TotalQuantity  float64         `db:"qty_all_material_in_all_job"` // including waste
+	NetQuantity    float64         `db:"net_quantity"`
 	Unit           string          `db:"unit"`
 	EstimatedPrice sql.NullFloat64 `db:"estimated_price"`
 	AvgActualPrice sql.NullFloat64 `db:"avg_actual_price"`
 	ActualPrice    sql.NullFloat64 `db:"actual_price"`
 	SupplierID     sql.NullString  `db:"supplier_id"`
 	SupplierName   sql.NullString  `db:"supplier_name"`
+
+	// Quantities on purchase orders that are not cancelled.
+	OrderedQuantity  float64 `db:"ordered_quantity"`
+	ReceivedQuantity float64 `db:"received_quantity"`
+}
+
+// MaterialPricePoint is the estimated and actual price of a material on one
+// project's BOQ, dated by the last change to its price log.
+type MaterialPricePoint struct {
+	MaterialID     string          `db:"material_id"`
+	Name           string          `db:"name"`
+	Unit           string          `db:"unit"`
+	ProjectID      uuid.UUID       `db:"project_id"`
+	ProjectName    string          `db:"project_name"`
+	BOQID          uuid.UUID       `db:"boq_id"`
+	SupplierID     uuid.NullUUID   `db:"supplier_id"`
+	SupplierName   sql.NullString  `db:"supplier_name"`
+	Quantity       float64         `db:"quantity"`
+	EstimatedPrice sql.NullFloat64 `db:"estimated_price"`
+	ActualPrice    sql.NullFloat64 `db:"actual_price"`
+	Date           time.Time       `db:"price_date"`
 }
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PaymentMethod string
+
+const (
+	PaymentMethodTransfer PaymentMethod = "transfer"
+	PaymentMethodCheque   PaymentMethod = "cheque"
+	PaymentMethodCash     PaymentMethod = "cash"
+)
+
+func (m PaymentMethod) IsValid() bool {
+	return m == PaymentMethodTransfer || m == PaymentMethodCheque || m == PaymentMethodCash
+}
+
+// Payment is money received against an invoice. Reversed payments are kept
+// for the audit trail but no longer count towards the paid amount.
+type Payment struct {
+	PaymentID      uuid.UUID      `db:"payment_id"`
+	InvoiceID      uuid.UUID      `db:"invoice_id"`
+	Amount         float64        `db:"amount"`
+	PaymentDate    time.Time      `db:"payment_date"`
+	Method         PaymentMethod  `db:"method"`
+	Reference      sql.NullString `db:"reference"`
+	BankName       sql.NullString `db:"bank_name"`
+	Remarks        sql.NullString `db:"remarks"`
+	ReversedAt     sql.NullTime   `db:"reversed_at"`
+	ReversalReason sql.NullString `db:"reversal_reason"`
+	CreatedAt      time.Time      `db:"created_at"`
+}
@@ -26,6 +26,7 @@ type Project struct {
 	ClientID    uuid.UUID       `db:"client_id"`
 	CreatedAt   time.Time       `db:"created_at"`
 	UpdatedAt   sql.NullTime    `db:"updated_at"`
+	CompanyID   *uuid.UUID      `db:"company_id"`
 }
 
 type ProjectStatusCheck struct {
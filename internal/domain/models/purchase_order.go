@@ -0,0 +1,111 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PurchaseOrderStatus string
+
+const (
+	PurchaseOrderStatusDraft             PurchaseOrderStatus = "draft"
+	PurchaseOrderStatusSent              PurchaseOrderStatus = "sent"
+	PurchaseOrderStatusPartiallyReceived PurchaseOrderStatus = "partially_received"
+	PurchaseOrderStatusReceived          PurchaseOrderStatus = "received"
+	PurchaseOrderStatusCancelled         PurchaseOrderStatus = "cancelled"
+)
+
+// purchaseOrderTransitions lists the statuses a purchase order may move to.
+// The received statuses are set by receiving goods, not by hand.
+var purchaseOrderTransitions = map[PurchaseOrderStatus][]PurchaseOrderStatus{
+	PurchaseOrderStatusDraft:             {PurchaseOrderStatusSent, PurchaseOrderStatusCancelled},
+	PurchaseOrderStatusSent:              {PurchaseOrderStatusPartiallyReceived, PurchaseOrderStatusReceived, PurchaseOrderStatusCancelled},
+	PurchaseOrderStatusPartiallyReceived: {PurchaseOrderStatusReceived},
+}
+
+func (s PurchaseOrderStatus) IsValid() bool {
+	switch s {
+	case PurchaseOrderStatusDraft, PurchaseOrderStatusSent, PurchaseOrderStatusPartiallyReceived,
+		PurchaseOrderStatusReceived, PurchaseOrderStatusCancelled:
+		return true
+	}
+	return false
+}
+
+func (s PurchaseOrderStatus) CanTransitionTo(next PurchaseOrderStatus) bool {
+	for _, allowed := range purchaseOrderTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// CanReceive reports whether goods can be received against the order.
+func (s PurchaseOrderStatus) CanReceive() bool {
+	return s == PurchaseOrderStatusSent || s == PurchaseOrderStatusPartiallyReceived
+}
+
+// PurchaseOrder is an order to one supplier for materials of a project's BOQ.
+type PurchaseOrder struct {
+	POID         uuid.UUID           `db:"po_id"`
+	PONumber     string              `db:"po_number"`
+	ProjectID    uuid.UUID           `db:"project_id"`
+	BOQID        uuid.UUID           `db:"boq_id"`
+	SupplierID   uuid.UUID           `db:"supplier_id"`
+	Status       PurchaseOrderStatus `db:"status"`
+	OrderDate    time.Time           `db:"order_date"`
+	DeliveryDate sql.NullTime        `db:"delivery_date"`
+	Remarks      sql.NullString      `db:"remarks"`
+	SentAt       sql.NullTime        `db:"sent_at"`
+	CreatedAt    time.Time           `db:"created_at"`
+	UpdatedAt    sql.NullTime        `db:"updated_at"`
+
+	// Related data (joined, not stored in purchase_order)
+	SupplierName string              `db:"supplier_name"`
+	Lines        []PurchaseOrderLine `db:"-"`
+}
+
+// PurchaseOrderLine is an ordered BOQ material. DeliveryDate overrides the
+// order's delivery date for the line.
+type PurchaseOrderLine struct {
+	LineID           uuid.UUID    `db:"po_line_id"`
+	POID             uuid.UUID    `db:"po_id"`
+	MaterialID       string       `db:"material_id"`
+	Quantity         float64      `db:"quantity"`
+	UnitPrice        float64      `db:"unit_price"`
+	DeliveryDate     sql.NullTime `db:"delivery_date"`
+	ReceivedQuantity float64      `db:"received_quantity"`
+	RejectedQuantity float64      `db:"rejected_quantity"`
+
+	// Related data (joined, not stored in purchase_order_line)
+	MaterialName string `db:"material_name"`
+	Unit         string `db:"unit"`
+}
+
+// OutstandingQuantity is the quantity still to be delivered. Rejected goods
+// are not counted as received, so they stay outstanding.
+func (l *PurchaseOrderLine) OutstandingQuantity() float64 {
+	if l.ReceivedQuantity >= l.Quantity {
+		return 0
+	}
+	return l.Quantity - l.ReceivedQuantity
+}
+
+// BOQMaterialRequirement is a material needed by a project's BOQ with the
+// quantities ordered on purchase orders that are not cancelled and received
+// against them.
+type BOQMaterialRequirement struct {
+	BOQID            uuid.UUID       `db:"boq_id"`
+	MaterialID       string          `db:"material_id"`
+	Name             string          `db:"name"`
+	Unit             string          `db:"unit"`
+	RequiredQuantity float64         `db:"required_quantity"` // including waste
+	NetQuantity      float64         `db:"net_quantity"`
+	OrderedQuantity  float64         `db:"ordered_quantity"`
+	ReceivedQuantity float64         `db:"received_quantity"`
+	RejectedQuantity float64         `db:"rejected_quantity"`
+	EstimatedPrice   sql.NullFloat64 `db:"estimated_price"`
+}
@@ -16,12 +16,22 @@ const (
 )
 
 type Quotation struct {
-	QuotationID   uuid.UUID       `db:"quotation_id"`
-	ProjectID     uuid.UUID       `db:"project_id"`
-	ValidDate     sql.NullTime    `db:"valid_date"`
-	Status        QuotationStatus `db:"status"`
-	FinalAmount   sql.NullFloat64 `db:"final_amount"`
-	TaxPercentage sql.NullFloat64 `db:"tax_percentage"`
+	QuotationID     uuid.UUID       `db:"quotation_id"`
+	QuotationNumber sql.NullString  `db:"quotation_number"`
+	ProjectID       uuid.UUID       `db:"project_id"`
+	ValidDate       sql.NullTime    `db:"valid_date"`
+	Status          QuotationStatus `db:"status"`
+	FinalAmount     sql.NullFloat64 `db:"final_amount"`
+	TaxPercentage   sql.NullFloat64 `db:"tax_percentage"`
+
+	// WithholdingTaxPercentage overrides the client's default rate when set.
+	WithholdingTaxPercentage sql.NullFloat64 `db:"withholding_tax_percentage"`
+
+	// ClientDecision is the client's answer through a share link, accepted or
+	// rejected, and ClientRespondedAt when it was given. Both are NULL until
+	// the client answers.
+	ClientDecision    sql.NullString `db:"client_decision"`
+	ClientRespondedAt sql.NullTime   `db:"client_responded_at"`
 }
 
 type QuotationJob struct {
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type QuotationDecision string
+
+const (
+	QuotationDecisionAccepted QuotationDecision = "accepted"
+	QuotationDecisionRejected QuotationDecision = "rejected"
+)
+
+// QuotationShare is a link sent to the client so they can review an approved
+// quotation without logging in.
+type QuotationShare struct {
+	ShareID     uuid.UUID    `db:"share_id"`
+	QuotationID uuid.UUID    `db:"quotation_id"`
+	ProjectID   uuid.UUID    `db:"project_id"`
+	ExpiresAt   time.Time    `db:"expires_at"`
+	CreatedAt   time.Time    `db:"created_at"`
+	RevokedAt   sql.NullTime `db:"revoked_at"`
+}
+
+// QuotationClientResponse records the client's decision on a shared quotation.
+type QuotationClientResponse struct {
+	ResponseID  uuid.UUID         `db:"response_id"`
+	QuotationID uuid.UUID         `db:"quotation_id"`
+	ShareID     uuid.UUID         `db:"share_id"`
+	Decision    QuotationDecision `db:"decision"`
+	ClientName  string            `db:"client_name"`
+	Remarks     sql.NullString    `db:"remarks"`
+	IPAddress   string            `db:"ip_address"`
+	RespondedAt time.Time         `db:"responded_at"`
+}
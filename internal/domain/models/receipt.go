@@ -0,0 +1,43 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Receipt is the receipt / tax invoice (ใบเสร็จรับเงิน/ใบกำกับภาษี) issued for a
+// payment. Seller and buyer details are copied at issue time so the document
+// does not change when the company or client is edited later. A receipt is
+// voided, never deleted, when its payment is reversed.
+type Receipt struct {
+	ReceiptID     uuid.UUID `db:"receipt_id"`
+	ReceiptNumber string    `db:"receipt_number"`
+	PaymentID     uuid.UUID `db:"payment_id"`
+	InvoiceID     uuid.UUID `db:"invoice_id"`
+	ProjectID     uuid.UUID `db:"project_id"`
+	ReceiptDate   time.Time `db:"receipt_date"`
+
+	SubTotal             float64 `db:"sub_total"`
+	TaxPercentage        float64 `db:"tax_percentage"`
+	TaxAmount            float64 `db:"tax_amount"`
+	TotalAmount          float64 `db:"total_amount"`
+	WithholdingTaxAmount float64 `db:"withholding_tax_amount"`
+	RetentionAmount      float64 `db:"retention_amount"`
+	AmountReceived       float64 `db:"amount_received"`
+
+	SellerName    string          `db:"seller_name"`
+	SellerTaxID   string          `db:"seller_tax_id"`
+	SellerBranch  string          `db:"seller_branch"`
+	SellerAddress json.RawMessage `db:"seller_address"`
+	BuyerName     string          `db:"buyer_name"`
+	BuyerTaxID    string          `db:"buyer_tax_id"`
+	BuyerBranch   string          `db:"buyer_branch"`
+	BuyerAddress  json.RawMessage `db:"buyer_address"`
+
+	VoidedAt   sql.NullTime   `db:"voided_at"`
+	VoidReason sql.NullString `db:"void_reason"`
+	CreatedAt  time.Time      `db:"created_at"`
+}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RFQStatus string
+
+const (
+	RFQStatusDraft     RFQStatus = "draft"
+	RFQStatusSent      RFQStatus = "sent"
+	RFQStatusAwarded   RFQStatus = "awarded"
+	RFQStatusCancelled RFQStatus = "cancelled"
+)
+
+// rfqTransitions lists the statuses an RFQ may move to. Awarded is set by
+// awarding the RFQ, not by hand.
+var rfqTransitions = map[RFQStatus][]RFQStatus{
+	RFQStatusDraft: {RFQStatusSent, RFQStatusCancelled},
+	RFQStatusSent:  {RFQStatusAwarded, RFQStatusCancelled},
+}
+
+func (s RFQStatus) IsValid() bool {
+	switch s {
+	case RFQStatusDraft, RFQStatusSent, RFQStatusAwarded, RFQStatusCancelled:
+		return true
+	}
+	return false
+}
+
+func (s RFQStatus) CanTransitionTo(next RFQStatus) bool {
+	for _, allowed := range rfqTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// RFQ is a request for quotation sending a list of BOQ materials to several
+// suppliers.
+type RFQ struct {
+	RFQID           uuid.UUID      `db:"rfq_id"`
+	RFQNumber       string         `db:"rfq_number"`
+	ProjectID       uuid.UUID      `db:"project_id"`
+	BOQID           uuid.UUID      `db:"boq_id"`
+	Status          RFQStatus      `db:"status"`
+	IssueDate       time.Time      `db:"issue_date"`
+	ResponseDueDate sql.NullTime   `db:"response_due_date"`
+	Notes           sql.NullString `db:"notes"`
+	SentAt          sql.NullTime   `db:"sent_at"`
+	AwardedAt       sql.NullTime   `db:"awarded_at"`
+	CreatedAt       time.Time      `db:"created_at"`
+	UpdatedAt       sql.NullTime   `db:"updated_at"`
+
+	// Related data (not stored in rfq)
+	Lines     []RFQLine     `db:"-"`
+	Suppliers []RFQSupplier `db:"-"`
+	Quotes    []RFQQuote    `db:"-"`
+}
+
+// RFQLine is a material and quantity suppliers are asked to price. Once the
+// RFQ is awarded it records the chosen supplier and price.
+type RFQLine struct {
+	RFQLineID         uuid.UUID       `db:"rfq_line_id"`
+	RFQID             uuid.UUID       `db:"rfq_id"`
+	MaterialID        string          `db:"material_id"`
+	Quantity          float64         `db:"quantity"`
+	AwardedSupplierID uuid.NullUUID   `db:"awarded_supplier_id"`
+	AwardedUnitPrice  sql.NullFloat64 `db:"awarded_unit_price"`
+
+	// Related data (joined, not stored in rfq_line)
+	MaterialName string `db:"material_name"`
+	Unit         string `db:"unit"`
+}
+
+// RFQSupplier is a supplier invited to quote on an RFQ.
+type RFQSupplier struct {
+	RFQID        uuid.UUID `db:"rfq_id"`
+	SupplierID   uuid.UUID `db:"supplier_id"`
+	SupplierName string    `db:"supplier_name"`
+}
+
+// RFQQuote is a supplier's quoted unit price and lead time for an RFQ line.
+type RFQQuote struct {
+	RFQLineID    uuid.UUID      `db:"rfq_line_id"`
+	SupplierID   uuid.UUID      `db:"supplier_id"`
+	RFQID        uuid.UUID      `db:"rfq_id"`
+	UnitPrice    float64        `db:"unit_price"`
+	LeadTimeDays int            `db:"lead_time_days"`
+	Notes        sql.NullString `db:"notes"`
+	QuotedAt     time.Time      `db:"quoted_at"`
+}
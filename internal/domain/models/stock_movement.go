@@ -0,0 +1,111 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type StockMovementType string
+
+const (
+	// StockMovementReceipt brings materials onto site, from a goods receipt or
+	// bought without a purchase order.
+	StockMovementReceipt StockMovementType = "receipt"
+	// StockMovementIssue consumes materials on a BOQ job.
+	StockMovementIssue StockMovementType = "issue"
+	// StockMovementTransferOut and StockMovementTransferIn are the two sides
+	// of moving materials between project sites.
+	StockMovementTransferOut StockMovementType = "transfer_out"
+	StockMovementTransferIn  StockMovementType = "transfer_in"
+	// StockMovementReturn sends materials back to the supplier.
+	StockMovementReturn StockMovementType = "return"
+)
+
+func (t StockMovementType) IsValid() bool {
+	switch t {
+	case StockMovementReceipt, StockMovementIssue, StockMovementTransferOut, StockMovementTransferIn, StockMovementReturn:
+		return true
+	}
+	return false
+}
+
+// IsOutgoing reports whether the movement takes stock off site.
+func (t StockMovementType) IsOutgoing() bool {
+	return t == StockMovementIssue || t == StockMovementTransferOut || t == StockMovementReturn
+}
+
+// StockMovement is an entry in a project's site stock ledger. Quantity is
+// always positive; the movement type decides the direction.
+type StockMovement struct {
+	MovementID   uuid.UUID         `db:"movement_id"`
+	ProjectID    uuid.UUID         `db:"project_id"`
+	MaterialID   string            `db:"material_id"`
+	MovementType StockMovementType `db:"movement_type"`
+	Quantity     float64           `db:"quantity"`
+	MovementDate time.Time         `db:"movement_date"`
+
+	// JobID is the BOQ job materials are issued to.
+	JobID uuid.NullUUID `db:"job_id"`
+	// GRID is the goods receipt a receipt was posted from.
+	GRID uuid.NullUUID `db:"gr_id"`
+	// SupplierID is the supplier materials were received from or are
+	// returned to.
+	SupplierID uuid.NullUUID `db:"supplier_id"`
+	// TransferID links the two movements of a transfer, and
+	// CounterpartProjectID is the other project of the transfer.
+	TransferID           uuid.NullUUID `db:"transfer_id"`
+	CounterpartProjectID uuid.NullUUID `db:"counterpart_project_id"`
+
+	Notes      sql.NullString `db:"notes"`
+	RecordedBy string         `db:"recorded_by"`
+	CreatedAt  time.Time      `db:"created_at"`
+}
+
+// SignedQuantity returns the change the movement makes to the on-hand balance.
+func (m *StockMovement) SignedQuantity() float64 {
+	if m.MovementType.IsOutgoing() {
+		return -m.Quantity
+	}
+	return m.Quantity
+}
+
+// StockMovementDetail is a stock movement with the names of related records.
+type StockMovementDetail struct {
+	StockMovement
+	MaterialName           string         `db:"material_name"`
+	Unit                   string         `db:"unit"`
+	JobName                sql.NullString `db:"job_name"`
+	SupplierName           sql.NullString `db:"supplier_name"`
+	CounterpartProjectName sql.NullString `db:"counterpart_project_name"`
+}
+
+// StockBalance is the site stock of a material on a project.
+type StockBalance struct {
+	MaterialID     string  `db:"material_id"`
+	Name           string  `db:"name"`
+	Unit           string  `db:"unit"`
+	Received       float64 `db:"received"`
+	Issued         float64 `db:"issued"`
+	TransferredIn  float64 `db:"transferred_in"`
+	TransferredOut float64 `db:"transferred_out"`
+	Returned       float64 `db:"returned"`
+}
+
+// OnHand is the quantity currently on site.
+func (b *StockBalance) OnHand() float64 {
+	return b.Received + b.TransferredIn - b.Issued - b.TransferredOut - b.Returned
+}
+
+// MaterialConsumption compares what the BOQ estimates a job needs of a
+// material with what has been issued to the job.
+type MaterialConsumption struct {
+	MaterialID        string    `db:"material_id"`
+	MaterialName      string    `db:"material_name"`
+	Unit              string    `db:"unit"`
+	JobID             uuid.UUID `db:"job_id"`
+	JobName           string    `db:"job_name"`
+	EstimatedQuantity float64   `db:"estimated_quantity"`
+	IssuedQuantity    float64   `db:"issued_quantity"`
+}
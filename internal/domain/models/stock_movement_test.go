@@ -0,0 +1,74 @@
+package models_test
+
+import (
+	"boonkosang/internal/domain/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStockMovementDirection(t *testing.T) {
+	tests := []struct {
+		movementType models.StockMovementType
+		outgoing     bool
+		signed       float64
+	}{
+		{movementType: models.StockMovementReceipt, outgoing: false, signed: 12.5},
+		{movementType: models.StockMovementTransferIn, outgoing: false, signed: 12.5},
+		{movementType: models.StockMovementIssue, outgoing: true, signed: -12.5},
+		{movementType: models.StockMovementTransferOut, outgoing: true, signed: -12.5},
+		{movementType: models.StockMovementReturn, outgoing: true, signed: -12.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.movementType), func(t *testing.T) {
+			assert.True(t, tt.movementType.IsValid())
+			assert.Equal(t, tt.outgoing, tt.movementType.IsOutgoing())
+
+			movement := &models.StockMovement{MovementType: tt.movementType, Quantity: 12.5}
+			assert.Equal(t, tt.signed, movement.SignedQuantity())
+		})
+	}
+
+	assert.False(t, models.StockMovementType("adjustment").IsValid())
+}
+
+func TestStockBalanceOnHand(t *testing.T) {
+	tests := []struct {
+		name    string
+		balance models.StockBalance
+		want    float64
+	}{
+		{
+			name:    "no movements",
+			balance: models.StockBalance{},
+			want:    0,
+		},
+		{
+			name:    "received and partly issued",
+			balance: models.StockBalance{Received: 100, Issued: 35},
+			want:    65,
+		},
+		{
+			name:    "transfers in and out",
+			balance: models.StockBalance{Received: 50, TransferredIn: 20, TransferredOut: 30},
+			want:    40,
+		},
+		{
+			name:    "returned to the supplier",
+			balance: models.StockBalance{Received: 80, Issued: 60, Returned: 20},
+			want:    0,
+		},
+		{
+			name:    "every movement type",
+			balance: models.StockBalance{Received: 120, TransferredIn: 15, Issued: 70, TransferredOut: 25, Returned: 10},
+			want:    30,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.balance.OnHand())
+		})
+	}
+}
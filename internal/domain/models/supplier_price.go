@@ -0,0 +1,36 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SupplierPrice is an entry of a supplier's price list: the unit price of a
+// material for orders of at least MinQuantity, valid from ValidFrom until
+// ValidTo, or indefinitely when ValidTo is NULL.
+type SupplierPrice struct {
+	PriceID     uuid.UUID    `db:"price_id"`
+	SupplierID  uuid.UUID    `db:"supplier_id"`
+	MaterialID  string       `db:"material_id"`
+	UnitPrice   float64      `db:"unit_price"`
+	MinQuantity float64      `db:"min_quantity"`
+	ValidFrom   time.Time    `db:"valid_from"`
+	ValidTo     sql.NullTime `db:"valid_to"`
+	CreatedAt   time.Time    `db:"created_at"`
+	UpdatedAt   sql.NullTime `db:"updated_at"`
+
+	// Related data (joined, not stored in supplier_price)
+	SupplierName string `db:"supplier_name"`
+	MaterialName string `db:"material_name"`
+	Unit         string `db:"unit"`
+}
+
+// IsValidOn reports whether the price applies on date.
+func (p *SupplierPrice) IsValidOn(date time.Time) bool {
+	if date.Before(p.ValidFrom) {
+		return false
+	}
+	return !p.ValidTo.Valid || !date.After(p.ValidTo.Time)
+}
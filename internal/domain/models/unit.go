@@ -0,0 +1,105 @@
+package models
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UnitDimension string
+
+const (
+	UnitDimensionMass      UnitDimension = "mass"
+	UnitDimensionLength    UnitDimension = "length"
+	UnitDimensionArea      UnitDimension = "area"
+	UnitDimensionVolume    UnitDimension = "volume"
+	UnitDimensionCount     UnitDimension = "count"
+	UnitDimensionPackaging UnitDimension = "packaging"
+	UnitDimensionLumpSum   UnitDimension = "lump_sum"
+)
+
+// Unit is a canonical unit of measure. Units of the same dimension convert
+// through ToBase, the size of the unit in the dimension's base unit.
+// Packaging and lump-sum units have no ToBase: how many kilograms are in a
+// bag depends on the material, so they only convert through a
+// UnitConversion.
+type Unit struct {
+	Code      string
+	Name      string
+	Dimension UnitDimension
+	ToBase    float64
+	Aliases   []string
+}
+
+// Units is the list of canonical units.
+var Units = []Unit{
+	{Code: "kg", Name: "กิโลกรัม", Dimension: UnitDimensionMass, ToBase: 1, Aliases: []string{"กก.", "กก", "kilogram"}},
+	{Code: "g", Name: "กรัม", Dimension: UnitDimensionMass, ToBase: 0.001, Aliases: []string{"ก.", "gram"}},
+	{Code: "t", Name: "ตัน", Dimension: UnitDimensionMass, ToBase: 1000, Aliases: []string{"ton", "tonne"}},
+
+	{Code: "m", Name: "เมตร", Dimension: UnitDimensionLength, ToBase: 1, Aliases: []string{"ม.", "meter", "metre"}},
+	{Code: "cm", Name: "เซนติเมตร", Dimension: UnitDimensionLength, ToBase: 0.01, Aliases: []string{"ซม."}},
+	{Code: "mm", Name: "มิลลิเมตร", Dimension: UnitDimensionLength, ToBase: 0.001, Aliases: []string{"มม."}},
+	{Code: "in", Name: "นิ้ว", Dimension: UnitDimensionLength, ToBase: 0.0254, Aliases: []string{"inch"}},
+	{Code: "ft", Name: "ฟุต", Dimension: UnitDimensionLength, ToBase: 0.3048, Aliases: []string{"foot", "feet"}},
+
+	{Code: "m2", Name: "ตารางเมตร", Dimension: UnitDimensionArea, ToBase: 1, Aliases: []string{"ตร.ม.", "m²", "sqm"}},
+	{Code: "ft2", Name: "ตารางฟุต", Dimension: UnitDimensionArea, ToBase: 0.09290304, Aliases: []string{"ตร.ฟ.", "ft²", "sqft"}},
+	{Code: "wa2", Name: "ตารางวา", Dimension: UnitDimensionArea, ToBase: 4, Aliases: []string{"ตร.ว."}},
+	{Code: "rai", Name: "ไร่", Dimension: UnitDimensionArea, ToBase: 1600, Aliases: []string{"ไร่"}},
+
+	{Code: "m3", Name: "ลูกบาศก์เมตร", Dimension: UnitDimensionVolume, ToBase: 1, Aliases: []string{"ลบ.ม.", "m³", "cum"}},
+	{Code: "l", Name: "ลิตร", Dimension: UnitDimensionVolume, ToBase: 0.001, Aliases: []string{"ลิตร", "liter", "litre"}},
+
+	{Code: "pcs", Name: "ชิ้น", Dimension: UnitDimensionCount, ToBase: 1, Aliases: []string{"ชิ้น", "อัน", "ต้น", "ea", "pc"}},
+	{Code: "dozen", Name: "โหล", Dimension: UnitDimensionCount, ToBase: 12, Aliases: []string{"โหล"}},
+
+	{Code: "bag", Name: "ถุง", Dimension: UnitDimensionPackaging, Aliases: []string{"ถุง", "กระสอบ"}},
+	{Code: "box", Name: "กล่อง", Dimension: UnitDimensionPackaging, Aliases: []string{"กล่อง"}},
+	{Code: "roll", Name: "ม้วน", Dimension: UnitDimensionPackaging, Aliases: []string{"ม้วน"}},
+	{Code: "sheet", Name: "แผ่น", Dimension: UnitDimensionPackaging, Aliases: []string{"แผ่น"}},
+	{Code: "set", Name: "ชุด", Dimension: UnitDimensionPackaging, Aliases: []string{"ชุด"}},
+	{Code: "can", Name: "กระป๋อง", Dimension: UnitDimensionPackaging, Aliases: []string{"กระป๋อง"}},
+	{Code: "bucket", Name: "ถัง", Dimension: UnitDimensionPackaging, Aliases: []string{"ถัง"}},
+	{Code: "bundle", Name: "มัด", Dimension: UnitDimensionPackaging, Aliases: []string{"มัด"}},
+	{Code: "point", Name: "จุด", Dimension: UnitDimensionPackaging, Aliases: []string{"จุด"}},
+
+	{Code: "ls", Name: "งาน", Dimension: UnitDimensionLumpSum, Aliases: []string{"งาน", "lot", "job"}},
+}
+
+// LookupUnit finds a canonical unit by its code or one of its aliases,
+// ignoring case and surrounding spaces.
+func LookupUnit(value string) (Unit, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return Unit{}, false
+	}
+	for _, unit := range Units {
+		if unit.Code == value {
+			return unit, true
+		}
+		for _, alias := range unit.Aliases {
+			if strings.ToLower(alias) == value {
+				return unit, true
+			}
+		}
+	}
+	return Unit{}, false
+}
+
+// UnitConversion states that one FromUnit equals Factor ToUnit. Conversions
+// without a material apply to every material; a material's own conversion
+// takes precedence over a global one between the same units.
+type UnitConversion struct {
+	ConversionID uuid.UUID      `db:"conversion_id"`
+	MaterialID   sql.NullString `db:"material_id"`
+	FromUnit     string         `db:"from_unit"`
+	ToUnit       string         `db:"to_unit"`
+	Factor       float64        `db:"factor"`
+	CreatedAt    time.Time      `db:"created_at"`
+
+	// Related data (joined, not stored in unit_conversion)
+	MaterialName sql.NullString `db:"material_name"`
+}
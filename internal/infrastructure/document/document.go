@@ -0,0 +1,37 @@
+// Package document renders simple structured documents (headings, paragraphs
+// and tables) to DOCX and PDF without external dependencies.
+package document
+
+type BlockKind int
+
+const (
+	BlockHeading BlockKind = iota
+	BlockParagraph
+	BlockTable
+)
+
+type Block struct {
+	Kind BlockKind
+	Text string
+
+	// Table content, only used when Kind is BlockTable.
+	Header []string
+	Rows   [][]string
+}
+
+type Document struct {
+	Title  string
+	Blocks []Block
+}
+
+func (d *Document) AddHeading(text string) {
+	d.Blocks = append(d.Blocks, Block{Kind: BlockHeading, Text: text})
+}
+
+func (d *Document) AddParagraph(text string) {
+	d.Blocks = append(d.Blocks, Block{Kind: BlockParagraph, Text: text})
+}
+
+func (d *Document) AddTable(header []string, rows [][]string) {
+	d.Blocks = append(d.Blocks, Block{Kind: BlockTable, Header: header, Rows: rows})
+}
@@ -0,0 +1,123 @@
+package document
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+const docxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>
+</Types>`
+
+const docxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>
+</Relationships>`
+
+// RenderDOCX renders the document as a minimal WordprocessingML package.
+func RenderDOCX(doc *Document) ([]byte, error) {
+	var body strings.Builder
+	body.WriteString(docxParagraph(doc.Title, true, 32, "center"))
+
+	for _, block := range doc.Blocks {
+		switch block.Kind {
+		case BlockHeading:
+			body.WriteString(docxParagraph(block.Text, true, 26, ""))
+		case BlockParagraph:
+			for _, line := range strings.Split(block.Text, "\n") {
+				body.WriteString(docxParagraph(line, false, 22, "both"))
+			}
+		case BlockTable:
+			body.WriteString(docxTable(block.Header, block.Rows))
+		}
+	}
+
+	documentXML := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		body.String() +
+		`<w:sectPr><w:pgSz w:w="11906" w:h="16838"/><w:pgMar w:top="1440" w:right="1440" w:bottom="1440" w:left="1440" w:header="708" w:footer="708" w:gutter="0"/></w:sectPr>` +
+		`</w:body></w:document>`
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", docxContentTypes},
+		{"_rels/.rels", docxRootRels},
+		{"word/document.xml", documentXML},
+	}
+
+	for _, file := range files {
+		w, err := zw.Create(file.name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s: %w", file.name, err)
+		}
+		if _, err := w.Write([]byte(file.content)); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", file.name, err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finalize docx: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func docxParagraph(text string, bold bool, size int, align string) string {
+	var b strings.Builder
+	b.WriteString("<w:p>")
+	if align != "" {
+		b.WriteString(`<w:pPr><w:jc w:val="` + align + `"/></w:pPr>`)
+	}
+	b.WriteString("<w:r><w:rPr>")
+	if bold {
+		b.WriteString("<w:b/>")
+	}
+	fmt.Fprintf(&b, `<w:sz w:val="%d"/><w:szCs w:val="%d"/></w:rPr>`, size, size)
+	b.WriteString(`<w:t xml:space="preserve">` + docxEscape(text) + "</w:t></w:r></w:p>")
+	return b.String()
+}
+
+func docxTable(header []string, rows [][]string) string {
+	var b strings.Builder
+	b.WriteString(`<w:tbl><w:tblPr><w:tblW w:w="5000" w:type="pct"/><w:tblBorders>`)
+	for _, side := range []string{"top", "left", "bottom", "right", "insideH", "insideV"} {
+		b.WriteString(`<w:` + side + ` w:val="single" w:sz="4" w:space="0" w:color="000000"/>`)
+	}
+	b.WriteString(`</w:tblBorders></w:tblPr>`)
+
+	writeRow := func(cells []string, bold bool) {
+		b.WriteString("<w:tr>")
+		for _, cell := range cells {
+			b.WriteString("<w:tc>" + docxParagraph(cell, bold, 20, "") + "</w:tc>")
+		}
+		b.WriteString("</w:tr>")
+	}
+
+	if len(header) > 0 {
+		writeRow(header, true)
+	}
+	for _, row := range rows {
+		writeRow(row, false)
+	}
+
+	b.WriteString("</w:tbl>")
+	// Word requires a paragraph between consecutive tables and before the section end
+	b.WriteString("<w:p/>")
+	return b.String()
+}
+
+func docxEscape(text string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(text))
+	return b.String()
+}
@@ -0,0 +1,539 @@
+package document
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	pdfPageWidth  = 595.28
+	pdfPageHeight = 841.89
+	pdfMargin     = 56.0
+	pdfBodySize   = 11.0
+	pdfTableSize  = 9.5
+)
+
+// helveticaWidths are the advance widths of the printable ASCII characters
+// in the standard Helvetica font, starting at the space character.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// ErrUnsupportedCharacters is returned by RenderPDF when the document
+// contains characters that the font cannot display.
+var ErrUnsupportedCharacters = errors.New("document contains characters the PDF font cannot display")
+
+// pdfFont encodes text and measures it for one of the two supported font
+// setups: the built-in Helvetica (Latin-1 only) or an embedded TrueType font.
+// Control characters are printed as spaces.
+type pdfFont interface {
+	supports(r rune) bool
+	encode(text string) string
+	width(text string, size float64) float64
+	resources() string
+}
+
+type standardFont struct{}
+
+func (standardFont) supports(r rune) bool {
+	return r <= 0xFF
+}
+
+func (standardFont) encode(text string) string {
+	var b strings.Builder
+	b.WriteByte('(')
+	for _, r := range text {
+		if unicode.IsControl(r) {
+			r = ' '
+		}
+		switch r {
+		case '(', ')', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(byte(r))
+		default:
+			if r < 0x80 {
+				b.WriteByte(byte(r))
+			} else {
+				fmt.Fprintf(&b, "\\%03o", r)
+			}
+		}
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+func (standardFont) width(text string, size float64) float64 {
+	total := 0
+	for _, r := range text {
+		if r >= 0x20 && r < 0x7F {
+			total += helveticaWidths[r-0x20]
+		} else {
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+func (standardFont) resources() string {
+	return "<< /F1 << /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >> " +
+		"/F2 << /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >> >>"
+}
+
+type embeddedFont struct {
+	font *trueTypeFont
+	used map[uint16]rune
+
+	// fontRef is the object number of the Type0 font dictionary.
+	fontRef int
+}
+
+func (f *embeddedFont) supports(r rune) bool {
+	_, ok := f.font.glyphs[r]
+	return ok
+}
+
+func (f *embeddedFont) glyph(r rune) uint16 {
+	if unicode.IsControl(r) {
+		r = ' '
+	}
+	glyph, ok := f.font.glyphs[r]
+	if !ok {
+		glyph = f.font.glyphs['?']
+	}
+	if _, seen := f.used[glyph]; !seen {
+		f.used[glyph] = r
+	}
+	return glyph
+}
+
+func (f *embeddedFont) encode(text string) string {
+	var b strings.Builder
+	b.WriteByte('<')
+	for _, r := range text {
+		fmt.Fprintf(&b, "%04X", f.glyph(r))
+	}
+	b.WriteByte('>')
+	return b.String()
+}
+
+func (f *embeddedFont) width(text string, size float64) float64 {
+	total := 0
+	for _, r := range text {
+		total += f.font.advance(f.glyph(r))
+	}
+	return float64(total) * size / 1000
+}
+
+func (f *embeddedFont) resources() string {
+	// Both regular and bold text use the embedded font; bold is simulated
+	// with a stroked outline in the content stream.
+	return fmt.Sprintf("<< /F1 %d 0 R /F2 %d 0 R >>", f.fontRef, f.fontRef)
+}
+
+// RenderPDF renders the document as an A4 PDF. When fontData contains a
+// TrueType font it is embedded so that non-Latin text (e.g. Thai) is shown;
+// otherwise the built-in Helvetica is used, which only covers Latin-1.
+// Rather than printing placeholders, it fails with ErrUnsupportedCharacters
+// when the font has no glyph for some of the text.
+//
+// Text is shown glyph by glyph from the font's cmap and advance widths; the
+// OpenType GSUB and GPOS tables are not applied. Thai marks are therefore
+// drawn at the default position of their glyphs: a tone mark over an upper
+// vowel, as in ที่, overlaps the vowel instead of being raised above it, and
+// marks on tall consonants such as ป are not shifted aside. Fonts whose
+// default mark glyphs already sit clear of each other print best.
+func RenderPDF(doc *Document, fontData []byte) ([]byte, error) {
+	var font pdfFont = standardFont{}
+	var embedded *embeddedFont
+	if len(fontData) > 0 {
+		ttf, err := parseTrueType(fontData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load font: %w", err)
+		}
+		embedded = &embeddedFont{font: ttf, used: make(map[uint16]rune)}
+		font = embedded
+	}
+
+	if missing := unsupportedCharacters(doc, font); len(missing) > 0 {
+		hint := "configure a TrueType font that covers them"
+		if embedded != nil {
+			hint = "the configured font does not cover them"
+		}
+		return nil, fmt.Errorf("%w: %q (%s)", ErrUnsupportedCharacters, string(missing), hint)
+	}
+
+	layout := &pdfLayout{font: font, fakeBold: embedded != nil}
+	layout.newPage()
+	layout.writeLines(doc.Title, 16, true, true)
+	layout.space(8)
+
+	for _, block := range doc.Blocks {
+		switch block.Kind {
+		case BlockHeading:
+			layout.space(6)
+			layout.writeLines(block.Text, 12.5, true, false)
+			layout.space(2)
+		case BlockParagraph:
+			for _, line := range strings.Split(block.Text, "\n") {
+				layout.writeLines(line, pdfBodySize, false, false)
+			}
+			layout.space(4)
+		case BlockTable:
+			layout.writeTable(block.Header, block.Rows)
+			layout.space(6)
+		}
+	}
+	layout.finishPage()
+
+	return assemblePDF(layout.pages, font, embedded)
+}
+
+// ValidateFont checks that fontData is a usable TrueType font with glyphs
+// for every character of sample.
+func ValidateFont(fontData []byte, sample string) error {
+	ttf, err := parseTrueType(fontData)
+	if err != nil {
+		return fmt.Errorf("failed to load font: %w", err)
+	}
+
+	font := &embeddedFont{font: ttf, used: make(map[uint16]rune)}
+	if missing := unsupportedCharacters(&Document{Title: sample}, font); len(missing) > 0 {
+		return fmt.Errorf("%w: %q", ErrUnsupportedCharacters, string(missing))
+	}
+	return nil
+}
+
+// unsupportedCharacters returns the distinct characters of the document,
+// in order of appearance, that the font cannot display.
+func unsupportedCharacters(doc *Document, font pdfFont) []rune {
+	var missing []rune
+	seen := make(map[rune]bool)
+	check := func(text string) {
+		for _, r := range text {
+			if seen[r] || unicode.IsControl(r) {
+				continue
+			}
+			seen[r] = true
+			if !font.supports(r) {
+				missing = append(missing, r)
+			}
+		}
+	}
+
+	check(doc.Title)
+	for _, block := range doc.Blocks {
+		check(block.Text)
+		for _, cell := range block.Header {
+			check(cell)
+		}
+		for _, row := range block.Rows {
+			for _, cell := range row {
+				check(cell)
+			}
+		}
+	}
+
+	return missing
+}
+
+type pdfLayout struct {
+	font     pdfFont
+	fakeBold bool
+	pages    []*bytes.Buffer
+	current  *bytes.Buffer
+	y        float64
+}
+
+func (l *pdfLayout) newPage() {
+	l.finishPage()
+	l.current = &bytes.Buffer{}
+	l.y = pdfPageHeight - pdfMargin
+}
+
+func (l *pdfLayout) finishPage() {
+	if l.current != nil {
+		l.pages = append(l.pages, l.current)
+		l.current = nil
+	}
+}
+
+func (l *pdfLayout) ensure(height float64) {
+	if l.y-height < pdfMargin {
+		l.newPage()
+	}
+}
+
+func (l *pdfLayout) space(height float64) {
+	l.y -= height
+}
+
+func (l *pdfLayout) text(x, y float64, text string, size float64, bold bool) {
+	fontName := "/F1"
+	if bold {
+		fontName = "/F2"
+	}
+	mode := ""
+	if bold && l.fakeBold {
+		mode = fmt.Sprintf("2 Tr %.2f w ", size/30)
+	}
+	fmt.Fprintf(l.current, "BT %s%s %.2f Tf %.2f %.2f Td %s Tj ET\n",
+		mode, fontName, size, x, y, l.font.encode(text))
+}
+
+func (l *pdfLayout) writeLines(text string, size float64, bold, center bool) {
+	width := pdfPageWidth - 2*pdfMargin
+	lineHeight := size * 1.45
+	lines := wrapText(l.font, text, size, width)
+	for _, line := range lines {
+		l.ensure(lineHeight)
+		l.y -= lineHeight
+		x := pdfMargin
+		if center {
+			x = (pdfPageWidth - l.font.width(line, size)) / 2
+		}
+		l.text(x, l.y+size*0.3, line, size, bold)
+	}
+}
+
+func (l *pdfLayout) writeTable(header []string, rows [][]string) {
+	columns := len(header)
+	for _, row := range rows {
+		if len(row) > columns {
+			columns = len(row)
+		}
+	}
+	if columns == 0 {
+		return
+	}
+
+	columnWidth := (pdfPageWidth - 2*pdfMargin) / float64(columns)
+	lineHeight := pdfTableSize * 1.4
+	padding := 3.0
+
+	drawRow := func(cells []string, bold bool) {
+		wrapped := make([][]string, columns)
+		maxLines := 1
+		for i := 0; i < columns; i++ {
+			cell := ""
+			if i < len(cells) {
+				cell = cells[i]
+			}
+			wrapped[i] = wrapText(l.font, cell, pdfTableSize, columnWidth-2*padding)
+			if len(wrapped[i]) > maxLines {
+				maxLines = len(wrapped[i])
+			}
+		}
+
+		height := float64(maxLines)*lineHeight + 2*padding
+		l.ensure(height)
+		top := l.y
+		for i := 0; i < columns; i++ {
+			x := pdfMargin + float64(i)*columnWidth
+			fmt.Fprintf(l.current, "0.5 w %.2f %.2f %.2f %.2f re S\n", x, top-height, columnWidth, height)
+			for j, line := range wrapped[i] {
+				l.text(x+padding, top-padding-float64(j+1)*lineHeight+pdfTableSize*0.3, line, pdfTableSize, bold)
+			}
+		}
+		l.y -= height
+	}
+
+	if len(header) > 0 {
+		drawRow(header, true)
+	}
+	for _, row := range rows {
+		drawRow(row, false)
+	}
+}
+
+// wrapText breaks text into lines that fit the given width. Words are split
+// on spaces; text without spaces (such as Thai) is broken between characters.
+func wrapText(font pdfFont, text string, size, width float64) []string {
+	if strings.TrimSpace(text) == "" {
+		return []string{""}
+	}
+
+	var lines []string
+	current := ""
+	for _, word := range strings.Fields(text) {
+		candidate := word
+		if current != "" {
+			candidate = current + " " + word
+		}
+		if font.width(candidate, size) <= width {
+			current = candidate
+			continue
+		}
+
+		if current != "" {
+			lines = append(lines, current)
+			current = ""
+		}
+
+		for font.width(word, size) > width {
+			cut := breakIndex(font, word, size, width)
+			lines = append(lines, word[:cut])
+			word = word[cut:]
+		}
+		current = word
+	}
+	if current != "" {
+		lines = append(lines, current)
+	}
+
+	return lines
+}
+
+// breakIndex returns the byte offset of the longest prefix that fits the
+// width, never separating a combining mark from its base character.
+func breakIndex(font pdfFont, word string, size, width float64) int {
+	cut := 0
+	for i, r := range word {
+		if i > 0 && !unicode.Is(unicode.Mn, r) {
+			if font.width(word[:i], size) > width {
+				break
+			}
+			cut = i
+		}
+	}
+	if cut == 0 {
+		for i := range word {
+			if i > 0 {
+				return i
+			}
+		}
+		return len(word)
+	}
+	return cut
+}
+
+func assemblePDF(pages []*bytes.Buffer, font pdfFont, embedded *embeddedFont) ([]byte, error) {
+	var objects []string
+	addObject := func(content string) int {
+		objects = append(objects, content)
+		return len(objects)
+	}
+
+	// Reserve catalog and page tree objects so that pages can refer to them
+	catalogRef := addObject("")
+	pagesRef := addObject("")
+
+	if embedded != nil {
+		fontFile, err := compress(embedded.font.data)
+		if err != nil {
+			return nil, err
+		}
+		fontFileRef := addObject(fmt.Sprintf("<< /Length %d /Length1 %d /Filter /FlateDecode >>\nstream\n%s\nendstream",
+			len(fontFile), len(embedded.font.data), fontFile))
+
+		ttf := embedded.font
+		descriptorRef := addObject(fmt.Sprintf(
+			"<< /Type /FontDescriptor /FontName /EmbeddedFont /Flags 32 /FontBBox [%d %d %d %d] "+
+				"/ItalicAngle 0 /Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 %d 0 R >>",
+			ttf.scale(ttf.bbox[0]), ttf.scale(ttf.bbox[1]), ttf.scale(ttf.bbox[2]), ttf.scale(ttf.bbox[3]),
+			ttf.scale(ttf.ascent), ttf.scale(ttf.descent), ttf.scale(ttf.ascent), fontFileRef))
+
+		glyphs := make([]int, 0, len(embedded.used))
+		for glyph := range embedded.used {
+			glyphs = append(glyphs, int(glyph))
+		}
+		sort.Ints(glyphs)
+
+		var widths, toUnicode strings.Builder
+		for _, glyph := range glyphs {
+			fmt.Fprintf(&widths, "%d [%d] ", glyph, ttf.advance(uint16(glyph)))
+		}
+
+		toUnicode.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n" +
+			"/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n" +
+			"/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n" +
+			"1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+		for start := 0; start < len(glyphs); start += 100 {
+			end := start + 100
+			if end > len(glyphs) {
+				end = len(glyphs)
+			}
+			fmt.Fprintf(&toUnicode, "%d beginbfchar\n", end-start)
+			for _, glyph := range glyphs[start:end] {
+				fmt.Fprintf(&toUnicode, "<%04X> <%s>\n", glyph, utf16Hex(embedded.used[uint16(glyph)]))
+			}
+			toUnicode.WriteString("endbfchar\n")
+		}
+		toUnicode.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend")
+
+		toUnicodeRef := addObject(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream",
+			toUnicode.Len(), toUnicode.String()))
+
+		cidFontRef := addObject(fmt.Sprintf(
+			"<< /Type /Font /Subtype /CIDFontType2 /BaseFont /EmbeddedFont "+
+				"/CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> "+
+				"/FontDescriptor %d 0 R /W [%s] /CIDToGIDMap /Identity >>",
+			descriptorRef, widths.String()))
+
+		embedded.fontRef = addObject(fmt.Sprintf(
+			"<< /Type /Font /Subtype /Type0 /BaseFont /EmbeddedFont /Encoding /Identity-H "+
+				"/DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>", cidFontRef, toUnicodeRef))
+	}
+
+	var kids []string
+	for _, page := range pages {
+		content, err := compress(page.Bytes())
+		if err != nil {
+			return nil, err
+		}
+		contentRef := addObject(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", len(content), content))
+		pageRef := addObject(fmt.Sprintf(
+			"<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font %s >> /Contents %d 0 R >>",
+			pagesRef, pdfPageWidth, pdfPageHeight, font.resources(), contentRef))
+		kids = append(kids, fmt.Sprintf("%d 0 R", pageRef))
+	}
+
+	objects[catalogRef-1] = fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesRef)
+	objects[pagesRef-1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids))
+
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n%\xE2\xE3\xCF\xD3\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, catalogRef, xref)
+
+	return out.Bytes(), nil
+}
+
+func compress(data []byte) (string, error) {
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return "", fmt.Errorf("failed to compress pdf stream: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return "", fmt.Errorf("failed to compress pdf stream: %w", err)
+	}
+	return buf.String(), nil
+}
+
+func utf16Hex(r rune) string {
+	if r > 0xFFFF {
+		r -= 0x10000
+		return fmt.Sprintf("%04X%04X", 0xD800+(r>>10), 0xDC00+(r&0x3FF))
+	}
+	return fmt.Sprintf("%04X", r)
+}
@@ -0,0 +1,305 @@
+package document
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parsedPDF is the content of a PDF produced by RenderPDF, read back through
+// its cross-reference table.
+type parsedPDF struct {
+	objects map[int]string
+	texts   []string
+}
+
+var (
+	pdfObjectHeader = regexp.MustCompile(`^(\d+) 0 obj\n`)
+	pdfStartXref    = regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`)
+	pdfTextShow     = regexp.MustCompile(`(\((?:\\.|[^\\)])*\)|<[0-9A-F]*>) Tj`)
+	pdfBfChar       = regexp.MustCompile(`<([0-9A-F]{4})> <([0-9A-F]+)>`)
+)
+
+func parsePDF(t *testing.T, data []byte) *parsedPDF {
+	t.Helper()
+
+	require.True(t, bytes.HasPrefix(data, []byte("%PDF-1.4\n")), "missing PDF header")
+	match := pdfStartXref.FindSubmatch(data)
+	require.NotNil(t, match, "missing startxref")
+	xref, err := strconv.Atoi(string(match[1]))
+	require.NoError(t, err)
+	require.True(t, bytes.HasPrefix(data[xref:], []byte("xref\n")), "startxref does not point at the xref table")
+
+	lines := strings.Split(string(data[xref:]), "\n")
+	var count int
+	_, err = fmt.Sscanf(lines[1], "0 %d", &count)
+	require.NoError(t, err)
+
+	pdf := &parsedPDF{objects: make(map[int]string)}
+	for i := 1; i < count; i++ {
+		offset, err := strconv.Atoi(lines[2+i][:10])
+		require.NoError(t, err)
+		header := pdfObjectHeader.FindSubmatch(data[offset:])
+		require.NotNil(t, header, "xref entry %d does not point at an object", i)
+		require.Equal(t, strconv.Itoa(i), string(header[1]))
+
+		body := data[offset+len(header[0]):]
+		end := bytes.Index(body, []byte("\nendobj\n"))
+		require.GreaterOrEqual(t, end, 0)
+		pdf.objects[i] = string(body[:end])
+	}
+
+	toUnicode := make(map[string]string)
+	for _, object := range pdf.objects {
+		stream, ok := streamData(t, object)
+		if !ok || !strings.Contains(stream, "beginbfchar") {
+			continue
+		}
+		for _, char := range pdfBfChar.FindAllStringSubmatch(stream, -1) {
+			toUnicode[char[1]] = char[2]
+		}
+	}
+
+	for _, object := range pdf.objects {
+		if strings.Contains(object, "/Length1") {
+			continue
+		}
+		stream, ok := streamData(t, object)
+		if !ok || !strings.Contains(stream, " Tj ET") {
+			continue
+		}
+		for _, shown := range pdfTextShow.FindAllStringSubmatch(stream, -1) {
+			pdf.texts = append(pdf.texts, decodePDFString(t, shown[1], toUnicode))
+		}
+	}
+
+	return pdf
+}
+
+// streamData returns the decoded content of a stream object.
+func streamData(t *testing.T, object string) (string, bool) {
+	t.Helper()
+
+	start := strings.Index(object, "\nstream\n")
+	if start < 0 {
+		return "", false
+	}
+	raw := strings.TrimSuffix(object[start+len("\nstream\n"):], "\nendstream")
+
+	lengthMatch := regexp.MustCompile(`/Length (\d+)`).FindStringSubmatch(object)
+	require.NotNil(t, lengthMatch)
+	require.Equal(t, lengthMatch[1], strconv.Itoa(len(raw)), "stream /Length does not match its data")
+
+	if !strings.Contains(object[:start], "/FlateDecode") {
+		return raw, true
+	}
+	reader, err := zlib.NewReader(strings.NewReader(raw))
+	require.NoError(t, err)
+	decoded, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	return string(decoded), true
+}
+
+// decodePDFString turns a shown string back into text, using the ToUnicode
+// map for hex (glyph ID) strings.
+func decodePDFString(t *testing.T, shown string, toUnicode map[string]string) string {
+	t.Helper()
+
+	if strings.HasPrefix(shown, "<") {
+		glyphs := shown[1 : len(shown)-1]
+		var text strings.Builder
+		for i := 0; i+4 <= len(glyphs); i += 4 {
+			unicodeHex, ok := toUnicode[glyphs[i:i+4]]
+			require.True(t, ok, "glyph %s has no ToUnicode entry", glyphs[i:i+4])
+			raw, err := hex.DecodeString(unicodeHex)
+			require.NoError(t, err)
+			units := make([]uint16, len(raw)/2)
+			for j := range units {
+				units[j] = uint16(raw[2*j])<<8 | uint16(raw[2*j+1])
+			}
+			text.WriteString(string(utf16.Decode(units)))
+		}
+		return text.String()
+	}
+
+	var text strings.Builder
+	literal := shown[1 : len(shown)-1]
+	for i := 0; i < len(literal); i++ {
+		c := literal[i]
+		if c != '\\' {
+			text.WriteRune(rune(c))
+			continue
+		}
+		i++
+		if literal[i] >= '0' && literal[i] <= '7' {
+			code, err := strconv.ParseUint(literal[i:i+3], 8, 8)
+			require.NoError(t, err)
+			text.WriteRune(rune(code))
+			i += 2
+			continue
+		}
+		text.WriteByte(literal[i])
+	}
+	return text.String()
+}
+
+func sampleDocument(title string) *Document {
+	doc := &Document{Title: title}
+	doc.AddHeading("AB")
+	doc.AddParagraph("A (B) \\ A")
+	doc.AddTable([]string{"A", "B"}, [][]string{{"AB", "BA"}})
+	return doc
+}
+
+func TestRenderPDFStandardFont(t *testing.T) {
+	doc := sampleDocument("Café (draft)")
+
+	data, err := RenderPDF(doc, nil)
+	require.NoError(t, err)
+
+	pdf := parsePDF(t, data)
+	assert.Equal(t, []string{"Café (draft)", "AB", "A (B) \\ A", "A", "B", "AB", "BA"}, pdf.texts)
+}
+
+func TestRenderPDFEmbeddedFont(t *testing.T) {
+	formats := []struct {
+		name   string
+		format cmapFormat
+	}{
+		{name: "cmap format 4", format: cmapFormat4Delta},
+		{name: "cmap format 12", format: cmapFormat12},
+	}
+
+	for _, tt := range formats {
+		t.Run(tt.name, func(t *testing.T) {
+			fontData := buildTrueType(testFontGlyphs, testFontAdvances, tt.format)
+			doc := sampleDocument("สัญญาจ้าง")
+			doc.AddParagraph("ค่าก่อสร้าง\tงานที่ ก")
+
+			data, err := RenderPDF(doc, fontData)
+			require.NoError(t, err)
+
+			pdf := parsePDF(t, data)
+			assert.Equal(t, []string{"สัญญาจ้าง", "AB", "A (B) \\ A", "A", "B", "AB", "BA", "ค่าก่อสร้าง งานที่ ก"}, pdf.texts)
+
+			var fontFile, cidFont string
+			for _, object := range pdf.objects {
+				switch {
+				case strings.Contains(object, "/Length1"):
+					fontFile = object
+				case strings.Contains(object, "/CIDFontType2"):
+					cidFont = object
+				}
+			}
+
+			embedded, ok := streamData(t, fontFile)
+			require.True(t, ok)
+			assert.Equal(t, string(fontData), embedded, "embedded font file differs from the font data")
+
+			// Widths are listed per used glyph in 1/1000 em
+			assert.Contains(t, cidFont, "/W [1 [250] 3 [666] 4 [634] 5 [341] 6 [341] 7 [292] 8 [585] ")
+			assert.Contains(t, cidFont, " 14 [0] 15 [683] ")
+		})
+	}
+}
+
+func TestRenderPDFRejectsUnsupportedCharacters(t *testing.T) {
+	fontData := buildTrueType(testFontGlyphs, testFontAdvances, cmapFormat4Delta)
+
+	tests := []struct {
+		name     string
+		doc      *Document
+		fontData []byte
+		missing  string
+	}{
+		{
+			name:    "thai text without a font",
+			doc:     sampleDocument("สัญญา"),
+			missing: `"สัญา"`,
+		},
+		{
+			name:    "thai text in a table without a font",
+			doc:     &Document{Title: "Receipt", Blocks: []Block{{Kind: BlockTable, Header: []string{"A"}, Rows: [][]string{{"ข"}}}}},
+			missing: `"ข"`,
+		},
+		{
+			name:     "characters missing from the embedded font",
+			doc:      sampleDocument("กขZ€"),
+			fontData: fontData,
+			missing:  `"Z€"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := RenderPDF(tt.doc, tt.fontData)
+			require.ErrorIs(t, err, ErrUnsupportedCharacters)
+			assert.Contains(t, err.Error(), tt.missing)
+		})
+	}
+}
+
+func TestValidateFont(t *testing.T) {
+	fontData := buildTrueType(testFontGlyphs, testFontAdvances, cmapFormat12)
+
+	assert.NoError(t, ValidateFont(fontData, "กขค"))
+	assert.ErrorIs(t, ValidateFont(fontData, "กฮ"), ErrUnsupportedCharacters)
+	assert.ErrorContains(t, ValidateFont([]byte("not a font"), "ก"), "failed to load font")
+}
+
+func TestWrapTextKeepsCombiningMarks(t *testing.T) {
+	ttf, err := parseTrueType(buildTrueType(testFontGlyphs, testFontAdvances, cmapFormat4Delta))
+	require.NoError(t, err)
+	font := &embeddedFont{font: ttf, used: make(map[uint16]rune)}
+
+	// Only two or three base characters fit on each line
+	lines := wrapText(font, "สัญญาจ้าง", 10, 15)
+	require.Greater(t, len(lines), 1)
+	assert.Equal(t, "สัญญาจ้าง", strings.Join(lines, ""))
+	for _, line := range lines {
+		first := []rune(line)[0]
+		assert.NotContains(t, "ั่้", string(first), "line %q starts with a combining mark", line)
+	}
+}
+
+// Without GSUB/GPOS support stacked Thai marks are shown as the font's
+// default glyphs in logical order, with no repositioning; see RenderPDF.
+func TestRenderPDFStackedThaiMarks(t *testing.T) {
+	fontData := buildTrueType(testFontGlyphs, testFontAdvances, cmapFormat4Delta)
+	doc := &Document{Title: "ที่"}
+	doc.AddParagraph("ทั้งที่")
+
+	data, err := RenderPDF(doc, fontData)
+	require.NoError(t, err)
+
+	pdf := parsePDF(t, data)
+	assert.Equal(t, []string{"ที่", "ทั้งที่"}, pdf.texts)
+
+	var content string
+	for _, object := range pdf.objects {
+		if stream, ok := streamData(t, object); ok && strings.Contains(stream, " Tj ET") {
+			content += stream
+		}
+	}
+	// ท ี ่ and ท ั ้ ง ท ี ่: every character maps to its cmap glyph, in
+	// order, with no substituted or positioned mark glyphs
+	assert.Contains(t, content, "<001300140010> Tj")
+	assert.Contains(t, content, "<0013000E00110012001300140010> Tj")
+
+	// The marks take no width, so both stack over the base consonant
+	ttf, err := parseTrueType(fontData)
+	require.NoError(t, err)
+	font := &embeddedFont{font: ttf, used: make(map[uint16]rune)}
+	assert.Equal(t, font.width("ท", pdfBodySize), font.width("ที่", pdfBodySize))
+	assert.Equal(t, font.width("ทง", pdfBodySize), font.width("ทั้ง", pdfBodySize))
+}
@@ -0,0 +1,220 @@
+package document
+
+import (
+	"encoding/binary"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type cmapFormat int
+
+const (
+	cmapFormat4Delta cmapFormat = iota
+	cmapFormat4GlyphArray
+	cmapFormat12
+)
+
+// testFontGlyphs maps Latin and Thai characters to glyphs of the test font.
+var testFontGlyphs = map[rune]uint16{
+	' ': 1, '?': 2, 'A': 3, 'B': 4, '(': 5, ')': 6, '\\': 7,
+	'ก': 8, 'ข': 9, 'ค': 10, 'า': 11, 'ร': 12, 'ส': 13, 'ั': 14, 'ญ': 15, '่': 16, '้': 17, 'ง': 18,
+	'ท': 19, 'ี': 20, 'ม': 21, 'ำ': 22, 'จ': 23, 'อ': 24, 'น': 25,
+}
+
+// testFontAdvances are the advance widths of the test font's glyphs in
+// font units (2048 per em). Thai combining marks have no advance.
+var testFontAdvances = []int{
+	1000, 512, 1100, 1366, 1300, 700, 700, 600, 1200, 1250, 1260, 1000, 1100,
+	1150, 0, 1400, 0, 0, 1100, 1250, 0, 1250, 1000, 1150, 1200, 1250,
+}
+
+// buildTrueType assembles a minimal TrueType font with the head, hhea, hmtx
+// and cmap tables that parseTrueType reads.
+func buildTrueType(glyphs map[rune]uint16, advances []int, format cmapFormat) []byte {
+	be := binary.BigEndian
+
+	head := make([]byte, 54)
+	be.PutUint16(head[18:], 2048)
+	for i, v := range []int16{-100, -500, 2200, 1900} {
+		be.PutUint16(head[36+2*i:], uint16(v))
+	}
+
+	hhea := make([]byte, 36)
+	for i, v := range []int16{1900, -500} {
+		be.PutUint16(hhea[4+2*i:], uint16(v))
+	}
+	be.PutUint16(hhea[34:], uint16(len(advances)))
+
+	hmtx := make([]byte, 4*len(advances))
+	for i, advance := range advances {
+		be.PutUint16(hmtx[4*i:], uint16(advance))
+	}
+
+	runes := make([]rune, 0, len(glyphs))
+	for r := range glyphs {
+		runes = append(runes, r)
+	}
+	sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+
+	var subtable []byte
+	encodingID := uint16(1)
+	if format == cmapFormat12 {
+		encodingID = 10
+		subtable = make([]byte, 16+12*len(runes))
+		be.PutUint16(subtable, 12)
+		be.PutUint32(subtable[4:], uint32(len(subtable)))
+		be.PutUint32(subtable[12:], uint32(len(runes)))
+		for i, r := range runes {
+			group := subtable[16+12*i:]
+			be.PutUint32(group, uint32(r))
+			be.PutUint32(group[4:], uint32(r))
+			be.PutUint32(group[8:], uint32(glyphs[r]))
+		}
+	} else {
+		// One segment per character plus the mandatory 0xFFFF segment
+		segCount := len(runes) + 1
+		arrayLen := 0
+		if format == cmapFormat4GlyphArray {
+			arrayLen = len(runes)
+		}
+		subtable = make([]byte, 16+8*segCount+2*arrayLen)
+		be.PutUint16(subtable, 4)
+		be.PutUint16(subtable[2:], uint16(len(subtable)))
+		be.PutUint16(subtable[6:], uint16(2*segCount))
+		endCodes := 14
+		startCodes := endCodes + 2*segCount + 2
+		idDeltas := startCodes + 2*segCount
+		idRangeOffsets := idDeltas + 2*segCount
+		glyphArray := idRangeOffsets + 2*segCount
+		for s := 0; s < segCount; s++ {
+			code, glyph := uint16(0xFFFF), uint16(0)
+			if s < len(runes) {
+				code, glyph = uint16(runes[s]), glyphs[runes[s]]
+			}
+			be.PutUint16(subtable[endCodes+2*s:], code)
+			be.PutUint16(subtable[startCodes+2*s:], code)
+			switch {
+			case s == len(runes):
+				be.PutUint16(subtable[idDeltas+2*s:], 1)
+			case format == cmapFormat4GlyphArray:
+				be.PutUint16(subtable[idRangeOffsets+2*s:], uint16(2*(segCount-s)+2*s))
+				be.PutUint16(subtable[glyphArray+2*s:], glyph)
+			default:
+				be.PutUint16(subtable[idDeltas+2*s:], glyph-code)
+			}
+		}
+	}
+
+	cmap := make([]byte, 12, 12+len(subtable))
+	be.PutUint16(cmap[2:], 1)
+	be.PutUint16(cmap[4:], 3)
+	be.PutUint16(cmap[6:], encodingID)
+	be.PutUint32(cmap[8:], 12)
+	cmap = append(cmap, subtable...)
+
+	tables := []struct {
+		tag  string
+		data []byte
+	}{{"cmap", cmap}, {"head", head}, {"hhea", hhea}, {"hmtx", hmtx}}
+
+	font := make([]byte, 12+16*len(tables))
+	be.PutUint32(font, 0x00010000)
+	be.PutUint16(font[4:], uint16(len(tables)))
+	for i, table := range tables {
+		entry := font[12+16*i:]
+		copy(entry, table.tag)
+		be.PutUint32(entry[8:], uint32(len(font)))
+		be.PutUint32(entry[12:], uint32(len(table.data)))
+		font = append(font, table.data...)
+		for len(font)%4 != 0 {
+			font = append(font, 0)
+		}
+	}
+
+	return font
+}
+
+func TestParseTrueType(t *testing.T) {
+	tests := []struct {
+		name   string
+		format cmapFormat
+	}{
+		{name: "cmap format 4 with deltas", format: cmapFormat4Delta},
+		{name: "cmap format 4 with glyph array", format: cmapFormat4GlyphArray},
+		{name: "cmap format 12", format: cmapFormat12},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			font, err := parseTrueType(buildTrueType(testFontGlyphs, testFontAdvances, tt.format))
+			require.NoError(t, err)
+
+			assert.Equal(t, 2048, font.unitsPerEm)
+			assert.Equal(t, 1900, font.ascent)
+			assert.Equal(t, -500, font.descent)
+			assert.Equal(t, [4]int{-100, -500, 2200, 1900}, font.bbox)
+			assert.Equal(t, testFontGlyphs, font.glyphs)
+		})
+	}
+}
+
+func TestTrueTypeAdvance(t *testing.T) {
+	font, err := parseTrueType(buildTrueType(testFontGlyphs, testFontAdvances, cmapFormat4Delta))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		glyph uint16
+		want  int
+	}{
+		{name: "notdef", glyph: 0, want: 488},
+		{name: "space", glyph: 1, want: 250},
+		{name: "latin letter", glyph: 3, want: 666},
+		{name: "thai consonant", glyph: 8, want: 585},
+		{name: "combining mark has no advance", glyph: 14, want: 0},
+		{name: "glyph past hmtx uses last width", glyph: 40, want: 610},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, font.advance(tt.glyph))
+		})
+	}
+
+	assert.Equal(t, 927, font.scale(1900))
+}
+
+func TestParseTrueTypeRejectsInvalidFonts(t *testing.T) {
+	valid := buildTrueType(testFontGlyphs, testFontAdvances, cmapFormat4Delta)
+
+	withoutCmap := append([]byte(nil), valid...)
+	copy(withoutCmap[12:], "xxxx")
+
+	truncated := valid[:40]
+
+	zeroUnits := append([]byte(nil), valid...)
+	headOffset := binary.BigEndian.Uint32(zeroUnits[12+16+8:])
+	binary.BigEndian.PutUint16(zeroUnits[headOffset+18:], 0)
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr string
+	}{
+		{name: "too short", data: []byte{0, 1}, wantErr: "font data is too short"},
+		{name: "missing cmap", data: withoutCmap, wantErr: "font is missing the cmap table"},
+		{name: "truncated tables", data: truncated, wantErr: "truncated"},
+		{name: "zero units per em", data: zeroUnits, wantErr: "font has an invalid unitsPerEm"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseTrueType(tt.data)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
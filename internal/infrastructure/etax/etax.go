@@ -0,0 +1,386 @@
+// Package etax builds Thai e-Tax Invoice XML following the ETDA cross
+// industry invoice standard (ขมธอ. 3-2560) so tax invoices, receipts and
+// credit/debit notes can be submitted through an e-Tax service provider.
+package etax
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	GuidelineID      = "ER3-2560"
+	GuidelineAgency  = "ETDA"
+	GuidelineVersion = "v2.0"
+
+	NamespaceTaxInvoice    = "urn:etda:uncefact:data:standard:TaxInvoice_CrossIndustryInvoice:2"
+	NamespaceTaxInvoiceRAM = "urn:etda:uncefact:data:standard:TaxInvoice_ReusableAggregateBusinessInformationEntity:2"
+	NamespaceNote          = "urn:etda:uncefact:data:standard:DebitCreditNote_CrossIndustryInvoice:2"
+	NamespaceNoteRAM       = "urn:etda:uncefact:data:standard:DebitCreditNote_ReusableAggregateBusinessInformationEntity:2"
+
+	rootTaxInvoice = "TaxInvoice_CrossIndustryInvoice"
+	rootNote       = "DebitCreditNote_CrossIndustryInvoice"
+
+	currencyCode = "THB"
+	countryCode  = "TH"
+	unitCode     = "C62"
+)
+
+// DocumentType is the ETDA document type code.
+type DocumentType string
+
+const (
+	TypeTaxInvoice        DocumentType = "388"
+	TypeReceiptTaxInvoice DocumentType = "T03"
+	TypeDebitNote         DocumentType = "80"
+	TypeCreditNote        DocumentType = "81"
+)
+
+// Name returns the Thai document name printed on the document.
+func (t DocumentType) Name() string {
+	switch t {
+	case TypeTaxInvoice:
+		return "ใบกำกับภาษี"
+	case TypeReceiptTaxInvoice:
+		return "ใบเสร็จรับเงิน/ใบกำกับภาษี"
+	case TypeDebitNote:
+		return "ใบเพิ่มหนี้"
+	case TypeCreditNote:
+		return "ใบลดหนี้"
+	}
+	return ""
+}
+
+func (t DocumentType) isNote() bool {
+	return t == TypeDebitNote || t == TypeCreditNote
+}
+
+func (t DocumentType) isValid() bool {
+	return t.Name() != ""
+}
+
+// Party is the seller or buyer of a document. TaxID is the 13 digit tax
+// identification number and Branch the 5 digit branch code ("00000" for the
+// head office). A buyer without a tax ID is exported as "N/A".
+type Party struct {
+	Name     string
+	TaxID    string
+	Branch   string
+	Address  string
+	Postcode string
+}
+
+// LineItem is a document line. Amounts are before VAT.
+type LineItem struct {
+	Name      string
+	Quantity  float64
+	UnitPrice float64
+	NetAmount float64
+}
+
+// Reference is the original tax invoice a credit or debit note adjusts.
+type Reference struct {
+	ID        string
+	IssueDate time.Time
+	TypeCode  DocumentType
+}
+
+// Document holds the data of a tax document. OriginalAmount and
+// DifferenceAmount are only used by credit and debit notes, and are the
+// value of the original invoice and of the adjustment before VAT.
+type Document struct {
+	TypeCode    DocumentType
+	ID          string
+	IssueDate   time.Time
+	Purpose     string
+	PurposeCode string
+
+	Seller    Party
+	Buyer     Party
+	Reference *Reference
+
+	TaxRate float64
+	Lines   []LineItem
+
+	OriginalAmount   float64
+	DifferenceAmount float64
+	BasisAmount      float64
+	TaxAmount        float64
+	GrandTotal       float64
+}
+
+// Marshal renders the document as e-Tax XML and checks the result with
+// Validate before returning it.
+func Marshal(doc *Document) ([]byte, error) {
+	if doc == nil {
+		return nil, errors.New("e-tax document is required")
+	}
+	if !doc.TypeCode.isValid() {
+		return nil, fmt.Errorf("unsupported e-tax document type %q", doc.TypeCode)
+	}
+	if doc.TypeCode.isNote() && doc.Reference == nil {
+		return nil, errors.New("credit and debit notes must reference the original tax invoice")
+	}
+
+	root := xmlDocument{
+		XMLName: xml.Name{Local: "rsm:" + rootTaxInvoice},
+		RSM:     NamespaceTaxInvoice,
+		RAM:     NamespaceTaxInvoiceRAM,
+	}
+	if doc.TypeCode.isNote() {
+		root.XMLName.Local = "rsm:" + rootNote
+		root.RSM = NamespaceNote
+		root.RAM = NamespaceNoteRAM
+	}
+
+	root.Context.Guideline.ID = xmlSchemeID{
+		AgencyID:  GuidelineAgency,
+		VersionID: GuidelineVersion,
+		Value:     GuidelineID,
+	}
+
+	root.Header = xmlHeader{
+		ID:           doc.ID,
+		Name:         doc.TypeCode.Name(),
+		TypeCode:     string(doc.TypeCode),
+		IssueDate:    formatDateTime(doc.IssueDate),
+		Purpose:      doc.Purpose,
+		PurposeCode:  doc.PurposeCode,
+		CreationDate: formatDateTime(time.Now()),
+	}
+
+	transaction := &root.Transaction
+	transaction.Agreement.Seller = toXMLParty(doc.Seller)
+	transaction.Agreement.Buyer = toXMLParty(doc.Buyer)
+	if doc.Reference != nil {
+		transaction.Agreement.Reference = &xmlReference{
+			ID:        doc.Reference.ID,
+			IssueDate: formatDateTime(doc.Reference.IssueDate),
+			TypeCode:  string(doc.Reference.TypeCode),
+		}
+	}
+
+	settlement := &transaction.Settlement
+	settlement.Currency = xmlCode{ListID: "ISO 4217 3A", Value: currencyCode}
+	settlement.Tax = xmlTradeTax{
+		TypeCode:         "VAT",
+		Rate:             formatAmount(doc.TaxRate),
+		BasisAmount:      formatAmount(doc.BasisAmount),
+		CalculatedAmount: formatAmount(doc.TaxAmount),
+	}
+
+	var lineTotal float64
+	for i, line := range doc.Lines {
+		lineTotal += line.NetAmount
+		lineTax := roundAmount(line.NetAmount * doc.TaxRate / 100)
+		transaction.Lines = append(transaction.Lines, xmlLineItem{
+			LineID:    fmt.Sprintf("%d", i+1),
+			Name:      line.Name,
+			UnitPrice: formatAmount(line.UnitPrice),
+			Quantity:  xmlQuantity{UnitCode: unitCode, Value: formatQuantity(line.Quantity)},
+			Tax: xmlTradeTax{
+				TypeCode:         "VAT",
+				Rate:             formatAmount(doc.TaxRate),
+				CalculatedAmount: formatAmount(lineTax),
+			},
+			Summation: xmlLineSummation{
+				TaxTotal:             formatAmount(lineTax),
+				NetTotal:             xmlAmount{CurrencyID: currencyCode, Value: formatAmount(line.NetAmount)},
+				NetIncludingTaxTotal: xmlAmount{CurrencyID: currencyCode, Value: formatAmount(line.NetAmount + lineTax)},
+			},
+		})
+	}
+
+	summation := &settlement.Summation
+	if doc.TypeCode.isNote() {
+		summation.Original = formatAmount(doc.OriginalAmount)
+		summation.Difference = formatAmount(doc.DifferenceAmount)
+	}
+	summation.LineTotal = formatAmount(lineTotal)
+	summation.TaxBasisTotal = formatAmount(doc.BasisAmount)
+	summation.TaxTotal = formatAmount(doc.TaxAmount)
+	summation.GrandTotal = formatAmount(doc.GrandTotal)
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&buf)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(root); err != nil {
+		return nil, fmt.Errorf("failed to encode e-tax document: %w", err)
+	}
+	buf.WriteByte('\n')
+
+	if err := Validate(buf.Bytes()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// TaxRegistrationID returns the value of the tax registration ID element: the
+// tax ID followed by the branch code, or "N/A" when there is no tax ID.
+func TaxRegistrationID(taxID, branch string) (scheme, value string) {
+	if taxID == "" {
+		return "OTHR", "N/A"
+	}
+	if branch == "" {
+		branch = "00000"
+	}
+	return "TXID", taxID + branch
+}
+
+func toXMLParty(party Party) xmlParty {
+	scheme, id := TaxRegistrationID(party.TaxID, party.Branch)
+	return xmlParty{
+		Name:            party.Name,
+		TaxRegistration: xmlTaxRegistration{ID: xmlScheme{SchemeID: scheme, Value: id}},
+		Address: xmlAddress{
+			Postcode: party.Postcode,
+			LineOne:  party.Address,
+			Country:  xmlScheme{SchemeID: "3166-1 alpha-2", Value: countryCode},
+		},
+	}
+}
+
+func formatDateTime(t time.Time) string {
+	return t.Format("2006-01-02T15:04:05")
+}
+
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+func formatAmount(amount float64) string {
+	return fmt.Sprintf("%.2f", roundAmount(amount))
+}
+
+func formatQuantity(quantity float64) string {
+	return fmt.Sprintf("%.4f", quantity)
+}
+
+type xmlDocument struct {
+	XMLName xml.Name
+	RSM     string `xml:"xmlns:rsm,attr"`
+	RAM     string `xml:"xmlns:ram,attr"`
+
+	Context     xmlContext     `xml:"rsm:ExchangedDocumentContext"`
+	Header      xmlHeader      `xml:"rsm:ExchangedDocument"`
+	Transaction xmlTransaction `xml:"rsm:SupplyChainTradeTransaction"`
+}
+
+type xmlContext struct {
+	Guideline struct {
+		ID xmlSchemeID `xml:"ram:ID"`
+	} `xml:"ram:GuidelineSpecifiedDocumentContextParameter"`
+}
+
+type xmlSchemeID struct {
+	AgencyID  string `xml:"schemeAgencyID,attr"`
+	VersionID string `xml:"schemeVersionID,attr"`
+	Value     string `xml:",chardata"`
+}
+
+type xmlHeader struct {
+	ID           string `xml:"ram:ID"`
+	Name         string `xml:"ram:Name"`
+	TypeCode     string `xml:"ram:TypeCode"`
+	IssueDate    string `xml:"ram:IssueDateTime"`
+	Purpose      string `xml:"ram:Purpose,omitempty"`
+	PurposeCode  string `xml:"ram:PurposeCode,omitempty"`
+	CreationDate string `xml:"ram:CreationDateTime"`
+}
+
+type xmlTransaction struct {
+	Agreement  xmlAgreement  `xml:"ram:ApplicableHeaderTradeAgreement"`
+	Delivery   struct{}      `xml:"ram:ApplicableHeaderTradeDelivery"`
+	Settlement xmlSettlement `xml:"ram:ApplicableHeaderTradeSettlement"`
+	Lines      []xmlLineItem `xml:"ram:IncludedSupplyChainTradeLineItem"`
+}
+
+type xmlAgreement struct {
+	Seller    xmlParty      `xml:"ram:SellerTradeParty"`
+	Buyer     xmlParty      `xml:"ram:BuyerTradeParty"`
+	Reference *xmlReference `xml:"ram:AdditionalReferencedDocument,omitempty"`
+}
+
+type xmlParty struct {
+	Name            string             `xml:"ram:Name"`
+	TaxRegistration xmlTaxRegistration `xml:"ram:SpecifiedTaxRegistration"`
+	Address         xmlAddress         `xml:"ram:PostalTradeAddress"`
+}
+
+type xmlTaxRegistration struct {
+	ID xmlScheme `xml:"ram:ID"`
+}
+
+type xmlScheme struct {
+	SchemeID string `xml:"schemeID,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type xmlAddress struct {
+	Postcode string    `xml:"ram:PostcodeCode,omitempty"`
+	LineOne  string    `xml:"ram:LineOne,omitempty"`
+	Country  xmlScheme `xml:"ram:CountryID"`
+}
+
+type xmlReference struct {
+	ID        string `xml:"ram:IssuerAssignedID"`
+	IssueDate string `xml:"ram:IssueDateTime"`
+	TypeCode  string `xml:"ram:ReferenceTypeCode"`
+}
+
+type xmlSettlement struct {
+	Currency  xmlCode            `xml:"ram:InvoiceCurrencyCode"`
+	Tax       xmlTradeTax        `xml:"ram:ApplicableTradeTax"`
+	Summation xmlHeaderSummation `xml:"ram:SpecifiedTradeSettlementHeaderMonetarySummation"`
+}
+
+type xmlCode struct {
+	ListID string `xml:"listID,attr"`
+	Value  string `xml:",chardata"`
+}
+
+type xmlTradeTax struct {
+	TypeCode         string `xml:"ram:TypeCode"`
+	Rate             string `xml:"ram:CalculatedRate"`
+	BasisAmount      string `xml:"ram:BasisAmount,omitempty"`
+	CalculatedAmount string `xml:"ram:CalculatedAmount"`
+}
+
+type xmlHeaderSummation struct {
+	Original      string `xml:"ram:OriginalInformationAmount,omitempty"`
+	LineTotal     string `xml:"ram:LineTotalAmount"`
+	Difference    string `xml:"ram:DifferenceInformationAmount,omitempty"`
+	TaxBasisTotal string `xml:"ram:TaxBasisTotalAmount"`
+	TaxTotal      string `xml:"ram:TaxTotalAmount"`
+	GrandTotal    string `xml:"ram:GrandTotalAmount"`
+}
+
+type xmlLineItem struct {
+	LineID    string           `xml:"ram:AssociatedDocumentLineDocument>ram:LineID"`
+	Name      string           `xml:"ram:SpecifiedTradeProduct>ram:Name"`
+	UnitPrice string           `xml:"ram:SpecifiedLineTradeAgreement>ram:GrossPriceProductTradePrice>ram:ChargeAmount"`
+	Quantity  xmlQuantity      `xml:"ram:SpecifiedLineTradeDelivery>ram:BilledQuantity"`
+	Tax       xmlTradeTax      `xml:"ram:SpecifiedLineTradeSettlement>ram:ApplicableTradeTax"`
+	Summation xmlLineSummation `xml:"ram:SpecifiedLineTradeSettlement>ram:SpecifiedTradeSettlementLineMonetarySummation"`
+}
+
+type xmlQuantity struct {
+	UnitCode string `xml:"unitCode,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type xmlAmount struct {
+	CurrencyID string `xml:"currencyID,attr"`
+	Value      string `xml:",chardata"`
+}
+
+type xmlLineSummation struct {
+	TaxTotal             string    `xml:"ram:TaxTotalAmount"`
+	NetTotal             xmlAmount `xml:"ram:NetLineTotalAmount"`
+	NetIncludingTaxTotal xmlAmount `xml:"ram:NetIncludingTaxesLineTotalAmount"`
+}
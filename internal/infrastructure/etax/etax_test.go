@@ -0,0 +1,298 @@
+package etax_test
+
+import (
+	"boonkosang/internal/infrastructure/etax"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func taxInvoice() *etax.Document {
+	return &etax.Document{
+		TypeCode:  etax.TypeTaxInvoice,
+		ID:        "INV-2610-001",
+		IssueDate: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+		Seller: etax.Party{
+			Name:     "Boonkosang Construction Co., Ltd.",
+			TaxID:    "0105561234567",
+			Branch:   "00000",
+			Address:  "99 Sukhumvit Road Khlong Toei Bangkok",
+			Postcode: "10110",
+		},
+		Buyer: etax.Party{
+			Name:     "Client Co., Ltd.",
+			TaxID:    "0105559876543",
+			Branch:   "00001",
+			Address:  "1 Silom Road Bang Rak Bangkok",
+			Postcode: "10500",
+		},
+		TaxRate: 7,
+		Lines: []etax.LineItem{
+			{Name: "Period 1", Quantity: 1, UnitPrice: 100000, NetAmount: 100000},
+		},
+		BasisAmount: 100000,
+		TaxAmount:   7000,
+		GrandTotal:  107000,
+	}
+}
+
+func creditNote() *etax.Document {
+	doc := taxInvoice()
+	doc.TypeCode = etax.TypeCreditNote
+	doc.ID = "CN-2610-001"
+	doc.Purpose = "Discount after completion"
+	doc.PurposeCode = "CDNS99"
+	doc.Reference = &etax.Reference{
+		ID:        "INV-2610-001",
+		IssueDate: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+		TypeCode:  etax.TypeTaxInvoice,
+	}
+	doc.Lines = []etax.LineItem{{Name: "Discount", Quantity: 1, UnitPrice: 5000, NetAmount: 5000}}
+	doc.OriginalAmount = 100000
+	doc.DifferenceAmount = 5000
+	doc.BasisAmount = 5000
+	doc.TaxAmount = 350
+	doc.GrandTotal = 5350
+	return doc
+}
+
+func TestMarshalTaxInvoice(t *testing.T) {
+	data, err := etax.Marshal(taxInvoice())
+	require.NoError(t, err)
+
+	xml := string(data)
+	assert.Contains(t, xml, `<rsm:TaxInvoice_CrossIndustryInvoice xmlns:rsm="`+etax.NamespaceTaxInvoice+`"`)
+	assert.Contains(t, xml, `<ram:ID schemeAgencyID="ETDA" schemeVersionID="v2.0">ER3-2560</ram:ID>`)
+	assert.Contains(t, xml, `<ram:TypeCode>388</ram:TypeCode>`)
+	assert.Contains(t, xml, `<ram:ID schemeID="TXID">010556123456700000</ram:ID>`)
+	assert.Contains(t, xml, `<ram:ID schemeID="TXID">010555987654300001</ram:ID>`)
+	assert.Contains(t, xml, `<ram:GrandTotalAmount>107000.00</ram:GrandTotalAmount>`)
+	assert.NotContains(t, xml, "AdditionalReferencedDocument")
+}
+
+func TestMarshalCreditNote(t *testing.T) {
+	data, err := etax.Marshal(creditNote())
+	require.NoError(t, err)
+
+	xml := string(data)
+	assert.Contains(t, xml, `<rsm:DebitCreditNote_CrossIndustryInvoice xmlns:rsm="`+etax.NamespaceNote+`"`)
+	assert.Contains(t, xml, `<ram:IssuerAssignedID>INV-2610-001</ram:IssuerAssignedID>`)
+	assert.Contains(t, xml, `<ram:OriginalInformationAmount>100000.00</ram:OriginalInformationAmount>`)
+	assert.Contains(t, xml, `<ram:DifferenceInformationAmount>5000.00</ram:DifferenceInformationAmount>`)
+}
+
+func TestMarshalBuyerWithoutTaxID(t *testing.T) {
+	doc := taxInvoice()
+	doc.Buyer.TaxID = ""
+
+	data, err := etax.Marshal(doc)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `<ram:ID schemeID="OTHR">N/A</ram:ID>`)
+}
+
+// schemaFiles are the root XSD files in testdata/schema by root element.
+var schemaFiles = map[string]string{
+	"TaxInvoice_CrossIndustryInvoice":      "TaxInvoice_CrossIndustryInvoice_2p0.xsd",
+	"DebitCreditNote_CrossIndustryInvoice": "DebitCreditNote_CrossIndustryInvoice_2p0.xsd",
+}
+
+// validateSchema checks data against the e-Tax XSD with xmllint.
+func validateSchema(t *testing.T, root string, data []byte) error {
+	t.Helper()
+
+	xmllint, err := exec.LookPath("xmllint")
+	if err != nil {
+		t.Fatal("xmllint is required for the e-Tax schema tests; install libxml2-utils")
+	}
+
+	path := filepath.Join(t.TempDir(), "document.xml")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	output, err := exec.Command(xmllint, "--noout", "--schema", filepath.Join("testdata", "schema", schemaFiles[root]), path).CombinedOutput()
+	if err != nil {
+		return errors.New(string(output))
+	}
+	return nil
+}
+
+func TestMarshalMatchesSchema(t *testing.T) {
+	debitNote := func() *etax.Document {
+		doc := creditNote()
+		doc.TypeCode = etax.TypeDebitNote
+		doc.PurposeCode = "DBNS99"
+		return doc
+	}
+	withoutBuyerTaxID := func() *etax.Document {
+		doc := taxInvoice()
+		doc.Buyer.TaxID = ""
+		return doc
+	}
+
+	tests := []struct {
+		name string
+		doc  *etax.Document
+		root string
+	}{
+		{name: "tax invoice", doc: taxInvoice(), root: "TaxInvoice_CrossIndustryInvoice"},
+		{name: "buyer without tax ID", doc: withoutBuyerTaxID(), root: "TaxInvoice_CrossIndustryInvoice"},
+		{name: "credit note", doc: creditNote(), root: "DebitCreditNote_CrossIndustryInvoice"},
+		{name: "debit note", doc: debitNote(), root: "DebitCreditNote_CrossIndustryInvoice"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := etax.Marshal(tt.doc)
+			require.NoError(t, err)
+			assert.NoError(t, validateSchema(t, tt.root, data))
+		})
+	}
+}
+
+func TestSchemaRejectsInvalidDocuments(t *testing.T) {
+	valid, err := etax.Marshal(taxInvoice())
+	require.NoError(t, err)
+	require.NoError(t, validateSchema(t, "TaxInvoice_CrossIndustryInvoice", valid))
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{
+			name:    "missing currency",
+			data:    strings.Replace(string(valid), `<ram:InvoiceCurrencyCode listID="ISO 4217 3A">THB</ram:InvoiceCurrencyCode>`, "", 1),
+			wantErr: "InvoiceCurrencyCode",
+		},
+		{
+			name:    "unknown type code",
+			data:    strings.Replace(string(valid), `<ram:TypeCode>388</ram:TypeCode>`, `<ram:TypeCode>999</ram:TypeCode>`, 1),
+			wantErr: "TypeCode",
+		},
+		{
+			name:    "amount with three decimals",
+			data:    strings.Replace(string(valid), `<ram:GrandTotalAmount>107000.00</ram:GrandTotalAmount>`, `<ram:GrandTotalAmount>107000.001</ram:GrandTotalAmount>`, 1),
+			wantErr: "GrandTotalAmount",
+		},
+		{
+			name:    "missing document name",
+			data:    strings.Replace(string(valid), `<ram:Name>ใบกำกับภาษี</ram:Name>`, "", 1),
+			wantErr: "TypeCode",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSchema(t, "TaxInvoice_CrossIndustryInvoice", []byte(tt.data))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestMarshalRejectsInvalidDocuments(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(doc *etax.Document)
+		wantErr string
+	}{
+		{
+			name:    "seller tax ID too short",
+			modify:  func(doc *etax.Document) { doc.Seller.TaxID = "12345" },
+			wantErr: "SellerTradeParty/SpecifiedTaxRegistration/ID has invalid value",
+		},
+		{
+			name:    "seller without tax ID",
+			modify:  func(doc *etax.Document) { doc.Seller.TaxID = "" },
+			wantErr: "SellerTradeParty/SpecifiedTaxRegistration/ID has invalid value",
+		},
+		{
+			name:    "missing document number",
+			modify:  func(doc *etax.Document) { doc.ID = "" },
+			wantErr: "ExchangedDocument/ID has invalid value",
+		},
+		{
+			name:    "no line items",
+			modify:  func(doc *etax.Document) { doc.Lines = nil },
+			wantErr: "at least one IncludedSupplyChainTradeLineItem is required",
+		},
+		{
+			name:    "totals do not add up",
+			modify:  func(doc *etax.Document) { doc.GrandTotal = 100000 },
+			wantErr: "GrandTotalAmount does not equal TaxBasisTotalAmount plus TaxTotalAmount",
+		},
+		{
+			name:    "note without reference",
+			modify:  func(doc *etax.Document) { doc.TypeCode = etax.TypeCreditNote },
+			wantErr: "must reference the original tax invoice",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := taxInvoice()
+			tt.modify(doc)
+
+			_, err := etax.Marshal(doc)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid, err := etax.Marshal(taxInvoice())
+	require.NoError(t, err)
+	require.NoError(t, etax.Validate(valid))
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{
+			name:    "not XML",
+			data:    "not xml",
+			wantErr: "invalid e-tax document",
+		},
+		{
+			name:    "unknown root element",
+			data:    `<Invoice xmlns="urn:oasis:names:specification:ubl:schema:xsd:Invoice-2"/>`,
+			wantErr: "unexpected root element",
+		},
+		{
+			name:    "wrong namespace",
+			data:    strings.Replace(string(valid), etax.NamespaceTaxInvoiceRAM, "urn:example:ram", 1),
+			wantErr: "must be in namespace " + etax.NamespaceTaxInvoiceRAM,
+		},
+		{
+			name:    "missing currency",
+			data:    strings.Replace(string(valid), `<ram:InvoiceCurrencyCode listID="ISO 4217 3A">THB</ram:InvoiceCurrencyCode>`, "", 1),
+			wantErr: "InvoiceCurrencyCode is required",
+		},
+		{
+			name:    "tax invoice with note type code",
+			data:    strings.Replace(string(valid), `<ram:TypeCode>388</ram:TypeCode>`, `<ram:TypeCode>81</ram:TypeCode>`, 1),
+			wantErr: "does not match root element",
+		},
+		{
+			name:    "line total mismatch",
+			data:    strings.Replace(string(valid), `<ram:LineTotalAmount>100000.00</ram:LineTotalAmount>`, `<ram:LineTotalAmount>90000.00</ram:LineTotalAmount>`, 1),
+			wantErr: "LineTotalAmount does not equal the sum of the line amounts",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := etax.Validate([]byte(tt.data))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,277 @@
+package etax
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	patternDateTime = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?$`)
+	patternAmount   = regexp.MustCompile(`^-?\d+(\.\d{1,2})?$`)
+	patternQuantity = regexp.MustCompile(`^\d+(\.\d{1,4})?$`)
+	patternTaxID    = regexp.MustCompile(`^\d{18}$`)
+	patternNotEmpty = regexp.MustCompile(`\S`)
+)
+
+// element is a parsed XML element with namespace-resolved names.
+type element struct {
+	name     xml.Name
+	attrs    []xml.Attr
+	text     string
+	children []*element
+}
+
+func (e *element) attr(name string) string {
+	for _, attr := range e.attrs {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// find returns the elements at a slash separated path of local names below e.
+func (e *element) find(path string) []*element {
+	current := []*element{e}
+	for _, local := range strings.Split(path, "/") {
+		var next []*element
+		for _, parent := range current {
+			for _, child := range parent.children {
+				if child.name.Local == local {
+					next = append(next, child)
+				}
+			}
+		}
+		current = next
+	}
+	return current
+}
+
+type rule struct {
+	path    string
+	pattern *regexp.Regexp
+}
+
+const (
+	agreementPath  = "SupplyChainTradeTransaction/ApplicableHeaderTradeAgreement"
+	settlementPath = "SupplyChainTradeTransaction/ApplicableHeaderTradeSettlement"
+	summationPath  = settlementPath + "/SpecifiedTradeSettlementHeaderMonetarySummation"
+	linePath       = "SupplyChainTradeTransaction/IncludedSupplyChainTradeLineItem"
+)
+
+// documentRules are the elements every document must contain once.
+var documentRules = []rule{
+	{"ExchangedDocumentContext/GuidelineSpecifiedDocumentContextParameter/ID", regexp.MustCompile(`^` + GuidelineID + `$`)},
+	{"ExchangedDocument/ID", regexp.MustCompile(`^\S.{0,34}$`)},
+	{"ExchangedDocument/Name", patternNotEmpty},
+	{"ExchangedDocument/TypeCode", regexp.MustCompile(`^(388|T03|80|81)$`)},
+	{"ExchangedDocument/IssueDateTime", patternDateTime},
+	{"ExchangedDocument/CreationDateTime", patternDateTime},
+	{agreementPath + "/SellerTradeParty/Name", patternNotEmpty},
+	{agreementPath + "/SellerTradeParty/SpecifiedTaxRegistration/ID", patternTaxID},
+	{agreementPath + "/SellerTradeParty/PostalTradeAddress/CountryID", regexp.MustCompile(`^[A-Z]{2}$`)},
+	{agreementPath + "/BuyerTradeParty/Name", patternNotEmpty},
+	{agreementPath + "/BuyerTradeParty/SpecifiedTaxRegistration/ID", regexp.MustCompile(`^(\d{18}|N/A)$`)},
+	{agreementPath + "/BuyerTradeParty/PostalTradeAddress/CountryID", regexp.MustCompile(`^[A-Z]{2}$`)},
+	{settlementPath + "/InvoiceCurrencyCode", regexp.MustCompile(`^` + currencyCode + `$`)},
+	{settlementPath + "/ApplicableTradeTax/TypeCode", regexp.MustCompile(`^VAT$`)},
+	{settlementPath + "/ApplicableTradeTax/CalculatedRate", patternAmount},
+	{settlementPath + "/ApplicableTradeTax/BasisAmount", patternAmount},
+	{settlementPath + "/ApplicableTradeTax/CalculatedAmount", patternAmount},
+	{summationPath + "/LineTotalAmount", patternAmount},
+	{summationPath + "/TaxBasisTotalAmount", patternAmount},
+	{summationPath + "/TaxTotalAmount", patternAmount},
+	{summationPath + "/GrandTotalAmount", patternAmount},
+}
+
+// noteRules are the additional elements required on credit and debit notes.
+var noteRules = []rule{
+	{"ExchangedDocument/Purpose", patternNotEmpty},
+	{"ExchangedDocument/PurposeCode", regexp.MustCompile(`^(CDNG|CDNS|DBNG|DBNS)\d{2}$`)},
+	{agreementPath + "/AdditionalReferencedDocument/IssuerAssignedID", patternNotEmpty},
+	{agreementPath + "/AdditionalReferencedDocument/IssueDateTime", patternDateTime},
+	{agreementPath + "/AdditionalReferencedDocument/ReferenceTypeCode", regexp.MustCompile(`^(388|T02|T03|T04)$`)},
+	{summationPath + "/OriginalInformationAmount", patternAmount},
+	{summationPath + "/DifferenceInformationAmount", patternAmount},
+}
+
+// lineRules are the elements every line item must contain once.
+var lineRules = []rule{
+	{"AssociatedDocumentLineDocument/LineID", regexp.MustCompile(`^\d+$`)},
+	{"SpecifiedTradeProduct/Name", patternNotEmpty},
+	{"SpecifiedLineTradeAgreement/GrossPriceProductTradePrice/ChargeAmount", patternAmount},
+	{"SpecifiedLineTradeDelivery/BilledQuantity", patternQuantity},
+	{"SpecifiedLineTradeSettlement/SpecifiedTradeSettlementLineMonetarySummation/NetLineTotalAmount", patternAmount},
+	{"SpecifiedLineTradeSettlement/SpecifiedTradeSettlementLineMonetarySummation/NetIncludingTaxesLineTotalAmount", patternAmount},
+}
+
+// Validate checks e-Tax XML against the structure of the ETDA schema: root
+// element and namespaces, required elements and their formats, tax
+// registration IDs and that the totals add up. It is not a compliance check
+// on its own. The tests also check Marshal output with xmllint against the
+// XSD in testdata/schema, which is written from the standard until the
+// official ETDA schema package replaces it.
+func Validate(data []byte) error {
+	root, err := parse(data)
+	if err != nil {
+		return fmt.Errorf("invalid e-tax document: %w", err)
+	}
+
+	var problems []string
+	addProblem := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	rsm, ram := NamespaceTaxInvoice, NamespaceTaxInvoiceRAM
+	isNote := false
+	switch root.name {
+	case xml.Name{Space: NamespaceTaxInvoice, Local: rootTaxInvoice}:
+	case xml.Name{Space: NamespaceNote, Local: rootNote}:
+		rsm, ram = NamespaceNote, NamespaceNoteRAM
+		isNote = true
+	default:
+		return fmt.Errorf("invalid e-tax document: unexpected root element {%s}%s", root.name.Space, root.name.Local)
+	}
+
+	for _, child := range root.children {
+		if child.name.Space != rsm {
+			addProblem("%s must be in namespace %s", child.name.Local, rsm)
+		}
+		checkNamespace(child.children, ram, addProblem)
+	}
+
+	rules := documentRules
+	if isNote {
+		rules = append(append([]rule{}, documentRules...), noteRules...)
+	}
+	checkRules(root, "", rules, addProblem)
+
+	typeCode := textOf(root, "ExchangedDocument/TypeCode")
+	if noteType := typeCode == string(TypeDebitNote) || typeCode == string(TypeCreditNote); typeCode != "" && noteType != isNote {
+		addProblem("type code %s does not match root element %s", typeCode, root.name.Local)
+	}
+
+	for _, party := range []string{"SellerTradeParty", "BuyerTradeParty"} {
+		for _, id := range root.find(agreementPath + "/" + party + "/SpecifiedTaxRegistration/ID") {
+			scheme := id.attr("schemeID")
+			if (scheme == "TXID") != patternTaxID.MatchString(id.text) || (scheme != "TXID" && scheme != "OTHR") {
+				addProblem("%s tax registration %q does not match scheme %q", party, id.text, scheme)
+			}
+		}
+	}
+
+	lines := root.find(linePath)
+	if len(lines) == 0 {
+		addProblem("at least one IncludedSupplyChainTradeLineItem is required")
+	}
+	var lineTotal float64
+	for i, line := range lines {
+		checkRules(line, fmt.Sprintf("line %d: ", i+1), lineRules, addProblem)
+		lineTotal += amountOf(line, "SpecifiedLineTradeSettlement/SpecifiedTradeSettlementLineMonetarySummation/NetLineTotalAmount")
+	}
+
+	if len(problems) == 0 {
+		if !amountsEqual(lineTotal, amountOf(root, summationPath+"/LineTotalAmount")) {
+			addProblem("LineTotalAmount does not equal the sum of the line amounts")
+		}
+		basis := amountOf(root, summationPath+"/TaxBasisTotalAmount")
+		tax := amountOf(root, summationPath+"/TaxTotalAmount")
+		if !amountsEqual(basis+tax, amountOf(root, summationPath+"/GrandTotalAmount")) {
+			addProblem("GrandTotalAmount does not equal TaxBasisTotalAmount plus TaxTotalAmount")
+		}
+		if !amountsEqual(tax, amountOf(root, settlementPath+"/ApplicableTradeTax/CalculatedAmount")) {
+			addProblem("TaxTotalAmount does not equal the VAT amount")
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid e-tax document: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func parse(data []byte) (*element, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var root *element
+	var stack []*element
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			e := &element{name: t.Name, attrs: t.Attr}
+			if len(stack) == 0 {
+				if root != nil {
+					return nil, fmt.Errorf("more than one root element")
+				}
+				root = e
+			} else {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, e)
+			}
+			stack = append(stack, e)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text += string(t)
+			}
+		}
+	}
+	if root == nil {
+		return nil, fmt.Errorf("document is empty")
+	}
+	return root, nil
+}
+
+func checkNamespace(elements []*element, namespace string, addProblem func(string, ...interface{})) {
+	for _, e := range elements {
+		if e.name.Space != namespace {
+			addProblem("%s must be in namespace %s", e.name.Local, namespace)
+		}
+		checkNamespace(e.children, namespace, addProblem)
+	}
+}
+
+func checkRules(parent *element, prefix string, rules []rule, addProblem func(string, ...interface{})) {
+	for _, r := range rules {
+		found := parent.find(r.path)
+		switch {
+		case len(found) == 0:
+			addProblem("%s%s is required", prefix, r.path)
+		case len(found) > 1:
+			addProblem("%s%s must appear once", prefix, r.path)
+		case !r.pattern.MatchString(strings.TrimSpace(found[0].text)):
+			addProblem("%s%s has invalid value %q", prefix, r.path, found[0].text)
+		}
+	}
+}
+
+func textOf(parent *element, path string) string {
+	found := parent.find(path)
+	if len(found) == 0 {
+		return ""
+	}
+	return strings.TrimSpace(found[0].text)
+}
+
+func amountOf(parent *element, path string) float64 {
+	amount, _ := strconv.ParseFloat(textOf(parent, path), 64)
+	return amount
+}
+
+func amountsEqual(a, b float64) bool {
+	return math.Abs(a-b) < 0.005
+}
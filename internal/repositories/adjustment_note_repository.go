@@ -0,0 +1,18 @@
+package repositories
+
+import (
+	"boonkosang/internal/domain/models"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type AdjustmentNoteRepository interface {
+	// Create allocates the note number, stores the note and updates the
+	// invoice adjustment amount, status and paid date in one transaction,
+	// with the invoice locked while update works out its new status.
+	Create(ctx context.Context, note *models.AdjustmentNote, update models.InvoiceBalanceUpdate) error
+	GetByID(ctx context.Context, noteID uuid.UUID) (*models.AdjustmentNote, error)
+	ListByInvoiceID(ctx context.Context, invoiceID uuid.UUID) ([]models.AdjustmentNote, error)
+	Void(ctx context.Context, note *models.AdjustmentNote, update models.InvoiceBalanceUpdate) error
+}
@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"boonkosang/internal/domain/models"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type ChangeOrderRepository interface {
+	Create(ctx context.Context, changeOrder *models.ChangeOrder) error
+	Update(ctx context.Context, changeOrder *models.ChangeOrder) error
+	Delete(ctx context.Context, changeOrderID uuid.UUID) error
+	GetByID(ctx context.Context, changeOrderID uuid.UUID) (*models.ChangeOrder, error)
+	ListByContractID(ctx context.Context, contractID uuid.UUID) ([]models.ChangeOrder, error)
+	UpdateStatus(ctx context.Context, changeOrderID uuid.UUID, status models.ChangeOrderStatus, rejectReason string) error
+
+	// Approve applies the change order to the BOQ, payment periods and quotation
+	// final amount, creates invoices for the new periods and marks it approved,
+	// all in one transaction. Adjusted periods are updated in place so the
+	// contract's periods always show the amounts to invoice; the amounts before
+	// each change stay on change_order_period.original_amount.
+	Approve(ctx context.Context, changeOrder *models.ChangeOrder) error
+	GetPeriodInvoiceStatuses(ctx context.Context, contractID uuid.UUID) (map[uuid.UUID]string, error)
+}
@@ -10,4 +10,5 @@ import (
 type CompanyRepository interface {
 	GetOrCreateCompanyByUserID(ctx context.Context, userID uuid.UUID) (*models.Company, error)
 	UpdateCompany(ctx context.Context, company *models.Company) error
+	GetByID(ctx context.Context, companyID uuid.UUID) (*models.Company, error)
 }
@@ -16,5 +16,9 @@ type ContractRepository interface {
 	GetByProjectID(ctx context.Context, projectID uuid.UUID) (*models.Contract, error)
 	ValidateProjectStatus(ctx context.Context, projectID uuid.UUID) error
 
-	ChangeStatus(ctx context.Context, projectID uuid.UUID, status string) error
+	// ChangeStatus moves the contract from history.FromStatus to history.ToStatus
+	// and records the transition. When the contract first becomes binding, its
+	// periods are invoiced in the same transaction.
+	ChangeStatus(ctx context.Context, history *models.ContractStatusHistory) error
+	GetStatusHistory(ctx context.Context, contractID uuid.UUID) ([]models.ContractStatusHistory, error)
 }
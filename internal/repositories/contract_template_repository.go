@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"boonkosang/internal/domain/models"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type ContractTemplateRepository interface {
+	Create(ctx context.Context, template *models.ContractClauseTemplate) error
+	Update(ctx context.Context, template *models.ContractClauseTemplate) error
+	Delete(ctx context.Context, companyID uuid.UUID, templateID uuid.UUID) error
+	GetByID(ctx context.Context, companyID uuid.UUID, templateID uuid.UUID) (*models.ContractClauseTemplate, error)
+	ListByCompanyID(ctx context.Context, companyID uuid.UUID) ([]models.ContractClauseTemplate, error)
+}
@@ -0,0 +1,14 @@
+package repositories
+
+import (
+	"boonkosang/internal/domain/models"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type DocumentNumberRepository interface {
+	ListFormats(ctx context.Context, companyID uuid.UUID) ([]models.DocumentNumberFormat, error)
+	UpsertFormat(ctx context.Context, format *models.DocumentNumberFormat) error
+	ListSequences(ctx context.Context, companyID uuid.UUID) ([]models.DocumentSequence, error)
+}
@@ -3,6 +3,7 @@ package repositories
 import (
 	"boonkosang/internal/domain/models"
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -11,7 +12,23 @@ type InvoiceRepository interface {
 	ValidateProjectStatus(ctx context.Context, projectID uuid.UUID) error
 	CreateForAllPeriods(ctx context.Context, projectID uuid.UUID, contractID uuid.UUID, paymentTerm string) error
 	GetByID(ctx context.Context, invoiceID uuid.UUID) (*models.Invoice, error)
+	// GetByProjectID returns the project's invoices with their periods and
+	// withholding tax certificates.
 	GetByProjectID(ctx context.Context, projectID uuid.UUID) ([]models.Invoice, error)
-	UpdateStatus(ctx context.Context, invoiceID uuid.UUID, status string) error
+	// ListReceivable returns the invoices of all projects that were issued on
+	// or before asOf, with the paid and adjustment amounts and the status they
+	// had on that date.
+	ListReceivable(ctx context.Context, asOf time.Time) ([]models.ReceivableInvoice, error)
+	// ChangeStatus moves the invoice from history.FromStatus to
+	// history.ToStatus and records the change in the same transaction.
+	ChangeStatus(ctx context.Context, history *models.InvoiceStatusHistory) error
+	GetStatusHistory(ctx context.Context, invoiceID uuid.UUID) ([]models.InvoiceStatusHistory, error)
 	Update(ctx context.Context, invoiceID uuid.UUID, updates map[string]interface{}) error // New method
+
+	GetTaxSettings(ctx context.Context, projectID uuid.UUID) (*models.InvoiceTaxSettings, error)
+	GetWithholdingCertificate(ctx context.Context, invoiceID uuid.UUID) (*models.WithholdingTaxCertificate, error)
+	SaveWithholdingCertificate(ctx context.Context, certificate *models.WithholdingTaxCertificate) error
+
+	// CreateRetentionRelease creates a draft invoice releasing retained money.
+	CreateRetentionRelease(ctx context.Context, invoice *models.Invoice) error
 }
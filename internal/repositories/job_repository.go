@@ -5,6 +5,7 @@ import (
 	"boonkosang/internal/requests"
 	"boonkosang/internal/responses"
 	"context"
+	"database/sql"
 
 	"github.com/google/uuid"
 )
@@ -21,5 +22,6 @@ type JobRepository interface {
 	AddJobMaterial(ctx context.Context, jobID uuid.UUID, req requests.AddJobMaterialRequest) error
 	DeleteJobMaterial(ctx context.Context, jobID uuid.UUID, materialID string) error
 	UpdateJobMaterialQuantity(ctx context.Context, jobID uuid.UUID, req requests.UpdateJobMaterialQuantityRequest) error
+	UpdateJobMaterialWaste(ctx context.Context, jobID uuid.UUID, materialID string, wastePercent sql.NullFloat64) error
 	GetJobByProjectID(ctx context.Context, projectID uuid.UUID) ([]responses.JobResponse, error)
 }
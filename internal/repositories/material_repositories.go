@@ -4,6 +4,7 @@ import (
 	"boonkosang/internal/domain/models"
 	"boonkosang/internal/requests"
 	"context"
+	"database/sql"
 
 	"github.com/google/uuid"
 )
@@ -13,10 +14,21 @@ type MaterialRepository interface {
 	Update(ctx context.Context, materialID string, req requests.UpdateMaterialRequest) error
 	Delete(ctx context.Context, materialID string) error
 	GetByID(ctx context.Context, materialID string) (*models.Material, error)
-	List(ctx context.Context) ([]models.Material, error)
+	List(ctx context.Context, filter models.MaterialFilter) ([]models.Material, int64, error)
+	Deprecate(ctx context.Context, materialID string, replacedBy sql.NullString) error
+	Restore(ctx context.Context, materialID string) error
 
 	GetMaterialPricesByProjectID(ctx context.Context, projectID uuid.UUID) ([]models.MaterialPriceInfo, error)
+	GetPriceHistory(ctx context.Context, materialID string, from, to sql.NullTime) ([]models.MaterialPricePoint, error)
+
+	// GetRecentActualPrices returns up to limit of the latest actual prices
+	// of each material on BOQs other than boqID, newest first.
+	GetRecentActualPrices(ctx context.Context, boqID uuid.UUID, materialIDs []string, limit int) ([]models.MaterialPricePoint, error)
 	UpdateEstimatedPrices(ctx context.Context, boqID uuid.UUID, materialID string, estimatedPrice float64) error
+	// UpdateEstimatedPriceList sets the estimated prices of several BOQ
+	// materials, by material ID, in one transaction.
+	UpdateEstimatedPriceList(ctx context.Context, boqID uuid.UUID, prices map[string]float64) error
+	GetBOQMaterials(ctx context.Context, boqID uuid.UUID) ([]models.BOQMaterialRequirement, error)
 	GetBOQStatus(ctx context.Context, boqID uuid.UUID) (string, error)
 	UpdateActualPrice(ctx context.Context, boqID uuid.UUID, req requests.UpdateMaterialActualPriceRequest) error
 	GetProjectStatus(ctx context.Context, projectID uuid.UUID) (string, error)
@@ -48,7 +48,15 @@ func (m *MockContractRepository) ValidateProjectStatus(ctx context.Context, proj
 	return args.Error(0)
 }
 
-func (m *MockContractRepository) ChangeStatus(ctx context.Context, projectID uuid.UUID, status string) error {
-	args := m.Called(ctx, projectID, status)
+func (m *MockContractRepository) ChangeStatus(ctx context.Context, history *models.ContractStatusHistory) error {
+	args := m.Called(ctx, history)
 	return args.Error(0)
 }
+
+func (m *MockContractRepository) GetStatusHistory(ctx context.Context, contractID uuid.UUID) ([]models.ContractStatusHistory, error) {
+	args := m.Called(ctx, contractID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ContractStatusHistory), args.Error(1)
+}
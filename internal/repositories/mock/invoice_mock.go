@@ -3,6 +3,7 @@ package mocks
 import (
 	"boonkosang/internal/domain/models"
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
@@ -43,14 +44,61 @@ func (m *MockInvoiceRepository) GetByProjectID(ctx context.Context, projectID uu
 	return args.Get(0).([]models.Invoice), args.Error(1)
 }
 
-// UpdateStatus mocks the UpdateStatus method
-func (m *MockInvoiceRepository) UpdateStatus(ctx context.Context, invoiceID uuid.UUID, status string) error {
-	args := m.Called(ctx, invoiceID, status)
+// ListReceivable mocks the ListReceivable method
+func (m *MockInvoiceRepository) ListReceivable(ctx context.Context, asOf time.Time) ([]models.ReceivableInvoice, error) {
+	args := m.Called(ctx, asOf)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ReceivableInvoice), args.Error(1)
+}
+
+// ChangeStatus mocks the ChangeStatus method
+func (m *MockInvoiceRepository) ChangeStatus(ctx context.Context, history *models.InvoiceStatusHistory) error {
+	args := m.Called(ctx, history)
 	return args.Error(0)
 }
 
+// GetStatusHistory mocks the GetStatusHistory method
+func (m *MockInvoiceRepository) GetStatusHistory(ctx context.Context, invoiceID uuid.UUID) ([]models.InvoiceStatusHistory, error) {
+	args := m.Called(ctx, invoiceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.InvoiceStatusHistory), args.Error(1)
+}
+
 // Update mocks the Update method
 func (m *MockInvoiceRepository) Update(ctx context.Context, invoiceID uuid.UUID, updates map[string]interface{}) error {
 	args := m.Called(ctx, invoiceID, updates)
 	return args.Error(0)
 }
+
+// GetTaxSettings mocks the GetTaxSettings method
+func (m *MockInvoiceRepository) GetTaxSettings(ctx context.Context, projectID uuid.UUID) (*models.InvoiceTaxSettings, error) {
+	args := m.Called(ctx, projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.InvoiceTaxSettings), args.Error(1)
+}
+
+// GetWithholdingCertificate mocks the GetWithholdingCertificate method
+func (m *MockInvoiceRepository) GetWithholdingCertificate(ctx context.Context, invoiceID uuid.UUID) (*models.WithholdingTaxCertificate, error) {
+	args := m.Called(ctx, invoiceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WithholdingTaxCertificate), args.Error(1)
+}
+
+// SaveWithholdingCertificate mocks the SaveWithholdingCertificate method
+func (m *MockInvoiceRepository) SaveWithholdingCertificate(ctx context.Context, certificate *models.WithholdingTaxCertificate) error {
+	args := m.Called(ctx, certificate)
+	return args.Error(0)
+}
+
+func (m *MockInvoiceRepository) CreateRetentionRelease(ctx context.Context, invoice *models.Invoice) error {
+	args := m.Called(ctx, invoice)
+	return args.Error(0)
+}
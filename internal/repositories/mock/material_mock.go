@@ -0,0 +1,139 @@
+package mocks
+
+import (
+	"boonkosang/internal/domain/models"
+	"boonkosang/internal/requests"
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockMaterialRepository is a mock implementation of the MaterialRepository interface
+type MockMaterialRepository struct {
+	mock.Mock
+}
+
+// Create mocks the Create method
+func (m *MockMaterialRepository) Create(ctx context.Context, req requests.CreateMaterialRequest) (*models.Material, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Material), args.Error(1)
+}
+
+// Update mocks the Update method
+func (m *MockMaterialRepository) Update(ctx context.Context, materialID string, req requests.UpdateMaterialRequest) error {
+	args := m.Called(ctx, materialID, req)
+	return args.Error(0)
+}
+
+// Delete mocks the Delete method
+func (m *MockMaterialRepository) Delete(ctx context.Context, materialID string) error {
+	args := m.Called(ctx, materialID)
+	return args.Error(0)
+}
+
+// GetByID mocks the GetByID method
+func (m *MockMaterialRepository) GetByID(ctx context.Context, materialID string) (*models.Material, error) {
+	args := m.Called(ctx, materialID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Material), args.Error(1)
+}
+
+// List mocks the List method
+func (m *MockMaterialRepository) List(ctx context.Context, filter models.MaterialFilter) ([]models.Material, int64, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]models.Material), args.Get(1).(int64), args.Error(2)
+}
+
+// Deprecate mocks the Deprecate method
+func (m *MockMaterialRepository) Deprecate(ctx context.Context, materialID string, replacedBy sql.NullString) error {
+	args := m.Called(ctx, materialID, replacedBy)
+	return args.Error(0)
+}
+
+// Restore mocks the Restore method
+func (m *MockMaterialRepository) Restore(ctx context.Context, materialID string) error {
+	args := m.Called(ctx, materialID)
+	return args.Error(0)
+}
+
+// GetMaterialPricesByProjectID mocks the GetMaterialPricesByProjectID method
+func (m *MockMaterialRepository) GetMaterialPricesByProjectID(ctx context.Context, projectID uuid.UUID) ([]models.MaterialPriceInfo, error) {
+	args := m.Called(ctx, projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.MaterialPriceInfo), args.Error(1)
+}
+
+// GetPriceHistory mocks the GetPriceHistory method
+func (m *MockMaterialRepository) GetPriceHistory(ctx context.Context, materialID string, from, to sql.NullTime) ([]models.MaterialPricePoint, error) {
+	args := m.Called(ctx, materialID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.MaterialPricePoint), args.Error(1)
+}
+
+// GetRecentActualPrices mocks the GetRecentActualPrices method
+func (m *MockMaterialRepository) GetRecentActualPrices(ctx context.Context, boqID uuid.UUID, materialIDs []string, limit int) ([]models.MaterialPricePoint, error) {
+	args := m.Called(ctx, boqID, materialIDs, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.MaterialPricePoint), args.Error(1)
+}
+
+// UpdateEstimatedPrices mocks the UpdateEstimatedPrices method
+func (m *MockMaterialRepository) UpdateEstimatedPrices(ctx context.Context, boqID uuid.UUID, materialID string, estimatedPrice float64) error {
+	args := m.Called(ctx, boqID, materialID, estimatedPrice)
+	return args.Error(0)
+}
+
+// UpdateEstimatedPriceList mocks the UpdateEstimatedPriceList method
+func (m *MockMaterialRepository) UpdateEstimatedPriceList(ctx context.Context, boqID uuid.UUID, prices map[string]float64) error {
+	args := m.Called(ctx, boqID, prices)
+	return args.Error(0)
+}
+
+// GetBOQMaterials mocks the GetBOQMaterials method
+func (m *MockMaterialRepository) GetBOQMaterials(ctx context.Context, boqID uuid.UUID) ([]models.BOQMaterialRequirement, error) {
+	args := m.Called(ctx, boqID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.BOQMaterialRequirement), args.Error(1)
+}
+
+// GetBOQStatus mocks the GetBOQStatus method
+func (m *MockMaterialRepository) GetBOQStatus(ctx context.Context, boqID uuid.UUID) (string, error) {
+	args := m.Called(ctx, boqID)
+	return args.String(0), args.Error(1)
+}
+
+// UpdateActualPrice mocks the UpdateActualPrice method
+func (m *MockMaterialRepository) UpdateActualPrice(ctx context.Context, boqID uuid.UUID, req requests.UpdateMaterialActualPriceRequest) error {
+	args := m.Called(ctx, boqID, req)
+	return args.Error(0)
+}
+
+// GetProjectStatus mocks the GetProjectStatus method
+func (m *MockMaterialRepository) GetProjectStatus(ctx context.Context, projectID uuid.UUID) (string, error) {
+	args := m.Called(ctx, projectID)
+	return args.String(0), args.Error(1)
+}
+
+// GetQuotationStatus mocks the GetQuotationStatus method
+func (m *MockMaterialRepository) GetQuotationStatus(ctx context.Context, projectID uuid.UUID) (string, error) {
+	args := m.Called(ctx, projectID)
+	return args.String(0), args.Error(1)
+}
@@ -0,0 +1,44 @@
+package mocks
+
+import (
+	"boonkosang/internal/domain/models"
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPaymentRepository is a mock implementation of the PaymentRepository interface
+type MockPaymentRepository struct {
+	mock.Mock
+}
+
+// Create mocks the Create method
+func (m *MockPaymentRepository) Create(ctx context.Context, payment *models.Payment, update models.InvoiceBalanceUpdate) error {
+	args := m.Called(ctx, payment, update)
+	return args.Error(0)
+}
+
+// GetByID mocks the GetByID method
+func (m *MockPaymentRepository) GetByID(ctx context.Context, paymentID uuid.UUID) (*models.Payment, error) {
+	args := m.Called(ctx, paymentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Payment), args.Error(1)
+}
+
+// ListByInvoiceID mocks the ListByInvoiceID method
+func (m *MockPaymentRepository) ListByInvoiceID(ctx context.Context, invoiceID uuid.UUID) ([]models.Payment, error) {
+	args := m.Called(ctx, invoiceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Payment), args.Error(1)
+}
+
+// Reverse mocks the Reverse method
+func (m *MockPaymentRepository) Reverse(ctx context.Context, payment *models.Payment, update models.InvoiceBalanceUpdate) error {
+	args := m.Called(ctx, payment, update)
+	return args.Error(0)
+}
@@ -0,0 +1,97 @@
+package mocks
+
+import (
+	"boonkosang/internal/domain/models"
+	"boonkosang/internal/requests"
+	"boonkosang/internal/responses"
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockQuotationRepository is a mock implementation of the QuotationRepository interface
+type MockQuotationRepository struct {
+	mock.Mock
+}
+
+// Create mocks the Create method
+func (m *MockQuotationRepository) Create(ctx context.Context, projectID uuid.UUID) (*models.Quotation, error) {
+	args := m.Called(ctx, projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Quotation), args.Error(1)
+}
+
+// GetByProjectID mocks the GetByProjectID method
+func (m *MockQuotationRepository) GetByProjectID(ctx context.Context, projectID uuid.UUID) (*models.Quotation, error) {
+	args := m.Called(ctx, projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Quotation), args.Error(1)
+}
+
+// GetQuotationJobs mocks the GetQuotationJobs method
+func (m *MockQuotationRepository) GetQuotationJobs(ctx context.Context, projectID uuid.UUID) ([]models.QuotationJob, error) {
+	args := m.Called(ctx, projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.QuotationJob), args.Error(1)
+}
+
+// GetQuotationGeneralCosts mocks the GetQuotationGeneralCosts method
+func (m *MockQuotationRepository) GetQuotationGeneralCosts(ctx context.Context, projectID uuid.UUID) ([]models.QuotationGeneralCost, error) {
+	args := m.Called(ctx, projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.QuotationGeneralCost), args.Error(1)
+}
+
+// CheckBOQStatus mocks the CheckBOQStatus method
+func (m *MockQuotationRepository) CheckBOQStatus(ctx context.Context, projectID uuid.UUID) (string, error) {
+	args := m.Called(ctx, projectID)
+	return args.String(0), args.Error(1)
+}
+
+// GetClientWithholdingTax mocks the GetClientWithholdingTax method
+func (m *MockQuotationRepository) GetClientWithholdingTax(ctx context.Context, projectID uuid.UUID) (float64, error) {
+	args := m.Called(ctx, projectID)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+// ApproveQuotation mocks the ApproveQuotation method
+func (m *MockQuotationRepository) ApproveQuotation(ctx context.Context, projectID uuid.UUID) error {
+	args := m.Called(ctx, projectID)
+	return args.Error(0)
+}
+
+// GetQuotationStatus mocks the GetQuotationStatus method
+func (m *MockQuotationRepository) GetQuotationStatus(ctx context.Context, projectID uuid.UUID) (string, error) {
+	args := m.Called(ctx, projectID)
+	return args.String(0), args.Error(1)
+}
+
+// ValidateApproval mocks the ValidateApproval method
+func (m *MockQuotationRepository) ValidateApproval(ctx context.Context, projectID uuid.UUID) error {
+	args := m.Called(ctx, projectID)
+	return args.Error(0)
+}
+
+// GetExportData mocks the GetExportData method
+func (m *MockQuotationRepository) GetExportData(ctx context.Context, projectID uuid.UUID) (*responses.QuotationExportData, error) {
+	args := m.Called(ctx, projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*responses.QuotationExportData), args.Error(1)
+}
+
+// UpdateProjectSellingPrice mocks the UpdateProjectSellingPrice method
+func (m *MockQuotationRepository) UpdateProjectSellingPrice(ctx context.Context, req requests.UpdateProjectSellingPriceRequest) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
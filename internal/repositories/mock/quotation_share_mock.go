@@ -0,0 +1,44 @@
+package mocks
+
+import (
+	"boonkosang/internal/domain/models"
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockQuotationShareRepository is a mock implementation of the QuotationShareRepository interface
+type MockQuotationShareRepository struct {
+	mock.Mock
+}
+
+// CreateShare mocks the CreateShare method
+func (m *MockQuotationShareRepository) CreateShare(ctx context.Context, share *models.QuotationShare) error {
+	args := m.Called(ctx, share)
+	return args.Error(0)
+}
+
+// GetShareByID mocks the GetShareByID method
+func (m *MockQuotationShareRepository) GetShareByID(ctx context.Context, shareID uuid.UUID) (*models.QuotationShare, error) {
+	args := m.Called(ctx, shareID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.QuotationShare), args.Error(1)
+}
+
+// GetClientResponse mocks the GetClientResponse method
+func (m *MockQuotationShareRepository) GetClientResponse(ctx context.Context, quotationID uuid.UUID) (*models.QuotationClientResponse, error) {
+	args := m.Called(ctx, quotationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.QuotationClientResponse), args.Error(1)
+}
+
+// RecordClientResponse mocks the RecordClientResponse method
+func (m *MockQuotationShareRepository) RecordClientResponse(ctx context.Context, projectID uuid.UUID, response *models.QuotationClientResponse) error {
+	args := m.Called(ctx, projectID, response)
+	return args.Error(0)
+}
@@ -0,0 +1,63 @@
+package mocks
+
+import (
+	"boonkosang/internal/domain/models"
+	"boonkosang/internal/requests"
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSupplierRepository is a mock implementation of the SupplierRepository interface
+type MockSupplierRepository struct {
+	mock.Mock
+}
+
+// Create mocks the Create method
+func (m *MockSupplierRepository) Create(ctx context.Context, req requests.CreateSupplierRequest) (*models.Supplier, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Supplier), args.Error(1)
+}
+
+// Update mocks the Update method
+func (m *MockSupplierRepository) Update(ctx context.Context, id uuid.UUID, req requests.UpdateSupplierRequest) error {
+	args := m.Called(ctx, id, req)
+	return args.Error(0)
+}
+
+// Delete mocks the Delete method
+func (m *MockSupplierRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// GetByID mocks the GetByID method
+func (m *MockSupplierRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Supplier, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Supplier), args.Error(1)
+}
+
+// List mocks the List method
+func (m *MockSupplierRepository) List(ctx context.Context, limit, offset int) ([]models.Supplier, int64, error) {
+	args := m.Called(ctx, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]models.Supplier), args.Get(1).(int64), args.Error(2)
+}
+
+// GetByEmail mocks the GetByEmail method
+func (m *MockSupplierRepository) GetByEmail(ctx context.Context, email string) (*models.Supplier, error) {
+	args := m.Called(ctx, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Supplier), args.Error(1)
+}
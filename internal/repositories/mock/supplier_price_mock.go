@@ -0,0 +1,60 @@
+package mocks
+
+import (
+	"boonkosang/internal/domain/models"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSupplierPriceRepository is a mock implementation of the SupplierPriceRepository interface
+type MockSupplierPriceRepository struct {
+	mock.Mock
+}
+
+// Create mocks the Create method
+func (m *MockSupplierPriceRepository) Create(ctx context.Context, prices []models.SupplierPrice) error {
+	args := m.Called(ctx, prices)
+	return args.Error(0)
+}
+
+// GetByID mocks the GetByID method
+func (m *MockSupplierPriceRepository) GetByID(ctx context.Context, priceID uuid.UUID) (*models.SupplierPrice, error) {
+	args := m.Called(ctx, priceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SupplierPrice), args.Error(1)
+}
+
+// Update mocks the Update method
+func (m *MockSupplierPriceRepository) Update(ctx context.Context, price *models.SupplierPrice) error {
+	args := m.Called(ctx, price)
+	return args.Error(0)
+}
+
+// Delete mocks the Delete method
+func (m *MockSupplierPriceRepository) Delete(ctx context.Context, priceID uuid.UUID) error {
+	args := m.Called(ctx, priceID)
+	return args.Error(0)
+}
+
+// ListBySupplierID mocks the ListBySupplierID method
+func (m *MockSupplierPriceRepository) ListBySupplierID(ctx context.Context, supplierID uuid.UUID) ([]models.SupplierPrice, error) {
+	args := m.Called(ctx, supplierID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.SupplierPrice), args.Error(1)
+}
+
+// ListValidPrices mocks the ListValidPrices method
+func (m *MockSupplierPriceRepository) ListValidPrices(ctx context.Context, date time.Time, materialIDs []string) ([]models.SupplierPrice, error) {
+	args := m.Called(ctx, date, materialIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.SupplierPrice), args.Error(1)
+}
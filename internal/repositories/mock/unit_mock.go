@@ -0,0 +1,35 @@
+package mocks
+
+import (
+	"boonkosang/internal/domain/models"
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockUnitRepository is a mock implementation of the UnitRepository interface
+type MockUnitRepository struct {
+	mock.Mock
+}
+
+// CreateConversion mocks the CreateConversion method
+func (m *MockUnitRepository) CreateConversion(ctx context.Context, conversion *models.UnitConversion) error {
+	args := m.Called(ctx, conversion)
+	return args.Error(0)
+}
+
+// DeleteConversion mocks the DeleteConversion method
+func (m *MockUnitRepository) DeleteConversion(ctx context.Context, conversionID uuid.UUID) error {
+	args := m.Called(ctx, conversionID)
+	return args.Error(0)
+}
+
+// ListConversions mocks the ListConversions method
+func (m *MockUnitRepository) ListConversions(ctx context.Context, materialID string) ([]models.UnitConversion, error) {
+	args := m.Called(ctx, materialID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.UnitConversion), args.Error(1)
+}
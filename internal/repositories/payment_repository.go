@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"boonkosang/internal/domain/models"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type PaymentRepository interface {
+	// Create records the payment and updates the invoice paid amount, status
+	// and paid date in the same transaction. The invoice is locked first and
+	// update decides its new status, or rejects the payment. The status change
+	// is recorded in the invoice status history when the status actually
+	// changes.
+	Create(ctx context.Context, payment *models.Payment, update models.InvoiceBalanceUpdate) error
+	GetByID(ctx context.Context, paymentID uuid.UUID) (*models.Payment, error)
+	ListByInvoiceID(ctx context.Context, invoiceID uuid.UUID) ([]models.Payment, error)
+	// Reverse marks the payment as reversed, voids its receipt if one was
+	// issued and updates the invoice in the same transaction.
+	Reverse(ctx context.Context, payment *models.Payment, update models.InvoiceBalanceUpdate) error
+}
@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"boonkosang/internal/domain/models"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type PurchaseOrderRepository interface {
+	ListBOQMaterials(ctx context.Context, projectID uuid.UUID) ([]models.BOQMaterialRequirement, error)
+	// Create allocates the PO numbers and stores the purchase orders with
+	// their lines in one transaction, so either all of them are created or
+	// none.
+	Create(ctx context.Context, orders []*models.PurchaseOrder) error
+	GetByID(ctx context.Context, poID uuid.UUID) (*models.PurchaseOrder, error)
+	ListByProjectID(ctx context.Context, projectID uuid.UUID) ([]models.PurchaseOrder, error)
+	Update(ctx context.Context, po *models.PurchaseOrder) error
+	ChangeStatus(ctx context.Context, po *models.PurchaseOrder, from models.PurchaseOrderStatus) error
+
+	// Receive records a goods receipt, adds the accepted and rejected
+	// quantities to the order lines, moves the order to status and updates
+	// the BOQ actual prices of the received materials. Accepted quantities
+	// are posted to the project's site stock.
+	Receive(ctx context.Context, po *models.PurchaseOrder, receipt *models.GoodsReceipt, status models.PurchaseOrderStatus) error
+	ListGoodsReceipts(ctx context.Context, poID uuid.UUID) ([]models.GoodsReceipt, error)
+}
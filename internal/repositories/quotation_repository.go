@@ -15,6 +15,7 @@ type QuotationRepository interface {
 	GetQuotationJobs(ctx context.Context, projectID uuid.UUID) ([]models.QuotationJob, error)
 	GetQuotationGeneralCosts(ctx context.Context, projectID uuid.UUID) ([]models.QuotationGeneralCost, error)
 	CheckBOQStatus(ctx context.Context, projectID uuid.UUID) (string, error)
+	GetClientWithholdingTax(ctx context.Context, projectID uuid.UUID) (float64, error)
 
 	ApproveQuotation(ctx context.Context, projectID uuid.UUID) error
 	GetQuotationStatus(ctx context.Context, projectID uuid.UUID) (string, error)
@@ -0,0 +1,15 @@
+package repositories
+
+import (
+	"boonkosang/internal/domain/models"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type QuotationShareRepository interface {
+	CreateShare(ctx context.Context, share *models.QuotationShare) error
+	GetShareByID(ctx context.Context, shareID uuid.UUID) (*models.QuotationShare, error)
+	GetClientResponse(ctx context.Context, quotationID uuid.UUID) (*models.QuotationClientResponse, error)
+	RecordClientResponse(ctx context.Context, projectID uuid.UUID, response *models.QuotationClientResponse) error
+}
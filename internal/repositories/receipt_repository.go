@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"boonkosang/internal/domain/models"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type ReceiptRepository interface {
+	// Create allocates the receipt number and stores the receipt. A payment
+	// can only have one receipt.
+	Create(ctx context.Context, receipt *models.Receipt) error
+	GetByPaymentID(ctx context.Context, paymentID uuid.UUID) (*models.Receipt, error)
+	ListByInvoiceID(ctx context.Context, invoiceID uuid.UUID) ([]models.Receipt, error)
+}
@@ -0,0 +1,26 @@
+package repositories
+
+import (
+	"boonkosang/internal/domain/models"
+	"boonkosang/internal/requests"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type RFQRepository interface {
+	Create(ctx context.Context, rfq *models.RFQ) error
+	GetByID(ctx context.Context, rfqID uuid.UUID) (*models.RFQ, error)
+	ListByProjectID(ctx context.Context, projectID uuid.UUID) ([]models.RFQ, error)
+	ChangeStatus(ctx context.Context, rfq *models.RFQ, from models.RFQStatus) error
+
+	// SaveQuotes replaces a supplier's quotes on a sent RFQ.
+	SaveQuotes(ctx context.Context, rfqID, supplierID uuid.UUID, quotes []models.RFQQuote) error
+
+	// Award moves the RFQ from sent to awarded, records the awarded supplier
+	// and price of each line and creates the purchase orders or sets the BOQ
+	// actual prices of the award, all in one transaction. The status change
+	// comes first so a second award of the same RFQ fails before writing
+	// anything.
+	Award(ctx context.Context, rfq *models.RFQ, orders []*models.PurchaseOrder, actualPrices []requests.UpdateMaterialActualPriceRequest) error
+}
@@ -0,0 +1,18 @@
+package repositories
+
+import (
+	"boonkosang/internal/domain/models"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type StockRepository interface {
+	// CreateMovements records movements in one transaction and fails if any
+	// of them would take a project's on-hand balance below zero.
+	CreateMovements(ctx context.Context, movements []models.StockMovement) error
+	ListMovements(ctx context.Context, projectID uuid.UUID, materialID string) ([]models.StockMovementDetail, error)
+	GetBalances(ctx context.Context, projectID uuid.UUID) ([]models.StockBalance, error)
+	GetConsumption(ctx context.Context, projectID uuid.UUID) ([]models.MaterialConsumption, error)
+	IsJobInBOQ(ctx context.Context, projectID, jobID uuid.UUID) (bool, error)
+}
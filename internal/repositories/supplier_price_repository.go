@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"boonkosang/internal/domain/models"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SupplierPriceRepository interface {
+	// Create adds price list entries in one transaction. An open entry of
+	// the same supplier, material and minimum quantity that starts earlier
+	// is closed the day before the new entry starts.
+	Create(ctx context.Context, prices []models.SupplierPrice) error
+	GetByID(ctx context.Context, priceID uuid.UUID) (*models.SupplierPrice, error)
+	Update(ctx context.Context, price *models.SupplierPrice) error
+	Delete(ctx context.Context, priceID uuid.UUID) error
+	ListBySupplierID(ctx context.Context, supplierID uuid.UUID) ([]models.SupplierPrice, error)
+
+	// ListValidPrices returns the prices valid on date, cheapest first for
+	// each material. All materials are included when materialIDs is empty.
+	ListValidPrices(ctx context.Context, date time.Time, materialIDs []string) ([]models.SupplierPrice, error)
+}
@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"boonkosang/internal/domain/models"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type UnitRepository interface {
+	CreateConversion(ctx context.Context, conversion *models.UnitConversion) error
+	DeleteConversion(ctx context.Context, conversionID uuid.UUID) error
+
+	// ListConversions returns the global conversions, plus the conversions
+	// of materialID when it is not empty.
+	ListConversions(ctx context.Context, materialID string) ([]models.UnitConversion, error)
+}
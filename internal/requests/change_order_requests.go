@@ -0,0 +1,30 @@
+package requests
+
+import "github.com/google/uuid"
+
+type ChangeOrderRequest struct {
+	Title   string                     `json:"title" validate:"required,max=255"`
+	Reason  string                     `json:"reason" validate:"max=1000"`
+	Items   []ChangeOrderItemRequest   `json:"items" validate:"required,min=1,dive"`
+	Periods []ChangeOrderPeriodRequest `json:"periods" validate:"dive"`
+}
+
+type ChangeOrderItemRequest struct {
+	JobID        uuid.UUID `json:"job_id" validate:"required"`
+	ChangeType   string    `json:"change_type" validate:"required,oneof=add remove modify"`
+	Quantity     float64   `json:"quantity" validate:"min=0"`
+	SellingPrice float64   `json:"selling_price" validate:"min=0"`
+	LaborCost    float64   `json:"labor_cost" validate:"min=0"`
+}
+
+// ChangeOrderPeriodRequest adjusts an existing period when PeriodID is set,
+// otherwise it adds a new period for the amount.
+type ChangeOrderPeriodRequest struct {
+	PeriodID        *uuid.UUID `json:"period_id"`
+	AmountChange    float64    `json:"amount_change"`
+	DeliveredWithin int        `json:"delivered_within" validate:"min=0,max=365"`
+}
+
+type RejectChangeOrderRequest struct {
+	Reason string `json:"reason" validate:"required,max=1000"`
+}
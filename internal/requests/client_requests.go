@@ -8,6 +8,9 @@ type CreateClientRequest struct {
 	Tel     string          `json:"tel" validate:"required,len=10"`
 	Address json.RawMessage `json:"address" validate:"required"`
 	TaxID   string          `json:"tax_id" validate:"required,len=13"`
+	Branch  string          `json:"branch" validate:"omitempty,len=5,numeric"`
+
+	WithholdingTaxPercentage float64 `json:"withholding_tax_percentage"`
 }
 
 type UpdateClientRequest struct {
@@ -16,4 +19,8 @@ type UpdateClientRequest struct {
 	Tel     string          `json:"tel" validate:"required,len=10"`
 	Address json.RawMessage `json:"address" validate:"required"`
 	TaxID   string          `json:"tax_id" validate:"required,len=13"`
+	Branch  string          `json:"branch" validate:"omitempty,len=5,numeric"`
+
+	// WithholdingTaxPercentage keeps the stored rate when omitted.
+	WithholdingTaxPercentage *float64 `json:"withholding_tax_percentage"`
 }
@@ -8,6 +8,7 @@ type CreateCompanyRequest struct {
 	Tel     string          `json:"tel"`
 	Address json.RawMessage `json:"address"`
 	TaxID   string          `json:"tax_id"`
+	Branch  string          `json:"branch"`
 }
 
 type UpdateCompanyRequest struct {
@@ -16,4 +17,5 @@ type UpdateCompanyRequest struct {
 	Tel     string          `json:"tel"`
 	Address json.RawMessage `json:"address"`
 	TaxID   string          `json:"tax_id"`
+	Branch  string          `json:"branch"`
 }
@@ -66,3 +66,22 @@ type UpdateJobPeriodRequest struct {
 	JobID     uuid.UUID `json:"job_id" validate:"required"`
 	JobAmount float64   `json:"job_amount" validate:"required,min=0"`
 }
+
+type ChangeContractStatusRequest struct {
+	Status            string `json:"status" validate:"required"`
+	TerminationReason string `json:"termination_reason" validate:"omitempty,oneof=client_breach contractor_breach mutual_agreement force_majeure other"`
+	Remarks           string `json:"remarks" validate:"max=1000"`
+}
+
+// ProposePeriodsRequest selects how the quotation total is split into
+// payment periods. Rule is one of "equal", "deposit" or "milestone".
+// Installments is checked by the usecase for the equal and deposit rules;
+// milestones take one period per milestone instead.
+type ProposePeriodsRequest struct {
+	Rule                string        `json:"rule" validate:"required,oneof=equal deposit milestone"`
+	Installments        int           `json:"installments" validate:"omitempty,min=1,max=100"`
+	DepositPercentage   float64       `json:"deposit_percentage" validate:"min=0,max=100"`
+	RetentionPercentage float64       `json:"retention_percentage" validate:"min=0,max=100"`
+	IntervalDays        int           `json:"interval_days" validate:"min=0,max=365"`
+	Milestones          [][]uuid.UUID `json:"milestones"`
+}
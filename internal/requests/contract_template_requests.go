@@ -0,0 +1,8 @@
+package requests
+
+type ContractClauseTemplateRequest struct {
+	ClauseType string `json:"clause_type" validate:"required,oneof=force_majeure breach_of_contract end_of_contract termination_of_contract amendment custom"`
+	Title      string `json:"title" validate:"required"`
+	Body       string `json:"body" validate:"required"`
+	SortOrder  int    `json:"sort_order"`
+}
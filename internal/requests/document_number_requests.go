@@ -0,0 +1,5 @@
+package requests
+
+type UpdateDocumentNumberFormatRequest struct {
+	Pattern string `json:"pattern" validate:"required"`
+}
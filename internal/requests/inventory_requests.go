@@ -0,0 +1,28 @@
+package requests
+
+import "github.com/google/uuid"
+
+// CreateStockMovementRequest records a receipt, an issue to a BOQ job or a
+// return to a supplier on a project site. Transfers between projects use
+// TransferStockRequest.
+type CreateStockMovementRequest struct {
+	MaterialID   string     `json:"material_id" validate:"required"`
+	MovementType string     `json:"movement_type" validate:"required,oneof=receipt issue return"`
+	Quantity     float64    `json:"quantity" validate:"required,gt=0"`
+	MovementDate string     `json:"movement_date" validate:"omitempty,datetime=2006-01-02"`
+	JobID        *uuid.UUID `json:"job_id"`
+	SupplierID   *uuid.UUID `json:"supplier_id"`
+	Notes        string     `json:"notes"`
+	RecordedBy   string     `json:"recorded_by" validate:"required"`
+}
+
+// TransferStockRequest moves materials from the project in the URL to
+// ToProjectID.
+type TransferStockRequest struct {
+	ToProjectID  uuid.UUID `json:"to_project_id" validate:"required"`
+	MaterialID   string    `json:"material_id" validate:"required"`
+	Quantity     float64   `json:"quantity" validate:"required,gt=0"`
+	MovementDate string    `json:"movement_date" validate:"omitempty,datetime=2006-01-02"`
+	Notes        string    `json:"notes"`
+	RecordedBy   string    `json:"recorded_by" validate:"required"`
+}
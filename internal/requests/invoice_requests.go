@@ -13,8 +13,12 @@ type DeleteInvoiceRequest struct {
 	InvoiceID uuid.UUID `json:"invoice_id" validate:"required"`
 }
 
+// UpdateInvoiceStatusRequest changes an invoice status. Reason is required to
+// void or cancel, and ChangedBy names who made the change.
 type UpdateInvoiceStatusRequest struct {
-	Status string `json:"status" validate:"required,oneof=pending processing paid canceled"`
+	Status    string `json:"status" validate:"required,oneof=issued void canceled"`
+	Reason    string `json:"reason"`
+	ChangedBy string `json:"changed_by"`
 }
 
 type UpdateInvoicePaidRequest struct {
@@ -28,4 +32,40 @@ type UpdateInvoiceRequest struct {
 	PaymentTerm    *string  `json:"payment_term"`
 	Remarks        *string  `json:"remarks"`
 	Retention      *float64 `json:"retention"`
+
+	WithholdingTaxPercentage *float64 `json:"withholding_tax_percentage"`
+}
+
+type RecordWithholdingCertificateRequest struct {
+	CertificateNumber string  `json:"certificate_number" validate:"required"`
+	CertificateDate   string  `json:"certificate_date" validate:"required,datetime=2006-01-02"`
+	ReceivedDate      string  `json:"received_date" validate:"omitempty,datetime=2006-01-02"`
+	Amount            float64 `json:"amount" validate:"min=0"`
+	Remarks           string  `json:"remarks"`
+}
+
+type RecordPaymentRequest struct {
+	Amount      float64 `json:"amount" validate:"required,gt=0"`
+	PaymentDate string  `json:"payment_date" validate:"omitempty,datetime=2006-01-02"`
+	Method      string  `json:"method" validate:"required,oneof=transfer cheque cash"`
+	Reference   string  `json:"reference"`
+	BankName    string  `json:"bank_name"`
+	Remarks     string  `json:"remarks"`
+}
+
+type ReversePaymentRequest struct {
+	Reason string `json:"reason" validate:"required"`
+}
+
+// CreateAdjustmentNoteRequest issues a credit or debit note. Amount is the
+// value before VAT; the VAT follows the invoice's rate.
+type CreateAdjustmentNoteRequest struct {
+	NoteType string  `json:"note_type" validate:"required,oneof=credit debit"`
+	Amount   float64 `json:"amount" validate:"required,gt=0"`
+	Reason   string  `json:"reason" validate:"required"`
+	NoteDate string  `json:"note_date" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type VoidAdjustmentNoteRequest struct {
+	Reason string `json:"reason" validate:"required"`
 }
@@ -18,9 +18,14 @@ type AddJobMaterialRequest struct {
 	Materials []JobMaterialItem `json:"materials" validate:"required,dive"`
 }
 
+// JobMaterialItem is the net material quantity per unit of the job. Unit
+// defaults to the material's unit; other units are converted to it.
+// WastePercent overrides the material's waste allowance for this job.
 type JobMaterialItem struct {
-	MaterialID string  `json:"material_id" validate:"required"`
-	Quantity   float64 `json:"quantity" validate:"required,gt=0"`
+	MaterialID   string   `json:"material_id" validate:"required"`
+	Quantity     float64  `json:"quantity" validate:"required,gt=0"`
+	Unit         string   `json:"unit"`
+	WastePercent *float64 `json:"waste_percent" validate:"omitempty,min=0,max=100"`
 }
 
 type DeleteJobMaterialRequest struct {
@@ -32,4 +37,11 @@ type UpdateJobMaterialQuantityRequest struct {
 	JobID      uuid.UUID `json:"job_id" validate:"required"`
 	MaterialID string    `json:"material_id" validate:"required"`
 	Quantity   float64   `json:"quantity" validate:"required,gt=0"`
+	Unit       string    `json:"unit"`
+}
+
+// UpdateJobMaterialWasteRequest overrides the waste allowance of a job
+// material. A null WastePercent goes back to the material's default.
+type UpdateJobMaterialWasteRequest struct {
+	WastePercent *float64 `json:"waste_percent" validate:"omitempty,min=0,max=100"`
 }
@@ -3,13 +3,34 @@ package requests
 import "github.com/google/uuid"
 
 type CreateMaterialRequest struct {
-	Name string `json:"name" validate:"required"`
-	Unit string `json:"unit" validate:"required"`
+	Name          string   `json:"name" validate:"required"`
+	Unit          string   `json:"unit" validate:"required"`
+	Category      string   `json:"category"`
+	Brand         string   `json:"brand"`
+	Specification string   `json:"specification"`
+	SKU           string   `json:"sku"`
+	Tags          []string `json:"tags"`
+	WastePercent  float64  `json:"waste_percent" validate:"min=0,max=100"`
 }
 
+// UpdateMaterialRequest changes a material. Catalog fields and the waste
+// allowance left out of the request keep their stored values; an empty
+// brand, specification or SKU clears it.
 type UpdateMaterialRequest struct {
-	Name string `json:"name" validate:"required"`
-	Unit string `json:"unit" validate:"required"`
+	Name          string    `json:"name" validate:"required"`
+	Unit          string    `json:"unit" validate:"required"`
+	Category      *string   `json:"category"`
+	Brand         *string   `json:"brand"`
+	Specification *string   `json:"specification"`
+	SKU           *string   `json:"sku"`
+	Tags          *[]string `json:"tags"`
+	WastePercent  *float64  `json:"waste_percent" validate:"omitempty,min=0,max=100"`
+}
+
+// DeprecateMaterialRequest retires a material from the catalog, optionally
+// naming the material that replaces it.
+type DeprecateMaterialRequest struct {
+	ReplacedBy string `json:"replaced_by"`
 }
 
 type UpdateMaterialEstimatedPriceRequest struct {
@@ -22,3 +43,34 @@ type UpdateMaterialActualPriceRequest struct {
 	ActualPrice float64   `json:"actual_price" validate:"required,gt=0"`
 	SupplierID  uuid.UUID `json:"supplier_id" validate:"required"`
 }
+
+// AutoFillEstimatedPricesRequest fills the estimated prices of a draft BOQ
+// using Strategy:
+//   - "last_actual": the latest actual price on another BOQ
+//   - "average_actual": the average of the last Count actual prices (3 when
+//     not given)
+//   - "supplier_price": the cheapest supplier price valid on Date (today
+//     when empty) for the quantity the BOQ needs
+//   - "price_index": the latest actual price, or the average of the last
+//     Count, adjusted by IndexChangePercent, the non-zero change of a price
+//     index since that price
+//
+// Materials that already have an estimate are kept unless Overwrite is set.
+type AutoFillEstimatedPricesRequest struct {
+	Strategy           string  `json:"strategy" validate:"required,oneof=last_actual average_actual supplier_price price_index"`
+	Count              int     `json:"count" validate:"omitempty,min=1"`
+	Date               string  `json:"date" validate:"omitempty,datetime=2006-01-02"`
+	IndexChangePercent float64 `json:"index_change_percent" validate:"gt=-100"`
+	Overwrite          bool    `json:"overwrite"`
+}
+
+// ListMaterialsRequest filters the material catalog. Without a page every
+// matching material is returned.
+type ListMaterialsRequest struct {
+	Search            string
+	Category          string
+	Tag               string
+	IncludeDeprecated bool
+	Page              int
+	PageSize          int
+}
@@ -12,6 +12,7 @@ type CreateProjectRequest struct {
 	Description string          `json:"description" validate:"required"`
 	Address     json.RawMessage `json:"address" validate:"required"`
 	ClientID    uuid.UUID       `json:"client_id" validate:"required"`
+	CompanyID   *uuid.UUID      `json:"company_id"`
 }
 
 type UpdateProjectRequest struct {
@@ -19,6 +20,8 @@ type UpdateProjectRequest struct {
 	Description string          `json:"description" validate:"required"`
 	Address     json.RawMessage `json:"address" validate:"required"`
 	ClientID    uuid.UUID       `json:"client_id" validate:"required"`
+	// CompanyID assigns the project to a company; omitted keeps the current one.
+	CompanyID *uuid.UUID `json:"company_id"`
 }
 
 type UpdateProjectStatusRequest struct {
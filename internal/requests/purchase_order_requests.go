@@ -0,0 +1,56 @@
+package requests
+
+import "github.com/google/uuid"
+
+// PurchaseOrderLineRequest orders a BOQ material. Quantity defaults to the
+// BOQ quantity not yet ordered and UnitPrice to the BOQ estimated price.
+// A Quantity and UnitPrice given in another Unit than the material's are
+// converted to the material's unit.
+type PurchaseOrderLineRequest struct {
+	MaterialID   string  `json:"material_id" validate:"required"`
+	Quantity     float64 `json:"quantity" validate:"omitempty,gt=0"`
+	UnitPrice    float64 `json:"unit_price" validate:"omitempty,gt=0"`
+	Unit         string  `json:"unit"`
+	DeliveryDate string  `json:"delivery_date" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type CreatePurchaseOrderLineRequest struct {
+	PurchaseOrderLineRequest
+	SupplierID uuid.UUID `json:"supplier_id" validate:"required"`
+}
+
+// CreatePurchaseOrdersRequest creates one draft purchase order per supplier
+// from the listed BOQ materials.
+type CreatePurchaseOrdersRequest struct {
+	OrderDate    string                           `json:"order_date" validate:"omitempty,datetime=2006-01-02"`
+	DeliveryDate string                           `json:"delivery_date" validate:"omitempty,datetime=2006-01-02"`
+	Remarks      string                           `json:"remarks"`
+	Lines        []CreatePurchaseOrderLineRequest `json:"lines" validate:"required,min=1,dive"`
+}
+
+type UpdatePurchaseOrderRequest struct {
+	DeliveryDate string                     `json:"delivery_date" validate:"omitempty,datetime=2006-01-02"`
+	Remarks      string                     `json:"remarks"`
+	Lines        []PurchaseOrderLineRequest `json:"lines" validate:"required,min=1,dive"`
+}
+
+type UpdatePurchaseOrderStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=sent cancelled"`
+}
+
+// ReceivePurchaseOrderLineRequest is a delivered quantity of an order line.
+// RejectedQuantity is the part of Quantity that was refused on site.
+type ReceivePurchaseOrderLineRequest struct {
+	LineID           uuid.UUID `json:"po_line_id" validate:"required"`
+	Quantity         float64   `json:"quantity" validate:"required,gt=0"`
+	RejectedQuantity float64   `json:"rejected_quantity" validate:"min=0"`
+	Notes            string    `json:"notes"`
+}
+
+// ReceivePurchaseOrderRequest records a goods receipt for a delivery.
+type ReceivePurchaseOrderRequest struct {
+	ReceivedDate string                            `json:"received_date" validate:"omitempty,datetime=2006-01-02"`
+	ReceivedBy   string                            `json:"received_by" validate:"required"`
+	Notes        string                            `json:"notes"`
+	Lines        []ReceivePurchaseOrderLineRequest `json:"lines" validate:"required,min=1,dive"`
+}
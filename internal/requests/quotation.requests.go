@@ -7,9 +7,37 @@ type UpdateProjectSellingPriceRequest struct {
 	TaxPercentage      float64           `json:"tax_percentage" validate:"required,gt=0"`
 	SellingGeneralCost float64           `json:"selling_general_cost" validate:"required,gt=0"`
 	JobSellingPrices   []JobSellingPrice `json:"job_selling_prices" validate:"required,dive"`
+
+	// WithholdingTaxPercentage overrides the client's default rate for this
+	// quotation. Nil keeps the current setting.
+	WithholdingTaxPercentage *float64 `json:"withholding_tax_percentage"`
 }
 
 type JobSellingPrice struct {
 	JobID        uuid.UUID `json:"job_id" validate:"required"`
 	SellingPrice float64   `json:"selling_price" validate:"required,gt=0"`
 }
+
+type CalculateSellingPriceRequest struct {
+	ProjectID        uuid.UUID   `json:"project_id" validate:"required"`
+	MarkupPercentage float64     `json:"markup_percentage" validate:"min=0"`
+	LaborMarkup      *float64    `json:"labor_markup" validate:"omitempty,min=0"`
+	MaterialMarkup   *float64    `json:"material_markup" validate:"omitempty,min=0"`
+	JobMarkups       []JobMarkup `json:"job_markups" validate:"dive"`
+	RoundTo          int         `json:"round_to" validate:"omitempty,oneof=0 1 10 100"`
+	Preview          bool        `json:"preview"`
+}
+
+type JobMarkup struct {
+	JobID            uuid.UUID `json:"job_id" validate:"required"`
+	MarkupPercentage float64   `json:"markup_percentage" validate:"min=0"`
+}
+
+type CreateQuotationShareRequest struct {
+	ExpiresInDays int `json:"expires_in_days" validate:"omitempty,min=1,max=90"`
+}
+
+type RespondToQuotationRequest struct {
+	ClientName string `json:"client_name" validate:"required"`
+	Remarks    string `json:"remarks"`
+}
@@ -0,0 +1,54 @@
+package requests
+
+import "github.com/google/uuid"
+
+// RFQLineRequest asks suppliers to price a BOQ material. Quantity defaults
+// to the BOQ quantity not yet ordered.
+type RFQLineRequest struct {
+	MaterialID string  `json:"material_id" validate:"required"`
+	Quantity   float64 `json:"quantity" validate:"omitempty,gt=0"`
+}
+
+type CreateRFQRequest struct {
+	IssueDate       string           `json:"issue_date" validate:"omitempty,datetime=2006-01-02"`
+	ResponseDueDate string           `json:"response_due_date" validate:"omitempty,datetime=2006-01-02"`
+	Notes           string           `json:"notes"`
+	SupplierIDs     []uuid.UUID      `json:"supplier_ids" validate:"required,min=1"`
+	Lines           []RFQLineRequest `json:"lines" validate:"required,min=1,dive"`
+}
+
+type UpdateRFQStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=sent cancelled"`
+}
+
+type RFQQuoteLineRequest struct {
+	RFQLineID    uuid.UUID `json:"rfq_line_id" validate:"required"`
+	UnitPrice    float64   `json:"unit_price" validate:"required,gt=0"`
+	LeadTimeDays *int      `json:"lead_time_days" validate:"omitempty,min=0"`
+	Notes        string    `json:"notes"`
+}
+
+// RecordRFQQuoteRequest records a supplier's reply to an RFQ and replaces
+// any earlier reply. LeadTimeDays applies to lines that do not give their
+// own. Lines the supplier did not price are left out.
+type RecordRFQQuoteRequest struct {
+	LeadTimeDays int                   `json:"lead_time_days" validate:"min=0"`
+	Lines        []RFQQuoteLineRequest `json:"lines" validate:"required,min=1,dive"`
+}
+
+type RFQAwardLineRequest struct {
+	RFQLineID  uuid.UUID `json:"rfq_line_id" validate:"required"`
+	SupplierID uuid.UUID `json:"supplier_id" validate:"required"`
+}
+
+// AwardRFQRequest awards an RFQ and either creates draft purchase orders
+// ("purchase_orders") or sets the BOQ actual prices ("actual_prices").
+// Without SupplierID or Lines the recommended award is used; SupplierID
+// awards every line to one supplier and Lines override single lines.
+type AwardRFQRequest struct {
+	Target     string                `json:"target" validate:"required,oneof=purchase_orders actual_prices"`
+	SupplierID *uuid.UUID            `json:"supplier_id"`
+	Lines      []RFQAwardLineRequest `json:"lines" validate:"dive"`
+	OrderDate  string                `json:"order_date" validate:"omitempty,datetime=2006-01-02"`
+	Remarks    string                `json:"remarks"`
+}
@@ -0,0 +1,13 @@
+package requests
+
+// SupplierPriceRequest is a price list entry. ValidTo may be left empty for
+// a price that stays valid until the next price list. Prices quoted per
+// another Unit than the material's are converted to the material's unit.
+type SupplierPriceRequest struct {
+	MaterialID  string  `json:"material_id" validate:"required"`
+	UnitPrice   float64 `json:"unit_price" validate:"required,gt=0"`
+	MinQuantity float64 `json:"min_quantity" validate:"min=0"`
+	Unit        string  `json:"unit"`
+	ValidFrom   string  `json:"valid_from" validate:"required,datetime=2006-01-02"`
+	ValidTo     string  `json:"valid_to" validate:"omitempty,datetime=2006-01-02"`
+}
@@ -0,0 +1,10 @@
+package requests
+
+// CreateUnitConversionRequest states that one FromUnit equals Factor ToUnit,
+// for MaterialID only or, when it is empty, for every material.
+type CreateUnitConversionRequest struct {
+	MaterialID string  `json:"material_id"`
+	FromUnit   string  `json:"from_unit" validate:"required"`
+	ToUnit     string  `json:"to_unit" validate:"required"`
+	Factor     float64 `json:"factor" validate:"required,gt=0"`
+}
@@ -56,6 +56,8 @@ type MaterialDTO struct {
 	JobName        string    `json:"job_name"`
 	MaterialName   string    `json:"material_name"`
 	Quantity       float64   `json:"quantity"`
+	WastePercent   float64   `json:"waste_percent"`
+	GrossQuantity  float64   `json:"gross_quantity"`
 	Unit           string    `json:"unit"`
 	EstimatedPrice float64   `json:"estimated_price"`
 	Total          float64   `json:"total"`
@@ -0,0 +1,57 @@
+package responses
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ChangeOrderResponse struct {
+	ChangeOrderID     uuid.UUID                   `json:"change_order_id"`
+	ContractID        uuid.UUID                   `json:"contract_id"`
+	ProjectID         uuid.UUID                   `json:"project_id"`
+	ChangeOrderNumber int                         `json:"change_order_number"`
+	Title             string                      `json:"title"`
+	Reason            string                      `json:"reason"`
+	Status            string                      `json:"status"`
+	TaxPercentage     float64                     `json:"tax_percentage"`
+	ValueChange       float64                     `json:"value_change"`
+	RejectReason      string                      `json:"reject_reason,omitempty"`
+	SubmittedAt       *time.Time                  `json:"submitted_at"`
+	ApprovedAt        *time.Time                  `json:"approved_at"`
+	RejectedAt        *time.Time                  `json:"rejected_at"`
+	CreatedAt         time.Time                   `json:"created_at"`
+	Items             []ChangeOrderItemResponse   `json:"items"`
+	Periods           []ChangeOrderPeriodResponse `json:"periods"`
+}
+
+type ChangeOrderItemResponse struct {
+	JobID                uuid.UUID `json:"job_id"`
+	JobName              string    `json:"job_name"`
+	ChangeType           string    `json:"change_type"`
+	OriginalQuantity     float64   `json:"original_quantity"`
+	NewQuantity          float64   `json:"new_quantity"`
+	OriginalSellingPrice float64   `json:"original_selling_price"`
+	NewSellingPrice      float64   `json:"new_selling_price"`
+	LaborCost            float64   `json:"labor_cost"`
+	AmountChange         float64   `json:"amount_change"`
+}
+
+type ChangeOrderPeriodResponse struct {
+	PeriodID        *uuid.UUID `json:"period_id"`
+	PeriodNumber    int        `json:"period_number"`
+	DeliveredWithin int        `json:"delivered_within"`
+	OriginalAmount  float64    `json:"original_amount"`
+	AmountChange    float64    `json:"amount_change"`
+	NewAmount       float64    `json:"new_amount"`
+}
+
+// ChangeOrderSummaryResponse shows the original contract value next to the
+// value after approved change orders.
+type ChangeOrderSummaryResponse struct {
+	ContractID          uuid.UUID             `json:"contract_id"`
+	OriginalValue       float64               `json:"original_value"`
+	ApprovedChangeTotal float64               `json:"approved_change_total"`
+	CurrentValue        float64               `json:"current_value"`
+	ChangeOrders        []ChangeOrderResponse `json:"change_orders"`
+}
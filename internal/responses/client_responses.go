@@ -8,14 +8,18 @@ import (
 )
 
 type ClientResponse struct {
-	ID        uuid.UUID       `json:"id"`
-	Name      string          `json:"name"`
-	Email     string          `json:"email"`
-	Tel       string          `json:"tel"`
-	Address   json.RawMessage `json:"address"`
-	TaxID     string          `json:"tax_id"`
-	CreatedAt time.Time       `json:"created_at"`
-	UpdatedAt time.Time       `json:"updated_at"`
+	ID      uuid.UUID       `json:"id"`
+	Name    string          `json:"name"`
+	Email   string          `json:"email"`
+	Tel     string          `json:"tel"`
+	Address json.RawMessage `json:"address"`
+	TaxID   string          `json:"tax_id"`
+	Branch  string          `json:"branch"`
+
+	WithholdingTaxPercentage float64 `json:"withholding_tax_percentage"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 type ClientListResponse struct {
@@ -13,6 +13,7 @@ type CompanyResponse struct {
 	Tel       string          `json:"tel"`
 	Address   json.RawMessage `json:"address"`
 	TaxID     string          `json:"tax_id"`
+	Branch    string          `json:"branch"`
 	IsNew     bool            `json:"-"`
 }
 
@@ -9,6 +9,7 @@ import (
 
 type ContractResponse struct {
 	ContractID          uuid.UUID        `json:"contract_id"`
+	ContractNumber      string           `json:"contract_number"`
 	ProjectID           uuid.UUID        `json:"project_id"`
 	ProjectDescription  string           `json:"project_description"`
 	AreaSize            float64          `json:"area_size"`
@@ -28,6 +29,26 @@ type ContractResponse struct {
 	CreatedAt           time.Time        `json:"created_at"`
 	UpdatedAt           time.Time        `json:"updated_at"`
 	Periods             []PeriodResponse `json:"periods"`
+
+	StatusHistory []ContractStatusHistoryResponse `json:"status_history"`
+}
+
+type ContractStatusHistoryResponse struct {
+	FromStatus        string    `json:"from_status"`
+	ToStatus          string    `json:"to_status"`
+	TerminationReason string    `json:"termination_reason,omitempty"`
+	Remarks           string    `json:"remarks,omitempty"`
+	ChangedAt         time.Time `json:"changed_at"`
+}
+
+func ToContractStatusHistoryResponse(h *models.ContractStatusHistory) ContractStatusHistoryResponse {
+	return ContractStatusHistoryResponse{
+		FromStatus:        string(h.FromStatus),
+		ToStatus:          string(h.ToStatus),
+		TerminationReason: h.TerminationReason.String,
+		Remarks:           h.Remarks.String,
+		ChangedAt:         h.ChangedAt,
+	}
 }
 
 type PeriodResponse struct {
@@ -46,10 +67,11 @@ type JobPeriodResponse struct {
 
 func ToContractResponse(c *models.Contract) *ContractResponse {
 	response := &ContractResponse{
-		ContractID: c.ContractID,
-		ProjectID:  c.ProjectID,
-		Format:     []string(c.Format),
-		CreatedAt:  c.CreatedAt,
+		ContractID:     c.ContractID,
+		ContractNumber: c.ContractNumber.String,
+		ProjectID:      c.ProjectID,
+		Format:         []string(c.Format),
+		CreatedAt:      c.CreatedAt,
 	}
 
 	if c.ProjectDescription.Valid {
@@ -97,3 +119,25 @@ func ToContractResponse(c *models.Contract) *ContractResponse {
 
 	return response
 }
+
+// PeriodProposalResponse uses the same period and job fields as
+// CreatePeriodRequest so a proposal can be submitted as is.
+type PeriodProposalResponse struct {
+	Rule        string                   `json:"rule"`
+	TotalAmount float64                  `json:"total_amount"`
+	Periods     []ProposedPeriodResponse `json:"periods"`
+}
+
+type ProposedPeriodResponse struct {
+	PeriodNumber    int                         `json:"period_number"`
+	Label           string                      `json:"label"`
+	AmountPeriod    float64                     `json:"amount_period"`
+	DeliveredWithin int                         `json:"delivered_within"`
+	Jobs            []ProposedJobPeriodResponse `json:"jobs"`
+}
+
+type ProposedJobPeriodResponse struct {
+	JobID     uuid.UUID `json:"job_id"`
+	JobName   string    `json:"job_name"`
+	JobAmount float64   `json:"job_amount"`
+}
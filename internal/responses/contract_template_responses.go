@@ -0,0 +1,24 @@
+package responses
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ContractClauseTemplateResponse struct {
+	TemplateID uuid.UUID `json:"template_id"`
+	CompanyID  uuid.UUID `json:"company_id"`
+	ClauseType string    `json:"clause_type"`
+	Title      string    `json:"title"`
+	Body       string    `json:"body"`
+	SortOrder  int       `json:"sort_order"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type ContractDocumentResponse struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+}
@@ -0,0 +1,13 @@
+package responses
+
+import "github.com/google/uuid"
+
+type DocumentNumberFormatResponse struct {
+	CompanyID    uuid.UUID `json:"company_id"`
+	DocumentType string    `json:"document_type"`
+	Pattern      string    `json:"pattern"`
+	IsDefault    bool      `json:"is_default"`
+	FiscalYear   int       `json:"fiscal_year"`
+	LastNumber   int       `json:"last_number"`
+	NextNumber   string    `json:"next_number"`
+}
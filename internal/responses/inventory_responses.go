@@ -0,0 +1,65 @@
+package responses
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type StockMovementResponse struct {
+	MovementID             uuid.UUID  `json:"movement_id"`
+	ProjectID              uuid.UUID  `json:"project_id"`
+	MaterialID             string     `json:"material_id"`
+	MaterialName           string     `json:"material_name"`
+	Unit                   string     `json:"unit"`
+	MovementType           string     `json:"movement_type"`
+	Quantity               float64    `json:"quantity"`
+	MovementDate           time.Time  `json:"movement_date"`
+	JobID                  *uuid.UUID `json:"job_id"`
+	JobName                string     `json:"job_name"`
+	GRID                   *uuid.UUID `json:"gr_id"`
+	SupplierID             *uuid.UUID `json:"supplier_id"`
+	SupplierName           string     `json:"supplier_name"`
+	TransferID             *uuid.UUID `json:"transfer_id"`
+	CounterpartProjectID   *uuid.UUID `json:"counterpart_project_id"`
+	CounterpartProjectName string     `json:"counterpart_project_name"`
+	Notes                  string     `json:"notes"`
+	RecordedBy             string     `json:"recorded_by"`
+	CreatedAt              time.Time  `json:"created_at"`
+}
+
+type StockBalanceResponse struct {
+	MaterialID     string  `json:"material_id"`
+	MaterialName   string  `json:"material_name"`
+	Unit           string  `json:"unit"`
+	Received       float64 `json:"received"`
+	Issued         float64 `json:"issued"`
+	TransferredIn  float64 `json:"transferred_in"`
+	TransferredOut float64 `json:"transferred_out"`
+	Returned       float64 `json:"returned"`
+	OnHand         float64 `json:"on_hand"`
+}
+
+// MaterialConsumptionResponse compares the BOQ estimate of a material with
+// the quantity issued to jobs. VariancePercent is nil when nothing was
+// estimated.
+type MaterialConsumptionResponse struct {
+	MaterialID        string                   `json:"material_id"`
+	MaterialName      string                   `json:"material_name"`
+	Unit              string                   `json:"unit"`
+	EstimatedQuantity float64                  `json:"estimated_quantity"`
+	IssuedQuantity    float64                  `json:"issued_quantity"`
+	Variance          float64                  `json:"variance"`
+	VariancePercent   *float64                 `json:"variance_percent"`
+	OnHand            float64                  `json:"on_hand"`
+	Jobs              []JobConsumptionResponse `json:"jobs"`
+}
+
+type JobConsumptionResponse struct {
+	JobID             uuid.UUID `json:"job_id"`
+	JobName           string    `json:"job_name"`
+	EstimatedQuantity float64   `json:"estimated_quantity"`
+	IssuedQuantity    float64   `json:"issued_quantity"`
+	Variance          float64   `json:"variance"`
+	VariancePercent   *float64  `json:"variance_percent"`
+}
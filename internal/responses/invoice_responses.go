@@ -1,6 +1,7 @@
 package responses
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -8,6 +9,8 @@ import (
 
 type InvoiceResponse struct {
 	InvoiceID      uuid.UUID      `json:"invoice_id"`
+	InvoiceNumber  string         `json:"invoice_number"`
+	InvoiceType    string         `json:"invoice_type"`
 	ProjectID      uuid.UUID      `json:"project_id"`
 	PeriodID       uuid.UUID      `json:"period_id"`
 	InvoiceDate    time.Time      `json:"invoice_date"`
@@ -20,6 +23,118 @@ type InvoiceResponse struct {
 	CreatedAt      time.Time      `json:"created_at"`
 	UpdatedAt      time.Time      `json:"updated_at"`
 	Period         PeriodResponse `json:"period"`
+
+	SubTotal                 float64 `json:"sub_total"`
+	TaxPercentage            float64 `json:"tax_percentage"`
+	TaxAmount                float64 `json:"tax_amount"`
+	TotalAmount              float64 `json:"total_amount"`
+	AdjustmentAmount         float64 `json:"adjustment_amount"`
+	AdjustedTotalAmount      float64 `json:"adjusted_total_amount"`
+	WithholdingTaxPercentage float64 `json:"withholding_tax_percentage"`
+	WithholdingTaxAmount     float64 `json:"withholding_tax_amount"`
+	NetReceivable            float64 `json:"net_receivable"`
+	PaidAmount               float64 `json:"paid_amount"`
+	OutstandingBalance       float64 `json:"outstanding_balance"`
+	IsOverdue                bool    `json:"is_overdue"`
+	DaysOverdue              int     `json:"days_overdue"`
+
+	StatusHistory []InvoiceStatusHistoryResponse `json:"status_history,omitempty"`
+
+	WithholdingCertificate *WithholdingCertificateResponse `json:"withholding_certificate"`
+}
+
+type WithholdingCertificateResponse struct {
+	CertificateID     uuid.UUID `json:"certificate_id"`
+	CertificateNumber string    `json:"certificate_number"`
+	CertificateDate   time.Time `json:"certificate_date"`
+	ReceivedDate      time.Time `json:"received_date"`
+	Amount            float64   `json:"amount"`
+	Remarks           string    `json:"remarks"`
+}
+
+type InvoiceStatusHistoryResponse struct {
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	Reason     string    `json:"reason,omitempty"`
+	ChangedBy  string    `json:"changed_by,omitempty"`
+	ChangedAt  time.Time `json:"changed_at"`
+}
+
+type PaymentResponse struct {
+	PaymentID      uuid.UUID  `json:"payment_id"`
+	InvoiceID      uuid.UUID  `json:"invoice_id"`
+	Amount         float64    `json:"amount"`
+	PaymentDate    time.Time  `json:"payment_date"`
+	Method         string     `json:"method"`
+	Reference      string     `json:"reference"`
+	BankName       string     `json:"bank_name"`
+	Remarks        string     `json:"remarks"`
+	Reversed       bool       `json:"reversed"`
+	ReversedAt     *time.Time `json:"reversed_at"`
+	ReversalReason string     `json:"reversal_reason,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type ReceiptResponse struct {
+	ReceiptID     uuid.UUID `json:"receipt_id"`
+	ReceiptNumber string    `json:"receipt_number"`
+	PaymentID     uuid.UUID `json:"payment_id"`
+	InvoiceID     uuid.UUID `json:"invoice_id"`
+	InvoiceNumber string    `json:"invoice_number"`
+	ReceiptDate   time.Time `json:"receipt_date"`
+
+	SubTotal             float64 `json:"sub_total"`
+	TaxPercentage        float64 `json:"tax_percentage"`
+	TaxAmount            float64 `json:"tax_amount"`
+	TotalAmount          float64 `json:"total_amount"`
+	WithholdingTaxAmount float64 `json:"withholding_tax_amount"`
+	RetentionAmount      float64 `json:"retention_amount"`
+	AmountReceived       float64 `json:"amount_received"`
+
+	SellerName    string          `json:"seller_name"`
+	SellerTaxID   string          `json:"seller_tax_id"`
+	SellerBranch  string          `json:"seller_branch"`
+	SellerAddress json.RawMessage `json:"seller_address"`
+	BuyerName     string          `json:"buyer_name"`
+	BuyerTaxID    string          `json:"buyer_tax_id"`
+	BuyerBranch   string          `json:"buyer_branch"`
+	BuyerAddress  json.RawMessage `json:"buyer_address"`
+
+	Voided     bool       `json:"voided"`
+	VoidedAt   *time.Time `json:"voided_at"`
+	VoidReason string     `json:"void_reason,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// ReceiptDocumentResponse is a rendered receipt ready to be sent as a file.
+type ReceiptDocumentResponse struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+}
+
+// RetentionSummaryResponse shows the retention withheld from paid period
+// invoices and when it can be released.
+type RetentionSummaryResponse struct {
+	ContractID       uuid.UUID                `json:"contract_id"`
+	RetentionMoney   float64                  `json:"retention_money"`
+	WithheldAmount   float64                  `json:"withheld_amount"`
+	ReleasedAmount   float64                  `json:"released_amount"`
+	Balance          float64                  `json:"balance"`
+	GuaranteeWithin  int                      `json:"guarantee_within"`
+	CompletedAt      *time.Time               `json:"completed_at"`
+	ReleaseDate      *time.Time               `json:"release_date"`
+	CanRelease       bool                     `json:"can_release"`
+	ReleaseInvoiceID *uuid.UUID               `json:"release_invoice_id"`
+	Invoices         []RetentionEntryResponse `json:"invoices"`
+}
+
+type RetentionEntryResponse struct {
+	InvoiceID     uuid.UUID `json:"invoice_id"`
+	InvoiceNumber string    `json:"invoice_number"`
+	PeriodNumber  int       `json:"period_number"`
+	Retention     float64   `json:"retention"`
+	PaidDate      time.Time `json:"paid_date"`
 }
 
 type InvoiceListResponse struct {
@@ -56,3 +171,72 @@ type ContractInvoiceStatusResponse struct {
 		PercentPaid     float64 `json:"percent_paid"`
 	} `json:"progress"`
 }
+
+type OverdueInvoiceResponse struct {
+	InvoiceID          uuid.UUID  `json:"invoice_id"`
+	InvoiceNumber      string     `json:"invoice_number"`
+	ProjectID          uuid.UUID  `json:"project_id"`
+	ProjectName        string     `json:"project_name"`
+	ClientID           *uuid.UUID `json:"client_id"`
+	ClientName         string     `json:"client_name"`
+	Status             string     `json:"status"`
+	PaymentDueDate     time.Time  `json:"payment_due_date"`
+	DaysOverdue        int        `json:"days_overdue"`
+	OutstandingBalance float64    `json:"outstanding_balance"`
+}
+
+// AgingBuckets splits outstanding balances by days past the payment due
+// date. Invoices without a due date count as current.
+type AgingBuckets struct {
+	Current    float64 `json:"current"`
+	Days1To30  float64 `json:"days_1_30"`
+	Days31To60 float64 `json:"days_31_60"`
+	Days61To90 float64 `json:"days_61_90"`
+	Over90     float64 `json:"over_90"`
+	Total      float64 `json:"total"`
+}
+
+type AgingProjectResponse struct {
+	ProjectID   uuid.UUID `json:"project_id"`
+	ProjectName string    `json:"project_name"`
+	AgingBuckets
+}
+
+type AgingClientResponse struct {
+	ClientID   *uuid.UUID             `json:"client_id"`
+	ClientName string                 `json:"client_name"`
+	Projects   []AgingProjectResponse `json:"projects"`
+	AgingBuckets
+}
+
+type AgingReportResponse struct {
+	AsOf    time.Time             `json:"as_of"`
+	Clients []AgingClientResponse `json:"clients"`
+	Totals  AgingBuckets          `json:"totals"`
+}
+
+type AdjustmentNoteResponse struct {
+	NoteID        uuid.UUID  `json:"note_id"`
+	NoteNumber    string     `json:"note_number"`
+	NoteType      string     `json:"note_type"`
+	InvoiceID     uuid.UUID  `json:"invoice_id"`
+	InvoiceNumber string     `json:"invoice_number"`
+	NoteDate      time.Time  `json:"note_date"`
+	Reason        string     `json:"reason"`
+	SubTotal      float64    `json:"sub_total"`
+	TaxPercentage float64    `json:"tax_percentage"`
+	TaxAmount     float64    `json:"tax_amount"`
+	TotalAmount   float64    `json:"total_amount"`
+	Voided        bool       `json:"voided"`
+	VoidedAt      *time.Time `json:"voided_at"`
+	VoidReason    string     `json:"void_reason,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// ETaxDocumentResponse is an e-Tax Invoice XML document ready to be sent as a
+// file to the e-Tax service provider.
+type ETaxDocumentResponse struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+}
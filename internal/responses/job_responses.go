@@ -31,11 +31,18 @@ type ProjectUsage struct {
 	BOQID       uuid.UUID `db:"boq_id"`
 	BOQStatus   string    `db:"boq_status"`
 }
+
+// JobMaterialItem is the net quantity of a material per unit of the job.
+// WastePercent is the job's own allowance when WasteOverridden is set, the
+// material's default otherwise.
 type JobMaterialItem struct {
-	MaterialID string  `json:"material_id" db:"material_id"`
-	Name       string  `json:"name" db:"name"`
-	Unit       string  `json:"unit" db:"unit"`
-	Quantity   float64 `json:"quantity" db:"quantity"`
+	MaterialID      string  `json:"material_id" db:"material_id"`
+	Name            string  `json:"name" db:"name"`
+	Unit            string  `json:"unit" db:"unit"`
+	Quantity        float64 `json:"quantity" db:"quantity"`
+	WastePercent    float64 `json:"waste_percent" db:"waste_percent"`
+	WasteOverridden bool    `json:"waste_overridden" db:"waste_overridden"`
+	GrossQuantity   float64 `json:"gross_quantity" db:"gross_quantity"`
 }
 
 type JobListResponse struct {
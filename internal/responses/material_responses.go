@@ -1,15 +1,29 @@
 package responses
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type MaterialResponse struct {
-	MaterialID string `json:"material_id"`
-	Name       string `json:"name"`
-	Unit       string `json:"unit"`
+	MaterialID    string     `json:"material_id"`
+	Name          string     `json:"name"`
+	Unit          string     `json:"unit"`
+	Category      string     `json:"category"`
+	Brand         string     `json:"brand,omitempty"`
+	Specification string     `json:"specification,omitempty"`
+	SKU           string     `json:"sku,omitempty"`
+	Tags          []string   `json:"tags"`
+	WastePercent  float64    `json:"waste_percent"`
+	IsDeprecated  bool       `json:"is_deprecated"`
+	DeprecatedAt  *time.Time `json:"deprecated_at,omitempty"`
+	ReplacedBy    string     `json:"replaced_by,omitempty"`
 }
 
 type MaterialListResponse struct {
 	Materials []MaterialResponse `json:"materials"`
+	Total     int64              `json:"total"`
 }
 
 type MaterialPriceListResponse struct {
@@ -20,12 +34,18 @@ type MaterialPriceDetail struct {
 	MaterialID     string  `json:"material_id"`
 	Name           string  `json:"name"`
 	TotalQuantity  float64 `json:"total_quantity"`
+	NetQuantity    float64 `json:"net_quantity"`
 	Unit           string  `json:"unit"`
 	EstimatedPrice float64 `json:"estimated_price"`
 	AvgActualPrice float64 `json:"avg_actual_price"`
 	ActualPrice    float64 `json:"actual_price"`
 	SupplierID     string  `json:"supplier_id"`
 	SupplierName   string  `json:"supplier_name"`
+
+	OrderedQuantity  float64 `json:"ordered_quantity"`
+	ReceivedQuantity float64 `json:"received_quantity"`
+	// RemainingQuantity is the required quantity not yet received.
+	RemainingQuantity float64 `json:"remaining_quantity"`
 }
 
 type MaterialActualPriceResponse struct {
@@ -33,3 +53,72 @@ type MaterialActualPriceResponse struct {
 	ActualPrice float64   `json:"actual_price"`
 	SupplierID  uuid.UUID `json:"supplier_id"`
 }
+
+// MaterialPriceHistoryResponse is the price history of a material across
+// projects, with statistics per period.
+type MaterialPriceHistoryResponse struct {
+	MaterialID string                       `json:"material_id"`
+	Name       string                       `json:"name"`
+	Unit       string                       `json:"unit"`
+	Points     []MaterialPricePointResponse `json:"points"`
+	Periods    []MaterialPricePeriod        `json:"periods"`
+	Estimated  *PriceStatistics             `json:"estimated"`
+	Actual     *PriceStatistics             `json:"actual"`
+}
+
+type MaterialPricePointResponse struct {
+	Date           time.Time  `json:"date"`
+	ProjectID      uuid.UUID  `json:"project_id"`
+	ProjectName    string     `json:"project_name"`
+	BOQID          uuid.UUID  `json:"boq_id"`
+	SupplierID     *uuid.UUID `json:"supplier_id"`
+	SupplierName   string     `json:"supplier_name"`
+	Quantity       float64    `json:"quantity"`
+	EstimatedPrice *float64   `json:"estimated_price"`
+	ActualPrice    *float64   `json:"actual_price"`
+}
+
+// MaterialPricePeriod summarizes the prices of a month ("2026-10"), quarter
+// ("2026-Q4") or year ("2026").
+type MaterialPricePeriod struct {
+	Period    string           `json:"period"`
+	StartDate time.Time        `json:"start_date"`
+	Estimated *PriceStatistics `json:"estimated"`
+	Actual    *PriceStatistics `json:"actual"`
+}
+
+// PriceStatistics are the minimum, maximum and average of a set of prices.
+// ChangePercent compares the average with the previous period that has
+// prices, or for a whole history the last period with the first.
+type PriceStatistics struct {
+	Count         int      `json:"count"`
+	Min           float64  `json:"min"`
+	Max           float64  `json:"max"`
+	Average       float64  `json:"average"`
+	ChangePercent *float64 `json:"change_percent"`
+}
+
+// EstimatedPriceAutoFillResponse lists the BOQ materials whose estimated
+// price was filled, those that kept their estimate and those the strategy
+// had no data for.
+type EstimatedPriceAutoFillResponse struct {
+	BOQID    uuid.UUID                    `json:"boq_id"`
+	Strategy string                       `json:"strategy"`
+	Updated  []EstimatedPriceAutoFillLine `json:"updated"`
+	Kept     []EstimatedPriceAutoFillLine `json:"kept"`
+	NoData   []EstimatedPriceAutoFillLine `json:"no_data"`
+}
+
+// EstimatedPriceAutoFillLine is a BOQ material and the price the strategy
+// found for it. Source describes where the price came from.
+type EstimatedPriceAutoFillLine struct {
+	MaterialID       string     `json:"material_id"`
+	MaterialName     string     `json:"material_name"`
+	Unit             string     `json:"unit"`
+	RequiredQuantity float64    `json:"required_quantity"`
+	PreviousPrice    *float64   `json:"previous_price"`
+	EstimatedPrice   *float64   `json:"estimated_price"`
+	SupplierID       *uuid.UUID `json:"supplier_id"`
+	SupplierName     string     `json:"supplier_name"`
+	Source           string     `json:"source"`
+}
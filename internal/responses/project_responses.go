@@ -15,6 +15,7 @@ type ProjectResponse struct {
 	Address     json.RawMessage      `json:"address"`
 	Status      models.ProjectStatus `json:"status"`
 	ClientID    uuid.UUID            `json:"client_id"`
+	CompanyID   *uuid.UUID           `json:"company_id"`
 	Client      *ClientResponse      `json:"client,omitempty"`
 	CreatedAt   time.Time            `json:"created_at"`
 	UpdatedAt   time.Time            `json:"updated_at"`
@@ -0,0 +1,84 @@
+package responses
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PurchaseOrderResponse struct {
+	POID         uuid.UUID  `json:"po_id"`
+	PONumber     string     `json:"po_number"`
+	ProjectID    uuid.UUID  `json:"project_id"`
+	BOQID        uuid.UUID  `json:"boq_id"`
+	SupplierID   uuid.UUID  `json:"supplier_id"`
+	SupplierName string     `json:"supplier_name"`
+	Status       string     `json:"status"`
+	OrderDate    time.Time  `json:"order_date"`
+	DeliveryDate *time.Time `json:"delivery_date"`
+	Remarks      string     `json:"remarks"`
+	SentAt       *time.Time `json:"sent_at"`
+	TotalAmount  float64    `json:"total_amount"`
+
+	// OutstandingAmount is the value of the quantities not yet received.
+	OutstandingAmount float64 `json:"outstanding_amount"`
+
+	Lines     []PurchaseOrderLineResponse `json:"lines"`
+	CreatedAt time.Time                   `json:"created_at"`
+	UpdatedAt *time.Time                  `json:"updated_at"`
+}
+
+type PurchaseOrderLineResponse struct {
+	LineID              uuid.UUID  `json:"po_line_id"`
+	MaterialID          string     `json:"material_id"`
+	MaterialName        string     `json:"material_name"`
+	Unit                string     `json:"unit"`
+	Quantity            float64    `json:"quantity"`
+	UnitPrice           float64    `json:"unit_price"`
+	Amount              float64    `json:"amount"`
+	DeliveryDate        *time.Time `json:"delivery_date"`
+	ReceivedQuantity    float64    `json:"received_quantity"`
+	RejectedQuantity    float64    `json:"rejected_quantity"`
+	OutstandingQuantity float64    `json:"outstanding_quantity"`
+}
+
+type GoodsReceiptResponse struct {
+	GRID         uuid.UUID                  `json:"gr_id"`
+	POID         uuid.UUID                  `json:"po_id"`
+	PONumber     string                     `json:"po_number"`
+	ReceivedDate time.Time                  `json:"received_date"`
+	ReceivedBy   string                     `json:"received_by"`
+	Notes        string                     `json:"notes"`
+	Lines        []GoodsReceiptLineResponse `json:"lines"`
+	CreatedAt    time.Time                  `json:"created_at"`
+}
+
+type GoodsReceiptLineResponse struct {
+	GRLineID         uuid.UUID `json:"gr_line_id"`
+	POLineID         uuid.UUID `json:"po_line_id"`
+	MaterialID       string    `json:"material_id"`
+	MaterialName     string    `json:"material_name"`
+	Unit             string    `json:"unit"`
+	QuantityReceived float64   `json:"quantity_received"`
+	QuantityRejected float64   `json:"quantity_rejected"`
+	QuantityAccepted float64   `json:"quantity_accepted"`
+	Notes            string    `json:"notes"`
+}
+
+// PurchaseOrderMaterialResponse compares what a BOQ material needs with what
+// has been ordered and received for it.
+type PurchaseOrderMaterialResponse struct {
+	MaterialID       string  `json:"material_id"`
+	Name             string  `json:"name"`
+	Unit             string  `json:"unit"`
+	RequiredQuantity float64 `json:"required_quantity"`
+	NetQuantity      float64 `json:"net_quantity"`
+	OrderedQuantity  float64 `json:"ordered_quantity"`
+	ReceivedQuantity float64 `json:"received_quantity"`
+	RejectedQuantity float64 `json:"rejected_quantity"`
+
+	// UnorderedQuantity is required but not yet on a purchase order.
+	UnorderedQuantity float64 `json:"unordered_quantity"`
+	// OutstandingQuantity is ordered but not yet received.
+	OutstandingQuantity float64 `json:"outstanding_quantity"`
+}
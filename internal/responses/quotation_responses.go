@@ -10,13 +10,25 @@ import (
 )
 
 type QuotationResponse struct {
-	QuotationID        uuid.UUID            `json:"quotation_id"`
-	Status             string               `json:"status"`
-	ValidDate          time.Time            `json:"valid_date"`
-	TaxPercentage      float64              `json:"tax_percentage"`
-	SellingGeneralCost float64              `json:"selling_general_cost"`
-	Jobs               []QuotationJobDetail `json:"jobs"`
-	Costs              []GeneralCostDetail  `json:"general_costs"`
+	QuotationID        uuid.UUID `json:"quotation_id"`
+	QuotationNumber    string    `json:"quotation_number"`
+	Status             string    `json:"status"`
+	ValidDate          time.Time `json:"valid_date"`
+	TaxPercentage      float64   `json:"tax_percentage"`
+	SellingGeneralCost float64   `json:"selling_general_cost"`
+
+	// ClientDecision is the client's answer through a share link, if any.
+	ClientDecision    string     `json:"client_decision,omitempty"`
+	ClientRespondedAt *time.Time `json:"client_responded_at,omitempty"`
+
+	WithholdingTaxPercentage float64 `json:"withholding_tax_percentage"`
+	SubTotal                 float64 `json:"sub_total"`
+	TaxAmount                float64 `json:"tax_amount"`
+	WithholdingTaxAmount     float64 `json:"withholding_tax_amount"`
+	NetReceivable            float64 `json:"net_receivable"`
+
+	Jobs  []QuotationJobDetail `json:"jobs"`
+	Costs []GeneralCostDetail  `json:"general_costs"`
 }
 
 type QuotationJobDetail struct {
@@ -59,6 +71,10 @@ type QuotationExportData struct {
 	SubTotal  float64 `json:"sub_total"`
 	TaxAmount float64 `json:"tax_amount"`
 
+	WithholdingTaxPercentage float64 `json:"withholding_tax_percentage" db:"withholding_tax_percentage"`
+	WithholdingTaxAmount     float64 `json:"withholding_tax_amount"`
+	NetReceivable            float64 `json:"net_receivable"`
+
 	JobDetails []JobDetail `json:"jobs"`
 
 	SellingGeneralCost   float64  `json:"selling_general_cost"`
@@ -100,3 +116,47 @@ func (j *JobDetail) FormatAmount() {
 		j.FormattedAmount = nil
 	}
 }
+
+type SellingPriceCalculationResponse struct {
+	ProjectID         uuid.UUID               `json:"project_id"`
+	Preview           bool                    `json:"preview"`
+	RoundTo           int                     `json:"round_to"`
+	Jobs              []SellingPriceJobDetail `json:"jobs"`
+	TotalOverallCost  float64                 `json:"total_overall_cost"`
+	TotalSellingPrice float64                 `json:"total_selling_price"`
+	TotalProfit       float64                 `json:"total_profit"`
+}
+
+type SellingPriceJobDetail struct {
+	JobID             uuid.UUID `json:"job_id"`
+	Name              string    `json:"name"`
+	Unit              string    `json:"unit"`
+	Quantity          float64   `json:"quantity"`
+	LaborCost         float64   `json:"labor_cost"`
+	MaterialCost      float64   `json:"material_cost"`
+	OverallCost       float64   `json:"overall_cost"`
+	MarkupPercentage  float64   `json:"markup_percentage"`
+	SellingPrice      float64   `json:"selling_price"`
+	TotalSellingPrice float64   `json:"total_selling_price"`
+}
+
+type QuotationShareResponse struct {
+	ShareID     uuid.UUID `json:"share_id"`
+	QuotationID uuid.UUID `json:"quotation_id"`
+	Token       string    `json:"token"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+type SharedQuotationResponse struct {
+	Quotation      *QuotationExportData     `json:"quotation"`
+	ExpiresAt      time.Time                `json:"expires_at"`
+	ClientResponse *QuotationClientResponse `json:"client_response"`
+}
+
+type QuotationClientResponse struct {
+	Decision    string    `json:"decision"`
+	ClientName  string    `json:"client_name"`
+	Remarks     string    `json:"remarks"`
+	IPAddress   string    `json:"ip_address"`
+	RespondedAt time.Time `json:"responded_at"`
+}
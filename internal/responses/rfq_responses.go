@@ -0,0 +1,110 @@
+package responses
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RFQResponse struct {
+	RFQID           uuid.UUID             `json:"rfq_id"`
+	RFQNumber       string                `json:"rfq_number"`
+	ProjectID       uuid.UUID             `json:"project_id"`
+	BOQID           uuid.UUID             `json:"boq_id"`
+	Status          string                `json:"status"`
+	IssueDate       time.Time             `json:"issue_date"`
+	ResponseDueDate *time.Time            `json:"response_due_date"`
+	Notes           string                `json:"notes"`
+	SentAt          *time.Time            `json:"sent_at"`
+	AwardedAt       *time.Time            `json:"awarded_at"`
+	Suppliers       []RFQSupplierResponse `json:"suppliers"`
+	Lines           []RFQLineResponse     `json:"lines"`
+	CreatedAt       time.Time             `json:"created_at"`
+	UpdatedAt       *time.Time            `json:"updated_at"`
+}
+
+type RFQSupplierResponse struct {
+	SupplierID   uuid.UUID `json:"supplier_id"`
+	SupplierName string    `json:"supplier_name"`
+}
+
+type RFQLineResponse struct {
+	RFQLineID         uuid.UUID  `json:"rfq_line_id"`
+	MaterialID        string     `json:"material_id"`
+	MaterialName      string     `json:"material_name"`
+	Unit              string     `json:"unit"`
+	Quantity          float64    `json:"quantity"`
+	AwardedSupplierID *uuid.UUID `json:"awarded_supplier_id"`
+	AwardedUnitPrice  *float64   `json:"awarded_unit_price"`
+}
+
+// RFQComparisonResponse is the quote matrix of an RFQ: each line with every
+// supplier's quote, the totals per supplier and the recommended award.
+type RFQComparisonResponse struct {
+	RFQID          uuid.UUID                   `json:"rfq_id"`
+	RFQNumber      string                      `json:"rfq_number"`
+	Status         string                      `json:"status"`
+	Lines          []RFQComparisonLineResponse `json:"lines"`
+	Suppliers      []RFQSupplierTotalResponse  `json:"suppliers"`
+	Recommendation *RFQRecommendationResponse  `json:"recommendation"`
+}
+
+type RFQComparisonLineResponse struct {
+	RFQLineID    uuid.UUID          `json:"rfq_line_id"`
+	MaterialID   string             `json:"material_id"`
+	MaterialName string             `json:"material_name"`
+	Unit         string             `json:"unit"`
+	Quantity     float64            `json:"quantity"`
+	Quotes       []RFQQuoteResponse `json:"quotes"`
+}
+
+type RFQQuoteResponse struct {
+	SupplierID   uuid.UUID `json:"supplier_id"`
+	SupplierName string    `json:"supplier_name"`
+	UnitPrice    float64   `json:"unit_price"`
+	Amount       float64   `json:"amount"`
+	LeadTimeDays int       `json:"lead_time_days"`
+	Notes        string    `json:"notes"`
+	IsLowest     bool      `json:"is_lowest"`
+}
+
+// RFQSupplierTotalResponse totals a supplier's quotes. Complete is true when
+// the supplier priced every line.
+type RFQSupplierTotalResponse struct {
+	SupplierID      uuid.UUID `json:"supplier_id"`
+	SupplierName    string    `json:"supplier_name"`
+	QuotedLines     int       `json:"quoted_lines"`
+	Complete        bool      `json:"complete"`
+	TotalAmount     float64   `json:"total_amount"`
+	MaxLeadTimeDays int       `json:"max_lead_time_days"`
+}
+
+// RFQRecommendationResponse is the cheapest award: to one supplier ("full")
+// or line by line ("split"). Savings is what a split award saves over the
+// cheapest full award.
+type RFQRecommendationResponse struct {
+	AwardType           string                 `json:"award_type"`
+	SupplierID          *uuid.UUID             `json:"supplier_id"`
+	TotalAmount         float64                `json:"total_amount"`
+	Savings             float64                `json:"savings"`
+	Lines               []RFQAwardLineResponse `json:"lines"`
+	UnquotedMaterialIDs []string               `json:"unquoted_material_ids"`
+}
+
+type RFQAwardLineResponse struct {
+	RFQLineID    uuid.UUID `json:"rfq_line_id"`
+	MaterialID   string    `json:"material_id"`
+	SupplierID   uuid.UUID `json:"supplier_id"`
+	SupplierName string    `json:"supplier_name"`
+	Quantity     float64   `json:"quantity"`
+	UnitPrice    float64   `json:"unit_price"`
+	Amount       float64   `json:"amount"`
+	LeadTimeDays int       `json:"lead_time_days"`
+}
+
+type RFQAwardResponse struct {
+	RFQ            RFQResponse             `json:"rfq"`
+	Target         string                  `json:"target"`
+	Lines          []RFQAwardLineResponse  `json:"lines"`
+	PurchaseOrders []PurchaseOrderResponse `json:"purchase_orders"`
+}
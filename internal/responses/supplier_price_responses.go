@@ -0,0 +1,27 @@
+package responses
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SupplierPriceResponse struct {
+	PriceID      uuid.UUID  `json:"price_id"`
+	SupplierID   uuid.UUID  `json:"supplier_id"`
+	SupplierName string     `json:"supplier_name"`
+	MaterialID   string     `json:"material_id"`
+	MaterialName string     `json:"material_name"`
+	Unit         string     `json:"unit"`
+	UnitPrice    float64    `json:"unit_price"`
+	MinQuantity  float64    `json:"min_quantity"`
+	ValidFrom    time.Time  `json:"valid_from"`
+	ValidTo      *time.Time `json:"valid_to"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    *time.Time `json:"updated_at"`
+}
+
+type SupplierPriceImportResponse struct {
+	Imported int                     `json:"imported"`
+	Prices   []SupplierPriceResponse `json:"prices"`
+}
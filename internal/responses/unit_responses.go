@@ -0,0 +1,35 @@
+package responses
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UnitResponse struct {
+	Code      string   `json:"code"`
+	Name      string   `json:"name"`
+	Dimension string   `json:"dimension"`
+	ToBase    float64  `json:"to_base,omitempty"`
+	Aliases   []string `json:"aliases"`
+}
+
+type UnitConversionResponse struct {
+	ConversionID uuid.UUID `json:"conversion_id"`
+	MaterialID   string    `json:"material_id,omitempty"`
+	MaterialName string    `json:"material_name,omitempty"`
+	FromUnit     string    `json:"from_unit"`
+	ToUnit       string    `json:"to_unit"`
+	Factor       float64   `json:"factor"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// UnitConversionResultResponse is Quantity FromUnit expressed in ToUnit.
+type UnitConversionResultResponse struct {
+	MaterialID string  `json:"material_id,omitempty"`
+	Quantity   float64 `json:"quantity"`
+	FromUnit   string  `json:"from_unit"`
+	ToUnit     string  `json:"to_unit"`
+	Factor     float64 `json:"factor"`
+	Result     float64 `json:"result"`
+}
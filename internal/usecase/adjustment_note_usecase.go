@@ -0,0 +1,226 @@
+package usecase
+
+import (
+	"boonkosang/internal/domain/models"
+	"boonkosang/internal/repositories"
+	"boonkosang/internal/requests"
+	"boonkosang/internal/responses"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AdjustmentNoteUsecase interface {
+	CreateNote(ctx context.Context, invoiceID uuid.UUID, req requests.CreateAdjustmentNoteRequest) (*responses.AdjustmentNoteResponse, error)
+	ListNotes(ctx context.Context, invoiceID uuid.UUID) ([]responses.AdjustmentNoteResponse, error)
+	VoidNote(ctx context.Context, invoiceID uuid.UUID, noteID uuid.UUID, req requests.VoidAdjustmentNoteRequest) (*responses.AdjustmentNoteResponse, error)
+}
+
+type adjustmentNoteUsecase struct {
+	noteRepo    repositories.AdjustmentNoteRepository
+	invoiceRepo repositories.InvoiceRepository
+}
+
+func NewAdjustmentNoteUsecase(
+	noteRepo repositories.AdjustmentNoteRepository,
+	invoiceRepo repositories.InvoiceRepository,
+) AdjustmentNoteUsecase {
+	return &adjustmentNoteUsecase{
+		noteRepo:    noteRepo,
+		invoiceRepo: invoiceRepo,
+	}
+}
+
+// CreateNote issues a credit or debit note against an issued invoice. The
+// note changes the amount the client owes, so the invoice may move between
+// paid and partially paid.
+func (u *adjustmentNoteUsecase) CreateNote(ctx context.Context, invoiceID uuid.UUID, req requests.CreateAdjustmentNoteRequest) (*responses.AdjustmentNoteResponse, error) {
+	invoice, err := getInvoiceWithTaxSettings(ctx, u.invoiceRepo, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	if !acceptsAdjustmentNotes(invoice) {
+		return nil, errors.New("credit and debit notes can only be issued against issued invoices")
+	}
+
+	noteType := models.AdjustmentNoteType(req.NoteType)
+	if !noteType.IsValid() {
+		return nil, errors.New("invalid note type")
+	}
+
+	if req.Amount <= 0 {
+		return nil, errors.New("note amount must be greater than 0")
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, errors.New("reason is required")
+	}
+
+	noteDate := time.Now().Truncate(24 * time.Hour)
+	if req.NoteDate != "" {
+		noteDate, err = time.Parse("2006-01-02", req.NoteDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid note date format: %w", err)
+		}
+	}
+
+	taxPercentage := invoice.TaxSettings.TaxPercentage
+	subTotal := roundMoney(req.Amount)
+	taxAmount := roundMoney(subTotal * taxPercentage / 100)
+
+	note := &models.AdjustmentNote{
+		NoteType:      noteType,
+		InvoiceID:     invoiceID,
+		ProjectID:     invoice.ProjectID,
+		NoteDate:      noteDate,
+		Reason:        reason,
+		SubTotal:      subTotal,
+		TaxPercentage: taxPercentage,
+		TaxAmount:     taxAmount,
+		TotalAmount:   roundMoney(subTotal + taxAmount),
+	}
+
+	if adjustedTotal := toInvoiceResponse(invoice).AdjustedTotalAmount; noteType == models.AdjustmentNoteTypeCredit && note.TotalAmount > adjustedTotal {
+		return nil, fmt.Errorf("credit note exceeds the invoice value of %.2f", adjustedTotal)
+	}
+
+	update := func(locked *models.Invoice) (*models.InvoiceStatusHistory, sql.NullTime, error) {
+		lockedInvoice(locked, invoice)
+		if !acceptsAdjustmentNotes(locked) {
+			return nil, sql.NullTime{}, errors.New("credit and debit notes can only be issued against issued invoices")
+		}
+		// AdjustmentAmount already includes this note
+		if adjustedTotal := toInvoiceResponse(locked).AdjustedTotalAmount; adjustedTotal < 0 {
+			return nil, sql.NullTime{}, fmt.Errorf("credit note exceeds the invoice value of %.2f", roundMoney(adjustedTotal+note.TotalAmount))
+		}
+
+		statusChange, paidDate := adjustedInvoiceStatus(locked, noteDate, reason)
+		return statusChange, paidDate, nil
+	}
+
+	if err := u.noteRepo.Create(ctx, note, update); err != nil {
+		return nil, err
+	}
+
+	return toAdjustmentNoteResponse(note, invoice.InvoiceNumber.String), nil
+}
+
+func (u *adjustmentNoteUsecase) ListNotes(ctx context.Context, invoiceID uuid.UUID) ([]responses.AdjustmentNoteResponse, error) {
+	invoice, err := getInvoiceWithTaxSettings(ctx, u.invoiceRepo, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	notes, err := u.noteRepo.ListByInvoiceID(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]responses.AdjustmentNoteResponse, len(notes))
+	for i := range notes {
+		response[i] = *toAdjustmentNoteResponse(&notes[i], invoice.InvoiceNumber.String)
+	}
+
+	return response, nil
+}
+
+// VoidNote cancels a note issued in error and restores the invoice balance.
+func (u *adjustmentNoteUsecase) VoidNote(ctx context.Context, invoiceID uuid.UUID, noteID uuid.UUID, req requests.VoidAdjustmentNoteRequest) (*responses.AdjustmentNoteResponse, error) {
+	invoice, err := getInvoiceWithTaxSettings(ctx, u.invoiceRepo, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	note, err := u.noteRepo.GetByID(ctx, noteID)
+	if err != nil {
+		return nil, err
+	}
+	if note.InvoiceID != invoiceID {
+		return nil, errors.New("note not found")
+	}
+	if note.VoidedAt.Valid {
+		return nil, errors.New("note is already voided")
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, errors.New("void reason is required")
+	}
+
+	update := func(locked *models.Invoice) (*models.InvoiceStatusHistory, sql.NullTime, error) {
+		lockedInvoice(locked, invoice)
+		statusChange, paidDate := adjustedInvoiceStatus(locked, time.Now().Truncate(24*time.Hour), reason)
+		return statusChange, paidDate, nil
+	}
+
+	note.VoidReason = sql.NullString{String: reason, Valid: true}
+	if err := u.noteRepo.Void(ctx, note, update); err != nil {
+		return nil, err
+	}
+
+	return toAdjustmentNoteResponse(note, invoice.InvoiceNumber.String), nil
+}
+
+func acceptsAdjustmentNotes(invoice *models.Invoice) bool {
+	status := invoice.CurrentStatus()
+	return status == models.InvoiceStatusIssued || status == models.InvoiceStatusPartiallyPaid || status == models.InvoiceStatusPaid
+}
+
+// adjustedInvoiceStatus works out the invoice status after its adjustment
+// amount changed. A paid invoice raised by a debit note is partially paid
+// again, and a credit note covering the rest of the balance settles it.
+func adjustedInvoiceStatus(invoice *models.Invoice, date time.Time, reason string) (*models.InvoiceStatusHistory, sql.NullTime) {
+	outstanding := toInvoiceResponse(invoice).OutstandingBalance
+
+	statusChange := &models.InvoiceStatusHistory{
+		InvoiceID:  invoice.InvoiceID,
+		FromStatus: invoice.CurrentStatus(),
+		ToStatus:   models.InvoiceStatusIssued,
+		Reason:     sql.NullString{String: reason, Valid: true},
+	}
+	var paidDate sql.NullTime
+
+	switch {
+	case invoice.PaidAmount > 0 && outstanding <= 0:
+		statusChange.ToStatus = models.InvoiceStatusPaid
+		paidDate = sql.NullTime{Time: date, Valid: true}
+		if statusChange.FromStatus == models.InvoiceStatusPaid && invoice.PaidDate.Valid {
+			paidDate = invoice.PaidDate
+		}
+	case invoice.PaidAmount > 0:
+		statusChange.ToStatus = models.InvoiceStatusPartiallyPaid
+	}
+
+	return statusChange, paidDate
+}
+
+func toAdjustmentNoteResponse(note *models.AdjustmentNote, invoiceNumber string) *responses.AdjustmentNoteResponse {
+	response := &responses.AdjustmentNoteResponse{
+		NoteID:        note.NoteID,
+		NoteNumber:    note.NoteNumber,
+		NoteType:      string(note.NoteType),
+		InvoiceID:     note.InvoiceID,
+		InvoiceNumber: invoiceNumber,
+		NoteDate:      note.NoteDate,
+		Reason:        note.Reason,
+		SubTotal:      note.SubTotal,
+		TaxPercentage: note.TaxPercentage,
+		TaxAmount:     note.TaxAmount,
+		TotalAmount:   note.TotalAmount,
+		Voided:        note.VoidedAt.Valid,
+		VoidReason:    note.VoidReason.String,
+		CreatedAt:     note.CreatedAt,
+	}
+
+	if note.VoidedAt.Valid {
+		response.VoidedAt = &note.VoidedAt.Time
+	}
+
+	return response
+}
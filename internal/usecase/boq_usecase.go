@@ -157,6 +157,8 @@ func transformMaterials(materials []models.BOQMaterialDetails) []responses.Mater
 			JobName:        material.JobName,
 			MaterialName:   material.MaterialName,
 			Quantity:       quantity,
+			WastePercent:   material.WastePercent,
+			GrossQuantity:  material.GrossQuantity.Float64,
 			Unit:           material.Unit,
 			EstimatedPrice: estimatedPrice,
 			Total:          material.Total.Float64,
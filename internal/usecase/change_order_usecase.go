@@ -0,0 +1,511 @@
+package usecase
+
+import (
+	"boonkosang/internal/domain/models"
+	"boonkosang/internal/repositories"
+	"boonkosang/internal/requests"
+	"boonkosang/internal/responses"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ChangeOrderUsecase interface {
+	Create(ctx context.Context, projectID uuid.UUID, req requests.ChangeOrderRequest) (*responses.ChangeOrderResponse, error)
+	Update(ctx context.Context, projectID uuid.UUID, changeOrderID uuid.UUID, req requests.ChangeOrderRequest) (*responses.ChangeOrderResponse, error)
+	Delete(ctx context.Context, projectID uuid.UUID, changeOrderID uuid.UUID) error
+	GetByID(ctx context.Context, projectID uuid.UUID, changeOrderID uuid.UUID) (*responses.ChangeOrderResponse, error)
+	List(ctx context.Context, projectID uuid.UUID) (*responses.ChangeOrderSummaryResponse, error)
+	Submit(ctx context.Context, projectID uuid.UUID, changeOrderID uuid.UUID) error
+	Approve(ctx context.Context, projectID uuid.UUID, changeOrderID uuid.UUID) (*responses.ChangeOrderResponse, error)
+	Reject(ctx context.Context, projectID uuid.UUID, changeOrderID uuid.UUID, req requests.RejectChangeOrderRequest) error
+}
+
+type changeOrderUsecase struct {
+	changeOrderRepo repositories.ChangeOrderRepository
+	contractRepo    repositories.ContractRepository
+	periodRepo      repositories.PeriodRepository
+	quotationRepo   repositories.QuotationRepository
+	jobRepo         repositories.JobRepository
+}
+
+func NewChangeOrderUsecase(
+	changeOrderRepo repositories.ChangeOrderRepository,
+	contractRepo repositories.ContractRepository,
+	periodRepo repositories.PeriodRepository,
+	quotationRepo repositories.QuotationRepository,
+	jobRepo repositories.JobRepository,
+) ChangeOrderUsecase {
+	return &changeOrderUsecase{
+		changeOrderRepo: changeOrderRepo,
+		contractRepo:    contractRepo,
+		periodRepo:      periodRepo,
+		quotationRepo:   quotationRepo,
+		jobRepo:         jobRepo,
+	}
+}
+
+func (u *changeOrderUsecase) Create(ctx context.Context, projectID uuid.UUID, req requests.ChangeOrderRequest) (*responses.ChangeOrderResponse, error) {
+	contract, err := u.getApprovedContract(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	changeOrder := &models.ChangeOrder{
+		ChangeOrderID: uuid.New(),
+		ContractID:    contract.ContractID,
+		ProjectID:     projectID,
+		Status:        models.ChangeOrderStatusDraft,
+		CreatedAt:     time.Now(),
+	}
+
+	if err := u.applyRequest(ctx, changeOrder, req); err != nil {
+		return nil, err
+	}
+
+	if err := u.changeOrderRepo.Create(ctx, changeOrder); err != nil {
+		return nil, err
+	}
+
+	return u.GetByID(ctx, projectID, changeOrder.ChangeOrderID)
+}
+
+func (u *changeOrderUsecase) Update(ctx context.Context, projectID uuid.UUID, changeOrderID uuid.UUID, req requests.ChangeOrderRequest) (*responses.ChangeOrderResponse, error) {
+	if _, err := u.getApprovedContract(ctx, projectID); err != nil {
+		return nil, err
+	}
+
+	changeOrder, err := u.getChangeOrder(ctx, projectID, changeOrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if changeOrder.Status != models.ChangeOrderStatusDraft {
+		return nil, errors.New("only draft change orders can be edited")
+	}
+
+	if err := u.applyRequest(ctx, changeOrder, req); err != nil {
+		return nil, err
+	}
+
+	if err := u.changeOrderRepo.Update(ctx, changeOrder); err != nil {
+		return nil, err
+	}
+
+	return u.GetByID(ctx, projectID, changeOrderID)
+}
+
+func (u *changeOrderUsecase) Delete(ctx context.Context, projectID uuid.UUID, changeOrderID uuid.UUID) error {
+	changeOrder, err := u.getChangeOrder(ctx, projectID, changeOrderID)
+	if err != nil {
+		return err
+	}
+
+	if changeOrder.Status != models.ChangeOrderStatusDraft {
+		return errors.New("only draft change orders can be deleted")
+	}
+
+	return u.changeOrderRepo.Delete(ctx, changeOrderID)
+}
+
+func (u *changeOrderUsecase) GetByID(ctx context.Context, projectID uuid.UUID, changeOrderID uuid.UUID) (*responses.ChangeOrderResponse, error) {
+	changeOrder, err := u.getChangeOrder(ctx, projectID, changeOrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toChangeOrderResponse(changeOrder), nil
+}
+
+func (u *changeOrderUsecase) List(ctx context.Context, projectID uuid.UUID) (*responses.ChangeOrderSummaryResponse, error) {
+	contract, err := u.contractRepo.GetByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	quotation, err := u.quotationRepo.GetByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quotation: %w", err)
+	}
+
+	changeOrders, err := u.changeOrderRepo.ListByContractID(ctx, contract.ContractID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &responses.ChangeOrderSummaryResponse{
+		ContractID:   contract.ContractID,
+		ChangeOrders: make([]responses.ChangeOrderResponse, len(changeOrders)),
+	}
+	if quotation != nil && quotation.FinalAmount.Valid {
+		summary.OriginalValue = quotation.FinalAmount.Float64
+	}
+
+	for i := range changeOrders {
+		summary.ChangeOrders[i] = *toChangeOrderResponse(&changeOrders[i])
+		if changeOrders[i].Status == models.ChangeOrderStatusApproved {
+			summary.ApprovedChangeTotal += changeOrders[i].ValueChange
+		}
+	}
+	summary.ApprovedChangeTotal = roundMoney(summary.ApprovedChangeTotal)
+	summary.CurrentValue = roundMoney(summary.OriginalValue + summary.ApprovedChangeTotal)
+
+	return summary, nil
+}
+
+func (u *changeOrderUsecase) Submit(ctx context.Context, projectID uuid.UUID, changeOrderID uuid.UUID) error {
+	changeOrder, err := u.getChangeOrder(ctx, projectID, changeOrderID)
+	if err != nil {
+		return err
+	}
+
+	if changeOrder.Status != models.ChangeOrderStatusDraft {
+		return errors.New("only draft change orders can be submitted")
+	}
+
+	return u.changeOrderRepo.UpdateStatus(ctx, changeOrderID, models.ChangeOrderStatusSubmitted, "")
+}
+
+func (u *changeOrderUsecase) Approve(ctx context.Context, projectID uuid.UUID, changeOrderID uuid.UUID) (*responses.ChangeOrderResponse, error) {
+	contract, err := u.getApprovedContract(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	changeOrder, err := u.getChangeOrder(ctx, projectID, changeOrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if changeOrder.Status != models.ChangeOrderStatusSubmitted {
+		return nil, errors.New("only submitted change orders can be approved")
+	}
+
+	// Periods may have been invoiced since the change order was drafted
+	statuses, err := u.changeOrderRepo.GetPeriodInvoiceStatuses(ctx, contract.ContractID)
+	if err != nil {
+		return nil, err
+	}
+	for _, period := range changeOrder.Periods {
+		if period.PeriodID.Valid && isPeriodInvoiced(statuses[period.PeriodID.UUID]) {
+			return nil, errors.New("cannot adjust a period that has already been invoiced")
+		}
+	}
+
+	if err := u.changeOrderRepo.Approve(ctx, changeOrder); err != nil {
+		return nil, err
+	}
+
+	return u.GetByID(ctx, projectID, changeOrderID)
+}
+
+func (u *changeOrderUsecase) Reject(ctx context.Context, projectID uuid.UUID, changeOrderID uuid.UUID, req requests.RejectChangeOrderRequest) error {
+	changeOrder, err := u.getChangeOrder(ctx, projectID, changeOrderID)
+	if err != nil {
+		return err
+	}
+
+	if changeOrder.Status != models.ChangeOrderStatusSubmitted {
+		return errors.New("only submitted change orders can be rejected")
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return errors.New("reject reason is required")
+	}
+
+	return u.changeOrderRepo.UpdateStatus(ctx, changeOrderID, models.ChangeOrderStatusRejected, reason)
+}
+
+func (u *changeOrderUsecase) getApprovedContract(ctx context.Context, projectID uuid.UUID) (*models.Contract, error) {
+	contract, err := u.contractRepo.GetByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !contract.CurrentStatus().IsBinding() {
+		return nil, errors.New("change orders require an approved contract")
+	}
+
+	return contract, nil
+}
+
+func (u *changeOrderUsecase) getChangeOrder(ctx context.Context, projectID uuid.UUID, changeOrderID uuid.UUID) (*models.ChangeOrder, error) {
+	changeOrder, err := u.changeOrderRepo.GetByID(ctx, changeOrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if changeOrder.ProjectID != projectID {
+		return nil, errors.New("change order not found")
+	}
+
+	return changeOrder, nil
+}
+
+// applyRequest validates the request against the current BOQ and payment
+// periods and fills in the items, periods and value change.
+func (u *changeOrderUsecase) applyRequest(ctx context.Context, changeOrder *models.ChangeOrder, req requests.ChangeOrderRequest) error {
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		return errors.New("title is required")
+	}
+	if len(req.Items) == 0 {
+		return errors.New("change order must have at least one item")
+	}
+
+	quotation, err := u.quotationRepo.GetByProjectID(ctx, changeOrder.ProjectID)
+	if err != nil {
+		return fmt.Errorf("failed to get quotation: %w", err)
+	}
+	if quotation == nil {
+		return errors.New("quotation not found")
+	}
+
+	boqJobs, err := u.quotationRepo.GetQuotationJobs(ctx, changeOrder.ProjectID)
+	if err != nil {
+		return fmt.Errorf("failed to get BOQ jobs: %w", err)
+	}
+	jobsByID := make(map[uuid.UUID]models.QuotationJob, len(boqJobs))
+	for _, job := range boqJobs {
+		jobsByID[job.JobID] = job
+	}
+
+	changeOrder.Title = title
+	changeOrder.Reason = sql.NullString{String: req.Reason, Valid: req.Reason != ""}
+	changeOrder.TaxPercentage = quotation.TaxPercentage.Float64
+	changeOrder.Items = make([]models.ChangeOrderItem, 0, len(req.Items))
+
+	var subTotal float64
+	seen := make(map[uuid.UUID]bool, len(req.Items))
+	for _, itemReq := range req.Items {
+		if seen[itemReq.JobID] {
+			return errors.New("a job can only appear once in a change order")
+		}
+		seen[itemReq.JobID] = true
+
+		if itemReq.Quantity < 0 || itemReq.SellingPrice < 0 || itemReq.LaborCost < 0 {
+			return errors.New("quantity, selling price and labor cost must not be negative")
+		}
+
+		item := models.ChangeOrderItem{
+			JobID:      itemReq.JobID,
+			ChangeType: models.ChangeType(itemReq.ChangeType),
+		}
+
+		existing, inBOQ := jobsByID[itemReq.JobID]
+		if inBOQ && existing.Quantity == 0 {
+			// Jobs removed by an earlier change order can be added back
+			inBOQ = false
+		}
+
+		switch item.ChangeType {
+		case models.ChangeTypeAdd:
+			if inBOQ {
+				return errors.New("job is already in the BOQ")
+			}
+			if itemReq.Quantity <= 0 {
+				return errors.New("quantity must be greater than 0")
+			}
+			job, err := u.jobRepo.GetByID(ctx, itemReq.JobID)
+			if err != nil {
+				return fmt.Errorf("failed to get job: %w", err)
+			}
+			if job == nil {
+				return errors.New("job not found")
+			}
+			item.JobName = job.Name
+			item.OriginalSellingPrice = existing.SellingPrice.Float64
+			item.NewQuantity = itemReq.Quantity
+			item.NewSellingPrice = itemReq.SellingPrice
+			item.LaborCost = itemReq.LaborCost
+		case models.ChangeTypeModify:
+			if !inBOQ {
+				return errors.New("job is not in the BOQ")
+			}
+			if itemReq.Quantity <= 0 {
+				return errors.New("quantity must be greater than 0")
+			}
+			item.JobName = existing.JobName
+			item.OriginalQuantity = existing.Quantity
+			item.OriginalSellingPrice = existing.SellingPrice.Float64
+			item.NewQuantity = itemReq.Quantity
+			item.NewSellingPrice = itemReq.SellingPrice
+			item.LaborCost = existing.LaborCost
+		case models.ChangeTypeRemove:
+			if !inBOQ {
+				return errors.New("job is not in the BOQ")
+			}
+			item.JobName = existing.JobName
+			item.OriginalQuantity = existing.Quantity
+			item.OriginalSellingPrice = existing.SellingPrice.Float64
+			item.NewSellingPrice = existing.SellingPrice.Float64
+			item.LaborCost = existing.LaborCost
+		default:
+			return errors.New("invalid change type")
+		}
+
+		item.AmountChange = roundMoney(item.NewQuantity*item.NewSellingPrice - item.OriginalQuantity*item.OriginalSellingPrice)
+		subTotal += item.AmountChange
+		changeOrder.Items = append(changeOrder.Items, item)
+	}
+
+	// Contract periods include VAT, so the value change does too
+	changeOrder.ValueChange = roundMoney(subTotal * (1 + changeOrder.TaxPercentage/100))
+
+	return u.applyPeriods(ctx, changeOrder, req.Periods)
+}
+
+func (u *changeOrderUsecase) applyPeriods(ctx context.Context, changeOrder *models.ChangeOrder, periodReqs []requests.ChangeOrderPeriodRequest) error {
+	periods, err := u.periodRepo.GetPeriodsByContractID(ctx, changeOrder.ContractID)
+	if err != nil {
+		return fmt.Errorf("failed to get periods: %w", err)
+	}
+	periodsByID := make(map[uuid.UUID]models.Period, len(periods))
+	for _, period := range periods {
+		periodsByID[period.PeriodID] = period
+	}
+
+	statuses, err := u.changeOrderRepo.GetPeriodInvoiceStatuses(ctx, changeOrder.ContractID)
+	if err != nil {
+		return err
+	}
+
+	return adjustPeriods(changeOrder, periodsByID, statuses, periodReqs)
+}
+
+// adjustPeriods sets the change order's period changes from the request. Changes
+// to existing periods must keep them non-negative and leave invoiced periods
+// alone, and all changes together must add up to the change order value.
+func adjustPeriods(changeOrder *models.ChangeOrder, periodsByID map[uuid.UUID]models.Period, statuses map[uuid.UUID]string, periodReqs []requests.ChangeOrderPeriodRequest) error {
+	changeOrder.Periods = make([]models.ChangeOrderPeriod, 0, len(periodReqs))
+
+	var total float64
+	seen := make(map[uuid.UUID]bool, len(periodReqs))
+	for _, periodReq := range periodReqs {
+		period := models.ChangeOrderPeriod{
+			AmountChange:    roundMoney(periodReq.AmountChange),
+			DeliveredWithin: periodReq.DeliveredWithin,
+		}
+
+		if periodReq.PeriodID != nil {
+			existing, ok := periodsByID[*periodReq.PeriodID]
+			if !ok {
+				return errors.New("period not found")
+			}
+			if seen[existing.PeriodID] {
+				return errors.New("a period can only appear once in a change order")
+			}
+			seen[existing.PeriodID] = true
+
+			if isPeriodInvoiced(statuses[existing.PeriodID]) {
+				return errors.New("cannot adjust a period that has already been invoiced")
+			}
+			if existing.AmountPeriod+period.AmountChange < 0 {
+				return errors.New("period amount cannot be negative")
+			}
+
+			period.PeriodID = uuid.NullUUID{UUID: existing.PeriodID, Valid: true}
+			period.PeriodNumber = existing.PeriodNumber
+			period.OriginalAmount = existing.AmountPeriod
+			if period.DeliveredWithin == 0 {
+				period.DeliveredWithin = existing.DeliveredWithin
+			}
+		} else {
+			if period.AmountChange <= 0 {
+				return errors.New("new period amount must be greater than 0")
+			}
+			if period.DeliveredWithin < 1 {
+				return errors.New("delivered within is required for a new period")
+			}
+		}
+
+		total += period.AmountChange
+		changeOrder.Periods = append(changeOrder.Periods, period)
+	}
+
+	if math.Abs(total-changeOrder.ValueChange) >= 0.005 {
+		return fmt.Errorf("sum of period changes (%.2f) must equal the change order value (%.2f)", total, changeOrder.ValueChange)
+	}
+
+	return nil
+}
+
+// isPeriodInvoiced reports whether a period's invoice has been issued, after
+// which its amount is fixed. A draft canceled before issue never fixed it.
+func isPeriodInvoiced(status string) bool {
+	switch models.InvoiceStatus(status) {
+	case "", models.InvoiceStatusDraft, models.InvoiceStatusCanceled:
+		return false
+	}
+	return true
+}
+
+func roundMoney(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+func toChangeOrderResponse(changeOrder *models.ChangeOrder) *responses.ChangeOrderResponse {
+	response := &responses.ChangeOrderResponse{
+		ChangeOrderID:     changeOrder.ChangeOrderID,
+		ContractID:        changeOrder.ContractID,
+		ProjectID:         changeOrder.ProjectID,
+		ChangeOrderNumber: changeOrder.ChangeOrderNumber,
+		Title:             changeOrder.Title,
+		Reason:            changeOrder.Reason.String,
+		Status:            string(changeOrder.Status),
+		TaxPercentage:     changeOrder.TaxPercentage,
+		ValueChange:       changeOrder.ValueChange,
+		RejectReason:      changeOrder.RejectReason.String,
+		CreatedAt:         changeOrder.CreatedAt,
+		Items:             make([]responses.ChangeOrderItemResponse, len(changeOrder.Items)),
+		Periods:           make([]responses.ChangeOrderPeriodResponse, len(changeOrder.Periods)),
+	}
+
+	if changeOrder.SubmittedAt.Valid {
+		response.SubmittedAt = &changeOrder.SubmittedAt.Time
+	}
+	if changeOrder.ApprovedAt.Valid {
+		response.ApprovedAt = &changeOrder.ApprovedAt.Time
+	}
+	if changeOrder.RejectedAt.Valid {
+		response.RejectedAt = &changeOrder.RejectedAt.Time
+	}
+
+	for i, item := range changeOrder.Items {
+		response.Items[i] = responses.ChangeOrderItemResponse{
+			JobID:                item.JobID,
+			JobName:              item.JobName,
+			ChangeType:           string(item.ChangeType),
+			OriginalQuantity:     item.OriginalQuantity,
+			NewQuantity:          item.NewQuantity,
+			OriginalSellingPrice: item.OriginalSellingPrice,
+			NewSellingPrice:      item.NewSellingPrice,
+			LaborCost:            item.LaborCost,
+			AmountChange:         item.AmountChange,
+		}
+	}
+
+	for i, period := range changeOrder.Periods {
+		periodResponse := responses.ChangeOrderPeriodResponse{
+			PeriodNumber:    period.PeriodNumber,
+			DeliveredWithin: period.DeliveredWithin,
+			OriginalAmount:  period.OriginalAmount,
+			AmountChange:    period.AmountChange,
+			NewAmount:       roundMoney(period.OriginalAmount + period.AmountChange),
+		}
+		if period.PeriodID.Valid {
+			periodID := period.PeriodID.UUID
+			periodResponse.PeriodID = &periodID
+		}
+		response.Periods[i] = periodResponse
+	}
+
+	return response
+}
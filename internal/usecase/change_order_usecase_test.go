@@ -0,0 +1,188 @@
+package usecase_test
+
+import (
+	"boonkosang/internal/domain/models"
+	"boonkosang/internal/requests"
+	"boonkosang/internal/usecase"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdjustPeriods(t *testing.T) {
+	first := models.Period{PeriodID: uuid.New(), PeriodNumber: 1, AmountPeriod: 53500, DeliveredWithin: 30}
+	second := models.Period{PeriodID: uuid.New(), PeriodNumber: 2, AmountPeriod: 53500, DeliveredWithin: 60}
+	periodsByID := map[uuid.UUID]models.Period{first.PeriodID: first, second.PeriodID: second}
+
+	type wantPeriod struct {
+		periodID        *uuid.UUID
+		originalAmount  float64
+		amountChange    float64
+		deliveredWithin int
+	}
+
+	tests := []struct {
+		name        string
+		valueChange float64
+		statuses    map[uuid.UUID]string
+		periods     []requests.ChangeOrderPeriodRequest
+		want        []wantPeriod
+		wantErr     string
+	}{
+		{
+			name:        "increase spread over existing periods",
+			valueChange: 10700,
+			periods: []requests.ChangeOrderPeriodRequest{
+				{PeriodID: &first.PeriodID, AmountChange: 5350},
+				{PeriodID: &second.PeriodID, AmountChange: 5350, DeliveredWithin: 75},
+			},
+			want: []wantPeriod{
+				{periodID: &first.PeriodID, originalAmount: 53500, amountChange: 5350, deliveredWithin: 30},
+				{periodID: &second.PeriodID, originalAmount: 53500, amountChange: 5350, deliveredWithin: 75},
+			},
+		},
+		{
+			name:        "decrease taken from one period",
+			valueChange: -3210,
+			periods: []requests.ChangeOrderPeriodRequest{
+				{PeriodID: &second.PeriodID, AmountChange: -3210},
+			},
+			want: []wantPeriod{
+				{periodID: &second.PeriodID, originalAmount: 53500, amountChange: -3210, deliveredWithin: 60},
+			},
+		},
+		{
+			name:        "new period for added work",
+			valueChange: 21400,
+			periods: []requests.ChangeOrderPeriodRequest{
+				{AmountChange: 21400, DeliveredWithin: 90},
+			},
+			want: []wantPeriod{
+				{amountChange: 21400, deliveredWithin: 90},
+			},
+		},
+		{
+			name:        "amounts are rounded to satang before summing",
+			valueChange: 1000,
+			periods: []requests.ChangeOrderPeriodRequest{
+				{PeriodID: &first.PeriodID, AmountChange: 333.333},
+				{PeriodID: &second.PeriodID, AmountChange: 333.333},
+				{AmountChange: 333.334, DeliveredWithin: 90},
+			},
+			wantErr: "sum of period changes (999.99) must equal the change order value (1000.00)",
+		},
+		{
+			name:        "uneven split that adds up exactly",
+			valueChange: 1000,
+			periods: []requests.ChangeOrderPeriodRequest{
+				{PeriodID: &first.PeriodID, AmountChange: 333.33},
+				{PeriodID: &second.PeriodID, AmountChange: 333.33},
+				{AmountChange: 333.34, DeliveredWithin: 90},
+			},
+			want: []wantPeriod{
+				{periodID: &first.PeriodID, originalAmount: 53500, amountChange: 333.33, deliveredWithin: 30},
+				{periodID: &second.PeriodID, originalAmount: 53500, amountChange: 333.33, deliveredWithin: 60},
+				{amountChange: 333.34, deliveredWithin: 90},
+			},
+		},
+		{
+			name:        "sum does not match the value change",
+			valueChange: 10700,
+			periods: []requests.ChangeOrderPeriodRequest{
+				{PeriodID: &first.PeriodID, AmountChange: 10000},
+			},
+			wantErr: "sum of period changes (10000.00) must equal the change order value (10700.00)",
+		},
+		{
+			name:        "period cannot become negative",
+			valueChange: -60000,
+			periods: []requests.ChangeOrderPeriodRequest{
+				{PeriodID: &first.PeriodID, AmountChange: -60000},
+			},
+			wantErr: "period amount cannot be negative",
+		},
+		{
+			name:        "period can be reduced to zero",
+			valueChange: -53500,
+			periods: []requests.ChangeOrderPeriodRequest{
+				{PeriodID: &first.PeriodID, AmountChange: -53500},
+			},
+			want: []wantPeriod{
+				{periodID: &first.PeriodID, originalAmount: 53500, amountChange: -53500, deliveredWithin: 30},
+			},
+		},
+		{
+			name:        "invoiced period is fixed",
+			valueChange: 1070,
+			statuses:    map[uuid.UUID]string{first.PeriodID: string(models.InvoiceStatusIssued)},
+			periods: []requests.ChangeOrderPeriodRequest{
+				{PeriodID: &first.PeriodID, AmountChange: 1070},
+			},
+			wantErr: "cannot adjust a period that has already been invoiced",
+		},
+		{
+			name:        "draft invoice does not fix the period",
+			valueChange: 1070,
+			statuses:    map[uuid.UUID]string{first.PeriodID: string(models.InvoiceStatusDraft)},
+			periods: []requests.ChangeOrderPeriodRequest{
+				{PeriodID: &first.PeriodID, AmountChange: 1070},
+			},
+			want: []wantPeriod{
+				{periodID: &first.PeriodID, originalAmount: 53500, amountChange: 1070, deliveredWithin: 30},
+			},
+		},
+		{
+			name:        "period listed twice",
+			valueChange: 2000,
+			periods: []requests.ChangeOrderPeriodRequest{
+				{PeriodID: &first.PeriodID, AmountChange: 1000},
+				{PeriodID: &first.PeriodID, AmountChange: 1000},
+			},
+			wantErr: "a period can only appear once in a change order",
+		},
+		{
+			name:        "new period must add value",
+			valueChange: -1000,
+			periods: []requests.ChangeOrderPeriodRequest{
+				{AmountChange: -1000, DeliveredWithin: 30},
+			},
+			wantErr: "new period amount must be greater than 0",
+		},
+		{
+			name:        "new period needs a delivery time",
+			valueChange: 1000,
+			periods: []requests.ChangeOrderPeriodRequest{
+				{AmountChange: 1000},
+			},
+			wantErr: "delivered within is required for a new period",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changeOrder := &models.ChangeOrder{ValueChange: tt.valueChange}
+
+			err := usecase.AdjustPeriods(changeOrder, periodsByID, tt.statuses, tt.periods)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			require.Len(t, changeOrder.Periods, len(tt.want))
+			for i, want := range tt.want {
+				got := changeOrder.Periods[i]
+				if want.periodID != nil {
+					assert.Equal(t, uuid.NullUUID{UUID: *want.periodID, Valid: true}, got.PeriodID)
+				} else {
+					assert.False(t, got.PeriodID.Valid)
+				}
+				assert.Equal(t, want.originalAmount, got.OriginalAmount)
+				assert.Equal(t, want.amountChange, got.AmountChange)
+				assert.Equal(t, want.deliveredWithin, got.DeliveredWithin)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"boonkosang/internal/domain/models"
 	"boonkosang/internal/repositories"
 	"boonkosang/internal/requests"
 	"boonkosang/internal/responses"
@@ -29,6 +30,16 @@ func NewClientUsecase(clientRepo repositories.ClientRepository) ClientUsecase {
 }
 
 func (u *clientUsecase) Create(ctx context.Context, req requests.CreateClientRequest) (*responses.ClientResponse, error) {
+	if err := validateWithholdingTaxPercentage(req.WithholdingTaxPercentage); err != nil {
+		return nil, err
+	}
+
+	branch, err := models.NormalizeBranch(req.Branch)
+	if err != nil {
+		return nil, err
+	}
+	req.Branch = branch
+
 	existing, err := u.clientRepo.GetByEmail(ctx, req.Email)
 	if err == nil && existing != nil {
 		return nil, errors.New("client with this email already exists")
@@ -46,10 +57,25 @@ func (u *clientUsecase) Create(ctx context.Context, req requests.CreateClientReq
 		Tel:     client.Tel,
 		Address: client.Address,
 		TaxID:   client.TaxID,
+		Branch:  client.Branch,
+
+		WithholdingTaxPercentage: client.WithholdingTaxPercentage.Float64,
 	}, nil
 }
 
 func (u *clientUsecase) Update(ctx context.Context, id uuid.UUID, req requests.UpdateClientRequest) error {
+	if req.WithholdingTaxPercentage != nil {
+		if err := validateWithholdingTaxPercentage(*req.WithholdingTaxPercentage); err != nil {
+			return err
+		}
+	}
+
+	branch, err := models.NormalizeBranch(req.Branch)
+	if err != nil {
+		return err
+	}
+	req.Branch = branch
+
 	existing, err := u.clientRepo.GetByID(ctx, id)
 	if err != nil {
 		return err
@@ -82,6 +108,9 @@ func (u *clientUsecase) GetByID(ctx context.Context, id uuid.UUID) (*responses.C
 		Tel:     client.Tel,
 		Address: client.Address,
 		TaxID:   client.TaxID,
+		Branch:  client.Branch,
+
+		WithholdingTaxPercentage: client.WithholdingTaxPercentage.Float64,
 	}, nil
 }
 
@@ -108,6 +137,9 @@ func (u *clientUsecase) List(ctx context.Context, page, pageSize int) (*response
 			Tel:     client.Tel,
 			Address: client.Address,
 			TaxID:   client.TaxID,
+			Branch:  client.Branch,
+
+			WithholdingTaxPercentage: client.WithholdingTaxPercentage.Float64,
 		}
 	}
 
@@ -51,6 +51,11 @@ func (u *companyUseCase) UpdateCompany(ctx context.Context, userID uuid.UUID, re
 		return nil, fmt.Errorf("failed to get company: %w", err)
 	}
 
+	branch, err := models.NormalizeBranch(req.Branch)
+	if err != nil {
+		return nil, err
+	}
+
 	// Convert address to JSON
 	addressJSON, err := json.Marshal(req.Address)
 	if err != nil {
@@ -65,6 +70,7 @@ func (u *companyUseCase) UpdateCompany(ctx context.Context, userID uuid.UUID, re
 		Tel:       req.Tel,
 		Address:   addressJSON,
 		TaxID:     req.TaxID,
+		Branch:    branch,
 	}
 
 	// Update in repository
@@ -96,6 +102,7 @@ func (u *companyUseCase) createCompanyResponse(company *models.Company) (*respon
 		Tel:       company.Tel,
 		Address:   company.Address,
 		TaxID:     company.TaxID,
+		Branch:    company.Branch,
 		IsNew:     company.TaxID == "",
 	}, nil
 }
@@ -0,0 +1,298 @@
+package usecase
+
+import (
+	"boonkosang/internal/domain/models"
+	"boonkosang/internal/requests"
+	"boonkosang/internal/responses"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/google/uuid"
+)
+
+const defaultPeriodIntervalDays = 30
+
+// ProposePeriods splits the quotation total into payment periods. Amounts are
+// allocated in satang so the periods always add up to the quotation total, and
+// each BOQ job's quantity is spread over the work periods so it adds up to the
+// BOQ quantity.
+func (u *contractUseCase) ProposePeriods(ctx context.Context, projectID uuid.UUID, req *requests.ProposePeriodsRequest) (*responses.PeriodProposalResponse, error) {
+	quotation, err := u.quotationRepo.GetByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quotation: %w", err)
+	}
+	if quotation == nil {
+		return nil, errors.New("quotation not found")
+	}
+	if !quotation.FinalAmount.Valid || quotation.FinalAmount.Float64 <= 0 {
+		return nil, errors.New("quotation final amount is not set")
+	}
+	totalCents := int64(math.Round(quotation.FinalAmount.Float64 * 100))
+
+	quotationJobs, err := u.quotationRepo.GetQuotationJobs(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get BOQ jobs: %w", err)
+	}
+	jobs := make([]models.QuotationJob, 0, len(quotationJobs))
+	for _, job := range quotationJobs {
+		if job.Quantity > 0 {
+			jobs = append(jobs, job)
+		}
+	}
+	if len(jobs) == 0 {
+		return nil, errors.New("BOQ has no jobs")
+	}
+
+	var periods []responses.ProposedPeriodResponse
+	switch req.Rule {
+	case "equal":
+		if req.Installments < 1 || req.Installments > 100 {
+			return nil, errors.New("installments must be between 1 and 100")
+		}
+		interval := u.periodInterval(ctx, projectID, req.IntervalDays, req.Installments)
+		periods = proposeProgressPeriods(totalCents, req.Installments, jobs, interval)
+	case "deposit":
+		if req.Installments < 1 || req.Installments > 98 {
+			return nil, errors.New("installments must be between 1 and 98")
+		}
+		if req.DepositPercentage <= 0 || req.RetentionPercentage < 0 ||
+			req.DepositPercentage+req.RetentionPercentage >= 100 {
+			return nil, errors.New("deposit and retention percentages must leave an amount for progress payments")
+		}
+		periods = u.proposeDepositPeriods(ctx, projectID, totalCents, jobs, req)
+	case "milestone":
+		periods, err = u.proposeMilestonePeriods(ctx, projectID, totalCents, jobs, req)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New("invalid period rule")
+	}
+
+	for i := range periods {
+		periods[i].PeriodNumber = i + 1
+	}
+
+	return &responses.PeriodProposalResponse{
+		Rule:        req.Rule,
+		TotalAmount: float64(totalCents) / 100,
+		Periods:     periods,
+	}, nil
+}
+
+func (u *contractUseCase) proposeDepositPeriods(ctx context.Context, projectID uuid.UUID, totalCents int64, jobs []models.QuotationJob, req *requests.ProposePeriodsRequest) []responses.ProposedPeriodResponse {
+	depositCents := int64(math.Round(float64(totalCents) * req.DepositPercentage / 100))
+	retentionCents := int64(math.Round(float64(totalCents) * req.RetentionPercentage / 100))
+	progressCents := totalCents - depositCents - retentionCents
+
+	interval := u.periodInterval(ctx, projectID, req.IntervalDays, req.Installments)
+
+	periods := []responses.ProposedPeriodResponse{{
+		Label:        "Deposit",
+		AmountPeriod: float64(depositCents) / 100,
+		// Due as soon as work starts
+		DeliveredWithin: 1,
+		Jobs:            []responses.ProposedJobPeriodResponse{},
+	}}
+	periods = append(periods, proposeProgressPeriods(progressCents, req.Installments, jobs, interval)...)
+
+	if retentionCents > 0 {
+		retentionDays := interval * (req.Installments + 1)
+		if contract, err := u.contractRepo.GetByProjectID(ctx, projectID); err == nil && contract.GuaranteeWithin.Valid {
+			retentionDays = interval*req.Installments + int(contract.GuaranteeWithin.Int32)
+		}
+		periods = append(periods, responses.ProposedPeriodResponse{
+			Label:           "Retention",
+			AmountPeriod:    float64(retentionCents) / 100,
+			DeliveredWithin: retentionDays,
+			Jobs:            []responses.ProposedJobPeriodResponse{},
+		})
+	}
+
+	return periods
+}
+
+func (u *contractUseCase) proposeMilestonePeriods(ctx context.Context, projectID uuid.UUID, totalCents int64, jobs []models.QuotationJob, req *requests.ProposePeriodsRequest) ([]responses.ProposedPeriodResponse, error) {
+	jobsByID := make(map[uuid.UUID]models.QuotationJob, len(jobs))
+	for _, job := range jobs {
+		jobsByID[job.JobID] = job
+	}
+
+	milestones := req.Milestones
+	if len(milestones) == 0 {
+		milestones = make([][]uuid.UUID, len(jobs))
+		for i, job := range jobs {
+			milestones[i] = []uuid.UUID{job.JobID}
+		}
+	}
+	if len(milestones) > 100 {
+		return nil, errors.New("a contract can have at most 100 periods")
+	}
+
+	assigned := make(map[uuid.UUID]bool, len(jobs))
+	weights := make([]float64, len(milestones))
+	for i, milestone := range milestones {
+		if len(milestone) == 0 {
+			return nil, errors.New("each milestone must have at least one job")
+		}
+		for _, jobID := range milestone {
+			job, ok := jobsByID[jobID]
+			if !ok {
+				return nil, errors.New("milestone job is not in the BOQ")
+			}
+			if assigned[jobID] {
+				return nil, errors.New("a job can only be in one milestone")
+			}
+			assigned[jobID] = true
+			weights[i] += job.Quantity * job.SellingPrice.Float64
+		}
+	}
+	if len(assigned) != len(jobs) {
+		return nil, errors.New("every BOQ job must be in a milestone")
+	}
+
+	interval := u.periodInterval(ctx, projectID, req.IntervalDays, len(milestones))
+	amounts := splitCents(totalCents, weights)
+
+	periods := make([]responses.ProposedPeriodResponse, len(milestones))
+	for i, milestone := range milestones {
+		period := responses.ProposedPeriodResponse{
+			Label:           fmt.Sprintf("Milestone %d", i+1),
+			AmountPeriod:    float64(amounts[i]) / 100,
+			DeliveredWithin: interval * (i + 1),
+			Jobs:            make([]responses.ProposedJobPeriodResponse, len(milestone)),
+		}
+		for j, jobID := range milestone {
+			job := jobsByID[jobID]
+			period.Jobs[j] = responses.ProposedJobPeriodResponse{
+				JobID:     job.JobID,
+				JobName:   job.JobName,
+				JobAmount: job.Quantity,
+			}
+		}
+		periods[i] = period
+	}
+
+	return periods, nil
+}
+
+// periodInterval returns the days between periods. Without an explicit
+// interval the contract duration is spread evenly over the periods.
+func (u *contractUseCase) periodInterval(ctx context.Context, projectID uuid.UUID, intervalDays int, count int) int {
+	if intervalDays > 0 {
+		return intervalDays
+	}
+
+	contract, err := u.contractRepo.GetByProjectID(ctx, projectID)
+	if err != nil || !contract.StartDate.Valid || !contract.EndDate.Valid {
+		return defaultPeriodIntervalDays
+	}
+
+	days := int(contract.EndDate.Time.Sub(contract.StartDate.Time).Hours() / 24)
+	if days < count {
+		return defaultPeriodIntervalDays
+	}
+
+	return days / count
+}
+
+// proposeProgressPeriods splits an amount into equal installments and spreads
+// every job's quantity evenly over them.
+func proposeProgressPeriods(amountCents int64, installments int, jobs []models.QuotationJob, interval int) []responses.ProposedPeriodResponse {
+	weights := make([]float64, installments)
+	for i := range weights {
+		weights[i] = 1
+	}
+	amounts := splitCents(amountCents, weights)
+
+	periods := make([]responses.ProposedPeriodResponse, installments)
+	for i := range periods {
+		periods[i] = responses.ProposedPeriodResponse{
+			Label:           fmt.Sprintf("Installment %d", i+1),
+			AmountPeriod:    float64(amounts[i]) / 100,
+			DeliveredWithin: interval * (i + 1),
+			Jobs:            make([]responses.ProposedJobPeriodResponse, 0, len(jobs)),
+		}
+	}
+
+	for _, job := range jobs {
+		quantities := splitQuantity(job.Quantity, installments)
+		for i, quantity := range quantities {
+			if quantity == 0 {
+				continue
+			}
+			periods[i].Jobs = append(periods[i].Jobs, responses.ProposedJobPeriodResponse{
+				JobID:     job.JobID,
+				JobName:   job.JobName,
+				JobAmount: quantity,
+			})
+		}
+	}
+
+	return periods
+}
+
+// splitCents divides total by weight using the largest remainder method, so
+// the parts always sum to total. Zero weights split the total equally.
+func splitCents(total int64, weights []float64) []int64 {
+	parts := make([]int64, len(weights))
+	if len(weights) == 0 {
+		return parts
+	}
+
+	var weightSum float64
+	for _, weight := range weights {
+		weightSum += weight
+	}
+	if weightSum <= 0 {
+		weights = make([]float64, len(parts))
+		for i := range weights {
+			weights[i] = 1
+		}
+		weightSum = float64(len(weights))
+	}
+
+	remainders := make([]float64, len(weights))
+	var allocated int64
+	for i, weight := range weights {
+		exact := float64(total) * weight / weightSum
+		parts[i] = int64(math.Floor(exact))
+		remainders[i] = exact - float64(parts[i])
+		allocated += parts[i]
+	}
+
+	for left := total - allocated; left > 0; left-- {
+		largest := 0
+		for i := range remainders {
+			if remainders[i] > remainders[largest] {
+				largest = i
+			}
+		}
+		parts[largest]++
+		remainders[largest] = -1
+	}
+
+	return parts
+}
+
+// splitQuantity spreads a quantity over n periods in steps of 0.01. The last
+// period takes whatever is left so the parts add back up to the quantity.
+func splitQuantity(quantity float64, n int) []float64 {
+	parts := make([]float64, n)
+	hundredths := int64(math.Round(quantity * 100))
+	base := hundredths / int64(n)
+	for i := 0; i < n-1; i++ {
+		parts[i] = float64(base) / 100
+	}
+
+	if math.Abs(quantity*100-float64(hundredths)) < 1e-6 {
+		parts[n-1] = float64(hundredths-base*int64(n-1)) / 100
+	} else {
+		// Quantities finer than 0.01 keep their precision in the last period
+		parts[n-1] = quantity - float64(base*int64(n-1))/100
+	}
+
+	return parts
+}
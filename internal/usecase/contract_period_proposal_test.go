@@ -0,0 +1,230 @@
+package usecase_test
+
+import (
+	"boonkosang/internal/domain/models"
+	mocks "boonkosang/internal/repositories/mock"
+	"boonkosang/internal/requests"
+	"boonkosang/internal/usecase"
+	"context"
+	"database/sql"
+	"math"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitCents(t *testing.T) {
+	tests := []struct {
+		name    string
+		total   int64
+		weights []float64
+		want    []int64
+	}{
+		{
+			name:    "even split",
+			total:   30000,
+			weights: []float64{1, 1, 1},
+			want:    []int64{10000, 10000, 10000},
+		},
+		{
+			name:    "one satang left over goes to the first largest remainder",
+			total:   10000,
+			weights: []float64{1, 1, 1},
+			want:    []int64{3334, 3333, 3333},
+		},
+		{
+			name:    "two satang left over",
+			total:   10001,
+			weights: []float64{1, 1, 1},
+			want:    []int64{3334, 3334, 3333},
+		},
+		{
+			name:    "weighted by job value",
+			total:   10700000,
+			weights: []float64{250000, 500000, 250000},
+			want:    []int64{2675000, 5350000, 2675000},
+		},
+		{
+			name:    "uneven weights",
+			total:   100,
+			weights: []float64{1, 2, 4},
+			want:    []int64{14, 29, 57},
+		},
+		{
+			name:    "zero weights split equally",
+			total:   100,
+			weights: []float64{0, 0, 0},
+			want:    []int64{34, 33, 33},
+		},
+		{
+			name:    "single part takes everything",
+			total:   12345,
+			weights: []float64{7},
+			want:    []int64{12345},
+		},
+		{
+			name:    "no parts",
+			total:   100,
+			weights: []float64{},
+			want:    []int64{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := usecase.SplitCents(tt.total, tt.weights)
+			assert.Equal(t, tt.want, got)
+
+			if len(got) > 0 {
+				var sum int64
+				for _, part := range got {
+					sum += part
+				}
+				assert.Equal(t, tt.total, sum)
+			}
+		})
+	}
+}
+
+func TestSplitCentsLeavesWeightsAlone(t *testing.T) {
+	weights := []float64{0, 0, 0}
+	assert.Equal(t, []int64{334, 333, 333}, usecase.SplitCents(1000, weights))
+	assert.Equal(t, []float64{0, 0, 0}, weights)
+}
+
+func TestSplitCentsSumsExactly(t *testing.T) {
+	for total := int64(0); total < 1000; total += 7 {
+		for n := 1; n <= 12; n++ {
+			weights := make([]float64, n)
+			for i := range weights {
+				weights[i] = float64(i%3 + 1)
+			}
+
+			var sum int64
+			for _, part := range usecase.SplitCents(total, weights) {
+				assert.GreaterOrEqual(t, part, int64(0))
+				sum += part
+			}
+			assert.Equal(t, total, sum, "total %d over %d parts", total, n)
+		}
+	}
+}
+
+func TestSplitQuantity(t *testing.T) {
+	tests := []struct {
+		name     string
+		quantity float64
+		n        int
+		want     []float64
+	}{
+		{
+			name:     "even split",
+			quantity: 9,
+			n:        3,
+			want:     []float64{3, 3, 3},
+		},
+		{
+			name:     "last period takes the remainder",
+			quantity: 10,
+			n:        3,
+			want:     []float64{3.33, 3.33, 3.34},
+		},
+		{
+			name:     "hundredths",
+			quantity: 0.05,
+			n:        2,
+			want:     []float64{0.02, 0.03},
+		},
+		{
+			name:     "quantity smaller than the number of periods",
+			quantity: 0.01,
+			n:        3,
+			want:     []float64{0, 0, 0.01},
+		},
+		{
+			name:     "single period",
+			quantity: 12.5,
+			n:        1,
+			want:     []float64{12.5},
+		},
+		{
+			name:     "finer precision stays in the last period",
+			quantity: 1.005,
+			n:        2,
+			want:     []float64{0.5, 0.505},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := usecase.SplitQuantity(tt.quantity, tt.n)
+			assert.Len(t, got, tt.n)
+			assert.InDeltaSlice(t, tt.want, got, 1e-9)
+
+			var sum float64
+			for _, part := range got {
+				sum += part
+			}
+			assert.InDelta(t, tt.quantity, sum, 1e-9)
+		})
+	}
+}
+
+func TestSplitQuantitySumsExactly(t *testing.T) {
+	for hundredths := 1; hundredths < 2000; hundredths += 13 {
+		quantity := float64(hundredths) / 100
+		for n := 1; n <= 12; n++ {
+			var sum float64
+			for _, part := range usecase.SplitQuantity(quantity, n) {
+				assert.GreaterOrEqual(t, part, 0.0)
+				sum += part
+			}
+			assert.Equal(t, int64(hundredths), int64(math.Round(sum*100)), "quantity %.2f over %d periods", quantity, n)
+		}
+	}
+}
+
+func TestProposePeriodsRequiresInstallments(t *testing.T) {
+	ctx := context.Background()
+	projectID := uuid.New()
+
+	tests := []struct {
+		name    string
+		req     requests.ProposePeriodsRequest
+		wantErr string
+	}{
+		{
+			name:    "equal without installments",
+			req:     requests.ProposePeriodsRequest{Rule: "equal"},
+			wantErr: "installments must be between 1 and 100",
+		},
+		{
+			name:    "deposit without installments",
+			req:     requests.ProposePeriodsRequest{Rule: "deposit", DepositPercentage: 30},
+			wantErr: "installments must be between 1 and 98",
+		},
+		{
+			name:    "deposit with too many installments",
+			req:     requests.ProposePeriodsRequest{Rule: "deposit", Installments: 99, DepositPercentage: 30},
+			wantErr: "installments must be between 1 and 98",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quotationRepo := new(mocks.MockQuotationRepository)
+			quotationRepo.On("GetByProjectID", ctx, projectID).Return(&models.Quotation{
+				ProjectID:   projectID,
+				FinalAmount: sql.NullFloat64{Float64: 107000, Valid: true},
+			}, nil)
+			quotationRepo.On("GetQuotationJobs", ctx, projectID).Return([]models.QuotationJob{
+				{JobID: uuid.New(), JobName: "Foundation", Quantity: 1},
+			}, nil)
+
+			uc := usecase.NewContractUsecase(nil, nil, nil, quotationRepo, nil)
+			_, err := uc.ProposePeriods(ctx, projectID, &tt.req)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,496 @@
+package usecase
+
+import (
+	"boonkosang/internal/domain/models"
+	"boonkosang/internal/infrastructure/document"
+	"boonkosang/internal/repositories"
+	"boonkosang/internal/requests"
+	"boonkosang/internal/responses"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// contractClauses lists the clauses stored on a contract in the order they
+// appear in the rendered document.
+var contractClauses = []struct {
+	clauseType models.ClauseType
+	title      string
+	value      func(c *models.Contract) string
+}{
+	{models.ClauseTypeForceMajeure, "Force Majeure", func(c *models.Contract) string { return c.ForceMajeure.String }},
+	{models.ClauseTypeBreachOfContract, "Breach of Contract", func(c *models.Contract) string { return c.BreachOfContract.String }},
+	{models.ClauseTypeEndOfContract, "End of Contract", func(c *models.Contract) string { return c.EndOfContract.String }},
+	{models.ClauseTypeTerminationContract, "Termination of Contract", func(c *models.Contract) string { return c.TerminationContract.String }},
+	{models.ClauseTypeAmendment, "Amendment", func(c *models.Contract) string { return c.Amendment.String }},
+}
+
+const (
+	periodTablePlaceholder = "{{period_table}}"
+	jobTablePlaceholder    = "{{job_table}}"
+)
+
+type ContractTemplateUsecase interface {
+	ListTemplates(ctx context.Context, companyID uuid.UUID) ([]responses.ContractClauseTemplateResponse, error)
+	CreateTemplate(ctx context.Context, companyID uuid.UUID, req requests.ContractClauseTemplateRequest) (*responses.ContractClauseTemplateResponse, error)
+	UpdateTemplate(ctx context.Context, companyID uuid.UUID, templateID uuid.UUID, req requests.ContractClauseTemplateRequest) (*responses.ContractClauseTemplateResponse, error)
+	DeleteTemplate(ctx context.Context, companyID uuid.UUID, templateID uuid.UUID) error
+
+	RenderContract(ctx context.Context, projectID uuid.UUID, format string) (*responses.ContractDocumentResponse, error)
+}
+
+type contractTemplateUsecase struct {
+	templateRepo  repositories.ContractTemplateRepository
+	contractRepo  repositories.ContractRepository
+	periodRepo    repositories.PeriodRepository
+	projectRepo   repositories.ProjectRepository
+	quotationRepo repositories.QuotationRepository
+	companyRepo   repositories.CompanyRepository
+	pdfFont       []byte
+}
+
+// NewContractTemplateUsecase creates the usecase. pdfFont is a TrueType font
+// embedded into generated PDFs so that Thai text is rendered; without it, PDF
+// generation fails for documents containing Thai text.
+func NewContractTemplateUsecase(
+	templateRepo repositories.ContractTemplateRepository,
+	contractRepo repositories.ContractRepository,
+	periodRepo repositories.PeriodRepository,
+	projectRepo repositories.ProjectRepository,
+	quotationRepo repositories.QuotationRepository,
+	companyRepo repositories.CompanyRepository,
+	pdfFont []byte,
+) ContractTemplateUsecase {
+	return &contractTemplateUsecase{
+		templateRepo:  templateRepo,
+		contractRepo:  contractRepo,
+		periodRepo:    periodRepo,
+		projectRepo:   projectRepo,
+		quotationRepo: quotationRepo,
+		companyRepo:   companyRepo,
+		pdfFont:       pdfFont,
+	}
+}
+
+func (u *contractTemplateUsecase) ListTemplates(ctx context.Context, companyID uuid.UUID) ([]responses.ContractClauseTemplateResponse, error) {
+	templates, err := u.templateRepo.ListByCompanyID(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]responses.ContractClauseTemplateResponse, len(templates))
+	for i := range templates {
+		result[i] = toContractClauseTemplateResponse(&templates[i])
+	}
+
+	return result, nil
+}
+
+func (u *contractTemplateUsecase) CreateTemplate(ctx context.Context, companyID uuid.UUID, req requests.ContractClauseTemplateRequest) (*responses.ContractClauseTemplateResponse, error) {
+	if err := validateClauseTemplateRequest(req); err != nil {
+		return nil, err
+	}
+
+	if _, err := u.companyRepo.GetByID(ctx, companyID); err != nil {
+		return nil, err
+	}
+
+	template := &models.ContractClauseTemplate{
+		TemplateID: uuid.New(),
+		CompanyID:  companyID,
+		ClauseType: models.ClauseType(req.ClauseType),
+		Title:      req.Title,
+		Body:       req.Body,
+		SortOrder:  req.SortOrder,
+		CreatedAt:  time.Now(),
+	}
+
+	if err := u.templateRepo.Create(ctx, template); err != nil {
+		return nil, err
+	}
+
+	response := toContractClauseTemplateResponse(template)
+	return &response, nil
+}
+
+func (u *contractTemplateUsecase) UpdateTemplate(ctx context.Context, companyID uuid.UUID, templateID uuid.UUID, req requests.ContractClauseTemplateRequest) (*responses.ContractClauseTemplateResponse, error) {
+	if err := validateClauseTemplateRequest(req); err != nil {
+		return nil, err
+	}
+
+	template, err := u.templateRepo.GetByID(ctx, companyID, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	template.ClauseType = models.ClauseType(req.ClauseType)
+	template.Title = req.Title
+	template.Body = req.Body
+	template.SortOrder = req.SortOrder
+
+	if err := u.templateRepo.Update(ctx, template); err != nil {
+		return nil, err
+	}
+
+	template, err = u.templateRepo.GetByID(ctx, companyID, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := toContractClauseTemplateResponse(template)
+	return &response, nil
+}
+
+func (u *contractTemplateUsecase) DeleteTemplate(ctx context.Context, companyID uuid.UUID, templateID uuid.UUID) error {
+	return u.templateRepo.Delete(ctx, companyID, templateID)
+}
+
+// RenderContract builds the full contract document for a project and renders
+// it as "pdf" or "docx". Clauses typed on the contract take precedence; empty
+// clauses fall back to the company's template of the same type, and custom
+// templates are appended in their sort order.
+func (u *contractTemplateUsecase) RenderContract(ctx context.Context, projectID uuid.UUID, format string) (*responses.ContractDocumentResponse, error) {
+	if format != "pdf" && format != "docx" {
+		return nil, errors.New("format must be pdf or docx")
+	}
+
+	project, client, err := u.projectRepo.GetByIDWithClient(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	contract, err := u.contractRepo.GetByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	periods, err := u.periodRepo.GetPeriodsByContractID(ctx, contract.ContractID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get periods: %w", err)
+	}
+
+	quotation, err := u.quotationRepo.GetExportData(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quotation: %w", err)
+	}
+
+	var company *models.Company
+	templates := []models.ContractClauseTemplate{}
+	if project.CompanyID != nil {
+		company, err = u.companyRepo.GetByID(ctx, *project.CompanyID)
+		if err != nil {
+			return nil, err
+		}
+		templates, err = u.templateRepo.ListByCompanyID(ctx, *project.CompanyID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	periodTable := buildPeriodTable(periods)
+	jobTable := buildJobTable(quotation)
+	values := contractPlaceholderValues(project, client, company, contract, quotation, periodTable, jobTable)
+
+	doc := &document.Document{Title: "Construction Contract"}
+	if contract.ContractNumber.Valid {
+		doc.Title += " " + contract.ContractNumber.String
+	}
+
+	doc.AddParagraph(fillPlaceholders(
+		"This contract is made on {{contract_date}} between {{company_name}} (the \"Contractor\") "+
+			"and {{client_name}}, tax ID {{client_tax_id}} (the \"Employer\"), for the project {{project_name}}.", values))
+
+	doc.AddHeading("Scope of Work")
+	if contract.ProjectDescription.Valid && contract.ProjectDescription.String != "" {
+		doc.AddParagraph(contract.ProjectDescription.String)
+	}
+	doc.AddParagraph(fillPlaceholders(
+		"Area size: {{area_size}} sq.m. Work starts on {{start_date}} and must be completed by {{end_date}}.", values))
+	doc.AddTable(jobTable.header, jobTable.rows)
+
+	doc.AddHeading("Contract Price and Payment")
+	doc.AddParagraph(fillPlaceholders(
+		"The total contract price is {{total_amount}} baht including VAT, paid in the periods below "+
+			"within {{pay_within}} days of each delivery. Retention of {{retention_money}} is held "+
+			"and released after the guarantee period of {{guarantee_within}} days.", values))
+	doc.AddTable(periodTable.header, periodTable.rows)
+
+	templatesByType := make(map[models.ClauseType]models.ContractClauseTemplate)
+	for _, template := range templates {
+		if _, ok := templatesByType[template.ClauseType]; !ok {
+			templatesByType[template.ClauseType] = template
+		}
+	}
+
+	for _, clause := range contractClauses {
+		title, body := clause.title, clause.value(contract)
+		if strings.TrimSpace(body) == "" {
+			template, ok := templatesByType[clause.clauseType]
+			if !ok {
+				continue
+			}
+			title, body = template.Title, template.Body
+		}
+		addClause(doc, title, body, values, periodTable, jobTable)
+	}
+
+	for _, template := range templates {
+		if template.ClauseType == models.ClauseTypeCustom {
+			addClause(doc, template.Title, template.Body, values, periodTable, jobTable)
+		}
+	}
+
+	doc.AddHeading("Signatures")
+	doc.AddTable(
+		[]string{"Contractor", "Employer"},
+		[][]string{{
+			fillPlaceholders("Signed ............................ ({{company_name}})", values),
+			fillPlaceholders("Signed ............................ ({{client_name}})", values),
+		}},
+	)
+
+	fileName := "contract-" + projectID.String()
+	if contract.ContractNumber.Valid {
+		fileName = contract.ContractNumber.String
+	}
+
+	if format == "docx" {
+		content, err := document.RenderDOCX(doc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render contract: %w", err)
+		}
+		return &responses.ContractDocumentResponse{
+			FileName:    fileName + ".docx",
+			ContentType: "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+			Content:     content,
+		}, nil
+	}
+
+	content, err := document.RenderPDF(doc, u.pdfFont)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render contract: %w", err)
+	}
+	return &responses.ContractDocumentResponse{
+		FileName:    fileName + ".pdf",
+		ContentType: "application/pdf",
+		Content:     content,
+	}, nil
+}
+
+type documentTable struct {
+	header []string
+	rows   [][]string
+}
+
+func buildPeriodTable(periods []models.Period) documentTable {
+	table := documentTable{header: []string{"Period", "Work Delivered", "Delivered Within (days)", "Amount (baht)"}}
+	for _, period := range periods {
+		var jobs []string
+		for _, job := range period.Jobs {
+			jobs = append(jobs, job.JobDetail.Name)
+		}
+		table.rows = append(table.rows, []string{
+			fmt.Sprintf("%d", period.PeriodNumber),
+			strings.Join(jobs, ", "),
+			fmt.Sprintf("%d", period.DeliveredWithin),
+			formatBaht(period.AmountPeriod),
+		})
+	}
+	return table
+}
+
+func buildJobTable(quotation *responses.QuotationExportData) documentTable {
+	table := documentTable{header: []string{"Job", "Quantity", "Unit", "Unit Price (baht)", "Amount (baht)"}}
+	for _, job := range quotation.JobDetails {
+		table.rows = append(table.rows, []string{
+			job.Name,
+			fmt.Sprintf("%g", job.Quantity),
+			job.Unit,
+			formatBaht(job.SellingPrice.Float64),
+			formatBaht(job.Amount.Float64),
+		})
+	}
+	return table
+}
+
+func contractPlaceholderValues(
+	project *models.Project,
+	client *models.Client,
+	company *models.Company,
+	contract *models.Contract,
+	quotation *responses.QuotationExportData,
+	periodTable documentTable,
+	jobTable documentTable,
+) map[string]string {
+	companyName := ""
+	if company != nil {
+		companyName = company.Name
+	}
+
+	retention := "-"
+	if contract.RetentionMoney.Valid {
+		retention = formatBaht(contract.RetentionMoney.Float64) + " baht"
+	}
+
+	return map[string]string{
+		"{{contract_number}}":  contract.ContractNumber.String,
+		"{{contract_date}}":    formatContractDate(contract.CreatedAt, true),
+		"{{project_name}}":     project.Name,
+		"{{project_address}}":  formatAddress(project.Address),
+		"{{client_name}}":      client.Name,
+		"{{client_tax_id}}":    client.TaxID,
+		"{{client_address}}":   formatAddress(client.Address),
+		"{{company_name}}":     companyName,
+		"{{area_size}}":        fmt.Sprintf("%g", contract.AreaSize.Float64),
+		"{{start_date}}":       formatContractDate(contract.StartDate.Time, contract.StartDate.Valid),
+		"{{end_date}}":         formatContractDate(contract.EndDate.Time, contract.EndDate.Valid),
+		"{{retention_money}}":  retention,
+		"{{pay_within}}":       fmt.Sprintf("%d", contract.PayWithin.Int32),
+		"{{guarantee_within}}": fmt.Sprintf("%d", contract.GuaranteeWithin.Int32),
+		"{{validate_within}}":  fmt.Sprintf("%d", contract.ValidateWithin.Int32),
+		"{{total_amount}}":     formatBaht(quotation.FinalAmount.Float64),
+		periodTablePlaceholder: tableAsText(periodTable),
+		jobTablePlaceholder:    tableAsText(jobTable),
+	}
+}
+
+// addClause appends a clause to the document. A line consisting only of a
+// table placeholder is rendered as a real table; other placeholders are
+// replaced inline.
+func addClause(doc *document.Document, title, body string, values map[string]string, periodTable, jobTable documentTable) {
+	doc.AddHeading(fillPlaceholders(title, values))
+
+	var paragraph []string
+	flush := func() {
+		if len(paragraph) > 0 {
+			doc.AddParagraph(fillPlaceholders(strings.Join(paragraph, "\n"), values))
+			paragraph = nil
+		}
+	}
+
+	for _, line := range strings.Split(body, "\n") {
+		switch strings.TrimSpace(line) {
+		case periodTablePlaceholder:
+			flush()
+			doc.AddTable(periodTable.header, periodTable.rows)
+		case jobTablePlaceholder:
+			flush()
+			doc.AddTable(jobTable.header, jobTable.rows)
+		default:
+			paragraph = append(paragraph, line)
+		}
+	}
+	flush()
+}
+
+// fillPlaceholders replaces every placeholder in a single pass, so values
+// that happen to contain placeholder syntax are left untouched. Longer
+// placeholders are tried first so that one placeholder that is a prefix of
+// another never wins.
+func fillPlaceholders(text string, values map[string]string) string {
+	placeholders := make([]string, 0, len(values))
+	for placeholder := range values {
+		placeholders = append(placeholders, placeholder)
+	}
+	sort.Slice(placeholders, func(i, j int) bool {
+		if len(placeholders[i]) != len(placeholders[j]) {
+			return len(placeholders[i]) > len(placeholders[j])
+		}
+		return placeholders[i] < placeholders[j]
+	})
+
+	pairs := make([]string, 0, 2*len(placeholders))
+	for _, placeholder := range placeholders {
+		pairs = append(pairs, placeholder, values[placeholder])
+	}
+	return strings.NewReplacer(pairs...).Replace(text)
+}
+
+func tableAsText(table documentTable) string {
+	lines := make([]string, 0, len(table.rows))
+	for _, row := range table.rows {
+		lines = append(lines, strings.Join(row, " | "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func formatContractDate(t time.Time, valid bool) string {
+	if !valid {
+		return "-"
+	}
+	return t.Format("2 January 2006")
+}
+
+// formatAddress joins the non-empty parts of an address stored as JSON.
+func formatAddress(raw json.RawMessage) string {
+	var address map[string]string
+	if err := json.Unmarshal(raw, &address); err != nil {
+		return ""
+	}
+
+	var parts []string
+	for _, key := range []string{"house_number", "moo", "soi", "road", "sub_district", "district", "province", "postal_code"} {
+		if value := strings.TrimSpace(address[key]); value != "" {
+			parts = append(parts, value)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// formatBaht formats an amount with thousands separators and two decimals.
+func formatBaht(amount float64) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	cents := int64(math.Round(amount * 100))
+	whole := fmt.Sprintf("%d", cents/100)
+	var grouped []string
+	for len(whole) > 3 {
+		grouped = append([]string{whole[len(whole)-3:]}, grouped...)
+		whole = whole[:len(whole)-3]
+	}
+	grouped = append([]string{whole}, grouped...)
+
+	return fmt.Sprintf("%s%s.%02d", sign, strings.Join(grouped, ","), cents%100)
+}
+
+func validateClauseTemplateRequest(req requests.ContractClauseTemplateRequest) error {
+	switch models.ClauseType(req.ClauseType) {
+	case models.ClauseTypeForceMajeure, models.ClauseTypeBreachOfContract, models.ClauseTypeEndOfContract,
+		models.ClauseTypeTerminationContract, models.ClauseTypeAmendment, models.ClauseTypeCustom:
+	default:
+		return errors.New("invalid clause type")
+	}
+
+	if strings.TrimSpace(req.Title) == "" || strings.TrimSpace(req.Body) == "" {
+		return errors.New("title and body are required")
+	}
+
+	return nil
+}
+
+func toContractClauseTemplateResponse(template *models.ContractClauseTemplate) responses.ContractClauseTemplateResponse {
+	response := responses.ContractClauseTemplateResponse{
+		TemplateID: template.TemplateID,
+		CompanyID:  template.CompanyID,
+		ClauseType: string(template.ClauseType),
+		Title:      template.Title,
+		Body:       template.Body,
+		SortOrder:  template.SortOrder,
+		CreatedAt:  template.CreatedAt,
+	}
+	if template.UpdatedAt.Valid {
+		response.UpdatedAt = template.UpdatedAt.Time
+	}
+	return response
+}
@@ -0,0 +1,58 @@
+package usecase_test
+
+import (
+	"boonkosang/internal/usecase"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFillPlaceholders(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		values map[string]string
+		want   string
+	}{
+		{
+			name:   "replaces every occurrence",
+			text:   "{{client_name}} agrees. Signed: {{client_name}}",
+			values: map[string]string{"{{client_name}}": "บริษัท ลูกค้า จำกัด"},
+			want:   "บริษัท ลูกค้า จำกัด agrees. Signed: บริษัท ลูกค้า จำกัด",
+		},
+		{
+			name: "longer placeholder wins over its prefix",
+			text: "{{total}} / {{total_words}}",
+			values: map[string]string{
+				"{{total":         "PREFIX",
+				"{{total}}":       "1,070.00",
+				"{{total_words}}": "one thousand seventy baht",
+			},
+			want: "1,070.00 / one thousand seventy baht",
+		},
+		{
+			name: "values are not substituted again",
+			text: "{{project_name}} at {{project_address}}",
+			values: map[string]string{
+				"{{project_name}}":    "Site {{project_address}}",
+				"{{project_address}}": "Bangkok",
+			},
+			want: "Site {{project_address}} at Bangkok",
+		},
+		{
+			name:   "unknown placeholders are kept",
+			text:   "{{unknown}}",
+			values: map[string]string{"{{client_name}}": "Client"},
+			want:   "{{unknown}}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The result must not depend on map iteration order
+			for i := 0; i < 20; i++ {
+				assert.Equal(t, tt.want, usecase.FillPlaceholders(tt.text, tt.values))
+			}
+		})
+	}
+}
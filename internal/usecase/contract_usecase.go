@@ -7,7 +7,9 @@ import (
 	"boonkosang/internal/responses"
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"math"
 
 	"github.com/google/uuid"
 )
@@ -17,7 +19,8 @@ type ContractUseCase interface {
 	Update(ctx context.Context, projectID uuid.UUID, req *requests.UpdateContractRequest) error
 	Delete(ctx context.Context, projectID uuid.UUID) error
 	GetByProjectID(ctx context.Context, projectID uuid.UUID) (*responses.ContractResponse, error)
-	ChangeStatus(ctx context.Context, projectID uuid.UUID, status string) error
+	ChangeStatus(ctx context.Context, projectID uuid.UUID, req *requests.ChangeContractStatusRequest) (*responses.ContractStatusHistoryResponse, error)
+	ProposePeriods(ctx context.Context, projectID uuid.UUID, req *requests.ProposePeriodsRequest) (*responses.PeriodProposalResponse, error)
 }
 
 type contractUseCase struct {
@@ -142,6 +145,10 @@ func (u *contractUseCase) Update(ctx context.Context, projectID uuid.UUID, req *
 		return err
 	}
 
+	if !contract.CurrentStatus().IsEditable() {
+		return errors.New("contract can only be edited in draft status")
+	}
+
 	// Update basic contract fields
 	if req.ProjectDescription != "" {
 		contract.ProjectDescription = sql.NullString{
@@ -249,7 +256,7 @@ func (u *contractUseCase) Update(ctx context.Context, projectID uuid.UUID, req *
 				}
 			}
 		}
-		if jobAmount != j.Quantity {
+		if math.Round(jobAmount*100) != math.Round(j.Quantity*100) {
 			return fmt.Errorf("job amount in period is not equal to job in project")
 		}
 	}
@@ -303,6 +310,10 @@ func (u *contractUseCase) Delete(ctx context.Context, projectID uuid.UUID) error
 		return err
 	}
 
+	if !contract.CurrentStatus().IsEditable() {
+		return errors.New("contract can only be deleted in draft status")
+	}
+
 	if err := u.periodRepo.DeletePeriodsByContractID(ctx, contract.ContractID); err != nil {
 		return fmt.Errorf("failed to delete periods: %w", err)
 	}
@@ -324,10 +335,11 @@ func (u *contractUseCase) GetByProjectID(ctx context.Context, projectID uuid.UUI
 
 	// Convert to response format
 	response := &responses.ContractResponse{
-		ContractID: contract.ContractID,
-		ProjectID:  contract.ProjectID,
-		Format:     []string(contract.Format),
-		CreatedAt:  contract.CreatedAt,
+		ContractID:     contract.ContractID,
+		ContractNumber: contract.ContractNumber.String,
+		ProjectID:      contract.ProjectID,
+		Format:         []string(contract.Format),
+		CreatedAt:      contract.CreatedAt,
 	}
 
 	// Handle nullable fields
@@ -404,12 +416,72 @@ func (u *contractUseCase) GetByProjectID(ctx context.Context, projectID uuid.UUI
 		response.Periods[i] = periodResponse
 	}
 
+	history, err := u.contractRepo.GetStatusHistory(ctx, contract.ContractID)
+	if err != nil {
+		return nil, err
+	}
+	response.StatusHistory = make([]responses.ContractStatusHistoryResponse, len(history))
+	for i := range history {
+		response.StatusHistory[i] = responses.ToContractStatusHistoryResponse(&history[i])
+	}
+
 	return response, nil
 }
 
-func (u *contractUseCase) ChangeStatus(ctx context.Context, projectID uuid.UUID, status string) error {
-	//contractRepo.ChangeStatus(ctx context.Context, projectID uuid.UUID, status string) error
+func (u *contractUseCase) ChangeStatus(ctx context.Context, projectID uuid.UUID, req *requests.ChangeContractStatusRequest) (*responses.ContractStatusHistoryResponse, error) {
+	contract, err := u.contractRepo.GetByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
 
+	current := contract.CurrentStatus()
+	next := models.ContractStatus(req.Status)
+	if !next.IsValid() {
+		return nil, errors.New("invalid contract status")
+	}
+	if !current.CanTransitionTo(next) {
+		return nil, fmt.Errorf("cannot change contract status from %s to %s", current, next)
+	}
+
+	history := &models.ContractStatusHistory{
+		ContractID: contract.ContractID,
+		FromStatus: current,
+		ToStatus:   next,
+		Remarks: sql.NullString{
+			String: req.Remarks,
+			Valid:  req.Remarks != "",
+		},
+	}
+
+	switch next {
+	case models.ContractStatusPendingSignature, models.ContractStatusApproved, models.ContractStatusSigned:
+		if current == models.ContractStatusDraft || current == models.ContractStatusPendingSignature {
+			if err := u.validateForSignature(ctx, projectID); err != nil {
+				return nil, err
+			}
+		}
+	case models.ContractStatusTerminated:
+		reason := models.TerminationReason(req.TerminationReason)
+		if reason == "" {
+			return nil, errors.New("termination reason is required")
+		}
+		if !reason.IsValid() {
+			return nil, errors.New("invalid termination reason")
+		}
+		history.TerminationReason = sql.NullString{String: string(reason), Valid: true}
+	}
+
+	if err := u.contractRepo.ChangeStatus(ctx, history); err != nil {
+		return nil, err
+	}
+
+	response := responses.ToContractStatusHistoryResponse(history)
+	return &response, nil
+}
+
+// validateForSignature checks that every contract term is filled in and the
+// payment periods add up to the quotation total.
+func (u *contractUseCase) validateForSignature(ctx context.Context, projectID uuid.UUID) error {
 	contract, err := u.GetByProjectID(ctx, projectID)
 	if err != nil {
 
@@ -465,14 +537,10 @@ func (u *contractUseCase) ChangeStatus(ctx context.Context, projectID uuid.UUID,
 	for _, period := range contract.Periods {
 		sum_amount_period += period.AmountPeriod
 	}
-	if sum_amount_period != final_amount {
+	// Compare in satang so float rounding in the sum is not reported as a mismatch
+	if math.Round(sum_amount_period*100) != math.Round(final_amount*100) {
 		return fmt.Errorf("sum amount period is not equal to final amount in quotation")
 	}
 
-	if status == "approved" {
-		u.contractRepo.ChangeStatus(ctx, projectID, status)
-	}
-
 	return nil
-
 }
@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"boonkosang/internal/domain/models"
+	"boonkosang/internal/repositories"
+	"boonkosang/internal/requests"
+	"boonkosang/internal/responses"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var documentTypes = []models.DocumentType{
+	models.DocumentTypeQuotation,
+	models.DocumentTypeContract,
+	models.DocumentTypeInvoice,
+	models.DocumentTypeReceipt,
+	models.DocumentTypeCreditNote,
+	models.DocumentTypeDebitNote,
+	models.DocumentTypePurchaseOrder,
+	models.DocumentTypeRequestForQuotation,
+}
+
+type DocumentNumberUsecase interface {
+	GetFormats(ctx context.Context, companyID uuid.UUID) ([]responses.DocumentNumberFormatResponse, error)
+	UpdateFormat(ctx context.Context, companyID uuid.UUID, documentType models.DocumentType, req requests.UpdateDocumentNumberFormatRequest) (*responses.DocumentNumberFormatResponse, error)
+}
+
+type documentNumberUsecase struct {
+	documentNumberRepo repositories.DocumentNumberRepository
+	companyRepo        repositories.CompanyRepository
+}
+
+func NewDocumentNumberUsecase(documentNumberRepo repositories.DocumentNumberRepository, companyRepo repositories.CompanyRepository) DocumentNumberUsecase {
+	return &documentNumberUsecase{
+		documentNumberRepo: documentNumberRepo,
+		companyRepo:        companyRepo,
+	}
+}
+
+// GetFormats returns the numbering pattern of every document type for a company,
+// falling back to the default patterns, together with the current calendar year's counter.
+func (u *documentNumberUsecase) GetFormats(ctx context.Context, companyID uuid.UUID) ([]responses.DocumentNumberFormatResponse, error) {
+	if _, err := u.companyRepo.GetByID(ctx, companyID); err != nil {
+		return nil, err
+	}
+
+	formats, err := u.documentNumberRepo.ListFormats(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	sequences, err := u.documentNumberRepo.ListSequences(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	patterns := make(map[models.DocumentType]string)
+	for _, format := range formats {
+		patterns[format.DocumentType] = format.Pattern
+	}
+
+	now := time.Now()
+	result := make([]responses.DocumentNumberFormatResponse, 0, len(documentTypes))
+	for _, documentType := range documentTypes {
+		pattern, ok := patterns[documentType]
+		if !ok {
+			pattern = models.DefaultDocumentNumberPatterns[documentType]
+		}
+
+		lastNumber := currentLastNumber(sequences, documentType, pattern, now)
+		result = append(result, toDocumentNumberFormatResponse(companyID, documentType, pattern, !ok, lastNumber, now))
+	}
+
+	return result, nil
+}
+
+func (u *documentNumberUsecase) UpdateFormat(ctx context.Context, companyID uuid.UUID, documentType models.DocumentType, req requests.UpdateDocumentNumberFormatRequest) (*responses.DocumentNumberFormatResponse, error) {
+	if _, ok := models.DefaultDocumentNumberPatterns[documentType]; !ok {
+		return nil, errors.New("invalid document type")
+	}
+
+	if _, err := u.companyRepo.GetByID(ctx, companyID); err != nil {
+		return nil, err
+	}
+
+	if err := models.ValidateDocumentNumberPattern(req.Pattern); err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+
+	format := &models.DocumentNumberFormat{
+		CompanyID:    companyID,
+		DocumentType: documentType,
+		Pattern:      req.Pattern,
+	}
+
+	if err := u.documentNumberRepo.UpsertFormat(ctx, format); err != nil {
+		return nil, err
+	}
+
+	sequences, err := u.documentNumberRepo.ListSequences(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	lastNumber := currentLastNumber(sequences, documentType, req.Pattern, now)
+	response := toDocumentNumberFormatResponse(companyID, documentType, req.Pattern, false, lastNumber, now)
+	return &response, nil
+}
+
+// currentLastNumber returns the last number issued in the sequence that a
+// document of the given type dated now would continue.
+func currentLastNumber(sequences []models.DocumentSequence, documentType models.DocumentType, pattern string, now time.Time) int {
+	year := models.DocumentSequenceYear(pattern, now)
+	for _, sequence := range sequences {
+		if sequence.DocumentType == documentType && sequence.FiscalYear == year {
+			return sequence.LastNumber
+		}
+	}
+	return 0
+}
+
+func toDocumentNumberFormatResponse(companyID uuid.UUID, documentType models.DocumentType, pattern string, isDefault bool, lastNumber int, now time.Time) responses.DocumentNumberFormatResponse {
+	return responses.DocumentNumberFormatResponse{
+		CompanyID:    companyID,
+		DocumentType: string(documentType),
+		Pattern:      pattern,
+		IsDefault:    isDefault,
+		FiscalYear:   models.DocumentSequenceYear(pattern, now),
+		LastNumber:   lastNumber,
+		NextNumber:   models.FormatDocumentNumber(pattern, now, lastNumber+1),
+	}
+}
@@ -0,0 +1,243 @@
+package usecase
+
+import (
+	"boonkosang/internal/domain/models"
+	"boonkosang/internal/infrastructure/etax"
+	"boonkosang/internal/repositories"
+	"boonkosang/internal/responses"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type ETaxUsecase interface {
+	ExportInvoice(ctx context.Context, invoiceID uuid.UUID) (*responses.ETaxDocumentResponse, error)
+	ExportReceipt(ctx context.Context, invoiceID uuid.UUID, paymentID uuid.UUID) (*responses.ETaxDocumentResponse, error)
+	ExportNote(ctx context.Context, invoiceID uuid.UUID, noteID uuid.UUID) (*responses.ETaxDocumentResponse, error)
+}
+
+type eTaxUsecase struct {
+	invoiceRepo repositories.InvoiceRepository
+	receiptRepo repositories.ReceiptRepository
+	noteRepo    repositories.AdjustmentNoteRepository
+	projectRepo repositories.ProjectRepository
+	companyRepo repositories.CompanyRepository
+}
+
+func NewETaxUsecase(
+	invoiceRepo repositories.InvoiceRepository,
+	receiptRepo repositories.ReceiptRepository,
+	noteRepo repositories.AdjustmentNoteRepository,
+	projectRepo repositories.ProjectRepository,
+	companyRepo repositories.CompanyRepository,
+) ETaxUsecase {
+	return &eTaxUsecase{
+		invoiceRepo: invoiceRepo,
+		receiptRepo: receiptRepo,
+		noteRepo:    noteRepo,
+		projectRepo: projectRepo,
+		companyRepo: companyRepo,
+	}
+}
+
+// ExportInvoice exports an issued invoice as a tax invoice (388). The export
+// carries the original invoice value; credit and debit notes are exported as
+// their own documents.
+func (u *eTaxUsecase) ExportInvoice(ctx context.Context, invoiceID uuid.UUID) (*responses.ETaxDocumentResponse, error) {
+	invoice, err := u.getIssuedInvoice(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	seller, buyer, err := u.getParties(ctx, invoice)
+	if err != nil {
+		return nil, err
+	}
+
+	amounts := toInvoiceResponse(invoice)
+	subTotal := roundMoney(amounts.SubTotal)
+	total := roundMoney(amounts.TotalAmount)
+
+	return exportETaxDocument(&etax.Document{
+		TypeCode:    etax.TypeTaxInvoice,
+		ID:          invoice.InvoiceNumber.String,
+		IssueDate:   invoice.InvoiceDate.Time,
+		Seller:      seller,
+		Buyer:       buyer,
+		TaxRate:     amounts.TaxPercentage,
+		Lines:       []etax.LineItem{invoiceLineItem(invoice, subTotal)},
+		BasisAmount: subTotal,
+		TaxAmount:   roundMoney(total - subTotal),
+		GrandTotal:  total,
+	})
+}
+
+// ExportReceipt exports the receipt / tax invoice of a payment (T03) using the
+// seller and buyer details recorded on the receipt.
+func (u *eTaxUsecase) ExportReceipt(ctx context.Context, invoiceID uuid.UUID, paymentID uuid.UUID) (*responses.ETaxDocumentResponse, error) {
+	invoice, err := getInvoiceWithTaxSettings(ctx, u.invoiceRepo, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt, err := u.receiptRepo.GetByPaymentID(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if receipt.InvoiceID != invoice.InvoiceID {
+		return nil, errors.New("receipt not found")
+	}
+	if receipt.VoidedAt.Valid {
+		return nil, errors.New("cannot export a voided receipt")
+	}
+
+	return exportETaxDocument(&etax.Document{
+		TypeCode:    etax.TypeReceiptTaxInvoice,
+		ID:          receipt.ReceiptNumber,
+		IssueDate:   receipt.ReceiptDate,
+		Seller:      toETaxParty(receipt.SellerName, receipt.SellerTaxID, receipt.SellerBranch, receipt.SellerAddress),
+		Buyer:       toETaxParty(receipt.BuyerName, receipt.BuyerTaxID, receipt.BuyerBranch, receipt.BuyerAddress),
+		TaxRate:     receipt.TaxPercentage,
+		Lines:       []etax.LineItem{invoiceLineItem(invoice, receipt.SubTotal)},
+		BasisAmount: receipt.SubTotal,
+		TaxAmount:   receipt.TaxAmount,
+		GrandTotal:  receipt.TotalAmount,
+	})
+}
+
+// ExportNote exports a credit (81) or debit (80) note referencing the tax
+// invoice it adjusts.
+func (u *eTaxUsecase) ExportNote(ctx context.Context, invoiceID uuid.UUID, noteID uuid.UUID) (*responses.ETaxDocumentResponse, error) {
+	invoice, err := u.getIssuedInvoice(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	note, err := u.noteRepo.GetByID(ctx, noteID)
+	if err != nil {
+		return nil, err
+	}
+	if note.InvoiceID != invoice.InvoiceID {
+		return nil, errors.New("note not found")
+	}
+	if note.VoidedAt.Valid {
+		return nil, errors.New("cannot export a voided note")
+	}
+
+	seller, buyer, err := u.getParties(ctx, invoice)
+	if err != nil {
+		return nil, err
+	}
+
+	typeCode, purposeCode := etax.TypeCreditNote, "CDNS99"
+	if note.NoteType == models.AdjustmentNoteTypeDebit {
+		typeCode, purposeCode = etax.TypeDebitNote, "DBNS99"
+	}
+
+	amounts := toInvoiceResponse(invoice)
+
+	return exportETaxDocument(&etax.Document{
+		TypeCode:    typeCode,
+		ID:          note.NoteNumber,
+		IssueDate:   note.NoteDate,
+		Purpose:     note.Reason,
+		PurposeCode: purposeCode,
+		Seller:      seller,
+		Buyer:       buyer,
+		Reference: &etax.Reference{
+			ID:        invoice.InvoiceNumber.String,
+			IssueDate: invoice.InvoiceDate.Time,
+			TypeCode:  etax.TypeTaxInvoice,
+		},
+		TaxRate: note.TaxPercentage,
+		Lines: []etax.LineItem{
+			{Name: note.Reason, Quantity: 1, UnitPrice: note.SubTotal, NetAmount: note.SubTotal},
+		},
+		OriginalAmount:   roundMoney(amounts.SubTotal),
+		DifferenceAmount: note.SubTotal,
+		BasisAmount:      note.SubTotal,
+		TaxAmount:        note.TaxAmount,
+		GrandTotal:       note.TotalAmount,
+	})
+}
+
+func (u *eTaxUsecase) getIssuedInvoice(ctx context.Context, invoiceID uuid.UUID) (*models.Invoice, error) {
+	invoice, err := getInvoiceWithTaxSettings(ctx, u.invoiceRepo, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch invoice.CurrentStatus() {
+	case models.InvoiceStatusIssued, models.InvoiceStatusPartiallyPaid, models.InvoiceStatusPaid:
+	default:
+		return nil, fmt.Errorf("cannot export %s invoice as an e-tax document", invoice.CurrentStatus())
+	}
+	if !invoice.InvoiceNumber.Valid || !invoice.InvoiceDate.Valid {
+		return nil, errors.New("invoice number and date are required for an e-tax document")
+	}
+
+	return invoice, nil
+}
+
+// getParties returns the company as seller and the project client as buyer.
+func (u *eTaxUsecase) getParties(ctx context.Context, invoice *models.Invoice) (etax.Party, etax.Party, error) {
+	project, client, err := u.projectRepo.GetByIDWithClient(ctx, invoice.ProjectID)
+	if err != nil {
+		return etax.Party{}, etax.Party{}, err
+	}
+	if project.CompanyID == nil {
+		return etax.Party{}, etax.Party{}, errors.New("project is not linked to a company")
+	}
+
+	company, err := u.companyRepo.GetByID(ctx, *project.CompanyID)
+	if err != nil {
+		return etax.Party{}, etax.Party{}, err
+	}
+	if strings.TrimSpace(company.TaxID) == "" {
+		return etax.Party{}, etax.Party{}, errors.New("company tax ID is required to issue a tax invoice")
+	}
+
+	seller := toETaxParty(company.Name, company.TaxID, branchOrHeadOffice(company.Branch), company.Address)
+	buyer := toETaxParty(client.Name, client.TaxID, branchOrHeadOffice(client.Branch), client.Address)
+	return seller, buyer, nil
+}
+
+func invoiceLineItem(invoice *models.Invoice, amount float64) etax.LineItem {
+	name := "Construction work"
+	if invoice.Type() == models.InvoiceTypeRetentionRelease {
+		name = "Retention release"
+	} else if invoice.Period.PeriodNumber > 0 {
+		name = fmt.Sprintf("Construction work, period %d", invoice.Period.PeriodNumber)
+	}
+	return etax.LineItem{Name: name, Quantity: 1, UnitPrice: amount, NetAmount: amount}
+}
+
+func toETaxParty(name, taxID, branch string, address json.RawMessage) etax.Party {
+	var fields map[string]string
+	_ = json.Unmarshal(address, &fields)
+
+	return etax.Party{
+		Name:     name,
+		TaxID:    strings.TrimSpace(taxID),
+		Branch:   branch,
+		Address:  formatAddress(address),
+		Postcode: strings.TrimSpace(fields["postal_code"]),
+	}
+}
+
+func exportETaxDocument(doc *etax.Document) (*responses.ETaxDocumentResponse, error) {
+	content, err := etax.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build e-tax document: %w", err)
+	}
+
+	return &responses.ETaxDocumentResponse{
+		FileName:    doc.ID + ".xml",
+		ContentType: "application/xml",
+		Content:     content,
+	}, nil
+}
@@ -0,0 +1,20 @@
+package usecase
+
+// Exported aliases of unexported helpers for the usecase_test package.
+var (
+	FillPlaceholders   = fillPlaceholders
+	AdjustPeriods      = adjustPeriods
+	SplitCents         = splitCents
+	SplitQuantity      = splitQuantity
+	BestSupplierPrices = bestSupplierPrices
+	PricePeriod        = pricePeriod
+	PriceStatistics    = priceStatistics
+	WithOverallChange  = withOverallChange
+	PercentChange      = percentChange
+	UnitFactor         = unitFactor
+	PeriodRetention    = periodRetention
+	ApplyMarkup        = applyMarkup
+	RoundToNearest     = roundToNearest
+	CompareRFQQuotes   = compareRFQQuotes
+	ResolveRFQAward    = resolveRFQAward
+)
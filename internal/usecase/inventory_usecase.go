@@ -0,0 +1,345 @@
+package usecase
+
+import (
+	"boonkosang/internal/domain/models"
+	"boonkosang/internal/repositories"
+	"boonkosang/internal/requests"
+	"boonkosang/internal/responses"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type InventoryUsecase interface {
+	RecordMovement(ctx context.Context, projectID uuid.UUID, req requests.CreateStockMovementRequest) (*responses.StockMovementResponse, error)
+	TransferStock(ctx context.Context, projectID uuid.UUID, req requests.TransferStockRequest) ([]responses.StockMovementResponse, error)
+	ListMovements(ctx context.Context, projectID uuid.UUID, materialID string) ([]responses.StockMovementResponse, error)
+	GetBalances(ctx context.Context, projectID uuid.UUID) ([]responses.StockBalanceResponse, error)
+	GetConsumption(ctx context.Context, projectID uuid.UUID) ([]responses.MaterialConsumptionResponse, error)
+}
+
+type inventoryUsecase struct {
+	stockRepo    repositories.StockRepository
+	projectRepo  repositories.ProjectRepository
+	materialRepo repositories.MaterialRepository
+	supplierRepo repositories.SupplierRepository
+	jobRepo      repositories.JobRepository
+}
+
+func NewInventoryUsecase(
+	stockRepo repositories.StockRepository,
+	projectRepo repositories.ProjectRepository,
+	materialRepo repositories.MaterialRepository,
+	supplierRepo repositories.SupplierRepository,
+	jobRepo repositories.JobRepository,
+) InventoryUsecase {
+	return &inventoryUsecase{
+		stockRepo:    stockRepo,
+		projectRepo:  projectRepo,
+		materialRepo: materialRepo,
+		supplierRepo: supplierRepo,
+		jobRepo:      jobRepo,
+	}
+}
+
+// RecordMovement records a receipt, an issue or a return on a project site.
+// Issues must name a job of the project's BOQ and returns the supplier the
+// materials go back to.
+func (u *inventoryUsecase) RecordMovement(ctx context.Context, projectID uuid.UUID, req requests.CreateStockMovementRequest) (*responses.StockMovementResponse, error) {
+	movementType := models.StockMovementType(req.MovementType)
+	switch movementType {
+	case models.StockMovementReceipt, models.StockMovementIssue, models.StockMovementReturn:
+	case models.StockMovementTransferIn, models.StockMovementTransferOut:
+		return nil, errors.New("transfers must be recorded through the transfer endpoint")
+	default:
+		return nil, fmt.Errorf("invalid movement type: %s", req.MovementType)
+	}
+
+	detail, err := u.newMovement(ctx, projectID, req.MaterialID, movementType, req.Quantity, req.MovementDate, req.Notes, req.RecordedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	switch movementType {
+	case models.StockMovementIssue:
+		if req.JobID == nil {
+			return nil, errors.New("job is required when issuing materials")
+		}
+		inBOQ, err := u.stockRepo.IsJobInBOQ(ctx, projectID, *req.JobID)
+		if err != nil {
+			return nil, err
+		}
+		if !inBOQ {
+			return nil, errors.New("job is not part of the project BOQ")
+		}
+		job, err := u.jobRepo.GetByID(ctx, *req.JobID)
+		if err != nil {
+			return nil, err
+		}
+		detail.JobID = uuid.NullUUID{UUID: job.JobID, Valid: true}
+		detail.JobName = sql.NullString{String: job.Name, Valid: true}
+	case models.StockMovementReturn:
+		if req.SupplierID == nil {
+			return nil, errors.New("supplier is required when returning materials")
+		}
+		supplier, err := u.supplierRepo.GetByID(ctx, *req.SupplierID)
+		if err != nil {
+			return nil, err
+		}
+		detail.SupplierID = uuid.NullUUID{UUID: supplier.SupplierID, Valid: true}
+		detail.SupplierName = sql.NullString{String: supplier.Name, Valid: true}
+	case models.StockMovementReceipt:
+		if req.SupplierID != nil {
+			supplier, err := u.supplierRepo.GetByID(ctx, *req.SupplierID)
+			if err != nil {
+				return nil, err
+			}
+			detail.SupplierID = uuid.NullUUID{UUID: supplier.SupplierID, Valid: true}
+			detail.SupplierName = sql.NullString{String: supplier.Name, Valid: true}
+		}
+	}
+
+	movements := []models.StockMovement{detail.StockMovement}
+	if err := u.stockRepo.CreateMovements(ctx, movements); err != nil {
+		return nil, err
+	}
+
+	detail.StockMovement = movements[0]
+	return toStockMovementResponse(detail), nil
+}
+
+// TransferStock moves materials from one project site to another. Both
+// sides of the transfer share a transfer ID.
+func (u *inventoryUsecase) TransferStock(ctx context.Context, projectID uuid.UUID, req requests.TransferStockRequest) ([]responses.StockMovementResponse, error) {
+	if req.ToProjectID == projectID {
+		return nil, errors.New("cannot transfer materials to the same project")
+	}
+
+	target, err := u.projectRepo.GetByID(ctx, req.ToProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := u.newMovement(ctx, projectID, req.MaterialID, models.StockMovementTransferOut, req.Quantity, req.MovementDate, req.Notes, req.RecordedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	transferID := uuid.NullUUID{UUID: uuid.New(), Valid: true}
+	out.TransferID = transferID
+	out.CounterpartProjectID = uuid.NullUUID{UUID: target.ProjectID, Valid: true}
+
+	in := *out
+	in.ProjectID = target.ProjectID
+	in.MovementType = models.StockMovementTransferIn
+	in.CounterpartProjectID = uuid.NullUUID{UUID: projectID, Valid: true}
+
+	movements := []models.StockMovement{out.StockMovement, in.StockMovement}
+	if err := u.stockRepo.CreateMovements(ctx, movements); err != nil {
+		return nil, err
+	}
+
+	source, err := u.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	out.StockMovement = movements[0]
+	out.CounterpartProjectName = sql.NullString{String: target.Name, Valid: true}
+	in.StockMovement = movements[1]
+	in.CounterpartProjectName = sql.NullString{String: source.Name, Valid: true}
+
+	return []responses.StockMovementResponse{
+		*toStockMovementResponse(out),
+		*toStockMovementResponse(&in),
+	}, nil
+}
+
+func (u *inventoryUsecase) ListMovements(ctx context.Context, projectID uuid.UUID, materialID string) ([]responses.StockMovementResponse, error) {
+	if _, err := u.projectRepo.GetByID(ctx, projectID); err != nil {
+		return nil, err
+	}
+
+	movements, err := u.stockRepo.ListMovements(ctx, projectID, materialID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]responses.StockMovementResponse, len(movements))
+	for i := range movements {
+		result[i] = *toStockMovementResponse(&movements[i])
+	}
+
+	return result, nil
+}
+
+func (u *inventoryUsecase) GetBalances(ctx context.Context, projectID uuid.UUID) ([]responses.StockBalanceResponse, error) {
+	if _, err := u.projectRepo.GetByID(ctx, projectID); err != nil {
+		return nil, err
+	}
+
+	balances, err := u.stockRepo.GetBalances(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]responses.StockBalanceResponse, len(balances))
+	for i, b := range balances {
+		result[i] = responses.StockBalanceResponse{
+			MaterialID:     b.MaterialID,
+			MaterialName:   b.Name,
+			Unit:           b.Unit,
+			Received:       b.Received,
+			Issued:         b.Issued,
+			TransferredIn:  b.TransferredIn,
+			TransferredOut: b.TransferredOut,
+			Returned:       b.Returned,
+			OnHand:         b.OnHand(),
+		}
+	}
+
+	return result, nil
+}
+
+// GetConsumption compares the quantity of each material issued to BOQ jobs
+// with the BOQ estimate, per material and per job.
+func (u *inventoryUsecase) GetConsumption(ctx context.Context, projectID uuid.UUID) ([]responses.MaterialConsumptionResponse, error) {
+	if _, err := u.projectRepo.GetByID(ctx, projectID); err != nil {
+		return nil, err
+	}
+
+	consumption, err := u.stockRepo.GetConsumption(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	balances, err := u.stockRepo.GetBalances(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	onHand := make(map[string]float64, len(balances))
+	for i := range balances {
+		onHand[balances[i].MaterialID] = balances[i].OnHand()
+	}
+
+	result := []responses.MaterialConsumptionResponse{}
+	index := map[string]int{}
+	for _, c := range consumption {
+		i, ok := index[c.MaterialID]
+		if !ok {
+			i = len(result)
+			index[c.MaterialID] = i
+			result = append(result, responses.MaterialConsumptionResponse{
+				MaterialID:   c.MaterialID,
+				MaterialName: c.MaterialName,
+				Unit:         c.Unit,
+				OnHand:       onHand[c.MaterialID],
+				Jobs:         []responses.JobConsumptionResponse{},
+			})
+		}
+
+		material := &result[i]
+		material.EstimatedQuantity += c.EstimatedQuantity
+		material.IssuedQuantity += c.IssuedQuantity
+		material.Jobs = append(material.Jobs, responses.JobConsumptionResponse{
+			JobID:             c.JobID,
+			JobName:           c.JobName,
+			EstimatedQuantity: c.EstimatedQuantity,
+			IssuedQuantity:    c.IssuedQuantity,
+			Variance:          c.IssuedQuantity - c.EstimatedQuantity,
+			VariancePercent:   variancePercent(c.EstimatedQuantity, c.IssuedQuantity),
+		})
+	}
+
+	for i := range result {
+		material := &result[i]
+		material.Variance = material.IssuedQuantity - material.EstimatedQuantity
+		material.VariancePercent = variancePercent(material.EstimatedQuantity, material.IssuedQuantity)
+	}
+
+	return result, nil
+}
+
+func (u *inventoryUsecase) newMovement(ctx context.Context, projectID uuid.UUID, materialID string, movementType models.StockMovementType, quantity float64, date, notes, recordedBy string) (*models.StockMovementDetail, error) {
+	if quantity <= 0 {
+		return nil, errors.New("quantity must be greater than zero")
+	}
+
+	recordedBy = strings.TrimSpace(recordedBy)
+	if recordedBy == "" {
+		return nil, errors.New("recorder is required")
+	}
+
+	material, err := u.materialRepo.GetByID(ctx, materialID)
+	if err != nil {
+		return nil, err
+	}
+
+	movementDate := time.Now().Truncate(24 * time.Hour)
+	if date != "" {
+		movementDate, err = time.Parse("2006-01-02", date)
+		if err != nil {
+			return nil, fmt.Errorf("invalid movement date format: %w", err)
+		}
+	}
+
+	return &models.StockMovementDetail{
+		StockMovement: models.StockMovement{
+			ProjectID:    projectID,
+			MaterialID:   material.MaterialID,
+			MovementType: movementType,
+			Quantity:     quantity,
+			MovementDate: movementDate,
+			Notes:        sql.NullString{String: notes, Valid: notes != ""},
+			RecordedBy:   recordedBy,
+		},
+		MaterialName: material.Name,
+		Unit:         material.Unit,
+	}, nil
+}
+
+// variancePercent is the over (positive) or under (negative) consumption as
+// a percentage of the estimate.
+func variancePercent(estimated, issued float64) *float64 {
+	if estimated == 0 {
+		return nil
+	}
+	percent := roundMoney((issued - estimated) / estimated * 100)
+	return &percent
+}
+
+func nullUUIDPtr(id uuid.NullUUID) *uuid.UUID {
+	if !id.Valid {
+		return nil
+	}
+	return &id.UUID
+}
+
+func toStockMovementResponse(m *models.StockMovementDetail) *responses.StockMovementResponse {
+	return &responses.StockMovementResponse{
+		MovementID:             m.MovementID,
+		ProjectID:              m.ProjectID,
+		MaterialID:             m.MaterialID,
+		MaterialName:           m.MaterialName,
+		Unit:                   m.Unit,
+		MovementType:           string(m.MovementType),
+		Quantity:               m.Quantity,
+		MovementDate:           m.MovementDate,
+		JobID:                  nullUUIDPtr(m.JobID),
+		JobName:                m.JobName.String,
+		GRID:                   nullUUIDPtr(m.GRID),
+		SupplierID:             nullUUIDPtr(m.SupplierID),
+		SupplierName:           m.SupplierName.String,
+		TransferID:             nullUUIDPtr(m.TransferID),
+		CounterpartProjectID:   nullUUIDPtr(m.CounterpartProjectID),
+		CounterpartProjectName: m.CounterpartProjectName.String,
+		Notes:                  m.Notes.String,
+		RecordedBy:             m.RecordedBy,
+		CreatedAt:              m.CreatedAt,
+	}
+}
@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"boonkosang/internal/domain/models"
 	"boonkosang/internal/repositories"
 	"boonkosang/internal/requests"
 	"boonkosang/internal/responses"
@@ -8,6 +9,8 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"math"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -24,7 +27,12 @@ type InvoiceUseCase interface {
 	UpdateInvoiceStatus(ctx context.Context, invoiceID uuid.UUID, req requests.UpdateInvoiceStatusRequest) error
 	CreateInvoicesForAllPeriods(ctx context.Context, projectID uuid.UUID) error
 	UpdateInvoice(ctx context.Context, invoiceID uuid.UUID, req requests.UpdateInvoiceRequest) error // New method
-
+	RecordWithholdingCertificate(ctx context.Context, invoiceID uuid.UUID, req requests.RecordWithholdingCertificateRequest) (*responses.InvoiceResponse, error)
+	GetRetentionSummary(ctx context.Context, projectID uuid.UUID) (*responses.RetentionSummaryResponse, error)
+	CreateRetentionReleaseInvoice(ctx context.Context, projectID uuid.UUID) (*responses.InvoiceResponse, error)
+	ListOverdueInvoices(ctx context.Context, asOf time.Time) ([]responses.OverdueInvoiceResponse, error)
+	GetAgingReport(ctx context.Context, asOf time.Time) (*responses.AgingReportResponse, error)
+	ExportAgingReportCSV(ctx context.Context, asOf time.Time) ([]byte, error)
 }
 
 type invoiceUseCase struct {
@@ -59,47 +67,16 @@ func (u *invoiceUseCase) GetProjectInvoices(ctx context.Context, projectID uuid.
 		return nil, fmt.Errorf("failed to get project invoices: %w", err)
 	}
 
-	var responseList []responses.InvoiceResponse
-	for _, invoice := range invoices {
-		response := responses.InvoiceResponse{
-			InvoiceID: invoice.InvoiceID,
-			ProjectID: invoice.ProjectID,
-			PeriodID:  invoice.PeriodID,
-			Status:    invoice.Status.String,
-			CreatedAt: invoice.CreatedAt,
-			Period: responses.PeriodResponse{
-				PeriodID:        invoice.Period.PeriodID,
-				PeriodNumber:    invoice.Period.PeriodNumber,
-				AmountPeriod:    invoice.Period.AmountPeriod,
-				DeliveredWithin: invoice.Period.DeliveredWithin,
-			},
-		}
-
-		if invoice.PaymentTerm.Valid {
-			response.PaymentTerm = invoice.PaymentTerm.String
-		}
-
-		if invoice.InvoiceDate.Valid {
-			response.InvoiceDate = invoice.InvoiceDate.Time
-		}
-
-		if invoice.PaymentDueDate.Valid {
-			response.PaymentDueDate = invoice.PaymentDueDate.Time
-		}
-
-		if invoice.PaidDate.Valid {
-			response.PaidDate = invoice.PaidDate.Time
-		}
-
-		if invoice.Remarks.Valid {
-			response.Remarks = invoice.Remarks.String
-		}
-
-		if invoice.UpdatedAt.Valid {
-			response.UpdatedAt = invoice.UpdatedAt.Time
-		}
+	settings, err := u.invoiceRepo.GetTaxSettings(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tax settings: %w", err)
+	}
 
-		responseList = append(responseList, response)
+	var responseList []responses.InvoiceResponse
+	for i := range invoices {
+		invoice := &invoices[i]
+		invoice.TaxSettings = *settings
+		responseList = append(responseList, *toInvoiceResponse(invoice))
 	}
 
 	return responseList, nil
@@ -115,12 +92,48 @@ func (u *invoiceUseCase) GetInvoiceByID(ctx context.Context, invoiceID uuid.UUID
 		return nil, errors.New("invoice not found")
 	}
 
+	settings, err := u.invoiceRepo.GetTaxSettings(ctx, invoice.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tax settings: %w", err)
+	}
+	invoice.TaxSettings = *settings
+
+	invoice.WithholdingCertificate, err = u.invoiceRepo.GetWithholdingCertificate(ctx, invoiceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get withholding tax certificate: %w", err)
+	}
+
+	history, err := u.invoiceRepo.GetStatusHistory(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := toInvoiceResponse(invoice)
+	response.StatusHistory = make([]responses.InvoiceStatusHistoryResponse, len(history))
+	for i, entry := range history {
+		response.StatusHistory[i] = responses.InvoiceStatusHistoryResponse{
+			FromStatus: string(entry.FromStatus),
+			ToStatus:   string(entry.ToStatus),
+			Reason:     entry.Reason.String,
+			ChangedBy:  entry.ChangedBy.String,
+			ChangedAt:  entry.ChangedAt,
+		}
+	}
+
+	return response, nil
+}
+
+// toInvoiceResponse maps an invoice to its response, breaking the period amount
+// (which already includes VAT) down into sub total, VAT and withholding tax.
+func toInvoiceResponse(invoice *models.Invoice) *responses.InvoiceResponse {
 	response := &responses.InvoiceResponse{
-		InvoiceID: invoice.InvoiceID,
-		ProjectID: invoice.ProjectID,
-		PeriodID:  invoice.PeriodID,
-		Status:    invoice.Status.String,
-		CreatedAt: invoice.CreatedAt,
+		InvoiceID:     invoice.InvoiceID,
+		InvoiceNumber: invoice.InvoiceNumber.String,
+		InvoiceType:   invoice.Type(),
+		ProjectID:     invoice.ProjectID,
+		PeriodID:      invoice.PeriodID,
+		Status:        string(invoice.CurrentStatus()),
+		CreatedAt:     invoice.CreatedAt,
 		Period: responses.PeriodResponse{
 			PeriodID:        invoice.Period.PeriodID,
 			PeriodNumber:    invoice.Period.PeriodNumber,
@@ -157,8 +170,62 @@ func (u *invoiceUseCase) GetInvoiceByID(ctx context.Context, invoiceID uuid.UUID
 		response.UpdatedAt = invoice.UpdatedAt.Time
 	}
 
-	return response, nil
+	taxPercentage := invoice.TaxSettings.TaxPercentage
+	withholdingTaxPercentage := effectiveWithholdingTax(invoice)
+
+	response.TotalAmount = invoice.Period.AmountPeriod
+	if invoice.Amount.Valid {
+		response.TotalAmount = invoice.Amount.Float64
+	}
+	if invoice.Type() == models.InvoiceTypeRetentionRelease {
+		// Withholding tax was already deducted from the full period amounts
+		withholdingTaxPercentage = 0
+	}
+
+	response.SubTotal = response.TotalAmount / (1 + taxPercentage/100)
+	response.TaxPercentage = taxPercentage
+	response.TaxAmount = response.TotalAmount - response.SubTotal
+	response.WithholdingTaxPercentage = withholdingTaxPercentage
+
+	// Credit and debit notes change what the client owes, and the withholding
+	// tax follows the adjusted value before VAT.
+	response.AdjustmentAmount = invoice.AdjustmentAmount
+	response.AdjustedTotalAmount = roundMoney(response.TotalAmount + invoice.AdjustmentAmount)
+	response.WithholdingTaxAmount = calculateWithholdingTax(response.AdjustedTotalAmount/(1+taxPercentage/100), withholdingTaxPercentage)
+	response.NetReceivable = response.AdjustedTotalAmount - response.WithholdingTaxAmount - response.Retention
+	response.PaidAmount = invoice.PaidAmount
+	response.OutstandingBalance = roundMoney(response.NetReceivable - invoice.PaidAmount)
+	response.DaysOverdue = invoice.DaysOverdue(time.Now())
+	response.IsOverdue = response.DaysOverdue > 0
+
+	if cert := invoice.WithholdingCertificate; cert != nil {
+		response.WithholdingCertificate = &responses.WithholdingCertificateResponse{
+			CertificateID:     cert.CertificateID,
+			CertificateNumber: cert.CertificateNumber,
+			CertificateDate:   cert.CertificateDate,
+			ReceivedDate:      cert.ReceivedDate,
+			Amount:            cert.Amount,
+			Remarks:           cert.Remarks.String,
+		}
+	}
+
+	return response
 }
+
+// effectiveWithholdingTax resolves the withholding tax rate for an invoice:
+// the invoice override first, then the quotation, then the client default.
+func effectiveWithholdingTax(invoice *models.Invoice) float64 {
+	if invoice.WithholdingTaxPercentage.Valid {
+		return invoice.WithholdingTaxPercentage.Float64
+	}
+	if invoice.TaxSettings.QuotationWithholdingTax.Valid {
+		return invoice.TaxSettings.QuotationWithholdingTax.Float64
+	}
+	return invoice.TaxSettings.ClientWithholdingTax
+}
+
+// UpdateInvoiceStatus issues, voids or cancels an invoice. The paid statuses
+// and the paid date follow from the payments recorded against the invoice.
 func (u *invoiceUseCase) UpdateInvoiceStatus(ctx context.Context, invoiceID uuid.UUID, req requests.UpdateInvoiceStatusRequest) error {
 	invoice, err := u.invoiceRepo.GetByID(ctx, invoiceID)
 	if err != nil {
@@ -169,12 +236,28 @@ func (u *invoiceUseCase) UpdateInvoiceStatus(ctx context.Context, invoiceID uuid
 		return errors.New("invoice not found")
 	}
 
-	// Prevent reverting from approved to draft
-	if invoice.Status.Valid && invoice.Status.String == "approved" && req.Status == "draft" {
-		return errors.New("cannot change status from approved to draft")
+	current := invoice.CurrentStatus()
+	next := models.InvoiceStatus(req.Status)
+	if !next.IsValid() {
+		return errors.New("invalid invoice status")
+	}
+	if next.IsPaymentStatus() {
+		return errors.New("invoice payment status is updated by recording payments")
+	}
+	if !current.CanTransitionTo(next) {
+		return fmt.Errorf("cannot change status from %s to %s", current, next)
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	if next.RequiresReason() && reason == "" {
+		return errors.New("reason is required to void or cancel an invoice")
+	}
+
+	if next == models.InvoiceStatusVoid && invoice.AdjustmentAmount != 0 {
+		return errors.New("void the credit and debit notes before voiding the invoice")
 	}
 
-	if req.Status == "approved" {
+	if next == models.InvoiceStatusIssued {
 		var missingFields []string
 		if !invoice.InvoiceDate.Valid {
 			missingFields = append(missingFields, "invoice_date")
@@ -187,16 +270,66 @@ func (u *invoiceUseCase) UpdateInvoiceStatus(ctx context.Context, invoiceID uuid
 		}
 
 		if len(missingFields) > 0 {
-			return fmt.Errorf("cannot approve invoice, required fields are missing: %v", missingFields)
+			return fmt.Errorf("cannot issue invoice, required fields are missing: %v", missingFields)
 		}
 	}
 
-	err = u.invoiceRepo.UpdateStatus(ctx, invoiceID, req.Status)
+	changedBy := strings.TrimSpace(req.ChangedBy)
+	history := &models.InvoiceStatusHistory{
+		InvoiceID:  invoiceID,
+		FromStatus: current,
+		ToStatus:   next,
+		Reason:     sql.NullString{String: reason, Valid: reason != ""},
+		ChangedBy:  sql.NullString{String: changedBy, Valid: changedBy != ""},
+	}
+
+	return u.invoiceRepo.ChangeStatus(ctx, history)
+}
+
+// retentionToWithhold works out the retention to hold back from a period
+// invoice. It is not valid when the contract has no retention money.
+func retentionToWithhold(ctx context.Context, invoiceRepo repositories.InvoiceRepository, contractRepo repositories.ContractRepository, invoice *models.Invoice) (sql.NullFloat64, error) {
+	contract, err := contractRepo.GetByProjectID(ctx, invoice.ProjectID)
 	if err != nil {
-		return fmt.Errorf("failed to update invoice status: %w", err)
+		return sql.NullFloat64{}, fmt.Errorf("failed to get contract: %w", err)
+	}
+	if !contract.RetentionMoney.Valid || contract.RetentionMoney.Float64 <= 0 {
+		return sql.NullFloat64{}, nil
 	}
 
-	return nil
+	invoices, err := invoiceRepo.GetByProjectID(ctx, invoice.ProjectID)
+	if err != nil {
+		return sql.NullFloat64{}, fmt.Errorf("failed to get project invoices: %w", err)
+	}
+
+	retention, ok := periodRetention(contract.RetentionMoney.Float64, invoices, invoice)
+	return sql.NullFloat64{Float64: retention, Valid: ok}, nil
+}
+
+// periodRetention works out the retention to hold back from a period invoice.
+// The contract's retention money is spread over the project's period invoices
+// by amount, and the last invoices are capped so the total never exceeds the
+// agreed retention. It reports false when the project has no period amounts.
+func periodRetention(retentionMoney float64, invoices []models.Invoice, invoice *models.Invoice) (float64, bool) {
+	var periodTotal, withheld float64
+	for _, other := range invoices {
+		if other.Type() != models.InvoiceTypePeriod {
+			continue
+		}
+		periodTotal += other.Period.AmountPeriod
+		if other.InvoiceID != invoice.InvoiceID && other.Retention.Valid {
+			withheld += other.Retention.Float64
+		}
+	}
+	if periodTotal <= 0 {
+		return 0, false
+	}
+
+	retention := roundMoney(retentionMoney * invoice.Period.AmountPeriod / periodTotal)
+	if remaining := roundMoney(retentionMoney - withheld); retention > remaining {
+		retention = math.Max(remaining, 0)
+	}
+	return retention, true
 }
 func (u *invoiceUseCase) CreateInvoicesForAllPeriods(ctx context.Context, projectID uuid.UUID) error {
 	project, err := u.projectRepo.GetByID(ctx, projectID)
@@ -218,7 +351,7 @@ func (u *invoiceUseCase) CreateInvoicesForAllPeriods(ctx context.Context, projec
 		return errors.New("contract does not belong to the specified project")
 	}
 
-	if !contract.Status.Valid || contract.Status.String != "approved" {
+	if !contract.CurrentStatus().IsBinding() {
 		return errors.New("contract is not approved")
 	}
 
@@ -243,8 +376,8 @@ func (u *invoiceUseCase) UpdateInvoice(ctx context.Context, invoiceID uuid.UUID,
 		return errors.New("invoice not found")
 	}
 
-	if invoice.Status.Valid && invoice.Status.String == "approved" {
-		return errors.New("cannot edit approved invoice")
+	if status := invoice.CurrentStatus(); status != models.InvoiceStatusDraft {
+		return fmt.Errorf("cannot edit %s invoice", status)
 	}
 
 	updates := make(map[string]interface{})
@@ -273,6 +406,20 @@ func (u *invoiceUseCase) UpdateInvoice(ctx context.Context, invoiceID uuid.UUID,
 		updates["remarks"] = *req.Remarks
 	}
 
+	if req.Retention != nil {
+		if *req.Retention < 0 {
+			return errors.New("retention cannot be negative")
+		}
+		updates["retention"] = *req.Retention
+	}
+
+	if req.WithholdingTaxPercentage != nil {
+		if err := validateWithholdingTaxPercentage(*req.WithholdingTaxPercentage); err != nil {
+			return err
+		}
+		updates["withholding_tax_percentage"] = *req.WithholdingTaxPercentage
+	}
+
 	if len(updates) == 0 {
 		return errors.New("no fields to update")
 	}
@@ -284,3 +431,212 @@ func (u *invoiceUseCase) UpdateInvoice(ctx context.Context, invoiceID uuid.UUID,
 
 	return nil
 }
+
+// RecordWithholdingCertificate stores the 50 ทวิ certificate the client issued
+// for a paid invoice. When no amount is given the computed withholding tax is used.
+func (u *invoiceUseCase) RecordWithholdingCertificate(ctx context.Context, invoiceID uuid.UUID, req requests.RecordWithholdingCertificateRequest) (*responses.InvoiceResponse, error) {
+	invoice, err := u.invoiceRepo.GetByID(ctx, invoiceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoice: %w", err)
+	}
+
+	if invoice == nil {
+		return nil, errors.New("invoice not found")
+	}
+
+	if invoice.CurrentStatus() != models.InvoiceStatusPaid {
+		return nil, errors.New("withholding tax certificate can only be recorded for paid invoices")
+	}
+
+	if req.CertificateNumber == "" {
+		return nil, errors.New("certificate number is required")
+	}
+
+	settings, err := u.invoiceRepo.GetTaxSettings(ctx, invoice.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tax settings: %w", err)
+	}
+	invoice.TaxSettings = *settings
+
+	if effectiveWithholdingTax(invoice) <= 0 {
+		return nil, errors.New("invoice is not subject to withholding tax")
+	}
+
+	certificateDate, err := time.Parse("2006-01-02", req.CertificateDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate date format: %w", err)
+	}
+
+	receivedDate := time.Now()
+	if req.ReceivedDate != "" {
+		receivedDate, err = time.Parse("2006-01-02", req.ReceivedDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid received date format: %w", err)
+		}
+	}
+
+	amount := req.Amount
+	if amount == 0 {
+		amount = toInvoiceResponse(invoice).WithholdingTaxAmount
+	}
+
+	certificate := &models.WithholdingTaxCertificate{
+		InvoiceID:         invoiceID,
+		CertificateNumber: req.CertificateNumber,
+		CertificateDate:   certificateDate,
+		ReceivedDate:      receivedDate,
+		Amount:            amount,
+		Remarks:           sql.NullString{String: req.Remarks, Valid: req.Remarks != ""},
+	}
+
+	if err := u.invoiceRepo.SaveWithholdingCertificate(ctx, certificate); err != nil {
+		return nil, err
+	}
+
+	invoice.WithholdingCertificate = certificate
+	return toInvoiceResponse(invoice), nil
+}
+
+func (u *invoiceUseCase) GetRetentionSummary(ctx context.Context, projectID uuid.UUID) (*responses.RetentionSummaryResponse, error) {
+	summary, _, err := u.retentionSummary(ctx, projectID)
+	return summary, err
+}
+
+// CreateRetentionReleaseInvoice bills the retention balance once the guarantee
+// period after completion has passed.
+func (u *invoiceUseCase) CreateRetentionReleaseInvoice(ctx context.Context, projectID uuid.UUID) (*responses.InvoiceResponse, error) {
+	summary, contract, err := u.retentionSummary(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if summary.ReleaseInvoiceID != nil {
+		return nil, errors.New("retention release invoice already exists")
+	}
+	if summary.Balance <= 0 {
+		return nil, errors.New("no retention to release")
+	}
+	if summary.CompletedAt == nil {
+		return nil, errors.New("project is not completed")
+	}
+	if !summary.CanRelease {
+		return nil, fmt.Errorf("retention can be released from %s", summary.ReleaseDate.Format("2006-01-02"))
+	}
+
+	payWithin := 0
+	if contract.PayWithin.Valid {
+		payWithin = int(contract.PayWithin.Int32)
+	}
+	today := time.Now().Truncate(24 * time.Hour)
+
+	invoice := &models.Invoice{
+		ProjectID:      projectID,
+		Amount:         sql.NullFloat64{Float64: summary.Balance, Valid: true},
+		InvoiceDate:    sql.NullTime{Time: today, Valid: true},
+		PaymentDueDate: sql.NullTime{Time: today.AddDate(0, 0, payWithin), Valid: true},
+		PaymentTerm:    sql.NullString{String: fmt.Sprintf("NET%d", payWithin), Valid: true},
+		Remarks:        sql.NullString{String: "Retention release", Valid: true},
+	}
+
+	if err := u.invoiceRepo.CreateRetentionRelease(ctx, invoice); err != nil {
+		return nil, err
+	}
+
+	return u.GetInvoiceByID(ctx, invoice.InvoiceID)
+}
+
+func (u *invoiceUseCase) retentionSummary(ctx context.Context, projectID uuid.UUID) (*responses.RetentionSummaryResponse, *models.Contract, error) {
+	project, err := u.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get project: %w", err)
+	}
+	if project == nil {
+		return nil, nil, errors.New("project not found")
+	}
+
+	contract, err := u.contractRepo.GetByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	invoices, err := u.invoiceRepo.GetByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get project invoices: %w", err)
+	}
+
+	summary := &responses.RetentionSummaryResponse{
+		ContractID: contract.ContractID,
+		Invoices:   []responses.RetentionEntryResponse{},
+	}
+	if contract.RetentionMoney.Valid {
+		summary.RetentionMoney = contract.RetentionMoney.Float64
+	}
+	if contract.GuaranteeWithin.Valid {
+		summary.GuaranteeWithin = int(contract.GuaranteeWithin.Int32)
+	}
+
+	for i := len(invoices) - 1; i >= 0; i-- {
+		invoice := invoices[i]
+		status := invoice.CurrentStatus()
+
+		switch invoice.Type() {
+		case models.InvoiceTypePeriod:
+			if status != models.InvoiceStatusPaid || !invoice.Retention.Valid || invoice.Retention.Float64 == 0 {
+				continue
+			}
+			summary.WithheldAmount += invoice.Retention.Float64
+			summary.Invoices = append(summary.Invoices, responses.RetentionEntryResponse{
+				InvoiceID:     invoice.InvoiceID,
+				InvoiceNumber: invoice.InvoiceNumber.String,
+				PeriodNumber:  invoice.Period.PeriodNumber,
+				Retention:     invoice.Retention.Float64,
+				PaidDate:      invoice.PaidDate.Time,
+			})
+		case models.InvoiceTypeRetentionRelease:
+			if status == models.InvoiceStatusCanceled || status == models.InvoiceStatusVoid {
+				continue
+			}
+			invoiceID := invoice.InvoiceID
+			summary.ReleaseInvoiceID = &invoiceID
+			if status == models.InvoiceStatusPaid {
+				summary.ReleasedAmount += invoice.Amount.Float64
+			}
+		}
+	}
+	summary.WithheldAmount = roundMoney(summary.WithheldAmount)
+	summary.ReleasedAmount = roundMoney(summary.ReleasedAmount)
+	summary.Balance = roundMoney(summary.WithheldAmount - summary.ReleasedAmount)
+
+	completedAt, err := u.completionDate(ctx, project, contract)
+	if err != nil {
+		return nil, nil, err
+	}
+	if completedAt != nil {
+		releaseDate := completedAt.AddDate(0, 0, summary.GuaranteeWithin)
+		summary.CompletedAt = completedAt
+		summary.ReleaseDate = &releaseDate
+		summary.CanRelease = summary.Balance > 0 && summary.ReleaseInvoiceID == nil && !time.Now().Before(releaseDate)
+	}
+
+	return summary, contract, nil
+}
+
+// completionDate is when the contract was marked completed, falling back to
+// the last update of a completed project for contracts without a status history.
+func (u *invoiceUseCase) completionDate(ctx context.Context, project *models.Project, contract *models.Contract) (*time.Time, error) {
+	history, err := u.contractRepo.GetStatusHistory(ctx, contract.ContractID)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].ToStatus == models.ContractStatusCompleted {
+			return &history[i].ChangedAt, nil
+		}
+	}
+
+	if project.Status == models.ProjectStatusCompleted && project.UpdatedAt.Valid {
+		return &project.UpdatedAt.Time, nil
+	}
+
+	return nil, nil
+}
@@ -193,11 +193,12 @@ func (suite *InvoiceUseCaseTestSuite) TestUpdateInvoiceStatus() {
 		name          string
 		setupInvoice  func() *models.Invoice
 		requestStatus string
+		reason        string
 		expectedErr   string
 		shouldUpdate  bool
 	}{
 		{
-			name: "Reject approval when required fields are missing",
+			name: "Reject issuing when required fields are missing",
 			setupInvoice: func() *models.Invoice {
 				return &models.Invoice{
 					InvoiceID: uuid.New(),
@@ -205,12 +206,12 @@ func (suite *InvoiceUseCaseTestSuite) TestUpdateInvoiceStatus() {
 					// Missing required fields
 				}
 			},
-			requestStatus: "approved",
+			requestStatus: "issued",
 			expectedErr:   "required fields are missing",
 			shouldUpdate:  false,
 		},
 		{
-			name: "Allow approval when all required fields are filled",
+			name: "Allow issuing when all required fields are filled",
 			setupInvoice: func() *models.Invoice {
 				return &models.Invoice{
 					InvoiceID:      uuid.New(),
@@ -220,12 +221,24 @@ func (suite *InvoiceUseCaseTestSuite) TestUpdateInvoiceStatus() {
 					PaymentTerm:    sql.NullString{String: "NET30", Valid: true},
 				}
 			},
-			requestStatus: "approved",
+			requestStatus: "issued",
 			expectedErr:   "",
 			shouldUpdate:  true,
 		},
 		{
-			name: "Prevent reverting from approved to draft",
+			name: "Prevent reverting from issued to draft",
+			setupInvoice: func() *models.Invoice {
+				return &models.Invoice{
+					InvoiceID: uuid.New(),
+					Status:    sql.NullString{String: "issued", Valid: true},
+				}
+			},
+			requestStatus: "draft",
+			expectedErr:   "cannot change status from issued to draft",
+			shouldUpdate:  false,
+		},
+		{
+			name: "Treat previously approved invoices as issued",
 			setupInvoice: func() *models.Invoice {
 				return &models.Invoice{
 					InvoiceID: uuid.New(),
@@ -233,7 +246,57 @@ func (suite *InvoiceUseCaseTestSuite) TestUpdateInvoiceStatus() {
 				}
 			},
 			requestStatus: "draft",
-			expectedErr:   "cannot change status from approved to draft",
+			expectedErr:   "cannot change status from issued to draft",
+			shouldUpdate:  false,
+		},
+		{
+			name: "Reject marking paid without payments",
+			setupInvoice: func() *models.Invoice {
+				return &models.Invoice{
+					InvoiceID: uuid.New(),
+					Status:    sql.NullString{String: "issued", Valid: true},
+				}
+			},
+			requestStatus: "paid",
+			expectedErr:   "updated by recording payments",
+			shouldUpdate:  false,
+		},
+		{
+			name: "Require a reason to void",
+			setupInvoice: func() *models.Invoice {
+				return &models.Invoice{
+					InvoiceID: uuid.New(),
+					Status:    sql.NullString{String: "issued", Valid: true},
+				}
+			},
+			requestStatus: "void",
+			expectedErr:   "reason is required",
+			shouldUpdate:  false,
+		},
+		{
+			name: "Void issued invoice with reason",
+			setupInvoice: func() *models.Invoice {
+				return &models.Invoice{
+					InvoiceID: uuid.New(),
+					Status:    sql.NullString{String: "issued", Valid: true},
+				}
+			},
+			requestStatus: "void",
+			reason:        "Wrong amount",
+			expectedErr:   "",
+			shouldUpdate:  true,
+		},
+		{
+			name: "Prevent canceling an issued invoice",
+			setupInvoice: func() *models.Invoice {
+				return &models.Invoice{
+					InvoiceID: uuid.New(),
+					Status:    sql.NullString{String: "issued", Valid: true},
+				}
+			},
+			requestStatus: "canceled",
+			reason:        "Client declined",
+			expectedErr:   "cannot change status from issued to canceled",
 			shouldUpdate:  false,
 		},
 	}
@@ -250,10 +313,15 @@ func (suite *InvoiceUseCaseTestSuite) TestUpdateInvoiceStatus() {
 			suite.mockInvoiceRepo.On("GetByID", suite.ctx, invoiceID).Return(invoice, nil).Once()
 
 			if tc.shouldUpdate {
-				suite.mockInvoiceRepo.On("UpdateStatus", suite.ctx, invoiceID, tc.requestStatus).Return(nil).Once()
+				suite.mockInvoiceRepo.On("ChangeStatus", suite.ctx, mock.MatchedBy(func(history *models.InvoiceStatusHistory) bool {
+					return history.InvoiceID == invoiceID &&
+						history.FromStatus == invoice.CurrentStatus() &&
+						string(history.ToStatus) == tc.requestStatus &&
+						history.Reason.String == tc.reason
+				})).Return(nil).Once()
 			}
 
-			req := requests.UpdateInvoiceStatusRequest{Status: tc.requestStatus}
+			req := requests.UpdateInvoiceStatusRequest{Status: tc.requestStatus, Reason: tc.reason}
 
 			// Execute
 			err := suite.uc.UpdateInvoiceStatus(suite.ctx, invoiceID, req)
@@ -263,7 +331,7 @@ func (suite *InvoiceUseCaseTestSuite) TestUpdateInvoiceStatus() {
 				assert.Error(suite.T(), err)
 				assert.Contains(suite.T(), err.Error(), tc.expectedErr)
 				if !tc.shouldUpdate {
-					suite.mockInvoiceRepo.AssertNotCalled(suite.T(), "UpdateStatus")
+					suite.mockInvoiceRepo.AssertNotCalled(suite.T(), "ChangeStatus")
 				}
 			} else {
 				assert.NoError(suite.T(), err)
@@ -300,17 +368,17 @@ func (suite *InvoiceUseCaseTestSuite) TestUpdateInvoice() {
 			shouldUpdate: true,
 		},
 		{
-			name: "Prevent editing approved invoice",
+			name: "Prevent editing issued invoice",
 			setupInvoice: func() *models.Invoice {
 				return &models.Invoice{
 					InvoiceID: uuid.New(),
-					Status:    sql.NullString{String: "approved", Valid: true},
+					Status:    sql.NullString{String: "issued", Valid: true},
 				}
 			},
 			request: requests.UpdateInvoiceRequest{
 				InvoiceDate: stringPtr("2023-01-01"),
 			},
-			expectedErr:  "cannot edit approved invoice",
+			expectedErr:  "cannot edit issued invoice",
 			shouldUpdate: false,
 		},
 		{
@@ -361,3 +429,244 @@ func (suite *InvoiceUseCaseTestSuite) TestUpdateInvoice() {
 		})
 	}
 }
+
+// Test GetAgingReport method
+func (suite *InvoiceUseCaseTestSuite) TestGetAgingReport() {
+	asOf := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	projectID := uuid.New()
+	clientID := uuid.New()
+
+	receivable := func(status string, dueDate time.Time, amount float64, paid float64) models.ReceivableInvoice {
+		return models.ReceivableInvoice{
+			Invoice: models.Invoice{
+				InvoiceID:      uuid.New(),
+				ProjectID:      projectID,
+				InvoiceType:    sql.NullString{String: models.InvoiceTypePeriod, Valid: true},
+				Status:         sql.NullString{String: status, Valid: true},
+				PaymentDueDate: sql.NullTime{Time: dueDate, Valid: true},
+				PaidAmount:     paid,
+				Period:         models.Period{AmountPeriod: amount},
+			},
+			ProjectName: "House",
+			ClientID:    uuid.NullUUID{UUID: clientID, Valid: true},
+			ClientName:  sql.NullString{String: "Somchai", Valid: true},
+		}
+	}
+
+	suite.mockInvoiceRepo.On("ListReceivable", suite.ctx, asOf).Return([]models.ReceivableInvoice{
+		receivable("issued", asOf.AddDate(0, 0, 5), 1000, 0),
+		receivable("issued", asOf.AddDate(0, 0, -10), 2000, 0),
+		receivable("partially_paid", asOf.AddDate(0, 0, -45), 3000, 1000),
+		receivable("approved", asOf.AddDate(0, 0, -120), 500, 0),
+	}, nil).Once()
+	suite.mockInvoiceRepo.On("GetTaxSettings", suite.ctx, projectID).Return(&models.InvoiceTaxSettings{}, nil).Once()
+
+	report, err := suite.uc.GetAgingReport(suite.ctx, asOf)
+
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), report.Clients, 1)
+	assert.Len(suite.T(), report.Clients[0].Projects, 1)
+	assert.Equal(suite.T(), 1000.0, report.Totals.Current)
+	assert.Equal(suite.T(), 2000.0, report.Totals.Days1To30)
+	assert.Equal(suite.T(), 2000.0, report.Totals.Days31To60)
+	assert.Equal(suite.T(), 0.0, report.Totals.Days61To90)
+	assert.Equal(suite.T(), 500.0, report.Totals.Over90)
+	assert.Equal(suite.T(), 5500.0, report.Totals.Total)
+	assert.Equal(suite.T(), report.Totals, report.Clients[0].AgingBuckets)
+	suite.mockInvoiceRepo.AssertExpectations(suite.T())
+}
+
+func TestPeriodRetention(t *testing.T) {
+	legacy := models.Invoice{InvoiceID: uuid.New(), Period: models.Period{AmountPeriod: 40000}}
+	period := models.Invoice{
+		InvoiceID:   uuid.New(),
+		InvoiceType: sql.NullString{String: models.InvoiceTypePeriod, Valid: true},
+		Period:      models.Period{AmountPeriod: 60000},
+	}
+	release := models.Invoice{
+		InvoiceID:   uuid.New(),
+		InvoiceType: sql.NullString{String: models.InvoiceTypeRetentionRelease, Valid: true},
+		Amount:      sql.NullFloat64{Float64: 5000, Valid: true},
+	}
+	withheld := func(invoice models.Invoice, retention float64) models.Invoice {
+		invoice.Retention = sql.NullFloat64{Float64: retention, Valid: true}
+		return invoice
+	}
+
+	tests := []struct {
+		name     string
+		invoices []models.Invoice
+		invoice  models.Invoice
+		want     float64
+		ok       bool
+	}{
+		{
+			name:     "share of the retention by period amount",
+			invoices: []models.Invoice{release, period, legacy},
+			invoice:  legacy,
+			want:     2000,
+			ok:       true,
+		},
+		{
+			name:     "invoice without a type counts as a period invoice",
+			invoices: []models.Invoice{period, withheld(legacy, 2000)},
+			invoice:  period,
+			want:     3000,
+			ok:       true,
+		},
+		{
+			name:     "capped at the retention left",
+			invoices: []models.Invoice{period, withheld(legacy, 4500)},
+			invoice:  period,
+			want:     500,
+			ok:       true,
+		},
+		{
+			name:     "nothing left to withhold",
+			invoices: []models.Invoice{period, withheld(legacy, 5200)},
+			invoice:  period,
+			want:     0,
+			ok:       true,
+		},
+		{
+			name:     "invoice's own retention is recalculated",
+			invoices: []models.Invoice{withheld(period, 1200), withheld(legacy, 2000)},
+			invoice:  withheld(period, 1200),
+			want:     3000,
+			ok:       true,
+		},
+		{
+			name:     "no period amounts",
+			invoices: []models.Invoice{release},
+			invoice:  release,
+			ok:       false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := usecase.PeriodRetention(5000, tt.invoices, &tt.invoice)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// Test CreateRetentionReleaseInvoice method
+func (suite *InvoiceUseCaseTestSuite) TestCreateRetentionReleaseInvoice() {
+	projectID := uuid.New()
+	contractID := uuid.New()
+	completedAt := time.Now().AddDate(0, 0, -60)
+
+	paidPeriod := func(retention float64, invoiceType sql.NullString) models.Invoice {
+		return models.Invoice{
+			InvoiceID:   uuid.New(),
+			ProjectID:   projectID,
+			InvoiceType: invoiceType,
+			Status:      sql.NullString{String: string(models.InvoiceStatusPaid), Valid: true},
+			Retention:   sql.NullFloat64{Float64: retention, Valid: true},
+		}
+	}
+	periodType := sql.NullString{String: models.InvoiceTypePeriod, Valid: true}
+	releaseInvoice := func(status models.InvoiceStatus, amount float64) models.Invoice {
+		return models.Invoice{
+			InvoiceID:   uuid.New(),
+			ProjectID:   projectID,
+			InvoiceType: sql.NullString{String: models.InvoiceTypeRetentionRelease, Valid: true},
+			Status:      sql.NullString{String: string(status), Valid: true},
+			Amount:      sql.NullFloat64{Float64: amount, Valid: true},
+		}
+	}
+	issued := paidPeriod(1000, periodType)
+	issued.Status = sql.NullString{String: string(models.InvoiceStatusIssued), Valid: true}
+
+	testCases := []struct {
+		name            string
+		guaranteeWithin int32
+		invoices        []models.Invoice
+		expectedAmount  float64
+		expectedErr     string
+	}{
+		{
+			name:            "Success - Releases retention withheld from paid period invoices",
+			guaranteeWithin: 30,
+			invoices: []models.Invoice{
+				releaseInvoice(models.InvoiceStatusCanceled, 3500),
+				issued,
+				paidPeriod(1500, sql.NullString{}),
+				paidPeriod(2000, periodType),
+			},
+			expectedAmount: 3500,
+		},
+		{
+			name:            "Failure - Guarantee period has not passed",
+			guaranteeWithin: 90,
+			invoices:        []models.Invoice{paidPeriod(2000, periodType)},
+			expectedErr:     "retention can be released from " + completedAt.AddDate(0, 0, 90).Format("2006-01-02"),
+		},
+		{
+			name:            "Failure - Release invoice already exists",
+			guaranteeWithin: 30,
+			invoices: []models.Invoice{
+				releaseInvoice(models.InvoiceStatusIssued, 2000),
+				paidPeriod(2000, periodType),
+			},
+			expectedErr: "retention release invoice already exists",
+		},
+		{
+			name:            "Failure - Retention already released",
+			guaranteeWithin: 30,
+			invoices:        []models.Invoice{issued},
+			expectedErr:     "no retention to release",
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			suite.SetupTest()
+
+			suite.mockProjectRepo.On("GetByID", suite.ctx, projectID).
+				Return(&models.Project{ProjectID: projectID, Status: models.ProjectStatusCompleted}, nil)
+			suite.mockContractRepo.On("GetByProjectID", suite.ctx, projectID).Return(&models.Contract{
+				ContractID:      contractID,
+				ProjectID:       projectID,
+				RetentionMoney:  sql.NullFloat64{Float64: 5000, Valid: true},
+				GuaranteeWithin: sql.NullInt32{Int32: tc.guaranteeWithin, Valid: true},
+				PayWithin:       sql.NullInt32{Int32: 15, Valid: true},
+			}, nil)
+			suite.mockContractRepo.On("GetStatusHistory", suite.ctx, contractID).Return([]models.ContractStatusHistory{
+				{ContractID: contractID, ToStatus: models.ContractStatusCompleted, ChangedAt: completedAt},
+			}, nil)
+			suite.mockInvoiceRepo.On("GetByProjectID", suite.ctx, projectID).Return(tc.invoices, nil)
+
+			// The repository assigns the ID and type; the stored copy is read back
+			releaseID := uuid.New()
+			created := &models.Invoice{}
+			suite.mockInvoiceRepo.On("CreateRetentionRelease", suite.ctx, mock.AnythingOfType("*models.Invoice")).
+				Run(func(args mock.Arguments) {
+					invoice := args.Get(1).(*models.Invoice)
+					invoice.InvoiceID = releaseID
+					invoice.InvoiceType = sql.NullString{String: models.InvoiceTypeRetentionRelease, Valid: true}
+					*created = *invoice
+				}).
+				Return(nil)
+			suite.mockInvoiceRepo.On("GetByID", suite.ctx, releaseID).Return(created, nil).Maybe()
+			suite.mockInvoiceRepo.On("GetTaxSettings", suite.ctx, projectID).Return(&models.InvoiceTaxSettings{}, nil).Maybe()
+			suite.mockInvoiceRepo.On("GetWithholdingCertificate", suite.ctx, releaseID).Return(nil, nil).Maybe()
+			suite.mockInvoiceRepo.On("GetStatusHistory", suite.ctx, releaseID).Return([]models.InvoiceStatusHistory{}, nil).Maybe()
+
+			response, err := suite.uc.CreateRetentionReleaseInvoice(suite.ctx, projectID)
+
+			if tc.expectedErr != "" {
+				assert.EqualError(suite.T(), err, tc.expectedErr)
+				suite.mockInvoiceRepo.AssertNotCalled(suite.T(), "CreateRetentionRelease", mock.Anything, mock.Anything)
+				return
+			}
+
+			assert.NoError(suite.T(), err)
+			assert.Equal(suite.T(), tc.expectedAmount, created.Amount.Float64)
+			assert.Equal(suite.T(), "NET15", created.PaymentTerm.String)
+			assert.Equal(suite.T(), models.InvoiceTypeRetentionRelease, response.InvoiceType)
+		})
+	}
+}
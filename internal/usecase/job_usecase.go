@@ -5,6 +5,7 @@ import (
 	"boonkosang/internal/requests"
 	"boonkosang/internal/responses"
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 
@@ -20,20 +21,29 @@ type JobUseCase interface {
 	AddMaterial(ctx context.Context, jobID uuid.UUID, req requests.AddJobMaterialRequest) error
 	DeleteMaterial(ctx context.Context, jobID uuid.UUID, materialID string) error
 	UpdateMaterialQuantity(ctx context.Context, jobID uuid.UUID, req requests.UpdateJobMaterialQuantityRequest) error
+	UpdateMaterialWaste(ctx context.Context, jobID uuid.UUID, materialID string, req requests.UpdateJobMaterialWasteRequest) error
 	GetJobByProjectID(ctx context.Context, projectID uuid.UUID) ([]responses.JobResponse, error)
 }
 
 type jobUseCase struct {
-	jobRepo repositories.JobRepository
+	jobRepo     repositories.JobRepository
+	unitUsecase UnitUsecase
 }
 
-func NewJobUseCase(jobRepo repositories.JobRepository) JobUseCase {
+func NewJobUseCase(jobRepo repositories.JobRepository, unitUsecase UnitUsecase) JobUseCase {
 	return &jobUseCase{
-		jobRepo: jobRepo,
+		jobRepo:     jobRepo,
+		unitUsecase: unitUsecase,
 	}
 }
 
 func (u *jobUseCase) Create(ctx context.Context, req requests.CreateJobRequest) (*responses.JobResponse, error) {
+	unit, err := normalizeUnitCode(req.Unit)
+	if err != nil {
+		return nil, err
+	}
+	req.Unit = unit
+
 	job, err := u.jobRepo.Create(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create job: %w", err)
@@ -50,6 +60,14 @@ func (u *jobUseCase) Update(ctx context.Context, id uuid.UUID, req requests.Upda
 		return errors.New("job not found")
 	}
 
+	// Jobs created before units were validated keep their unit until it
+	// is changed.
+	if unitKey(req.Unit) != unitKey(existing.Unit) {
+		if req.Unit, err = normalizeUnitCode(req.Unit); err != nil {
+			return err
+		}
+	}
+
 	return u.jobRepo.Update(ctx, id, req)
 }
 
@@ -87,6 +105,20 @@ func (u *jobUseCase) AddMaterial(ctx context.Context, jobID uuid.UUID, req reque
 		return errors.New("job not found")
 	}
 
+	for i, item := range req.Materials {
+		if item.WastePercent != nil {
+			if err := validateWastePercent(*item.WastePercent); err != nil {
+				return err
+			}
+		}
+		factor, err := u.unitUsecase.MaterialUnitFactor(ctx, item.MaterialID, item.Unit)
+		if err != nil {
+			return err
+		}
+		req.Materials[i].Quantity = item.Quantity * factor
+		req.Materials[i].Unit = ""
+	}
+
 	return u.jobRepo.AddJobMaterial(ctx, jobID, req)
 }
 
@@ -111,9 +143,36 @@ func (u *jobUseCase) UpdateMaterialQuantity(ctx context.Context, jobID uuid.UUID
 		return errors.New("job not found")
 	}
 
+	factor, err := u.unitUsecase.MaterialUnitFactor(ctx, req.MaterialID, req.Unit)
+	if err != nil {
+		return err
+	}
+	req.Quantity *= factor
+	req.Unit = ""
+
 	return u.jobRepo.UpdateJobMaterialQuantity(ctx, jobID, req)
 }
 
+func (u *jobUseCase) UpdateMaterialWaste(ctx context.Context, jobID uuid.UUID, materialID string, req requests.UpdateJobMaterialWasteRequest) error {
+	existing, err := u.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return errors.New("job not found")
+	}
+
+	wastePercent := sql.NullFloat64{}
+	if req.WastePercent != nil {
+		if err := validateWastePercent(*req.WastePercent); err != nil {
+			return err
+		}
+		wastePercent = sql.NullFloat64{Float64: *req.WastePercent, Valid: true}
+	}
+
+	return u.jobRepo.UpdateJobMaterialWaste(ctx, jobID, materialID, wastePercent)
+}
+
 func (u *jobUseCase) GetJobByProjectID(ctx context.Context, projectID uuid.UUID) ([]responses.JobResponse, error) {
 	return u.jobRepo.GetJobByProjectID(ctx, projectID)
 }
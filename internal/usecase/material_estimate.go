@@ -0,0 +1,213 @@
+package usecase
+
+import (
+	"boonkosang/internal/domain/models"
+	"boonkosang/internal/requests"
+	"boonkosang/internal/responses"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+const (
+	estimateStrategyLastActual    = "last_actual"
+	estimateStrategyAverageActual = "average_actual"
+	estimateStrategySupplierPrice = "supplier_price"
+	estimateStrategyPriceIndex    = "price_index"
+
+	defaultAverageActualCount = 3
+)
+
+// estimatedPrice is the price a strategy found for a material.
+type estimatedPrice struct {
+	price      float64
+	supplierID uuid.NullUUID
+	supplier   string
+	source     string
+}
+
+// AutoFillEstimatedPrices fills the estimated price of every material of a
+// draft BOQ using the requested strategy.
+func (u *materialUsecase) AutoFillEstimatedPrices(ctx context.Context, boqID uuid.UUID, req requests.AutoFillEstimatedPricesRequest) (*responses.EstimatedPriceAutoFillResponse, error) {
+	status, err := u.materialRepo.GetBOQStatus(ctx, boqID)
+	if err != nil {
+		return nil, err
+	}
+	if status != string(models.BOQStatusDraft) {
+		return nil, errors.New("can only update estimated prices for BOQ in draft status")
+	}
+
+	materials, err := u.materialRepo.GetBOQMaterials(ctx, boqID)
+	if err != nil {
+		return nil, err
+	}
+
+	var prices map[string]estimatedPrice
+	switch req.Strategy {
+	case estimateStrategyLastActual:
+		prices, err = u.actualPriceEstimates(ctx, boqID, materials, 1, 0)
+	case estimateStrategyAverageActual:
+		count := req.Count
+		if count < 0 {
+			return nil, errors.New("count must be greater than 0")
+		}
+		if count == 0 {
+			count = defaultAverageActualCount
+		}
+		prices, err = u.actualPriceEstimates(ctx, boqID, materials, count, 0)
+	case estimateStrategySupplierPrice:
+		prices, err = u.supplierPriceEstimates(ctx, materials, req.Date)
+	case estimateStrategyPriceIndex:
+		if req.IndexChangePercent == 0 {
+			return nil, errors.New("index change is required for the price_index strategy")
+		}
+		if req.IndexChangePercent <= -100 {
+			return nil, errors.New("index change must be greater than -100 percent")
+		}
+		if req.Count < 0 {
+			return nil, errors.New("count must be greater than 0")
+		}
+		prices, err = u.actualPriceEstimates(ctx, boqID, materials, max(req.Count, 1), req.IndexChangePercent)
+	default:
+		return nil, fmt.Errorf("invalid strategy: %s", req.Strategy)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	response := &responses.EstimatedPriceAutoFillResponse{
+		BOQID:    boqID,
+		Strategy: req.Strategy,
+		Updated:  []responses.EstimatedPriceAutoFillLine{},
+		Kept:     []responses.EstimatedPriceAutoFillLine{},
+		NoData:   []responses.EstimatedPriceAutoFillLine{},
+	}
+
+	updates := map[string]float64{}
+	for _, material := range materials {
+		line := responses.EstimatedPriceAutoFillLine{
+			MaterialID:       material.MaterialID,
+			MaterialName:     material.Name,
+			Unit:             material.Unit,
+			RequiredQuantity: material.RequiredQuantity,
+		}
+		hasEstimate := material.EstimatedPrice.Valid && material.EstimatedPrice.Float64 > 0
+		if hasEstimate {
+			previous := material.EstimatedPrice.Float64
+			line.PreviousPrice = &previous
+		}
+
+		price, ok := prices[material.MaterialID]
+		if !ok {
+			response.NoData = append(response.NoData, line)
+			continue
+		}
+
+		estimate := price.price
+		line.EstimatedPrice = &estimate
+		line.SupplierID = nullUUIDPtr(price.supplierID)
+		line.SupplierName = price.supplier
+		line.Source = price.source
+
+		if hasEstimate && !req.Overwrite {
+			response.Kept = append(response.Kept, line)
+			continue
+		}
+
+		updates[material.MaterialID] = estimate
+		response.Updated = append(response.Updated, line)
+	}
+
+	if len(updates) > 0 {
+		if err := u.materialRepo.UpdateEstimatedPriceList(ctx, boqID, updates); err != nil {
+			return nil, err
+		}
+	}
+
+	return response, nil
+}
+
+// actualPriceEstimates averages the last count actual prices of each
+// material on other BOQs and adjusts the result by indexChangePercent.
+func (u *materialUsecase) actualPriceEstimates(ctx context.Context, boqID uuid.UUID, materials []models.BOQMaterialRequirement, count int, indexChangePercent float64) (map[string]estimatedPrice, error) {
+	materialIDs := make([]string, len(materials))
+	for i, material := range materials {
+		materialIDs[i] = material.MaterialID
+	}
+
+	points, err := u.materialRepo.GetRecentActualPrices(ctx, boqID, materialIDs, count)
+	if err != nil {
+		return nil, err
+	}
+
+	totals := map[string]float64{}
+	counts := map[string]int{}
+	latest := map[string]models.MaterialPricePoint{}
+	for _, point := range points {
+		if _, ok := latest[point.MaterialID]; !ok {
+			latest[point.MaterialID] = point
+		}
+		totals[point.MaterialID] += point.ActualPrice.Float64
+		counts[point.MaterialID]++
+	}
+
+	prices := make(map[string]estimatedPrice, len(latest))
+	for materialID, point := range latest {
+		price := estimatedPrice{
+			price: roundMoney(totals[materialID] / float64(counts[materialID]) * (1 + indexChangePercent/100)),
+		}
+
+		if counts[materialID] > 1 {
+			price.source = fmt.Sprintf("average of %d actual prices, latest on %s (%s)",
+				counts[materialID], point.ProjectName, point.Date.Format("2006-01-02"))
+		} else {
+			price.source = fmt.Sprintf("actual price on %s (%s)", point.ProjectName, point.Date.Format("2006-01-02"))
+		}
+		if indexChangePercent != 0 {
+			price.source += fmt.Sprintf(" adjusted by %.2f%%", indexChangePercent)
+		}
+		if counts[materialID] == 1 {
+			price.supplierID = point.SupplierID
+			price.supplier = point.SupplierName.String
+		}
+
+		prices[materialID] = price
+	}
+
+	return prices, nil
+}
+
+// supplierPriceEstimates takes the cheapest supplier price valid on date
+// whose minimum quantity the BOQ meets.
+func (u *materialUsecase) supplierPriceEstimates(ctx context.Context, materials []models.BOQMaterialRequirement, date string) (map[string]estimatedPrice, error) {
+	asOf, err := parsePriceDate(date)
+	if err != nil {
+		return nil, err
+	}
+
+	materialIDs := make([]string, len(materials))
+	quantities := make(map[string]float64, len(materials))
+	for i, material := range materials {
+		materialIDs[i] = material.MaterialID
+		quantities[material.MaterialID] = material.RequiredQuantity
+	}
+
+	supplierPrices, err := u.supplierPriceRepo.ListValidPrices(ctx, asOf, materialIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	prices := map[string]estimatedPrice{}
+	for _, price := range bestSupplierPrices(supplierPrices, func(materialID string) float64 { return quantities[materialID] }) {
+		prices[price.MaterialID] = estimatedPrice{
+			price:      price.UnitPrice,
+			supplierID: uuid.NullUUID{UUID: price.SupplierID, Valid: true},
+			supplier:   price.SupplierName,
+			source:     fmt.Sprintf("%s price list valid from %s", price.SupplierName, price.ValidFrom.Format("2006-01-02")),
+		}
+	}
+
+	return prices, nil
+}
@@ -0,0 +1,113 @@
+package usecase_test
+
+import (
+	"boonkosang/internal/domain/models"
+	mocks "boonkosang/internal/repositories/mock"
+	"boonkosang/internal/requests"
+	"boonkosang/internal/usecase"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutoFillEstimatedPricesPriceIndex(t *testing.T) {
+	boqID := uuid.New()
+	supplierID := uuid.New()
+	materials := []models.BOQMaterialRequirement{
+		{BOQID: boqID, MaterialID: "CEM-01", Name: "Portland cement", Unit: "bag", RequiredQuantity: 40},
+	}
+	latest := models.MaterialPricePoint{
+		MaterialID:   "CEM-01",
+		ProjectName:  "Riverside Townhomes",
+		SupplierID:   uuid.NullUUID{UUID: supplierID, Valid: true},
+		SupplierName: sql.NullString{String: "Siam Cement Trading", Valid: true},
+		ActualPrice:  sql.NullFloat64{Float64: 100, Valid: true},
+		Date:         time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC),
+	}
+	earlier := models.MaterialPricePoint{
+		MaterialID:  "CEM-01",
+		ProjectName: "Lakeview Office",
+		ActualPrice: sql.NullFloat64{Float64: 120, Valid: true},
+		Date:        time.Date(2026, 6, 15, 0, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name         string
+		req          requests.AutoFillEstimatedPricesRequest
+		wantLimit    int
+		points       []models.MaterialPricePoint
+		wantPrice    float64
+		wantSource   string
+		wantSupplier string
+		wantErr      string
+	}{
+		{
+			name:         "latest actual price",
+			req:          requests.AutoFillEstimatedPricesRequest{IndexChangePercent: 10},
+			wantLimit:    1,
+			points:       []models.MaterialPricePoint{latest},
+			wantPrice:    110,
+			wantSource:   "actual price on Riverside Townhomes (2026-09-01) adjusted by 10.00%",
+			wantSupplier: "Siam Cement Trading",
+		},
+		{
+			name:       "average of the last count actual prices",
+			req:        requests.AutoFillEstimatedPricesRequest{IndexChangePercent: -5, Count: 2},
+			wantLimit:  2,
+			points:     []models.MaterialPricePoint{latest, earlier},
+			wantPrice:  104.5,
+			wantSource: "average of 2 actual prices, latest on Riverside Townhomes (2026-09-01) adjusted by -5.00%",
+		},
+		{
+			name:    "no index change",
+			req:     requests.AutoFillEstimatedPricesRequest{},
+			wantErr: "index change is required for the price_index strategy",
+		},
+		{
+			name:    "index change wipes out the price",
+			req:     requests.AutoFillEstimatedPricesRequest{IndexChangePercent: -100},
+			wantErr: "index change must be greater than -100 percent",
+		},
+		{
+			name:    "negative count",
+			req:     requests.AutoFillEstimatedPricesRequest{IndexChangePercent: 10, Count: -1},
+			wantErr: "count must be greater than 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			materialRepo := new(mocks.MockMaterialRepository)
+			materialRepo.On("GetBOQStatus", mock.Anything, boqID).Return(string(models.BOQStatusDraft), nil)
+			materialRepo.On("GetBOQMaterials", mock.Anything, boqID).Return(materials, nil)
+			if tt.wantErr == "" {
+				materialRepo.On("GetRecentActualPrices", mock.Anything, boqID, []string{"CEM-01"}, tt.wantLimit).Return(tt.points, nil)
+				materialRepo.On("UpdateEstimatedPriceList", mock.Anything, boqID, map[string]float64{"CEM-01": tt.wantPrice}).Return(nil)
+			}
+			u := usecase.NewMaterialUsecase(materialRepo, nil, nil)
+
+			tt.req.Strategy = "price_index"
+			resp, err := u.AutoFillEstimatedPrices(context.Background(), boqID, tt.req)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				materialRepo.AssertNotCalled(t, "UpdateEstimatedPriceList", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, resp.Updated, 1)
+			line := resp.Updated[0]
+			require.NotNil(t, line.EstimatedPrice)
+			assert.Equal(t, tt.wantPrice, *line.EstimatedPrice)
+			assert.Equal(t, tt.wantSource, line.Source)
+			assert.Equal(t, tt.wantSupplier, line.SupplierName)
+			materialRepo.AssertExpectations(t)
+		})
+	}
+}
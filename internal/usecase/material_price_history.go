@@ -0,0 +1,204 @@
+package usecase
+
+import (
+	"boonkosang/internal/domain/models"
+	"boonkosang/internal/responses"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// GetPriceHistory returns the estimated and actual prices of materials on
+// every project's BOQ, grouped into month, quarter or year periods. It covers
+// one material when materialID is set, otherwise all priced materials.
+func (u *materialUsecase) GetPriceHistory(ctx context.Context, materialID, from, to, period string) ([]responses.MaterialPriceHistoryResponse, error) {
+	if period == "" {
+		period = "month"
+	}
+	if period != "month" && period != "quarter" && period != "year" {
+		return nil, errors.New("period must be month, quarter or year")
+	}
+
+	fromDate, err := parseOptionalDate(from, "from date")
+	if err != nil {
+		return nil, err
+	}
+	toDate, err := parseOptionalDate(to, "to date")
+	if err != nil {
+		return nil, err
+	}
+	if fromDate.Valid && toDate.Valid && toDate.Time.Before(fromDate.Time) {
+		return nil, errors.New("to date cannot be before from date")
+	}
+
+	if materialID != "" {
+		if _, err := u.materialRepo.GetByID(ctx, materialID); err != nil {
+			return nil, err
+		}
+	}
+
+	points, err := u.materialRepo.GetPriceHistory(ctx, materialID, fromDate, toDate)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []responses.MaterialPriceHistoryResponse{}
+	for start := 0; start < len(points); {
+		end := start
+		for end < len(points) && points[end].MaterialID == points[start].MaterialID {
+			end++
+		}
+		result = append(result, materialPriceHistory(points[start:end], period))
+		start = end
+	}
+
+	return result, nil
+}
+
+// materialPriceHistory summarizes the price points of one material, which
+// must be sorted by date.
+func materialPriceHistory(points []models.MaterialPricePoint, period string) responses.MaterialPriceHistoryResponse {
+	history := responses.MaterialPriceHistoryResponse{
+		MaterialID: points[0].MaterialID,
+		Name:       points[0].Name,
+		Unit:       points[0].Unit,
+		Points:     make([]responses.MaterialPricePointResponse, len(points)),
+		Periods:    []responses.MaterialPricePeriod{},
+	}
+
+	var estimated, actual []float64
+	var periodEstimated, periodActual []float64
+	var previousEstimated, previousActual *responses.PriceStatistics
+	closePeriod := func() {
+		current := &history.Periods[len(history.Periods)-1]
+		current.Estimated = priceStatistics(periodEstimated, previousEstimated)
+		current.Actual = priceStatistics(periodActual, previousActual)
+		if current.Estimated != nil {
+			previousEstimated = current.Estimated
+		}
+		if current.Actual != nil {
+			previousActual = current.Actual
+		}
+		periodEstimated, periodActual = nil, nil
+	}
+
+	for i, point := range points {
+		response := responses.MaterialPricePointResponse{
+			Date:         point.Date,
+			ProjectID:    point.ProjectID,
+			ProjectName:  point.ProjectName,
+			BOQID:        point.BOQID,
+			SupplierID:   nullUUIDPtr(point.SupplierID),
+			SupplierName: point.SupplierName.String,
+			Quantity:     point.Quantity,
+		}
+
+		label, startDate := pricePeriod(point.Date, period)
+		if len(history.Periods) == 0 || history.Periods[len(history.Periods)-1].Period != label {
+			if len(history.Periods) > 0 {
+				closePeriod()
+			}
+			history.Periods = append(history.Periods, responses.MaterialPricePeriod{
+				Period:    label,
+				StartDate: startDate,
+			})
+		}
+
+		if point.EstimatedPrice.Valid {
+			price := point.EstimatedPrice.Float64
+			response.EstimatedPrice = &price
+			estimated = append(estimated, price)
+			periodEstimated = append(periodEstimated, price)
+		}
+		if point.ActualPrice.Valid {
+			price := roundMoney(point.ActualPrice.Float64)
+			response.ActualPrice = &price
+			actual = append(actual, price)
+			periodActual = append(periodActual, price)
+		}
+
+		history.Points[i] = response
+	}
+	closePeriod()
+
+	history.Estimated = withOverallChange(priceStatistics(estimated, nil), history.Periods,
+		func(p responses.MaterialPricePeriod) *responses.PriceStatistics { return p.Estimated })
+	history.Actual = withOverallChange(priceStatistics(actual, nil), history.Periods,
+		func(p responses.MaterialPricePeriod) *responses.PriceStatistics { return p.Actual })
+
+	return history
+}
+
+// priceStatistics summarizes prices, comparing their average with previous.
+// It returns nil when there are no prices.
+func priceStatistics(prices []float64, previous *responses.PriceStatistics) *responses.PriceStatistics {
+	if len(prices) == 0 {
+		return nil
+	}
+
+	stats := &responses.PriceStatistics{Count: len(prices), Min: prices[0], Max: prices[0]}
+	var total float64
+	for _, price := range prices {
+		if price < stats.Min {
+			stats.Min = price
+		}
+		if price > stats.Max {
+			stats.Max = price
+		}
+		total += price
+	}
+	stats.Average = roundMoney(total / float64(len(prices)))
+
+	if previous != nil {
+		stats.ChangePercent = percentChange(previous.Average, stats.Average)
+	}
+
+	return stats
+}
+
+// withOverallChange sets the change of stats from the first to the last
+// period that has prices.
+func withOverallChange(stats *responses.PriceStatistics, periods []responses.MaterialPricePeriod, get func(responses.MaterialPricePeriod) *responses.PriceStatistics) *responses.PriceStatistics {
+	if stats == nil {
+		return nil
+	}
+
+	var first, last *responses.PriceStatistics
+	for _, period := range periods {
+		if s := get(period); s != nil {
+			if first == nil {
+				first = s
+			}
+			last = s
+		}
+	}
+	if first != nil && first != last {
+		stats.ChangePercent = percentChange(first.Average, last.Average)
+	}
+
+	return stats
+}
+
+func percentChange(from, to float64) *float64 {
+	if from == 0 {
+		return nil
+	}
+	change := roundMoney((to - from) / from * 100)
+	return &change
+}
+
+// pricePeriod returns the label and first day of the period date falls in.
+func pricePeriod(date time.Time, period string) (string, time.Time) {
+	switch period {
+	case "quarter":
+		quarter := (int(date.Month()) - 1) / 3
+		start := time.Date(date.Year(), time.Month(quarter*3+1), 1, 0, 0, 0, 0, time.UTC)
+		return fmt.Sprintf("%d-Q%d", date.Year(), quarter+1), start
+	case "year":
+		return fmt.Sprintf("%d", date.Year()), time.Date(date.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	default:
+		start := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return start.Format("2006-01"), start
+	}
+}
@@ -0,0 +1,188 @@
+package usecase_test
+
+import (
+	"boonkosang/internal/responses"
+	"boonkosang/internal/usecase"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
+
+func TestPricePeriod(t *testing.T) {
+	tests := []struct {
+		name      string
+		date      time.Time
+		period    string
+		wantLabel string
+		wantStart time.Time
+	}{
+		{
+			name:      "month",
+			date:      time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC),
+			period:    "month",
+			wantLabel: "2026-10",
+			wantStart: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "unknown period falls back to month",
+			date:      time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC),
+			period:    "",
+			wantLabel: "2026-02",
+			wantStart: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "first quarter",
+			date:      time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC),
+			period:    "quarter",
+			wantLabel: "2026-Q1",
+			wantStart: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "first day of a quarter",
+			date:      time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+			period:    "quarter",
+			wantLabel: "2026-Q2",
+			wantStart: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "fourth quarter",
+			date:      time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC),
+			period:    "quarter",
+			wantLabel: "2026-Q4",
+			wantStart: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "year",
+			date:      time.Date(2026, 7, 15, 0, 0, 0, 0, time.UTC),
+			period:    "year",
+			wantLabel: "2026",
+			wantStart: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			label, start := usecase.PricePeriod(tt.date, tt.period)
+			assert.Equal(t, tt.wantLabel, label)
+			assert.Equal(t, tt.wantStart, start)
+		})
+	}
+}
+
+func TestPercentChange(t *testing.T) {
+	tests := []struct {
+		name string
+		from float64
+		to   float64
+		want *float64
+	}{
+		{name: "increase", from: 100, to: 112.5, want: floatPtr(12.5)},
+		{name: "decrease", from: 80, to: 60, want: floatPtr(-25)},
+		{name: "no change", from: 45, to: 45, want: floatPtr(0)},
+		{name: "rounded to two decimals", from: 3, to: 4, want: floatPtr(33.33)},
+		{name: "no base price", from: 0, to: 50, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, usecase.PercentChange(tt.from, tt.to))
+		})
+	}
+}
+
+func TestPriceStatistics(t *testing.T) {
+	tests := []struct {
+		name     string
+		prices   []float64
+		previous *responses.PriceStatistics
+		want     *responses.PriceStatistics
+	}{
+		{
+			name:   "no prices",
+			prices: nil,
+			want:   nil,
+		},
+		{
+			name:   "single price",
+			prices: []float64{135},
+			want:   &responses.PriceStatistics{Count: 1, Min: 135, Max: 135, Average: 135},
+		},
+		{
+			name:   "average is rounded",
+			prices: []float64{10, 10, 11},
+			want:   &responses.PriceStatistics{Count: 3, Min: 10, Max: 11, Average: 10.33},
+		},
+		{
+			name:     "change from the previous period",
+			prices:   []float64{130, 150},
+			previous: &responses.PriceStatistics{Count: 1, Min: 125, Max: 125, Average: 125},
+			want:     &responses.PriceStatistics{Count: 2, Min: 130, Max: 150, Average: 140, ChangePercent: floatPtr(12)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, usecase.PriceStatistics(tt.prices, tt.previous))
+		})
+	}
+}
+
+func TestWithOverallChange(t *testing.T) {
+	stats := func(average float64) *responses.PriceStatistics {
+		return &responses.PriceStatistics{Count: 1, Min: average, Max: average, Average: average}
+	}
+	actual := func(p responses.MaterialPricePeriod) *responses.PriceStatistics { return p.Actual }
+
+	tests := []struct {
+		name    string
+		periods []responses.MaterialPricePeriod
+		want    *float64
+	}{
+		{
+			name: "first to last period",
+			periods: []responses.MaterialPricePeriod{
+				{Period: "2026-Q1", Actual: stats(100)},
+				{Period: "2026-Q2", Actual: stats(130)},
+				{Period: "2026-Q3", Actual: stats(110)},
+			},
+			want: floatPtr(10),
+		},
+		{
+			name: "periods without prices are skipped",
+			periods: []responses.MaterialPricePeriod{
+				{Period: "2026-Q1"},
+				{Period: "2026-Q2", Actual: stats(200)},
+				{Period: "2026-Q3", Actual: stats(150)},
+				{Period: "2026-Q4"},
+			},
+			want: floatPtr(-25),
+		},
+		{
+			name: "single period has no change",
+			periods: []responses.MaterialPricePeriod{
+				{Period: "2026-Q1", Actual: stats(100)},
+				{Period: "2026-Q2"},
+			},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := usecase.WithOverallChange(stats(120), tt.periods, actual)
+			require.NotNil(t, got)
+			assert.Equal(t, 120.0, got.Average)
+			assert.Equal(t, tt.want, got.ChangePercent)
+		})
+	}
+
+	t.Run("no prices", func(t *testing.T) {
+		assert.Nil(t, usecase.WithOverallChange(nil, nil, actual))
+	})
+}
@@ -7,8 +7,11 @@ import (
 	"boonkosang/internal/requests"
 	"boonkosang/internal/responses"
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"math"
+	"strings"
 
 	"github.com/google/uuid"
 )
@@ -18,29 +21,54 @@ type MaterialUsecase interface {
 	Update(ctx context.Context, materialID string, req requests.UpdateMaterialRequest) error
 	Delete(ctx context.Context, materialID string) error
 	GetByID(ctx context.Context, materialID string) (*responses.MaterialResponse, error)
-	List(ctx context.Context) (*responses.MaterialListResponse, error)
+	List(ctx context.Context, req requests.ListMaterialsRequest) (*responses.MaterialListResponse, error)
+	Deprecate(ctx context.Context, materialID string, req requests.DeprecateMaterialRequest) error
+	Restore(ctx context.Context, materialID string) error
+	ListCategories() []string
 
 	GetMaterialPrices(ctx context.Context, projectID uuid.UUID) (*responses.MaterialPriceListResponse, error)
 	UpdateEstimatedPrice(ctx context.Context, boqID uuid.UUID, req requests.UpdateMaterialEstimatedPriceRequest) error
 	UpdateActualPrice(ctx context.Context, boqID uuid.UUID, req requests.UpdateMaterialActualPriceRequest) error
+	// CheckActualPriceUpdate returns why the BOQ actual prices cannot be
+	// updated, or nil if they can.
+	CheckActualPriceUpdate(ctx context.Context, boqID uuid.UUID) error
+	GetPriceHistory(ctx context.Context, materialID, from, to, period string) ([]responses.MaterialPriceHistoryResponse, error)
+	AutoFillEstimatedPrices(ctx context.Context, boqID uuid.UUID, req requests.AutoFillEstimatedPricesRequest) (*responses.EstimatedPriceAutoFillResponse, error)
 }
 
 type materialUsecase struct {
-	materialRepo repositories.MaterialRepository
-	supplierRepo repositories.SupplierRepository
+	materialRepo      repositories.MaterialRepository
+	supplierRepo      repositories.SupplierRepository
+	supplierPriceRepo repositories.SupplierPriceRepository
 }
 
 func NewMaterialUsecase(
 	materialRepo repositories.MaterialRepository,
 	supplierRepo repositories.SupplierRepository,
+	supplierPriceRepo repositories.SupplierPriceRepository,
 ) MaterialUsecase {
 	return &materialUsecase{
-		materialRepo: materialRepo,
-		supplierRepo: supplierRepo,
+		materialRepo:      materialRepo,
+		supplierRepo:      supplierRepo,
+		supplierPriceRepo: supplierPriceRepo,
 	}
 }
 
 func (u *materialUsecase) Create(ctx context.Context, req requests.CreateMaterialRequest) (*responses.MaterialResponse, error) {
+	category, err := normalizeCategory(req.Category)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateWastePercent(req.WastePercent); err != nil {
+		return nil, err
+	}
+	req.Category, req.Tags = category, normalizeTags(req.Tags)
+	req.Brand = strings.TrimSpace(req.Brand)
+	req.Specification = strings.TrimSpace(req.Specification)
+	req.SKU = strings.TrimSpace(req.SKU)
+	if req.Unit, err = normalizeUnitCode(req.Unit); err != nil {
+		return nil, err
+	}
 
 	material, err := u.materialRepo.Create(ctx, req)
 	if err != nil {
@@ -59,9 +87,132 @@ func (u *materialUsecase) Update(ctx context.Context, materialID string, req req
 		return errors.New("material not found")
 	}
 
+	if req.Category != nil {
+		category, err := normalizeCategory(*req.Category)
+		if err != nil {
+			return err
+		}
+		req.Category = &category
+	}
+	if req.Tags != nil {
+		tags := normalizeTags(*req.Tags)
+		req.Tags = &tags
+	}
+	if req.WastePercent != nil {
+		if err := validateWastePercent(*req.WastePercent); err != nil {
+			return err
+		}
+	}
+	req.Brand = trimOptional(req.Brand)
+	req.Specification = trimOptional(req.Specification)
+	req.SKU = trimOptional(req.SKU)
+
+	// Materials created before units were validated keep their unit until
+	// it is changed.
+	if unitKey(req.Unit) != unitKey(existing.Unit) {
+		if req.Unit, err = normalizeUnitCode(req.Unit); err != nil {
+			return err
+		}
+	}
+
 	return u.materialRepo.Update(ctx, materialID, req)
 }
 
+// normalizeCategory defaults an empty category to "other".
+func normalizeCategory(category string) (string, error) {
+	if category == "" {
+		category = string(models.MaterialCategoryOther)
+	}
+	if !models.MaterialCategory(category).IsValid() {
+		return "", fmt.Errorf("invalid material category: %s", category)
+	}
+	return category, nil
+}
+
+// normalizeTags trims, lower-cases and de-duplicates tags.
+func normalizeTags(tags []string) []string {
+	normalized := []string{}
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+
+	return normalized
+}
+
+// trimOptional trims a request field that may have been left out.
+func trimOptional(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	return &trimmed
+}
+
+// validateWastePercent checks a waste allowance, a percentage of the net
+// quantity.
+func validateWastePercent(value float64) error {
+	if value < 0 || value > 100 {
+		return errors.New("waste percent must be between 0 and 100")
+	}
+	return nil
+}
+
+func (u *materialUsecase) Deprecate(ctx context.Context, materialID string, req requests.DeprecateMaterialRequest) error {
+	material, err := u.materialRepo.GetByID(ctx, materialID)
+	if err != nil {
+		return err
+	}
+	if material.IsDeprecated {
+		return errors.New("material is already deprecated")
+	}
+
+	replacedBy := sql.NullString{}
+	if req.ReplacedBy != "" {
+		if req.ReplacedBy == materialID {
+			return errors.New("a material cannot replace itself")
+		}
+		replacement, err := u.materialRepo.GetByID(ctx, req.ReplacedBy)
+		if err != nil {
+			if err.Error() == "material not found" {
+				return errors.New("replacement material not found")
+			}
+			return err
+		}
+		if replacement.IsDeprecated {
+			return errors.New("replacement material is deprecated")
+		}
+		replacedBy = sql.NullString{String: req.ReplacedBy, Valid: true}
+	}
+
+	return u.materialRepo.Deprecate(ctx, materialID, replacedBy)
+}
+
+func (u *materialUsecase) Restore(ctx context.Context, materialID string) error {
+	material, err := u.materialRepo.GetByID(ctx, materialID)
+	if err != nil {
+		return err
+	}
+	if !material.IsDeprecated {
+		return errors.New("material is not deprecated")
+	}
+
+	return u.materialRepo.Restore(ctx, materialID)
+}
+
+func (u *materialUsecase) ListCategories() []string {
+	categories := make([]string, len(models.MaterialCategories))
+	for i, category := range models.MaterialCategories {
+		categories[i] = string(category)
+	}
+	return categories
+}
+
 func (u *materialUsecase) Delete(ctx context.Context, materialID string) error {
 	existing, err := u.materialRepo.GetByID(ctx, materialID)
 	if err != nil {
@@ -86,8 +237,26 @@ func (u *materialUsecase) GetByID(ctx context.Context, materialID string) (*resp
 	return u.createMaterialResponse(material)
 }
 
-func (u *materialUsecase) List(ctx context.Context) (*responses.MaterialListResponse, error) {
-	materials, err := u.materialRepo.List(ctx)
+func (u *materialUsecase) List(ctx context.Context, req requests.ListMaterialsRequest) (*responses.MaterialListResponse, error) {
+	if req.Category != "" && !models.MaterialCategory(req.Category).IsValid() {
+		return nil, fmt.Errorf("invalid material category: %s", req.Category)
+	}
+
+	filter := models.MaterialFilter{
+		Search:            req.Search,
+		Category:          models.MaterialCategory(req.Category),
+		Tag:               strings.ToLower(strings.TrimSpace(req.Tag)),
+		IncludeDeprecated: req.IncludeDeprecated,
+	}
+	if req.Page > 0 {
+		if req.PageSize < 1 {
+			req.PageSize = 10
+		}
+		filter.Limit = req.PageSize
+		filter.Offset = (req.Page - 1) * req.PageSize
+	}
+
+	materials, total, err := u.materialRepo.List(ctx, filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list materials: %w", err)
 	}
@@ -108,17 +277,34 @@ func (u *materialUsecase) List(ctx context.Context) (*responses.MaterialListResp
 
 	return &responses.MaterialListResponse{
 		Materials: materialValues,
+		Total:     total,
 	}, nil
 
 }
 
 func (u *materialUsecase) createMaterialResponse(material *models.Material) (*responses.MaterialResponse, error) {
 
-	return &responses.MaterialResponse{
-		MaterialID: material.MaterialID,
-		Name:       material.Name,
-		Unit:       material.Unit,
-	}, nil
+	response := &responses.MaterialResponse{
+		MaterialID:    material.MaterialID,
+		Name:          material.Name,
+		Unit:          material.Unit,
+		Category:      string(material.Category),
+		Brand:         material.Brand.String,
+		Specification: material.Specification.String,
+		SKU:           material.SKU.String,
+		Tags:          []string(material.Tags),
+		WastePercent:  material.WastePercent,
+		IsDeprecated:  material.IsDeprecated,
+		ReplacedBy:    material.ReplacedBy.String,
+	}
+	if response.Tags == nil {
+		response.Tags = []string{}
+	}
+	if material.DeprecatedAt.Valid {
+		response.DeprecatedAt = &material.DeprecatedAt.Time
+	}
+
+	return response, nil
 }
 
 func (u *materialUsecase) GetMaterialPrices(ctx context.Context, projectID uuid.UUID) (*responses.MaterialPriceListResponse, error) {
@@ -134,12 +320,17 @@ func (u *materialUsecase) GetMaterialPrices(ctx context.Context, projectID uuid.
 			MaterialID:     m.MaterialID,
 			Name:           m.Name,
 			TotalQuantity:  m.TotalQuantity,
+			NetQuantity:    m.NetQuantity,
 			Unit:           m.Unit,
 			EstimatedPrice: m.EstimatedPrice.Float64,
 			AvgActualPrice: m.AvgActualPrice.Float64,
 			ActualPrice:    m.ActualPrice.Float64,
 			SupplierID:     m.SupplierID.String,
 			SupplierName:   m.SupplierName.String,
+
+			OrderedQuantity:   m.OrderedQuantity,
+			ReceivedQuantity:  m.ReceivedQuantity,
+			RemainingQuantity: math.Max(m.TotalQuantity-m.ReceivedQuantity, 0),
 		}
 
 		response = append(response, detail)
@@ -170,6 +361,19 @@ func (u *materialUsecase) UpdateEstimatedPrice(ctx context.Context, boqID uuid.U
 }
 
 func (u *materialUsecase) UpdateActualPrice(ctx context.Context, boqID uuid.UUID, req requests.UpdateMaterialActualPriceRequest) error {
+	if err := u.CheckActualPriceUpdate(ctx, boqID); err != nil {
+		return err
+	}
+
+	// Validate actual price
+	if req.ActualPrice <= 0 {
+		return errors.New("actual price must be greater than 0")
+	}
+
+	return u.materialRepo.UpdateActualPrice(ctx, boqID, req)
+}
+
+func (u *materialUsecase) CheckActualPriceUpdate(ctx context.Context, boqID uuid.UUID) error {
 	// Get BOQ status
 	status, err := u.materialRepo.GetBOQStatus(ctx, boqID)
 	if err != nil {
@@ -200,10 +404,5 @@ func (u *materialUsecase) UpdateActualPrice(ctx context.Context, boqID uuid.UUID
 		return errors.New("can only update actual prices when quotation is approved")
 	}
 
-	// Validate actual price
-	if req.ActualPrice <= 0 {
-		return errors.New("actual price must be greater than 0")
-	}
-
-	return u.materialRepo.UpdateActualPrice(ctx, boqID, req)
+	return nil
 }
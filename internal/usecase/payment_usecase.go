@@ -0,0 +1,259 @@
+package usecase
+
+import (
+	"boonkosang/internal/domain/models"
+	"boonkosang/internal/repositories"
+	"boonkosang/internal/requests"
+	"boonkosang/internal/responses"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PaymentUsecase interface {
+	RecordPayment(ctx context.Context, invoiceID uuid.UUID, req requests.RecordPaymentRequest) (*responses.PaymentResponse, error)
+	ListPayments(ctx context.Context, invoiceID uuid.UUID) ([]responses.PaymentResponse, error)
+	ReversePayment(ctx context.Context, invoiceID uuid.UUID, paymentID uuid.UUID, req requests.ReversePaymentRequest) (*responses.PaymentResponse, error)
+}
+
+type paymentUsecase struct {
+	paymentRepo  repositories.PaymentRepository
+	invoiceRepo  repositories.InvoiceRepository
+	contractRepo repositories.ContractRepository
+}
+
+func NewPaymentUsecase(
+	paymentRepo repositories.PaymentRepository,
+	invoiceRepo repositories.InvoiceRepository,
+	contractRepo repositories.ContractRepository,
+) PaymentUsecase {
+	return &paymentUsecase{
+		paymentRepo:  paymentRepo,
+		invoiceRepo:  invoiceRepo,
+		contractRepo: contractRepo,
+	}
+}
+
+// RecordPayment adds a payment to an issued invoice. The invoice becomes
+// partially_paid until the payments cover the net receivable, then paid.
+func (u *paymentUsecase) RecordPayment(ctx context.Context, invoiceID uuid.UUID, req requests.RecordPaymentRequest) (*responses.PaymentResponse, error) {
+	invoice, err := u.getInvoice(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := invoice.CurrentStatus()
+	if status != models.InvoiceStatusIssued && status != models.InvoiceStatusPartiallyPaid {
+		return nil, errors.New("payments can only be recorded for issued or partially paid invoices")
+	}
+
+	if req.Amount <= 0 {
+		return nil, errors.New("payment amount must be greater than 0")
+	}
+
+	method := models.PaymentMethod(req.Method)
+	if !method.IsValid() {
+		return nil, errors.New("invalid payment method")
+	}
+	if method == models.PaymentMethodCheque && (req.Reference == "" || req.BankName == "") {
+		return nil, errors.New("cheque number and bank are required for cheque payments")
+	}
+
+	paymentDate := time.Now().Truncate(24 * time.Hour)
+	if req.PaymentDate != "" {
+		paymentDate, err = time.Parse("2006-01-02", req.PaymentDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid payment date format: %w", err)
+		}
+	}
+
+	// Retention is fixed when the client starts paying so the amount due
+	// does not move between partial payments. It is stored with the payment,
+	// below, once the invoice is locked.
+	var retention sql.NullFloat64
+	if invoice.Type() == models.InvoiceTypePeriod && !invoice.Retention.Valid {
+		retention, err = retentionToWithhold(ctx, u.invoiceRepo, u.contractRepo, invoice)
+		if err != nil {
+			return nil, err
+		}
+		invoice.Retention = retention
+	}
+
+	amount := roundMoney(req.Amount)
+	if outstanding := toInvoiceResponse(invoice).OutstandingBalance; amount > outstanding {
+		return nil, fmt.Errorf("payment exceeds outstanding balance of %.2f", outstanding)
+	}
+
+	payment := &models.Payment{
+		InvoiceID:   invoiceID,
+		Amount:      amount,
+		PaymentDate: paymentDate,
+		Method:      method,
+		Reference:   sql.NullString{String: req.Reference, Valid: req.Reference != ""},
+		BankName:    sql.NullString{String: req.BankName, Valid: req.BankName != ""},
+		Remarks:     sql.NullString{String: req.Remarks, Valid: req.Remarks != ""},
+	}
+
+	// The check above used a snapshot; the balance is checked again with the
+	// invoice locked, after the payments recorded since are counted.
+	update := func(locked *models.Invoice) (*models.InvoiceStatusHistory, sql.NullTime, error) {
+		lockedInvoice(locked, invoice)
+
+		status := locked.CurrentStatus()
+		if status != models.InvoiceStatusIssued && status != models.InvoiceStatusPartiallyPaid {
+			return nil, sql.NullTime{}, errors.New("payments can only be recorded for issued or partially paid invoices")
+		}
+
+		// A payment recorded since the snapshot may have fixed the retention
+		if !locked.Retention.Valid {
+			locked.Retention = retention
+		}
+
+		// PaidAmount already includes this payment
+		outstanding := toInvoiceResponse(locked).OutstandingBalance
+		if outstanding < 0 {
+			return nil, sql.NullTime{}, fmt.Errorf("payment exceeds outstanding balance of %.2f", roundMoney(outstanding+amount))
+		}
+
+		statusChange := &models.InvoiceStatusHistory{
+			InvoiceID:  invoiceID,
+			FromStatus: status,
+			ToStatus:   models.InvoiceStatusPartiallyPaid,
+		}
+		var paidDate sql.NullTime
+		if outstanding == 0 {
+			statusChange.ToStatus = models.InvoiceStatusPaid
+			paidDate = sql.NullTime{Time: paymentDate, Valid: true}
+		}
+
+		return statusChange, paidDate, nil
+	}
+
+	if err := u.paymentRepo.Create(ctx, payment, update); err != nil {
+		return nil, err
+	}
+
+	return toPaymentResponse(payment), nil
+}
+
+func (u *paymentUsecase) ListPayments(ctx context.Context, invoiceID uuid.UUID) ([]responses.PaymentResponse, error) {
+	if _, err := u.getInvoice(ctx, invoiceID); err != nil {
+		return nil, err
+	}
+
+	payments, err := u.paymentRepo.ListByInvoiceID(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]responses.PaymentResponse, len(payments))
+	for i := range payments {
+		response[i] = *toPaymentResponse(&payments[i])
+	}
+
+	return response, nil
+}
+
+// ReversePayment cancels a payment, for example a bounced cheque, and moves
+// the invoice back to partially_paid or issued.
+func (u *paymentUsecase) ReversePayment(ctx context.Context, invoiceID uuid.UUID, paymentID uuid.UUID, req requests.ReversePaymentRequest) (*responses.PaymentResponse, error) {
+	if _, err := u.getInvoice(ctx, invoiceID); err != nil {
+		return nil, err
+	}
+
+	payment, err := u.paymentRepo.GetByID(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if payment.InvoiceID != invoiceID {
+		return nil, errors.New("payment not found")
+	}
+	if payment.ReversedAt.Valid {
+		return nil, errors.New("payment is already reversed")
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, errors.New("reversal reason is required")
+	}
+
+	update := func(locked *models.Invoice) (*models.InvoiceStatusHistory, sql.NullTime, error) {
+		statusChange := &models.InvoiceStatusHistory{
+			InvoiceID:  invoiceID,
+			FromStatus: locked.CurrentStatus(),
+			ToStatus:   models.InvoiceStatusIssued,
+			Reason:     sql.NullString{String: reason, Valid: true},
+		}
+		// PaidAmount no longer includes the reversed payment
+		if roundMoney(locked.PaidAmount) > 0 {
+			statusChange.ToStatus = models.InvoiceStatusPartiallyPaid
+		}
+
+		return statusChange, sql.NullTime{}, nil
+	}
+
+	payment.ReversalReason = sql.NullString{String: reason, Valid: true}
+	if err := u.paymentRepo.Reverse(ctx, payment, update); err != nil {
+		return nil, err
+	}
+
+	return toPaymentResponse(payment), nil
+}
+
+func (u *paymentUsecase) getInvoice(ctx context.Context, invoiceID uuid.UUID) (*models.Invoice, error) {
+	return getInvoiceWithTaxSettings(ctx, u.invoiceRepo, invoiceID)
+}
+
+// getInvoiceWithTaxSettings loads an invoice together with the tax settings
+// needed to work out its amounts.
+func getInvoiceWithTaxSettings(ctx context.Context, invoiceRepo repositories.InvoiceRepository, invoiceID uuid.UUID) (*models.Invoice, error) {
+	invoice, err := invoiceRepo.GetByID(ctx, invoiceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoice: %w", err)
+	}
+	if invoice == nil {
+		return nil, errors.New("invoice not found")
+	}
+
+	settings, err := invoiceRepo.GetTaxSettings(ctx, invoice.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tax settings: %w", err)
+	}
+	invoice.TaxSettings = *settings
+
+	return invoice, nil
+}
+
+// lockedInvoice copies the related data loaded with invoice to the invoice row
+// the repository read under lock, so its amounts can be worked out.
+func lockedInvoice(locked *models.Invoice, invoice *models.Invoice) {
+	locked.Period = invoice.Period
+	locked.TaxSettings = invoice.TaxSettings
+}
+
+func toPaymentResponse(payment *models.Payment) *responses.PaymentResponse {
+	response := &responses.PaymentResponse{
+		PaymentID:      payment.PaymentID,
+		InvoiceID:      payment.InvoiceID,
+		Amount:         payment.Amount,
+		PaymentDate:    payment.PaymentDate,
+		Method:         string(payment.Method),
+		Reference:      payment.Reference.String,
+		BankName:       payment.BankName.String,
+		Remarks:        payment.Remarks.String,
+		Reversed:       payment.ReversedAt.Valid,
+		ReversalReason: payment.ReversalReason.String,
+		CreatedAt:      payment.CreatedAt,
+	}
+
+	if payment.ReversedAt.Valid {
+		response.ReversedAt = &payment.ReversedAt.Time
+	}
+
+	return response
+}
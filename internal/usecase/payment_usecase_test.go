@@ -0,0 +1,311 @@
+package usecase_test
+
+import (
+	"boonkosang/internal/domain/models"
+	mocks "boonkosang/internal/repositories/mock"
+	"boonkosang/internal/requests"
+	"boonkosang/internal/usecase"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// balanceResult records what the repository would store for an invoice
+// balance update.
+type balanceResult struct {
+	statusChange *models.InvoiceStatusHistory
+	paidDate     sql.NullTime
+	retention    sql.NullFloat64
+	err          error
+}
+
+// runBalanceUpdate calls update the way the repository does, on a copy of the
+// invoice as read under lock with the given paid amount.
+func runBalanceUpdate(locked models.Invoice, paidAmount float64, result *balanceResult) func(mock.Arguments) {
+	return func(args mock.Arguments) {
+		locked.PaidAmount = paidAmount
+		update := args.Get(2).(models.InvoiceBalanceUpdate)
+		statusChange, paidDate, err := update(&locked)
+		*result = balanceResult{statusChange: statusChange, paidDate: paidDate, retention: locked.Retention, err: err}
+	}
+}
+
+func issuedPeriodInvoice(projectID uuid.UUID, status models.InvoiceStatus) models.Invoice {
+	return models.Invoice{
+		InvoiceID: uuid.New(),
+		ProjectID: projectID,
+		Status:    sql.NullString{String: string(status), Valid: true},
+		Period:    models.Period{PeriodNumber: 1, AmountPeriod: 107000},
+	}
+}
+
+func TestRecordPayment(t *testing.T) {
+	ctx := context.Background()
+	projectID := uuid.New()
+	paymentDate := time.Date(2026, time.May, 4, 0, 0, 0, 0, time.UTC)
+	noRetention := &models.Contract{ProjectID: projectID}
+	withRetention := &models.Contract{ProjectID: projectID, RetentionMoney: sql.NullFloat64{Float64: 5000, Valid: true}}
+
+	tests := []struct {
+		name          string
+		status        models.InvoiceStatus
+		paid          float64
+		retention     sql.NullFloat64
+		contract      *models.Contract
+		amount        float64
+		lockedPaid    float64
+		lockedRetains sql.NullFloat64
+		wantErr       string
+		wantStatus    models.InvoiceStatus
+		wantPaidDate  bool
+		wantRetention sql.NullFloat64
+	}{
+		{
+			name:       "first part of the balance",
+			status:     models.InvoiceStatusIssued,
+			contract:   noRetention,
+			amount:     50000,
+			lockedPaid: 50000,
+			wantStatus: models.InvoiceStatusPartiallyPaid,
+		},
+		{
+			name:         "rest of the balance",
+			status:       models.InvoiceStatusPartiallyPaid,
+			paid:         50000,
+			contract:     noRetention,
+			amount:       57000,
+			lockedPaid:   107000,
+			wantStatus:   models.InvoiceStatusPaid,
+			wantPaidDate: true,
+		},
+		{
+			name:     "more than the outstanding balance",
+			status:   models.InvoiceStatusPartiallyPaid,
+			paid:     50000,
+			contract: noRetention,
+			amount:   57000.01,
+			wantErr:  "payment exceeds outstanding balance of 57000.00",
+		},
+		{
+			name:       "overpaid by a payment recorded since",
+			status:     models.InvoiceStatusIssued,
+			contract:   noRetention,
+			amount:     60000,
+			lockedPaid: 120000,
+			wantErr:    "payment exceeds outstanding balance of 47000.00",
+		},
+		{
+			name:     "draft invoice",
+			status:   models.InvoiceStatusDraft,
+			contract: noRetention,
+			amount:   1000,
+			wantErr:  "payments can only be recorded for issued or partially paid invoices",
+		},
+		{
+			name:     "paid invoice",
+			status:   models.InvoiceStatusPaid,
+			paid:     107000,
+			contract: noRetention,
+			amount:   1000,
+			wantErr:  "payments can only be recorded for issued or partially paid invoices",
+		},
+		{
+			name:          "first payment fixes the retention",
+			status:        models.InvoiceStatusIssued,
+			contract:      withRetention,
+			amount:        102000,
+			lockedPaid:    102000,
+			wantStatus:    models.InvoiceStatusPaid,
+			wantPaidDate:  true,
+			wantRetention: sql.NullFloat64{Float64: 5000, Valid: true},
+		},
+		{
+			name:          "retention already fixed by a payment recorded since",
+			status:        models.InvoiceStatusIssued,
+			contract:      withRetention,
+			amount:        50000,
+			lockedPaid:    100000,
+			lockedRetains: sql.NullFloat64{Float64: 4000, Valid: true},
+			wantStatus:    models.InvoiceStatusPartiallyPaid,
+			wantRetention: sql.NullFloat64{Float64: 4000, Valid: true},
+		},
+		{
+			name:          "retention stays as it was for later payments",
+			status:        models.InvoiceStatusPartiallyPaid,
+			paid:          50000,
+			retention:     sql.NullFloat64{Float64: 5000, Valid: true},
+			contract:      withRetention,
+			amount:        52000,
+			lockedPaid:    102000,
+			wantStatus:    models.InvoiceStatusPaid,
+			wantPaidDate:  true,
+			wantRetention: sql.NullFloat64{Float64: 5000, Valid: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invoice := issuedPeriodInvoice(projectID, tt.status)
+			invoice.PaidAmount = tt.paid
+			invoice.Retention = tt.retention
+			locked := invoice
+			locked.Retention = tt.lockedRetains
+			if tt.retention.Valid {
+				locked.Retention = tt.retention
+			}
+			snapshot := invoice
+
+			paymentRepo := new(mocks.MockPaymentRepository)
+			invoiceRepo := new(mocks.MockInvoiceRepository)
+			contractRepo := new(mocks.MockContractRepository)
+			invoiceRepo.On("GetByID", ctx, invoice.InvoiceID).Return(&snapshot, nil)
+			invoiceRepo.On("GetTaxSettings", ctx, projectID).Return(&models.InvoiceTaxSettings{TaxPercentage: 7}, nil)
+			invoiceRepo.On("GetByProjectID", ctx, projectID).Return([]models.Invoice{invoice}, nil)
+			contractRepo.On("GetByProjectID", ctx, projectID).Return(tt.contract, nil)
+
+			var result balanceResult
+			paymentRepo.On("Create", ctx, mock.AnythingOfType("*models.Payment"), mock.Anything).
+				Run(runBalanceUpdate(locked, tt.lockedPaid, &result)).
+				Return(nil).Maybe()
+
+			uc := usecase.NewPaymentUsecase(paymentRepo, invoiceRepo, contractRepo)
+			response, err := uc.RecordPayment(ctx, invoice.InvoiceID, requests.RecordPaymentRequest{
+				Amount:      tt.amount,
+				PaymentDate: paymentDate.Format("2006-01-02"),
+				Method:      string(models.PaymentMethodTransfer),
+			})
+
+			if tt.wantErr != "" && tt.lockedPaid == 0 {
+				require.EqualError(t, err, tt.wantErr)
+				paymentRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.amount, response.Amount)
+
+			// The repository rolls back when update rejects the payment under lock
+			if tt.wantErr != "" {
+				assert.EqualError(t, result.err, tt.wantErr)
+				return
+			}
+			require.NoError(t, result.err)
+			require.NotNil(t, result.statusChange)
+			assert.Equal(t, tt.status, result.statusChange.FromStatus)
+			assert.Equal(t, tt.wantStatus, result.statusChange.ToStatus)
+			assert.Equal(t, tt.wantPaidDate, result.paidDate.Valid)
+			if tt.wantPaidDate {
+				assert.Equal(t, paymentDate, result.paidDate.Time)
+			}
+			assert.Equal(t, tt.wantRetention, result.retention)
+			invoiceRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestReversePayment(t *testing.T) {
+	ctx := context.Background()
+	projectID := uuid.New()
+	reversedAt := sql.NullTime{Time: time.Now(), Valid: true}
+
+	tests := []struct {
+		name       string
+		status     models.InvoiceStatus
+		payment    models.Payment
+		otherInv   bool
+		reason     string
+		lockedPaid float64
+		wantErr    string
+		wantStatus models.InvoiceStatus
+	}{
+		{
+			name:       "only payment of a paid invoice",
+			status:     models.InvoiceStatusPaid,
+			payment:    models.Payment{Amount: 107000},
+			reason:     "Cheque bounced",
+			wantStatus: models.InvoiceStatusIssued,
+		},
+		{
+			name:       "one of two payments of a paid invoice",
+			status:     models.InvoiceStatusPaid,
+			payment:    models.Payment{Amount: 57000},
+			reason:     "Cheque bounced",
+			lockedPaid: 50000,
+			wantStatus: models.InvoiceStatusPartiallyPaid,
+		},
+		{
+			name:       "only payment of a partially paid invoice",
+			status:     models.InvoiceStatusPartiallyPaid,
+			payment:    models.Payment{Amount: 50000},
+			reason:     "Recorded twice",
+			wantStatus: models.InvoiceStatusIssued,
+		},
+		{
+			name:    "payment already reversed",
+			status:  models.InvoiceStatusIssued,
+			payment: models.Payment{Amount: 50000, ReversedAt: reversedAt},
+			reason:  "Cheque bounced",
+			wantErr: "payment is already reversed",
+		},
+		{
+			name:     "payment of another invoice",
+			status:   models.InvoiceStatusPaid,
+			payment:  models.Payment{Amount: 107000},
+			otherInv: true,
+			reason:   "Cheque bounced",
+			wantErr:  "payment not found",
+		},
+		{
+			name:    "no reason",
+			status:  models.InvoiceStatusPaid,
+			payment: models.Payment{Amount: 107000},
+			reason:  "  ",
+			wantErr: "reversal reason is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invoice := issuedPeriodInvoice(projectID, tt.status)
+			payment := tt.payment
+			payment.PaymentID = uuid.New()
+			payment.InvoiceID = invoice.InvoiceID
+			if tt.otherInv {
+				payment.InvoiceID = uuid.New()
+			}
+
+			paymentRepo := new(mocks.MockPaymentRepository)
+			invoiceRepo := new(mocks.MockInvoiceRepository)
+			invoiceRepo.On("GetByID", ctx, invoice.InvoiceID).Return(&invoice, nil)
+			invoiceRepo.On("GetTaxSettings", ctx, projectID).Return(&models.InvoiceTaxSettings{TaxPercentage: 7}, nil)
+			paymentRepo.On("GetByID", ctx, payment.PaymentID).Return(&payment, nil)
+
+			var result balanceResult
+			paymentRepo.On("Reverse", ctx, &payment, mock.Anything).
+				Run(runBalanceUpdate(invoice, tt.lockedPaid, &result)).
+				Return(nil).Maybe()
+
+			uc := usecase.NewPaymentUsecase(paymentRepo, invoiceRepo, new(mocks.MockContractRepository))
+			response, err := uc.ReversePayment(ctx, invoice.InvoiceID, payment.PaymentID, requests.ReversePaymentRequest{Reason: tt.reason})
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				paymentRepo.AssertNotCalled(t, "Reverse", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.reason, response.ReversalReason)
+			require.NoError(t, result.err)
+			require.NotNil(t, result.statusChange)
+			assert.Equal(t, tt.status, result.statusChange.FromStatus)
+			assert.Equal(t, tt.wantStatus, result.statusChange.ToStatus)
+			assert.Equal(t, tt.reason, result.statusChange.Reason.String)
+			assert.False(t, result.paidDate.Valid)
+		})
+	}
+}
@@ -58,6 +58,7 @@ func (u *projectUsecase) Create(ctx context.Context, req requests.CreateProjectR
 		Address:     project.Address,
 		Status:      project.Status,
 		ClientID:    project.ClientID,
+		CompanyID:   project.CompanyID,
 		Client: &responses.ClientResponse{
 			ID:      client.ClientID,
 			Name:    client.Name,
@@ -65,6 +66,7 @@ func (u *projectUsecase) Create(ctx context.Context, req requests.CreateProjectR
 			Tel:     client.Tel,
 			Address: client.Address,
 			TaxID:   client.TaxID,
+			Branch:  client.Branch,
 		},
 		CreatedAt: project.CreatedAt,
 	}, nil
@@ -97,6 +99,7 @@ func (u *projectUsecase) GetByID(ctx context.Context, id uuid.UUID) (*responses.
 		Address:     project.Address,
 		Status:      project.Status,
 		ClientID:    project.ClientID,
+		CompanyID:   project.CompanyID,
 		Client: &responses.ClientResponse{
 			ID:      client.ClientID,
 			Name:    client.Name,
@@ -104,6 +107,7 @@ func (u *projectUsecase) GetByID(ctx context.Context, id uuid.UUID) (*responses.
 			Tel:     client.Tel,
 			Address: client.Address,
 			TaxID:   client.TaxID,
+			Branch:  client.Branch,
 		},
 		CreatedAt: project.CreatedAt,
 		UpdatedAt: project.UpdatedAt.Time,
@@ -133,6 +137,7 @@ func (u *projectUsecase) List(
 			Address:     project.Address,
 			Status:      project.Status,
 			ClientID:    project.ClientID,
+			CompanyID:   project.CompanyID,
 			Client: &responses.ClientResponse{
 				ID:      client.ClientID,
 				Name:    client.Name,
@@ -140,6 +145,7 @@ func (u *projectUsecase) List(
 				Tel:     client.Tel,
 				Address: client.Address,
 				TaxID:   client.TaxID,
+				Branch:  client.Branch,
 			},
 			CreatedAt: project.CreatedAt,
 			UpdatedAt: project.UpdatedAt.Time,
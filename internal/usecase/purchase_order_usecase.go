@@ -0,0 +1,576 @@
+package usecase
+
+import (
+	"boonkosang/internal/domain/models"
+	"boonkosang/internal/repositories"
+	"boonkosang/internal/requests"
+	"boonkosang/internal/responses"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PurchaseOrderUsecase interface {
+	CreateFromBOQ(ctx context.Context, projectID uuid.UUID, req requests.CreatePurchaseOrdersRequest) ([]responses.PurchaseOrderResponse, error)
+	// PrepareFromBOQ validates req and builds the purchase orders
+	// CreateFromBOQ would create without storing them, for callers that
+	// store them together with other changes.
+	PrepareFromBOQ(ctx context.Context, projectID uuid.UUID, req requests.CreatePurchaseOrdersRequest) ([]*models.PurchaseOrder, error)
+	GetPurchaseOrder(ctx context.Context, poID uuid.UUID) (*responses.PurchaseOrderResponse, error)
+	ListPurchaseOrders(ctx context.Context, projectID uuid.UUID) ([]responses.PurchaseOrderResponse, error)
+	UpdatePurchaseOrder(ctx context.Context, poID uuid.UUID, req requests.UpdatePurchaseOrderRequest) (*responses.PurchaseOrderResponse, error)
+	UpdateStatus(ctx context.Context, poID uuid.UUID, req requests.UpdatePurchaseOrderStatusRequest) (*responses.PurchaseOrderResponse, error)
+	ReceiveGoods(ctx context.Context, poID uuid.UUID, req requests.ReceivePurchaseOrderRequest) (*responses.GoodsReceiptResponse, error)
+	ListGoodsReceipts(ctx context.Context, poID uuid.UUID) ([]responses.GoodsReceiptResponse, error)
+	GetMaterialStatus(ctx context.Context, projectID uuid.UUID) ([]responses.PurchaseOrderMaterialResponse, error)
+}
+
+type purchaseOrderUsecase struct {
+	poRepo       repositories.PurchaseOrderRepository
+	materialRepo repositories.MaterialRepository
+	supplierRepo repositories.SupplierRepository
+	unitUsecase  UnitUsecase
+}
+
+func NewPurchaseOrderUsecase(
+	poRepo repositories.PurchaseOrderRepository,
+	materialRepo repositories.MaterialRepository,
+	supplierRepo repositories.SupplierRepository,
+	unitUsecase UnitUsecase,
+) PurchaseOrderUsecase {
+	return &purchaseOrderUsecase{
+		poRepo:       poRepo,
+		materialRepo: materialRepo,
+		supplierRepo: supplierRepo,
+		unitUsecase:  unitUsecase,
+	}
+}
+
+// CreateFromBOQ creates one draft purchase order per supplier for materials
+// of the project's approved BOQ.
+func (u *purchaseOrderUsecase) CreateFromBOQ(ctx context.Context, projectID uuid.UUID, req requests.CreatePurchaseOrdersRequest) ([]responses.PurchaseOrderResponse, error) {
+	orders, err := u.PrepareFromBOQ(ctx, projectID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.poRepo.Create(ctx, orders); err != nil {
+		return nil, err
+	}
+
+	result := make([]responses.PurchaseOrderResponse, len(orders))
+	for i, order := range orders {
+		result[i] = *toPurchaseOrderResponse(order)
+	}
+
+	return result, nil
+}
+
+func (u *purchaseOrderUsecase) PrepareFromBOQ(ctx context.Context, projectID uuid.UUID, req requests.CreatePurchaseOrdersRequest) ([]*models.PurchaseOrder, error) {
+	if len(req.Lines) == 0 {
+		return nil, errors.New("at least one material is required")
+	}
+
+	boqID, materials, err := u.boqMaterials(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	orderDate := time.Now().Truncate(24 * time.Hour)
+	if req.OrderDate != "" {
+		orderDate, err = time.Parse("2006-01-02", req.OrderDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid order date format: %w", err)
+		}
+	}
+
+	deliveryDate, err := parseOptionalDate(req.DeliveryDate, "delivery date")
+	if err != nil {
+		return nil, err
+	}
+
+	orders := map[uuid.UUID]*models.PurchaseOrder{}
+	var supplierOrder []uuid.UUID
+	for _, lineReq := range req.Lines {
+		order, ok := orders[lineReq.SupplierID]
+		if !ok {
+			supplier, err := u.supplierRepo.GetByID(ctx, lineReq.SupplierID)
+			if err != nil {
+				return nil, err
+			}
+
+			order = &models.PurchaseOrder{
+				ProjectID:    projectID,
+				BOQID:        boqID,
+				SupplierID:   supplier.SupplierID,
+				SupplierName: supplier.Name,
+				OrderDate:    orderDate,
+				DeliveryDate: deliveryDate,
+				Remarks:      sql.NullString{String: req.Remarks, Valid: req.Remarks != ""},
+			}
+			orders[lineReq.SupplierID] = order
+			supplierOrder = append(supplierOrder, lineReq.SupplierID)
+		}
+
+		if err := u.toMaterialUnit(ctx, &lineReq.PurchaseOrderLineRequest); err != nil {
+			return nil, err
+		}
+		line, err := toPurchaseOrderLine(lineReq.PurchaseOrderLineRequest, materials)
+		if err != nil {
+			return nil, err
+		}
+		for _, existing := range order.Lines {
+			if existing.MaterialID == line.MaterialID {
+				return nil, fmt.Errorf("material %s is listed more than once for the same supplier", line.MaterialName)
+			}
+		}
+		order.Lines = append(order.Lines, line)
+	}
+
+	result := make([]*models.PurchaseOrder, len(supplierOrder))
+	for i, supplierID := range supplierOrder {
+		result[i] = orders[supplierID]
+	}
+
+	return result, nil
+}
+
+func (u *purchaseOrderUsecase) GetPurchaseOrder(ctx context.Context, poID uuid.UUID) (*responses.PurchaseOrderResponse, error) {
+	po, err := u.poRepo.GetByID(ctx, poID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPurchaseOrderResponse(po), nil
+}
+
+func (u *purchaseOrderUsecase) ListPurchaseOrders(ctx context.Context, projectID uuid.UUID) ([]responses.PurchaseOrderResponse, error) {
+	orders, err := u.poRepo.ListByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]responses.PurchaseOrderResponse, len(orders))
+	for i := range orders {
+		result[i] = *toPurchaseOrderResponse(&orders[i])
+	}
+
+	return result, nil
+}
+
+// UpdatePurchaseOrder replaces the lines of a draft purchase order.
+func (u *purchaseOrderUsecase) UpdatePurchaseOrder(ctx context.Context, poID uuid.UUID, req requests.UpdatePurchaseOrderRequest) (*responses.PurchaseOrderResponse, error) {
+	po, err := u.poRepo.GetByID(ctx, poID)
+	if err != nil {
+		return nil, err
+	}
+	if po.Status != models.PurchaseOrderStatusDraft {
+		return nil, fmt.Errorf("cannot edit %s purchase order", po.Status)
+	}
+	if len(req.Lines) == 0 {
+		return nil, errors.New("at least one material is required")
+	}
+
+	_, materials, err := u.boqMaterials(ctx, po.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The order's own quantities are counted as ordered; release them so
+	// default quantities are worked out as if the order were new.
+	for _, line := range po.Lines {
+		if material, ok := materials[line.MaterialID]; ok {
+			material.OrderedQuantity -= line.Quantity
+			materials[line.MaterialID] = material
+		}
+	}
+
+	po.DeliveryDate, err = parseOptionalDate(req.DeliveryDate, "delivery date")
+	if err != nil {
+		return nil, err
+	}
+	po.Remarks = sql.NullString{String: req.Remarks, Valid: req.Remarks != ""}
+
+	lines := make([]models.PurchaseOrderLine, 0, len(req.Lines))
+	for _, lineReq := range req.Lines {
+		if err := u.toMaterialUnit(ctx, &lineReq); err != nil {
+			return nil, err
+		}
+		line, err := toPurchaseOrderLine(lineReq, materials)
+		if err != nil {
+			return nil, err
+		}
+		for _, existing := range lines {
+			if existing.MaterialID == line.MaterialID {
+				return nil, fmt.Errorf("material %s is listed more than once", line.MaterialName)
+			}
+		}
+		lines = append(lines, line)
+	}
+	po.Lines = lines
+
+	if err := u.poRepo.Update(ctx, po); err != nil {
+		return nil, err
+	}
+
+	return u.GetPurchaseOrder(ctx, poID)
+}
+
+// UpdateStatus sends or cancels a purchase order. The received statuses
+// follow from receiving goods.
+func (u *purchaseOrderUsecase) UpdateStatus(ctx context.Context, poID uuid.UUID, req requests.UpdatePurchaseOrderStatusRequest) (*responses.PurchaseOrderResponse, error) {
+	status := models.PurchaseOrderStatus(req.Status)
+	if !status.IsValid() {
+		return nil, errors.New("invalid purchase order status")
+	}
+	if status == models.PurchaseOrderStatusPartiallyReceived || status == models.PurchaseOrderStatusReceived {
+		return nil, errors.New("purchase order received status is updated by receiving goods")
+	}
+
+	po, err := u.poRepo.GetByID(ctx, poID)
+	if err != nil {
+		return nil, err
+	}
+	if !po.Status.CanTransitionTo(status) {
+		return nil, fmt.Errorf("cannot change status from %s to %s", po.Status, status)
+	}
+
+	from := po.Status
+	po.Status = status
+	if err := u.poRepo.ChangeStatus(ctx, po, from); err != nil {
+		return nil, err
+	}
+
+	return u.GetPurchaseOrder(ctx, poID)
+}
+
+// ReceiveGoods records a delivery against a sent purchase order. Accepted
+// quantities count towards the order, are added to the project's site stock
+// and update the BOQ actual prices of the delivered materials; rejected
+// quantities stay outstanding.
+func (u *purchaseOrderUsecase) ReceiveGoods(ctx context.Context, poID uuid.UUID, req requests.ReceivePurchaseOrderRequest) (*responses.GoodsReceiptResponse, error) {
+	if len(req.Lines) == 0 {
+		return nil, errors.New("at least one received line is required")
+	}
+
+	receivedBy := strings.TrimSpace(req.ReceivedBy)
+	if receivedBy == "" {
+		return nil, errors.New("receiver is required")
+	}
+
+	receivedDate := time.Now().Truncate(24 * time.Hour)
+	if req.ReceivedDate != "" {
+		var err error
+		receivedDate, err = time.Parse("2006-01-02", req.ReceivedDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid received date format: %w", err)
+		}
+	}
+
+	po, err := u.poRepo.GetByID(ctx, poID)
+	if err != nil {
+		return nil, err
+	}
+	if !po.Status.CanReceive() {
+		return nil, fmt.Errorf("cannot receive goods for %s purchase order", po.Status)
+	}
+
+	receipt := &models.GoodsReceipt{
+		ReceivedDate: receivedDate,
+		ReceivedBy:   receivedBy,
+		Notes:        sql.NullString{String: req.Notes, Valid: req.Notes != ""},
+	}
+
+	accepted := map[uuid.UUID]float64{}
+	for _, lineReq := range req.Lines {
+		if lineReq.Quantity <= 0 {
+			return nil, errors.New("received quantity must be greater than 0")
+		}
+		if lineReq.RejectedQuantity < 0 || lineReq.RejectedQuantity > lineReq.Quantity {
+			return nil, errors.New("rejected quantity must be between 0 and the received quantity")
+		}
+
+		orderLine := findPurchaseOrderLine(po, lineReq.LineID)
+		if orderLine == nil {
+			return nil, errors.New("purchase order line not found")
+		}
+
+		line := models.GoodsReceiptLine{
+			POLineID:         orderLine.LineID,
+			QuantityReceived: lineReq.Quantity,
+			QuantityRejected: lineReq.RejectedQuantity,
+			Notes:            sql.NullString{String: lineReq.Notes, Valid: lineReq.Notes != ""},
+			MaterialID:       orderLine.MaterialID,
+			MaterialName:     orderLine.MaterialName,
+			Unit:             orderLine.Unit,
+		}
+		accepted[orderLine.LineID] += line.AcceptedQuantity()
+		receipt.Lines = append(receipt.Lines, line)
+	}
+
+	status := models.PurchaseOrderStatusReceived
+	for _, line := range po.Lines {
+		outstanding := line.OutstandingQuantity() - accepted[line.LineID]
+		if outstanding < -0.0001 {
+			return nil, fmt.Errorf("accepted quantity of %s exceeds the outstanding quantity of %g", line.MaterialName, line.OutstandingQuantity())
+		}
+		if outstanding > 0.0001 {
+			status = models.PurchaseOrderStatusPartiallyReceived
+		}
+	}
+	if err := u.poRepo.Receive(ctx, po, receipt, status); err != nil {
+		return nil, err
+	}
+
+	return toGoodsReceiptResponse(receipt, po.PONumber), nil
+}
+
+func (u *purchaseOrderUsecase) ListGoodsReceipts(ctx context.Context, poID uuid.UUID) ([]responses.GoodsReceiptResponse, error) {
+	po, err := u.poRepo.GetByID(ctx, poID)
+	if err != nil {
+		return nil, err
+	}
+
+	receipts, err := u.poRepo.ListGoodsReceipts(ctx, poID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]responses.GoodsReceiptResponse, len(receipts))
+	for i := range receipts {
+		result[i] = *toGoodsReceiptResponse(&receipts[i], po.PONumber)
+	}
+
+	return result, nil
+}
+
+// GetMaterialStatus returns, per BOQ material, the quantities required,
+// ordered and received, and what is still to be ordered or delivered.
+func (u *purchaseOrderUsecase) GetMaterialStatus(ctx context.Context, projectID uuid.UUID) ([]responses.PurchaseOrderMaterialResponse, error) {
+	requirements, err := u.poRepo.ListBOQMaterials(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]responses.PurchaseOrderMaterialResponse, len(requirements))
+	for i, requirement := range requirements {
+		result[i] = responses.PurchaseOrderMaterialResponse{
+			MaterialID:          requirement.MaterialID,
+			Name:                requirement.Name,
+			Unit:                requirement.Unit,
+			RequiredQuantity:    requirement.RequiredQuantity,
+			NetQuantity:         requirement.NetQuantity,
+			OrderedQuantity:     requirement.OrderedQuantity,
+			ReceivedQuantity:    requirement.ReceivedQuantity,
+			RejectedQuantity:    requirement.RejectedQuantity,
+			UnorderedQuantity:   math.Max(requirement.RequiredQuantity-requirement.OrderedQuantity, 0),
+			OutstandingQuantity: math.Max(requirement.OrderedQuantity-requirement.ReceivedQuantity, 0),
+		}
+	}
+
+	return result, nil
+}
+
+// boqMaterials returns the project's BOQ and its materials by material ID.
+// The BOQ must be approved before materials are ordered.
+func (u *purchaseOrderUsecase) boqMaterials(ctx context.Context, projectID uuid.UUID) (uuid.UUID, map[string]models.BOQMaterialRequirement, error) {
+	requirements, err := u.poRepo.ListBOQMaterials(ctx, projectID)
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+	if len(requirements) == 0 {
+		return uuid.Nil, nil, errors.New("project BOQ has no materials")
+	}
+
+	boqID := requirements[0].BOQID
+	status, err := u.materialRepo.GetBOQStatus(ctx, boqID)
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+	if status != string(models.BOQStatusApproved) {
+		return uuid.Nil, nil, errors.New("purchase orders can only be created for an approved BOQ")
+	}
+
+	materials := make(map[string]models.BOQMaterialRequirement, len(requirements))
+	for _, requirement := range requirements {
+		materials[requirement.MaterialID] = requirement
+	}
+
+	return boqID, materials, nil
+}
+
+// toMaterialUnit converts the quantity and unit price of a line ordered in
+// another unit to the material's unit.
+func (u *purchaseOrderUsecase) toMaterialUnit(ctx context.Context, req *requests.PurchaseOrderLineRequest) error {
+	if req.Unit == "" {
+		return nil
+	}
+
+	factor, err := u.unitUsecase.MaterialUnitFactor(ctx, strings.TrimSpace(req.MaterialID), req.Unit)
+	if err != nil {
+		return err
+	}
+	req.Quantity *= factor
+	req.UnitPrice /= factor
+	req.Unit = ""
+
+	return nil
+}
+
+func toPurchaseOrderLine(req requests.PurchaseOrderLineRequest, materials map[string]models.BOQMaterialRequirement) (models.PurchaseOrderLine, error) {
+	material, ok := materials[strings.TrimSpace(req.MaterialID)]
+	if !ok {
+		return models.PurchaseOrderLine{}, fmt.Errorf("material %s is not in the project BOQ", req.MaterialID)
+	}
+
+	quantity := req.Quantity
+	if quantity < 0 {
+		return models.PurchaseOrderLine{}, errors.New("quantity must be greater than 0")
+	}
+	if quantity == 0 {
+		quantity = material.RequiredQuantity - material.OrderedQuantity
+		if quantity <= 0 {
+			return models.PurchaseOrderLine{}, fmt.Errorf("material %s is already fully ordered", material.Name)
+		}
+	}
+
+	unitPrice := req.UnitPrice
+	if unitPrice < 0 {
+		return models.PurchaseOrderLine{}, errors.New("unit price must be greater than 0")
+	}
+	if unitPrice == 0 {
+		if !material.EstimatedPrice.Valid || material.EstimatedPrice.Float64 <= 0 {
+			return models.PurchaseOrderLine{}, fmt.Errorf("unit price is required for material %s", material.Name)
+		}
+		unitPrice = material.EstimatedPrice.Float64
+	}
+
+	deliveryDate, err := parseOptionalDate(req.DeliveryDate, "delivery date")
+	if err != nil {
+		return models.PurchaseOrderLine{}, err
+	}
+
+	return models.PurchaseOrderLine{
+		MaterialID:   material.MaterialID,
+		Quantity:     quantity,
+		UnitPrice:    unitPrice,
+		DeliveryDate: deliveryDate,
+		MaterialName: material.Name,
+		Unit:         material.Unit,
+	}, nil
+}
+
+func findPurchaseOrderLine(po *models.PurchaseOrder, lineID uuid.UUID) *models.PurchaseOrderLine {
+	for i := range po.Lines {
+		if po.Lines[i].LineID == lineID {
+			return &po.Lines[i]
+		}
+	}
+	return nil
+}
+
+// parseOptionalDate parses a YYYY-MM-DD date, leaving it NULL when empty.
+func parseOptionalDate(value string, field string) (sql.NullTime, error) {
+	if value == "" {
+		return sql.NullTime{}, nil
+	}
+
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return sql.NullTime{}, fmt.Errorf("invalid %s format: %w", field, err)
+	}
+
+	return sql.NullTime{Time: date, Valid: true}, nil
+}
+
+func toPurchaseOrderResponse(po *models.PurchaseOrder) *responses.PurchaseOrderResponse {
+	response := &responses.PurchaseOrderResponse{
+		POID:         po.POID,
+		PONumber:     po.PONumber,
+		ProjectID:    po.ProjectID,
+		BOQID:        po.BOQID,
+		SupplierID:   po.SupplierID,
+		SupplierName: po.SupplierName,
+		Status:       string(po.Status),
+		OrderDate:    po.OrderDate,
+		Remarks:      po.Remarks.String,
+		Lines:        make([]responses.PurchaseOrderLineResponse, len(po.Lines)),
+		CreatedAt:    po.CreatedAt,
+	}
+
+	if po.DeliveryDate.Valid {
+		response.DeliveryDate = &po.DeliveryDate.Time
+	}
+	if po.SentAt.Valid {
+		response.SentAt = &po.SentAt.Time
+	}
+	if po.UpdatedAt.Valid {
+		response.UpdatedAt = &po.UpdatedAt.Time
+	}
+
+	for i, line := range po.Lines {
+		amount := roundMoney(line.Quantity * line.UnitPrice)
+		response.TotalAmount += amount
+		response.Lines[i] = responses.PurchaseOrderLineResponse{
+			LineID:              line.LineID,
+			MaterialID:          line.MaterialID,
+			MaterialName:        line.MaterialName,
+			Unit:                line.Unit,
+			Quantity:            line.Quantity,
+			UnitPrice:           line.UnitPrice,
+			Amount:              amount,
+			ReceivedQuantity:    line.ReceivedQuantity,
+			RejectedQuantity:    line.RejectedQuantity,
+			OutstandingQuantity: line.OutstandingQuantity(),
+		}
+		if po.Status != models.PurchaseOrderStatusCancelled {
+			response.OutstandingAmount += line.OutstandingQuantity() * line.UnitPrice
+		}
+
+		// Lines without their own date are due with the order.
+		if line.DeliveryDate.Valid {
+			response.Lines[i].DeliveryDate = &line.DeliveryDate.Time
+		} else {
+			response.Lines[i].DeliveryDate = response.DeliveryDate
+		}
+	}
+	response.TotalAmount = roundMoney(response.TotalAmount)
+	response.OutstandingAmount = roundMoney(response.OutstandingAmount)
+
+	return response
+}
+
+func toGoodsReceiptResponse(receipt *models.GoodsReceipt, poNumber string) *responses.GoodsReceiptResponse {
+	response := &responses.GoodsReceiptResponse{
+		GRID:         receipt.GRID,
+		POID:         receipt.POID,
+		PONumber:     poNumber,
+		ReceivedDate: receipt.ReceivedDate,
+		ReceivedBy:   receipt.ReceivedBy,
+		Notes:        receipt.Notes.String,
+		Lines:        make([]responses.GoodsReceiptLineResponse, len(receipt.Lines)),
+		CreatedAt:    receipt.CreatedAt,
+	}
+
+	for i, line := range receipt.Lines {
+		response.Lines[i] = responses.GoodsReceiptLineResponse{
+			GRLineID:         line.GRLineID,
+			POLineID:         line.POLineID,
+			MaterialID:       line.MaterialID,
+			MaterialName:     line.MaterialName,
+			Unit:             line.Unit,
+			QuantityReceived: line.QuantityReceived,
+			QuantityRejected: line.QuantityRejected,
+			QuantityAccepted: line.AcceptedQuantity(),
+			Notes:            line.Notes.String,
+		}
+	}
+
+	return response
+}
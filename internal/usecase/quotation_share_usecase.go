@@ -0,0 +1,241 @@
+package usecase
+
+import (
+	"boonkosang/internal/domain/models"
+	"boonkosang/internal/repositories"
+	"boonkosang/internal/requests"
+	"boonkosang/internal/responses"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+)
+
+const (
+	quotationSharePurpose       = "quotation_share"
+	defaultQuotationShareExpiry = 14
+)
+
+var ErrInvalidShareLink = errors.New("invalid or expired link")
+
+type QuotationShareUsecase interface {
+	CreateShareLink(ctx context.Context, projectID uuid.UUID, req requests.CreateQuotationShareRequest) (*responses.QuotationShareResponse, error)
+	GetSharedQuotation(ctx context.Context, token string) (*responses.SharedQuotationResponse, error)
+	RespondToQuotation(ctx context.Context, token string, decision models.QuotationDecision, req requests.RespondToQuotationRequest, ipAddress string) (*responses.QuotationClientResponse, error)
+}
+
+type quotationShareUsecase struct {
+	shareRepo        repositories.QuotationShareRepository
+	quotationRepo    repositories.QuotationRepository
+	projectRepo      repositories.ProjectRepository
+	quotationUsecase QuotationUsecase
+	secret           []byte
+}
+
+// NewQuotationShareUsecase creates the share link usecase. secret signs the
+// share tokens and must not be the key used for login tokens.
+func NewQuotationShareUsecase(
+	shareRepo repositories.QuotationShareRepository,
+	quotationRepo repositories.QuotationRepository,
+	projectRepo repositories.ProjectRepository,
+	quotationUsecase QuotationUsecase,
+	secret string,
+) QuotationShareUsecase {
+	return &quotationShareUsecase{
+		shareRepo:        shareRepo,
+		quotationRepo:    quotationRepo,
+		projectRepo:      projectRepo,
+		quotationUsecase: quotationUsecase,
+		secret:           []byte(secret),
+	}
+}
+
+// CreateShareLink issues a signed token that lets the client view and answer
+// an approved quotation until it expires.
+func (u *quotationShareUsecase) CreateShareLink(ctx context.Context, projectID uuid.UUID, req requests.CreateQuotationShareRequest) (*responses.QuotationShareResponse, error) {
+	if req.ExpiresInDays < 0 || req.ExpiresInDays > 90 {
+		return nil, errors.New("expires_in_days must be between 1 and 90")
+	}
+
+	quotation, err := u.quotationRepo.GetByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if quotation == nil {
+		return nil, errors.New("quotation not found")
+	}
+	if quotation.Status != models.QuotationStatusApproved {
+		return nil, errors.New("only approved quotations can be shared")
+	}
+
+	expiresInDays := req.ExpiresInDays
+	if expiresInDays == 0 {
+		expiresInDays = defaultQuotationShareExpiry
+	}
+
+	now := time.Now()
+	share := &models.QuotationShare{
+		ShareID:     uuid.New(),
+		QuotationID: quotation.QuotationID,
+		ProjectID:   projectID,
+		ExpiresAt:   now.AddDate(0, 0, expiresInDays),
+		CreatedAt:   now,
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"share_id":     share.ShareID.String(),
+		"quotation_id": share.QuotationID.String(),
+		"purpose":      quotationSharePurpose,
+		"exp":          share.ExpiresAt.Unix(),
+	})
+
+	signed, err := token.SignedString(u.secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign share token: %w", err)
+	}
+
+	if err := u.shareRepo.CreateShare(ctx, share); err != nil {
+		return nil, err
+	}
+
+	return &responses.QuotationShareResponse{
+		ShareID:     share.ShareID,
+		QuotationID: share.QuotationID,
+		Token:       signed,
+		ExpiresAt:   share.ExpiresAt,
+	}, nil
+}
+
+func (u *quotationShareUsecase) GetSharedQuotation(ctx context.Context, token string) (*responses.SharedQuotationResponse, error) {
+	share, err := u.resolveShare(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	quotation, err := u.quotationUsecase.ExportQuotation(ctx, share.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	clientResponse, err := u.shareRepo.GetClientResponse(ctx, share.QuotationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &responses.SharedQuotationResponse{
+		Quotation:      quotation,
+		ExpiresAt:      share.ExpiresAt,
+		ClientResponse: toQuotationClientResponse(clientResponse),
+	}, nil
+}
+
+// RespondToQuotation records the client's acceptance or rejection on the
+// quotation. Only the first answer counts. Accepting moves the project into
+// progress through the usual project status transition check; rejecting
+// changes no status.
+func (u *quotationShareUsecase) RespondToQuotation(ctx context.Context, token string, decision models.QuotationDecision, req requests.RespondToQuotationRequest, ipAddress string) (*responses.QuotationClientResponse, error) {
+	if strings.TrimSpace(req.ClientName) == "" {
+		return nil, errors.New("client name is required")
+	}
+
+	share, err := u.resolveShare(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	quotationStatus, err := u.quotationRepo.GetQuotationStatus(ctx, share.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if quotationStatus != string(models.QuotationStatusApproved) {
+		return nil, errors.New("quotation is no longer available")
+	}
+
+	existing, err := u.shareRepo.GetClientResponse(ctx, share.QuotationID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, errors.New("quotation has already been responded to")
+	}
+
+	if decision == models.QuotationDecisionAccepted {
+		if err := u.projectRepo.ValidateStatusTransition(ctx, share.ProjectID, models.ProjectStatusInProgress); err != nil {
+			return nil, err
+		}
+	}
+
+	clientResponse := &models.QuotationClientResponse{
+		ResponseID:  uuid.New(),
+		QuotationID: share.QuotationID,
+		ShareID:     share.ShareID,
+		Decision:    decision,
+		ClientName:  strings.TrimSpace(req.ClientName),
+		Remarks:     sql.NullString{String: req.Remarks, Valid: req.Remarks != ""},
+		IPAddress:   ipAddress,
+		RespondedAt: time.Now(),
+	}
+
+	if err := u.shareRepo.RecordClientResponse(ctx, share.ProjectID, clientResponse); err != nil {
+		return nil, err
+	}
+
+	return toQuotationClientResponse(clientResponse), nil
+}
+
+// resolveShare verifies the token signature and expiry and checks that the
+// share it refers to still exists and has not been revoked.
+func (u *quotationShareUsecase) resolveShare(ctx context.Context, token string) (*models.QuotationShare, error) {
+	parsed, err := jwt.Parse(token, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		return u.secret, nil
+	})
+	if err != nil || !parsed.Valid {
+		return nil, ErrInvalidShareLink
+	}
+
+	claims, ok := parsed.Claims.(jwt.MapClaims)
+	if !ok || claims["purpose"] != quotationSharePurpose {
+		return nil, ErrInvalidShareLink
+	}
+
+	shareIDClaim, _ := claims["share_id"].(string)
+	shareID, err := uuid.Parse(shareIDClaim)
+	if err != nil {
+		return nil, ErrInvalidShareLink
+	}
+
+	share, err := u.shareRepo.GetShareByID(ctx, shareID)
+	if err != nil {
+		return nil, err
+	}
+	if share == nil || share.RevokedAt.Valid || time.Now().After(share.ExpiresAt) {
+		return nil, ErrInvalidShareLink
+	}
+	if claims["quotation_id"] != share.QuotationID.String() {
+		return nil, ErrInvalidShareLink
+	}
+
+	return share, nil
+}
+
+func toQuotationClientResponse(response *models.QuotationClientResponse) *responses.QuotationClientResponse {
+	if response == nil {
+		return nil
+	}
+
+	return &responses.QuotationClientResponse{
+		Decision:    string(response.Decision),
+		ClientName:  response.ClientName,
+		Remarks:     response.Remarks.String,
+		IPAddress:   response.IPAddress,
+		RespondedAt: response.RespondedAt,
+	}
+}
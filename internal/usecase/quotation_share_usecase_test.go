@@ -0,0 +1,207 @@
+package usecase_test
+
+import (
+	"boonkosang/internal/domain/models"
+	mocks "boonkosang/internal/repositories/mock"
+	"boonkosang/internal/requests"
+	"boonkosang/internal/usecase"
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testShareSecret = "share-secret"
+
+func TestRespondToQuotation(t *testing.T) {
+	ctx := context.Background()
+	projectID := uuid.New()
+	quotationID := uuid.New()
+	shareID := uuid.New()
+	answer := requests.RespondToQuotationRequest{ClientName: " Somchai ", Remarks: "Go ahead"}
+
+	sign := func(secret string, claims jwt.MapClaims) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+		require.NoError(t, err)
+		return token
+	}
+	claims := func(purpose string, expiresAt time.Time) jwt.MapClaims {
+		return jwt.MapClaims{
+			"share_id":     shareID.String(),
+			"quotation_id": quotationID.String(),
+			"purpose":      purpose,
+			"exp":          expiresAt.Unix(),
+		}
+	}
+	validToken := sign(testShareSecret, claims("quotation_share", time.Now().Add(time.Hour)))
+	share := &models.QuotationShare{
+		ShareID:     shareID,
+		QuotationID: quotationID,
+		ProjectID:   projectID,
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+
+	tests := []struct {
+		name             string
+		token            string
+		decision         models.QuotationDecision
+		share            *models.QuotationShare
+		existing         *models.QuotationClientResponse
+		transitionErr    error
+		wantErr          string
+		checksTransition bool
+	}{
+		{
+			name:             "accepted",
+			token:            validToken,
+			decision:         models.QuotationDecisionAccepted,
+			share:            share,
+			checksTransition: true,
+		},
+		{
+			name:     "rejected without a status change",
+			token:    validToken,
+			decision: models.QuotationDecisionRejected,
+			share:    share,
+		},
+		{
+			name:             "accepted for a project past planning",
+			token:            validToken,
+			decision:         models.QuotationDecisionAccepted,
+			share:            share,
+			transitionErr:    errors.New("project must be in planning status to move to in_progress"),
+			wantErr:          "project must be in planning status to move to in_progress",
+			checksTransition: true,
+		},
+		{
+			name:     "link used again",
+			token:    validToken,
+			decision: models.QuotationDecisionAccepted,
+			share:    share,
+			existing: &models.QuotationClientResponse{QuotationID: quotationID, Decision: models.QuotationDecisionRejected},
+			wantErr:  "quotation has already been responded to",
+		},
+		{
+			name:     "expired token",
+			token:    sign(testShareSecret, claims("quotation_share", time.Now().Add(-time.Minute))),
+			decision: models.QuotationDecisionAccepted,
+			share:    share,
+			wantErr:  usecase.ErrInvalidShareLink.Error(),
+		},
+		{
+			name:     "expired share",
+			token:    validToken,
+			decision: models.QuotationDecisionAccepted,
+			share:    &models.QuotationShare{ShareID: shareID, QuotationID: quotationID, ProjectID: projectID, ExpiresAt: time.Now().Add(-time.Minute)},
+			wantErr:  usecase.ErrInvalidShareLink.Error(),
+		},
+		{
+			name:     "revoked share",
+			token:    validToken,
+			decision: models.QuotationDecisionAccepted,
+			share:    &models.QuotationShare{ShareID: shareID, QuotationID: quotationID, ProjectID: projectID, ExpiresAt: share.ExpiresAt, RevokedAt: sql.NullTime{Time: time.Now(), Valid: true}},
+			wantErr:  usecase.ErrInvalidShareLink.Error(),
+		},
+		{
+			name:     "token for another purpose",
+			token:    sign(testShareSecret, claims("login", time.Now().Add(time.Hour))),
+			decision: models.QuotationDecisionAccepted,
+			share:    share,
+			wantErr:  usecase.ErrInvalidShareLink.Error(),
+		},
+		{
+			name:     "token signed with another secret",
+			token:    sign("login-secret", claims("quotation_share", time.Now().Add(time.Hour))),
+			decision: models.QuotationDecisionAccepted,
+			share:    share,
+			wantErr:  usecase.ErrInvalidShareLink.Error(),
+		},
+		{
+			name:     "token for another quotation",
+			token:    validToken,
+			decision: models.QuotationDecisionAccepted,
+			share:    &models.QuotationShare{ShareID: shareID, QuotationID: uuid.New(), ProjectID: projectID, ExpiresAt: share.ExpiresAt},
+			wantErr:  usecase.ErrInvalidShareLink.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shareRepo := new(mocks.MockQuotationShareRepository)
+			quotationRepo := new(mocks.MockQuotationRepository)
+			projectRepo := new(mocks.MockProjectRepository)
+
+			shareRepo.On("GetShareByID", ctx, shareID).Return(tt.share, nil)
+			quotationRepo.On("GetQuotationStatus", ctx, projectID).Return(string(models.QuotationStatusApproved), nil)
+			if tt.existing != nil {
+				shareRepo.On("GetClientResponse", ctx, quotationID).Return(tt.existing, nil)
+			} else {
+				shareRepo.On("GetClientResponse", ctx, quotationID).Return(nil, nil)
+			}
+			projectRepo.On("ValidateStatusTransition", ctx, projectID, models.ProjectStatusInProgress).Return(tt.transitionErr)
+			shareRepo.On("RecordClientResponse", ctx, projectID, mock.AnythingOfType("*models.QuotationClientResponse")).Return(nil)
+
+			uc := usecase.NewQuotationShareUsecase(shareRepo, quotationRepo, projectRepo, usecase.NewQuotationUsecase(quotationRepo), testShareSecret)
+			response, err := uc.RespondToQuotation(ctx, tt.token, tt.decision, answer, "203.0.113.7")
+
+			if tt.checksTransition {
+				projectRepo.AssertCalled(t, "ValidateStatusTransition", ctx, projectID, models.ProjectStatusInProgress)
+			} else {
+				projectRepo.AssertNotCalled(t, "ValidateStatusTransition", mock.Anything, mock.Anything, mock.Anything)
+			}
+
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				shareRepo.AssertNotCalled(t, "RecordClientResponse", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, string(tt.decision), response.Decision)
+			assert.Equal(t, "Somchai", response.ClientName)
+			assert.Equal(t, "203.0.113.7", response.IPAddress)
+			shareRepo.AssertCalled(t, "RecordClientResponse", ctx, projectID, mock.MatchedBy(func(r *models.QuotationClientResponse) bool {
+				return r.QuotationID == quotationID && r.ShareID == shareID && r.Decision == tt.decision
+			}))
+		})
+	}
+}
+
+func TestCreateShareLinkTokenResolves(t *testing.T) {
+	ctx := context.Background()
+	projectID := uuid.New()
+	quotation := &models.Quotation{QuotationID: uuid.New(), ProjectID: projectID, Status: models.QuotationStatusApproved}
+
+	shareRepo := new(mocks.MockQuotationShareRepository)
+	quotationRepo := new(mocks.MockQuotationRepository)
+	quotationRepo.On("GetByProjectID", ctx, projectID).Return(quotation, nil)
+
+	stored := &models.QuotationShare{}
+	shareRepo.On("CreateShare", ctx, mock.AnythingOfType("*models.QuotationShare")).
+		Run(func(args mock.Arguments) { *stored = *args.Get(1).(*models.QuotationShare) }).
+		Return(nil)
+
+	uc := usecase.NewQuotationShareUsecase(shareRepo, quotationRepo, new(mocks.MockProjectRepository), usecase.NewQuotationUsecase(quotationRepo), testShareSecret)
+	share, err := uc.CreateShareLink(ctx, projectID, requests.CreateQuotationShareRequest{ExpiresInDays: 3})
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().AddDate(0, 0, 3), share.ExpiresAt, time.Minute)
+
+	// The issued token is accepted by the same usecase, and by no other secret
+	shareRepo.On("GetShareByID", ctx, stored.ShareID).Return(stored, nil)
+	quotationRepo.On("GetQuotationStatus", ctx, projectID).Return(string(models.QuotationStatusApproved), nil)
+	shareRepo.On("GetClientResponse", ctx, quotation.QuotationID).Return(&models.QuotationClientResponse{}, nil)
+
+	_, err = uc.RespondToQuotation(ctx, share.Token, models.QuotationDecisionRejected, requests.RespondToQuotationRequest{ClientName: "Somchai"}, "")
+	assert.EqualError(t, err, "quotation has already been responded to")
+
+	other := usecase.NewQuotationShareUsecase(shareRepo, quotationRepo, new(mocks.MockProjectRepository), usecase.NewQuotationUsecase(quotationRepo), "other-secret")
+	_, err = other.RespondToQuotation(ctx, share.Token, models.QuotationDecisionRejected, requests.RespondToQuotationRequest{ClientName: "Somchai"}, "")
+	assert.ErrorIs(t, err, usecase.ErrInvalidShareLink)
+}
@@ -9,6 +9,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/google/uuid"
@@ -20,6 +21,7 @@ type QuotationUsecase interface {
 	ExportQuotation(ctx context.Context, projectID uuid.UUID) (*responses.QuotationExportData, error)
 
 	UpdateProjectSellingPrice(ctx context.Context, req requests.UpdateProjectSellingPriceRequest) error
+	CalculateSellingPrice(ctx context.Context, req requests.CalculateSellingPriceRequest) (*responses.SellingPriceCalculationResponse, error)
 }
 
 type quotationUsecase struct {
@@ -40,11 +42,16 @@ func (u *quotationUsecase) buildQuotationResponse(
 	costs []models.QuotationGeneralCost,
 ) *responses.QuotationResponse {
 	response := &responses.QuotationResponse{
-		QuotationID: quotation.QuotationID,
-		Status:      string(quotation.Status),
-		ValidDate:   getValidTime(quotation.ValidDate),
-		Jobs:        make([]responses.QuotationJobDetail, 0),
-		Costs:       make([]responses.GeneralCostDetail, 0),
+		QuotationID:     quotation.QuotationID,
+		QuotationNumber: quotation.QuotationNumber.String,
+		Status:          string(quotation.Status),
+		ValidDate:       getValidTime(quotation.ValidDate),
+		Jobs:            make([]responses.QuotationJobDetail, 0),
+		Costs:           make([]responses.GeneralCostDetail, 0),
+	}
+	if quotation.ClientDecision.Valid {
+		response.ClientDecision = quotation.ClientDecision.String
+		response.ClientRespondedAt = &quotation.ClientRespondedAt.Time
 	}
 
 	// Process jobs
@@ -145,6 +152,24 @@ func (u *quotationUsecase) CreateOrGetQuotation(ctx context.Context, projectID u
 		response.SellingGeneralCost = jobs[0].SellingGeneralCost.Float64
 		response.TaxPercentage = jobs[0].TaxPercentage.Float64
 	}
+
+	withholdingTax, err := u.quotationRepo.GetClientWithholdingTax(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if quotation.WithholdingTaxPercentage.Valid {
+		withholdingTax = quotation.WithholdingTaxPercentage.Float64
+	}
+
+	response.WithholdingTaxPercentage = withholdingTax
+	response.SubTotal = response.SellingGeneralCost
+	for _, job := range response.Jobs {
+		response.SubTotal += job.TotalSellingPrice
+	}
+	response.TaxAmount = calculateTaxAmount(response.SubTotal, response.TaxPercentage)
+	response.WithholdingTaxAmount = calculateWithholdingTax(response.SubTotal, withholdingTax)
+	response.NetReceivable = response.SubTotal + response.TaxAmount - response.WithholdingTaxAmount
+
 	return response, nil
 }
 
@@ -267,5 +292,209 @@ func validateSellingPriceRequest(req requests.UpdateProjectSellingPriceRequest)
 		}
 	}
 
+	if req.WithholdingTaxPercentage != nil {
+		if err := validateWithholdingTaxPercentage(*req.WithholdingTaxPercentage); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// withholdingTaxRates are the withholding tax rates (%) applicable to
+// construction and service payments.
+var withholdingTaxRates = []float64{0, 1, 1.5, 2, 3, 5}
+
+func validateWithholdingTaxPercentage(percentage float64) error {
+	for _, rate := range withholdingTaxRates {
+		if percentage == rate {
+			return nil
+		}
+	}
+	return errors.New("withholding tax percentage must be one of 0, 1, 1.5, 2, 3 or 5")
+}
+
+// calculateWithholdingTax returns the tax withheld by the client, which is
+// always computed on the amount before VAT.
+func calculateWithholdingTax(amountBeforeVAT, percentage float64) float64 {
+	return amountBeforeVAT * percentage / 100
+}
+
+// CalculateSellingPrice prices every BOQ job as overall cost plus markup.
+// A job's own markup applies to its overall cost and overrides the others.
+// Otherwise, when labor or material markup is given, each cost component is
+// marked up separately, and the global markup covers the component without
+// one; without either, the global markup applies to the overall cost.
+// Preview requests return the prices without saving them.
+func (u *quotationUsecase) CalculateSellingPrice(ctx context.Context, req requests.CalculateSellingPriceRequest) (*responses.SellingPriceCalculationResponse, error) {
+	if err := validateMarkupRequest(req); err != nil {
+		return nil, err
+	}
+
+	boqStatus, err := u.quotationRepo.CheckBOQStatus(ctx, req.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if boqStatus != "approved" {
+		return nil, errors.New("BOQ must be approved before updating selling price")
+	}
+
+	quotation, err := u.quotationRepo.GetByProjectID(ctx, req.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if quotation == nil {
+		return nil, errors.New("quotation not found")
+	}
+
+	if !req.Preview && quotation.Status != models.QuotationStatusDraft {
+		return nil, errors.New("can only update selling price for quotation in draft status")
+	}
+
+	jobs, err := u.quotationRepo.GetQuotationJobs(ctx, req.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(jobs) == 0 {
+		return nil, errors.New("no jobs found in BOQ")
+	}
+
+	// Saving keeps the quotation's tax percentage and selling general cost,
+	// which must already be set.
+	if !req.Preview {
+		if quotation.TaxPercentage.Float64 <= 0 {
+			return nil, errors.New("set the quotation tax percentage before saving selling prices")
+		}
+		if jobs[0].SellingGeneralCost.Float64 <= 0 {
+			return nil, errors.New("set the selling general cost before saving selling prices")
+		}
+	}
+
+	jobMarkups := make(map[uuid.UUID]float64, len(req.JobMarkups))
+	for _, jm := range req.JobMarkups {
+		jobMarkups[jm.JobID] = jm.MarkupPercentage
+	}
+
+	response := &responses.SellingPriceCalculationResponse{
+		ProjectID: req.ProjectID,
+		Preview:   req.Preview,
+		RoundTo:   req.RoundTo,
+		Jobs:      make([]responses.SellingPriceJobDetail, 0, len(jobs)),
+	}
+
+	jobSellingPrices := make([]requests.JobSellingPrice, 0, len(jobs))
+	for _, job := range jobs {
+		materialCost := job.TotalMaterialPrice.Float64
+		overallCost := job.LaborCost + materialCost
+
+		var sellingPrice float64
+		markup, ownMarkup := jobMarkups[job.JobID]
+		if !ownMarkup {
+			markup = req.MarkupPercentage
+		}
+
+		if !ownMarkup && (req.LaborMarkup != nil || req.MaterialMarkup != nil) {
+			laborMarkup, materialMarkup := markup, markup
+			if req.LaborMarkup != nil {
+				laborMarkup = *req.LaborMarkup
+			}
+			if req.MaterialMarkup != nil {
+				materialMarkup = *req.MaterialMarkup
+			}
+			sellingPrice = applyMarkup(job.LaborCost, laborMarkup) + applyMarkup(materialCost, materialMarkup)
+			if overallCost > 0 {
+				markup = (sellingPrice - overallCost) / overallCost * 100
+			}
+		} else {
+			sellingPrice = applyMarkup(overallCost, markup)
+		}
+
+		sellingPrice = roundToNearest(sellingPrice, req.RoundTo)
+		if sellingPrice <= 0 {
+			return nil, fmt.Errorf("selling price for job %s must be greater than 0", job.JobID)
+		}
+
+		totalSellingPrice := sellingPrice * job.Quantity
+
+		response.Jobs = append(response.Jobs, responses.SellingPriceJobDetail{
+			JobID:             job.JobID,
+			Name:              job.JobName,
+			Unit:              job.Unit,
+			Quantity:          job.Quantity,
+			LaborCost:         job.LaborCost,
+			MaterialCost:      materialCost,
+			OverallCost:       overallCost,
+			MarkupPercentage:  markup,
+			SellingPrice:      sellingPrice,
+			TotalSellingPrice: totalSellingPrice,
+		})
+
+		response.TotalOverallCost += overallCost * job.Quantity
+		response.TotalSellingPrice += totalSellingPrice
+
+		jobSellingPrices = append(jobSellingPrices, requests.JobSellingPrice{
+			JobID:        job.JobID,
+			SellingPrice: sellingPrice,
+		})
+	}
+	response.TotalProfit = response.TotalSellingPrice - response.TotalOverallCost
+
+	if req.Preview {
+		return response, nil
+	}
+
+	// Keep the current tax percentage and selling general cost; only the job
+	// selling prices are replaced.
+	updateReq := requests.UpdateProjectSellingPriceRequest{
+		ProjectID:          req.ProjectID,
+		TaxPercentage:      quotation.TaxPercentage.Float64,
+		SellingGeneralCost: jobs[0].SellingGeneralCost.Float64,
+		JobSellingPrices:   jobSellingPrices,
+	}
+
+	if err := u.UpdateProjectSellingPrice(ctx, updateReq); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func validateMarkupRequest(req requests.CalculateSellingPriceRequest) error {
+	if req.MarkupPercentage < 0 {
+		return errors.New("markup percentage must not be negative")
+	}
+	if req.LaborMarkup != nil && *req.LaborMarkup < 0 {
+		return errors.New("labor markup must not be negative")
+	}
+	if req.MaterialMarkup != nil && *req.MaterialMarkup < 0 {
+		return errors.New("material markup must not be negative")
+	}
+	for _, jm := range req.JobMarkups {
+		if jm.MarkupPercentage < 0 {
+			return fmt.Errorf("markup percentage for job %s must not be negative", jm.JobID)
+		}
+	}
+
+	switch req.RoundTo {
+	case 0, 1, 10, 100:
+	default:
+		return errors.New("round_to must be one of 0, 1, 10 or 100")
+	}
+
 	return nil
 }
+
+func applyMarkup(cost, markupPercentage float64) float64 {
+	return cost * (1 + markupPercentage/100)
+}
+
+// roundToNearest rounds value to the nearest multiple of step baht.
+// A step of 0 leaves the value at satang precision.
+func roundToNearest(value float64, step int) float64 {
+	if step <= 0 {
+		return math.Round(value*100) / 100
+	}
+	return math.Round(value/float64(step)) * float64(step)
+}
@@ -0,0 +1,207 @@
+package usecase_test
+
+import (
+	"boonkosang/internal/domain/models"
+	mocks "boonkosang/internal/repositories/mock"
+	"boonkosang/internal/requests"
+	"boonkosang/internal/usecase"
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyMarkup(t *testing.T) {
+	tests := []struct {
+		name   string
+		cost   float64
+		markup float64
+		want   float64
+	}{
+		{name: "no markup", cost: 1500, markup: 0, want: 1500},
+		{name: "whole percentage", cost: 1500, markup: 20, want: 1800},
+		{name: "fractional percentage", cost: 200, markup: 12.5, want: 225},
+		{name: "no cost", cost: 0, markup: 30, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, usecase.ApplyMarkup(tt.cost, tt.markup), 1e-9)
+		})
+	}
+}
+
+func TestRoundToNearest(t *testing.T) {
+	tests := []struct {
+		name  string
+		value float64
+		step  int
+		want  float64
+	}{
+		{name: "satang precision", value: 1234.567, step: 0, want: 1234.57},
+		{name: "whole baht", value: 1234.5, step: 1, want: 1235},
+		{name: "tens down", value: 1234, step: 10, want: 1230},
+		{name: "tens up", value: 1235, step: 10, want: 1240},
+		{name: "hundreds half up", value: 1250, step: 100, want: 1300},
+		{name: "hundreds down", value: 1249.99, step: 100, want: 1200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, usecase.RoundToNearest(tt.value, tt.step))
+		})
+	}
+}
+
+func TestCalculateSellingPrice(t *testing.T) {
+	ctx := context.Background()
+	projectID := uuid.New()
+	wallID, roofID := uuid.New(), uuid.New()
+
+	quotationJobs := func(sellingGeneralCost float64) []models.QuotationJob {
+		general := sql.NullFloat64{Float64: sellingGeneralCost, Valid: true}
+		return []models.QuotationJob{
+			{JobID: wallID, JobName: "Wall", Quantity: 2, LaborCost: 100, TotalMaterialPrice: sql.NullFloat64{Float64: 200, Valid: true}, SellingGeneralCost: general},
+			{JobID: roofID, JobName: "Roof", Quantity: 1, LaborCost: 50, TotalMaterialPrice: sql.NullFloat64{Float64: 50, Valid: true}, SellingGeneralCost: general},
+		}
+	}
+	markup := func(value float64) *float64 { return &value }
+
+	t.Run("preview", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			req    requests.CalculateSellingPriceRequest
+			prices map[uuid.UUID]float64
+		}{
+			{
+				name:   "global markup on overall cost",
+				req:    requests.CalculateSellingPriceRequest{MarkupPercentage: 10},
+				prices: map[uuid.UUID]float64{wallID: 330, roofID: 110},
+			},
+			{
+				name:   "labor and material markups",
+				req:    requests.CalculateSellingPriceRequest{LaborMarkup: markup(20), MaterialMarkup: markup(10)},
+				prices: map[uuid.UUID]float64{wallID: 340, roofID: 115},
+			},
+			{
+				name:   "global markup covers the component without one",
+				req:    requests.CalculateSellingPriceRequest{MarkupPercentage: 5, LaborMarkup: markup(20)},
+				prices: map[uuid.UUID]float64{wallID: 330, roofID: 112.5},
+			},
+			{
+				name: "job markup overrides component markups",
+				req: requests.CalculateSellingPriceRequest{
+					LaborMarkup:    markup(20),
+					MaterialMarkup: markup(10),
+					JobMarkups:     []requests.JobMarkup{{JobID: wallID, MarkupPercentage: 50}},
+				},
+				prices: map[uuid.UUID]float64{wallID: 450, roofID: 115},
+			},
+			{
+				name:   "rounded to tens",
+				req:    requests.CalculateSellingPriceRequest{MarkupPercentage: 7, RoundTo: 10},
+				prices: map[uuid.UUID]float64{wallID: 320, roofID: 110},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				repo := new(mocks.MockQuotationRepository)
+				repo.On("CheckBOQStatus", ctx, projectID).Return("approved", nil)
+				// Previews do not need the tax and general cost that saving keeps
+				repo.On("GetByProjectID", ctx, projectID).Return(&models.Quotation{ProjectID: projectID, Status: models.QuotationStatusApproved}, nil)
+				repo.On("GetQuotationJobs", ctx, projectID).Return(quotationJobs(0), nil)
+
+				tt.req.ProjectID = projectID
+				tt.req.Preview = true
+				response, err := usecase.NewQuotationUsecase(repo).CalculateSellingPrice(ctx, tt.req)
+				require.NoError(t, err)
+
+				var total float64
+				for _, job := range response.Jobs {
+					assert.InDelta(t, tt.prices[job.JobID], job.SellingPrice, 1e-9, job.Name)
+					total += job.SellingPrice * job.Quantity
+				}
+				assert.InDelta(t, total, response.TotalSellingPrice, 1e-9)
+				assert.InDelta(t, 700, response.TotalOverallCost, 1e-9)
+				assert.InDelta(t, total-700, response.TotalProfit, 1e-9)
+				repo.AssertNotCalled(t, "UpdateProjectSellingPrice", mock.Anything, mock.Anything)
+			})
+		}
+	})
+
+	t.Run("save", func(t *testing.T) {
+		tests := []struct {
+			name               string
+			status             models.QuotationStatus
+			taxPercentage      float64
+			sellingGeneralCost float64
+			wantErr            string
+		}{
+			{
+				name:               "keeps tax and selling general cost",
+				status:             models.QuotationStatusDraft,
+				taxPercentage:      7,
+				sellingGeneralCost: 1500,
+			},
+			{
+				name:               "quotation without tax percentage",
+				status:             models.QuotationStatusDraft,
+				sellingGeneralCost: 1500,
+				wantErr:            "set the quotation tax percentage before saving selling prices",
+			},
+			{
+				name:          "quotation without selling general cost",
+				status:        models.QuotationStatusDraft,
+				taxPercentage: 7,
+				wantErr:       "set the selling general cost before saving selling prices",
+			},
+			{
+				name:               "quotation no longer draft",
+				status:             models.QuotationStatusApproved,
+				taxPercentage:      7,
+				sellingGeneralCost: 1500,
+				wantErr:            "can only update selling price for quotation in draft status",
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				repo := new(mocks.MockQuotationRepository)
+				repo.On("CheckBOQStatus", ctx, projectID).Return("approved", nil)
+				repo.On("GetByProjectID", ctx, projectID).Return(&models.Quotation{
+					ProjectID:     projectID,
+					Status:        tt.status,
+					TaxPercentage: sql.NullFloat64{Float64: tt.taxPercentage, Valid: tt.taxPercentage != 0},
+				}, nil)
+				repo.On("GetQuotationJobs", ctx, projectID).Return(quotationJobs(tt.sellingGeneralCost), nil)
+				repo.On("GetQuotationStatus", ctx, projectID).Return(string(tt.status), nil)
+				repo.On("UpdateProjectSellingPrice", ctx, mock.Anything).Return(nil)
+
+				req := requests.CalculateSellingPriceRequest{ProjectID: projectID, MarkupPercentage: 10}
+				_, err := usecase.NewQuotationUsecase(repo).CalculateSellingPrice(ctx, req)
+
+				if tt.wantErr != "" {
+					require.EqualError(t, err, tt.wantErr)
+					repo.AssertNotCalled(t, "UpdateProjectSellingPrice", mock.Anything, mock.Anything)
+					return
+				}
+
+				require.NoError(t, err)
+				repo.AssertCalled(t, "UpdateProjectSellingPrice", ctx, requests.UpdateProjectSellingPriceRequest{
+					ProjectID:          projectID,
+					TaxPercentage:      7,
+					SellingGeneralCost: 1500,
+					JobSellingPrices: []requests.JobSellingPrice{
+						{JobID: wallID, SellingPrice: 330},
+						{JobID: roofID, SellingPrice: 110},
+					},
+				})
+			})
+		}
+	})
+}
@@ -0,0 +1,305 @@
+package usecase
+
+import (
+	"boonkosang/internal/domain/models"
+	"boonkosang/internal/infrastructure/document"
+	"boonkosang/internal/infrastructure/etax"
+	"boonkosang/internal/repositories"
+	"boonkosang/internal/responses"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type ReceiptUsecase interface {
+	IssueReceipt(ctx context.Context, invoiceID uuid.UUID, paymentID uuid.UUID) (*responses.ReceiptResponse, error)
+	GetReceipt(ctx context.Context, invoiceID uuid.UUID, paymentID uuid.UUID) (*responses.ReceiptResponse, error)
+	ListReceipts(ctx context.Context, invoiceID uuid.UUID) ([]responses.ReceiptResponse, error)
+	RenderReceipt(ctx context.Context, invoiceID uuid.UUID, paymentID uuid.UUID) (*responses.ReceiptDocumentResponse, error)
+}
+
+type receiptUsecase struct {
+	receiptRepo repositories.ReceiptRepository
+	paymentRepo repositories.PaymentRepository
+	invoiceRepo repositories.InvoiceRepository
+	projectRepo repositories.ProjectRepository
+	companyRepo repositories.CompanyRepository
+	pdfFont     []byte
+}
+
+// NewReceiptUsecase creates the usecase. pdfFont is the same TrueType font
+// used for contracts; receipts carry the Thai document name, so they cannot
+// be rendered without it.
+func NewReceiptUsecase(
+	receiptRepo repositories.ReceiptRepository,
+	paymentRepo repositories.PaymentRepository,
+	invoiceRepo repositories.InvoiceRepository,
+	projectRepo repositories.ProjectRepository,
+	companyRepo repositories.CompanyRepository,
+	pdfFont []byte,
+) ReceiptUsecase {
+	return &receiptUsecase{
+		receiptRepo: receiptRepo,
+		paymentRepo: paymentRepo,
+		invoiceRepo: invoiceRepo,
+		projectRepo: projectRepo,
+		companyRepo: companyRepo,
+		pdfFont:     pdfFont,
+	}
+}
+
+// IssueReceipt issues the receipt / tax invoice for a payment. The payment
+// covers its share of the invoice, so the VAT, withholding tax and retention
+// on the receipt are the invoice amounts in the same proportion as the
+// payment is to the net receivable.
+func (u *receiptUsecase) IssueReceipt(ctx context.Context, invoiceID uuid.UUID, paymentID uuid.UUID) (*responses.ReceiptResponse, error) {
+	invoice, payment, err := u.getPayment(ctx, invoiceID, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if payment.ReversedAt.Valid {
+		return nil, errors.New("cannot issue a receipt for a reversed payment")
+	}
+
+	project, client, err := u.projectRepo.GetByIDWithClient(ctx, invoice.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if project.CompanyID == nil {
+		return nil, errors.New("project is not linked to a company")
+	}
+
+	company, err := u.companyRepo.GetByID(ctx, *project.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(company.TaxID) == "" {
+		return nil, errors.New("company tax ID is required to issue a tax invoice")
+	}
+
+	amounts := toInvoiceResponse(invoice)
+	if amounts.NetReceivable <= 0 {
+		return nil, errors.New("invoice has no amount receivable")
+	}
+
+	share := payment.Amount / amounts.NetReceivable
+	withholdingTax := roundMoney(amounts.WithholdingTaxAmount * share)
+	retention := roundMoney(amounts.Retention * share)
+	total := roundMoney(payment.Amount + withholdingTax + retention)
+	subTotal := roundMoney(total / (1 + amounts.TaxPercentage/100))
+
+	receipt := &models.Receipt{
+		PaymentID:            payment.PaymentID,
+		InvoiceID:            invoice.InvoiceID,
+		ProjectID:            invoice.ProjectID,
+		ReceiptDate:          payment.PaymentDate,
+		SubTotal:             subTotal,
+		TaxPercentage:        amounts.TaxPercentage,
+		TaxAmount:            roundMoney(total - subTotal),
+		TotalAmount:          total,
+		WithholdingTaxAmount: withholdingTax,
+		RetentionAmount:      retention,
+		AmountReceived:       payment.Amount,
+		SellerName:           company.Name,
+		SellerTaxID:          company.TaxID,
+		SellerBranch:         branchOrHeadOffice(company.Branch),
+		SellerAddress:        company.Address,
+		BuyerName:            client.Name,
+		BuyerTaxID:           client.TaxID,
+		BuyerBranch:          branchOrHeadOffice(client.Branch),
+		BuyerAddress:         client.Address,
+	}
+
+	if err := u.receiptRepo.Create(ctx, receipt); err != nil {
+		return nil, err
+	}
+
+	return toReceiptResponse(receipt, invoice.InvoiceNumber.String), nil
+}
+
+func (u *receiptUsecase) GetReceipt(ctx context.Context, invoiceID uuid.UUID, paymentID uuid.UUID) (*responses.ReceiptResponse, error) {
+	invoice, payment, err := u.getPayment(ctx, invoiceID, paymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt, err := u.receiptRepo.GetByPaymentID(ctx, payment.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toReceiptResponse(receipt, invoice.InvoiceNumber.String), nil
+}
+
+func (u *receiptUsecase) ListReceipts(ctx context.Context, invoiceID uuid.UUID) ([]responses.ReceiptResponse, error) {
+	invoice, err := getInvoiceWithTaxSettings(ctx, u.invoiceRepo, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	receipts, err := u.receiptRepo.ListByInvoiceID(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]responses.ReceiptResponse, len(receipts))
+	for i := range receipts {
+		response[i] = *toReceiptResponse(&receipts[i], invoice.InvoiceNumber.String)
+	}
+
+	return response, nil
+}
+
+// RenderReceipt renders the receipt / tax invoice of a payment as a PDF.
+func (u *receiptUsecase) RenderReceipt(ctx context.Context, invoiceID uuid.UUID, paymentID uuid.UUID) (*responses.ReceiptDocumentResponse, error) {
+	invoice, payment, err := u.getPayment(ctx, invoiceID, paymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt, err := u.receiptRepo.GetByPaymentID(ctx, payment.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &document.Document{Title: etax.TypeReceiptTaxInvoice.Name()}
+	doc.AddParagraph(fmt.Sprintf("No. %s    Date: %s", receipt.ReceiptNumber, receipt.ReceiptDate.Format("2 January 2006")))
+	if receipt.VoidedAt.Valid {
+		doc.AddParagraph(fmt.Sprintf("VOID on %s: %s", receipt.VoidedAt.Time.Format("2 January 2006"), receipt.VoidReason.String))
+	}
+
+	doc.AddHeading("Seller")
+	doc.AddParagraph(receipt.SellerName)
+	doc.AddParagraph("Tax ID: " + receipt.SellerTaxID + " " + branchLabel(receipt.SellerBranch))
+	if address := formatAddress(receipt.SellerAddress); address != "" {
+		doc.AddParagraph(address)
+	}
+
+	doc.AddHeading("Buyer")
+	doc.AddParagraph(receipt.BuyerName)
+	if receipt.BuyerTaxID != "" {
+		doc.AddParagraph("Tax ID: " + receipt.BuyerTaxID + " " + branchLabel(receipt.BuyerBranch))
+	}
+	if address := formatAddress(receipt.BuyerAddress); address != "" {
+		doc.AddParagraph(address)
+	}
+
+	description := "Payment for invoice " + invoice.InvoiceNumber.String
+	if invoice.Type() == models.InvoiceTypeRetentionRelease {
+		description += " (retention release)"
+	} else if invoice.Period.PeriodNumber > 0 {
+		description += fmt.Sprintf(" (period %d)", invoice.Period.PeriodNumber)
+	}
+
+	rows := [][]string{
+		{description, formatBaht(receipt.SubTotal)},
+		{fmt.Sprintf("VAT %g%%", receipt.TaxPercentage), formatBaht(receipt.TaxAmount)},
+		{"Total including VAT", formatBaht(receipt.TotalAmount)},
+	}
+	if receipt.WithholdingTaxAmount > 0 {
+		rows = append(rows, []string{"Less withholding tax", formatBaht(-receipt.WithholdingTaxAmount)})
+	}
+	if receipt.RetentionAmount > 0 {
+		rows = append(rows, []string{"Less retention", formatBaht(-receipt.RetentionAmount)})
+	}
+	rows = append(rows, []string{"Amount received", formatBaht(receipt.AmountReceived)})
+	doc.AddTable([]string{"Description", "Amount (baht)"}, rows)
+
+	paidBy := "Paid by " + string(payment.Method)
+	if payment.Method == models.PaymentMethodCheque {
+		paidBy += fmt.Sprintf(" no. %s, %s", payment.Reference.String, payment.BankName.String)
+	} else if payment.Reference.Valid {
+		paidBy += ", reference " + payment.Reference.String
+	}
+	doc.AddParagraph(paidBy + ".")
+
+	doc.AddTable(
+		[]string{"Received by", "Authorized signature"},
+		[][]string{{"Signed ............................", "Signed ............................"}},
+	)
+
+	content, err := document.RenderPDF(doc, u.pdfFont)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render receipt: %w", err)
+	}
+
+	return &responses.ReceiptDocumentResponse{
+		FileName:    receipt.ReceiptNumber + ".pdf",
+		ContentType: "application/pdf",
+		Content:     content,
+	}, nil
+}
+
+func (u *receiptUsecase) getPayment(ctx context.Context, invoiceID uuid.UUID, paymentID uuid.UUID) (*models.Invoice, *models.Payment, error) {
+	invoice, err := getInvoiceWithTaxSettings(ctx, u.invoiceRepo, invoiceID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	payment, err := u.paymentRepo.GetByID(ctx, paymentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if payment.InvoiceID != invoiceID {
+		return nil, nil, errors.New("payment not found")
+	}
+
+	return invoice, payment, nil
+}
+
+func toReceiptResponse(receipt *models.Receipt, invoiceNumber string) *responses.ReceiptResponse {
+	response := &responses.ReceiptResponse{
+		ReceiptID:            receipt.ReceiptID,
+		ReceiptNumber:        receipt.ReceiptNumber,
+		PaymentID:            receipt.PaymentID,
+		InvoiceID:            receipt.InvoiceID,
+		InvoiceNumber:        invoiceNumber,
+		ReceiptDate:          receipt.ReceiptDate,
+		SubTotal:             receipt.SubTotal,
+		TaxPercentage:        receipt.TaxPercentage,
+		TaxAmount:            receipt.TaxAmount,
+		TotalAmount:          receipt.TotalAmount,
+		WithholdingTaxAmount: receipt.WithholdingTaxAmount,
+		RetentionAmount:      receipt.RetentionAmount,
+		AmountReceived:       receipt.AmountReceived,
+		SellerName:           receipt.SellerName,
+		SellerTaxID:          receipt.SellerTaxID,
+		SellerBranch:         receipt.SellerBranch,
+		SellerAddress:        receipt.SellerAddress,
+		BuyerName:            receipt.BuyerName,
+		BuyerTaxID:           receipt.BuyerTaxID,
+		BuyerBranch:          receipt.BuyerBranch,
+		BuyerAddress:         receipt.BuyerAddress,
+		Voided:               receipt.VoidedAt.Valid,
+		VoidReason:           receipt.VoidReason.String,
+		CreatedAt:            receipt.CreatedAt,
+	}
+
+	if receipt.VoidedAt.Valid {
+		response.VoidedAt = &receipt.VoidedAt.Time
+	}
+
+	return response
+}
+
+// branchOrHeadOffice falls back to the head office for companies and clients
+// saved before branch codes were recorded.
+func branchOrHeadOffice(code string) string {
+	branch, err := models.NormalizeBranch(code)
+	if err != nil {
+		return models.HeadOfficeBranch
+	}
+	return branch
+}
+
+// branchLabel is the branch wording required next to the tax ID on a tax invoice.
+func branchLabel(code string) string {
+	branch := branchOrHeadOffice(code)
+	if branch == models.HeadOfficeBranch {
+		return "(Head office)"
+	}
+	return "(Branch " + branch + ")"
+}
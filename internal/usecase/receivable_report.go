@@ -0,0 +1,220 @@
+package usecase
+
+import (
+	"boonkosang/internal/domain/models"
+	"boonkosang/internal/responses"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ListOverdueInvoices returns the invoices of all projects that were open and
+// past their payment due date on asOf, the longest overdue first.
+func (u *invoiceUseCase) ListOverdueInvoices(ctx context.Context, asOf time.Time) ([]responses.OverdueInvoiceResponse, error) {
+	invoices, err := u.receivableInvoices(ctx, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []responses.OverdueInvoiceResponse{}
+	for i := range invoices {
+		invoice := &invoices[i]
+		days := invoice.DaysOverdue(asOf)
+		outstanding := toInvoiceResponse(&invoice.Invoice).OutstandingBalance
+		if days == 0 || outstanding <= 0 {
+			continue
+		}
+
+		overdue := responses.OverdueInvoiceResponse{
+			InvoiceID:          invoice.InvoiceID,
+			InvoiceNumber:      invoice.InvoiceNumber.String,
+			ProjectID:          invoice.ProjectID,
+			ProjectName:        invoice.ProjectName,
+			ClientName:         invoice.ClientName.String,
+			Status:             string(invoice.CurrentStatus()),
+			PaymentDueDate:     invoice.PaymentDueDate.Time,
+			DaysOverdue:        days,
+			OutstandingBalance: outstanding,
+		}
+		if invoice.ClientID.Valid {
+			clientID := invoice.ClientID.UUID
+			overdue.ClientID = &clientID
+		}
+		result = append(result, overdue)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].DaysOverdue > result[j].DaysOverdue
+	})
+
+	return result, nil
+}
+
+// GetAgingReport buckets the balance every invoice had outstanding on asOf
+// by days past due, grouped by client and project.
+func (u *invoiceUseCase) GetAgingReport(ctx context.Context, asOf time.Time) (*responses.AgingReportResponse, error) {
+	invoices, err := u.receivableInvoices(ctx, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &responses.AgingReportResponse{
+		AsOf:    asOf,
+		Clients: []responses.AgingClientResponse{},
+	}
+	clientIndex := make(map[uuid.NullUUID]int)
+	projectIndex := make(map[uuid.UUID]int)
+
+	for i := range invoices {
+		invoice := &invoices[i]
+		outstanding := toInvoiceResponse(&invoice.Invoice).OutstandingBalance
+		if outstanding <= 0 {
+			continue
+		}
+
+		ci, ok := clientIndex[invoice.ClientID]
+		if !ok {
+			client := responses.AgingClientResponse{
+				ClientName: invoice.ClientName.String,
+				Projects:   []responses.AgingProjectResponse{},
+			}
+			if invoice.ClientID.Valid {
+				clientID := invoice.ClientID.UUID
+				client.ClientID = &clientID
+			}
+			report.Clients = append(report.Clients, client)
+			ci = len(report.Clients) - 1
+			clientIndex[invoice.ClientID] = ci
+		}
+		client := &report.Clients[ci]
+
+		pi, ok := projectIndex[invoice.ProjectID]
+		if !ok {
+			client.Projects = append(client.Projects, responses.AgingProjectResponse{
+				ProjectID:   invoice.ProjectID,
+				ProjectName: invoice.ProjectName,
+			})
+			pi = len(client.Projects) - 1
+			projectIndex[invoice.ProjectID] = pi
+		}
+
+		days := invoice.DaysOverdue(asOf)
+		addToAgingBucket(&client.Projects[pi].AgingBuckets, days, outstanding)
+		addToAgingBucket(&client.AgingBuckets, days, outstanding)
+		addToAgingBucket(&report.Totals, days, outstanding)
+	}
+
+	sort.SliceStable(report.Clients, func(i, j int) bool {
+		return report.Clients[i].ClientName < report.Clients[j].ClientName
+	})
+	for i := range report.Clients {
+		client := &report.Clients[i]
+		sort.SliceStable(client.Projects, func(a, b int) bool {
+			return client.Projects[a].ProjectName < client.Projects[b].ProjectName
+		})
+		for j := range client.Projects {
+			roundAgingBuckets(&client.Projects[j].AgingBuckets)
+		}
+		roundAgingBuckets(&client.AgingBuckets)
+	}
+	roundAgingBuckets(&report.Totals)
+
+	return report, nil
+}
+
+// ExportAgingReportCSV renders the aging report with one row per project,
+// a subtotal row per client and a grand total row.
+func (u *invoiceUseCase) ExportAgingReportCSV(ctx context.Context, asOf time.Time) ([]byte, error) {
+	report, err := u.GetAgingReport(ctx, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	records := [][]string{{"Client", "Project", "Current", "1-30", "31-60", "61-90", "90+", "Total"}}
+	for _, client := range report.Clients {
+		for _, project := range client.Projects {
+			records = append(records, agingRecord(client.ClientName, project.ProjectName, project.AgingBuckets))
+		}
+		records = append(records, agingRecord(client.ClientName, "Subtotal", client.AgingBuckets))
+	}
+	records = append(records, agingRecord("Total", "", report.Totals))
+
+	if err := writer.WriteAll(records); err != nil {
+		return nil, fmt.Errorf("failed to write aging report: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// receivableInvoices loads the invoices as they stood on asOf with the tax
+// settings of their project, which the outstanding balance depends on.
+func (u *invoiceUseCase) receivableInvoices(ctx context.Context, asOf time.Time) ([]models.ReceivableInvoice, error) {
+	invoices, err := u.invoiceRepo.ListReceivable(ctx, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := make(map[uuid.UUID]models.InvoiceTaxSettings)
+	for i := range invoices {
+		projectID := invoices[i].ProjectID
+		if _, ok := settings[projectID]; !ok {
+			projectSettings, err := u.invoiceRepo.GetTaxSettings(ctx, projectID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get tax settings: %w", err)
+			}
+			settings[projectID] = *projectSettings
+		}
+		invoices[i].TaxSettings = settings[projectID]
+	}
+
+	return invoices, nil
+}
+
+func addToAgingBucket(buckets *responses.AgingBuckets, daysOverdue int, amount float64) {
+	switch {
+	case daysOverdue <= 0:
+		buckets.Current += amount
+	case daysOverdue <= 30:
+		buckets.Days1To30 += amount
+	case daysOverdue <= 60:
+		buckets.Days31To60 += amount
+	case daysOverdue <= 90:
+		buckets.Days61To90 += amount
+	default:
+		buckets.Over90 += amount
+	}
+	buckets.Total += amount
+}
+
+func roundAgingBuckets(buckets *responses.AgingBuckets) {
+	buckets.Current = roundMoney(buckets.Current)
+	buckets.Days1To30 = roundMoney(buckets.Days1To30)
+	buckets.Days31To60 = roundMoney(buckets.Days31To60)
+	buckets.Days61To90 = roundMoney(buckets.Days61To90)
+	buckets.Over90 = roundMoney(buckets.Over90)
+	buckets.Total = roundMoney(buckets.Total)
+}
+
+func agingRecord(client, project string, buckets responses.AgingBuckets) []string {
+	if strings.TrimSpace(client) == "" {
+		client = "(no client)"
+	}
+	return []string{
+		client,
+		project,
+		fmt.Sprintf("%.2f", buckets.Current),
+		fmt.Sprintf("%.2f", buckets.Days1To30),
+		fmt.Sprintf("%.2f", buckets.Days31To60),
+		fmt.Sprintf("%.2f", buckets.Days61To90),
+		fmt.Sprintf("%.2f", buckets.Over90),
+		fmt.Sprintf("%.2f", buckets.Total),
+	}
+}
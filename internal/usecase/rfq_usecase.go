@@ -0,0 +1,640 @@
+package usecase
+
+import (
+	"boonkosang/internal/domain/models"
+	"boonkosang/internal/repositories"
+	"boonkosang/internal/requests"
+	"boonkosang/internal/responses"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RFQUsecase interface {
+	CreateRFQ(ctx context.Context, projectID uuid.UUID, req requests.CreateRFQRequest) (*responses.RFQResponse, error)
+	GetRFQ(ctx context.Context, rfqID uuid.UUID) (*responses.RFQResponse, error)
+	ListRFQs(ctx context.Context, projectID uuid.UUID) ([]responses.RFQResponse, error)
+	UpdateStatus(ctx context.Context, rfqID uuid.UUID, req requests.UpdateRFQStatusRequest) (*responses.RFQResponse, error)
+	RecordQuote(ctx context.Context, rfqID, supplierID uuid.UUID, req requests.RecordRFQQuoteRequest) (*responses.RFQComparisonResponse, error)
+	GetComparison(ctx context.Context, rfqID uuid.UUID) (*responses.RFQComparisonResponse, error)
+	Award(ctx context.Context, rfqID uuid.UUID, req requests.AwardRFQRequest) (*responses.RFQAwardResponse, error)
+}
+
+type rfqUsecase struct {
+	rfqRepo         repositories.RFQRepository
+	poRepo          repositories.PurchaseOrderRepository
+	supplierRepo    repositories.SupplierRepository
+	poUsecase       PurchaseOrderUsecase
+	materialUsecase MaterialUsecase
+}
+
+func NewRFQUsecase(
+	rfqRepo repositories.RFQRepository,
+	poRepo repositories.PurchaseOrderRepository,
+	supplierRepo repositories.SupplierRepository,
+	poUsecase PurchaseOrderUsecase,
+	materialUsecase MaterialUsecase,
+) RFQUsecase {
+	return &rfqUsecase{
+		rfqRepo:         rfqRepo,
+		poRepo:          poRepo,
+		supplierRepo:    supplierRepo,
+		poUsecase:       poUsecase,
+		materialUsecase: materialUsecase,
+	}
+}
+
+// CreateRFQ creates a draft RFQ asking the listed suppliers to price
+// materials of the project's BOQ.
+func (u *rfqUsecase) CreateRFQ(ctx context.Context, projectID uuid.UUID, req requests.CreateRFQRequest) (*responses.RFQResponse, error) {
+	if len(req.Lines) == 0 {
+		return nil, errors.New("at least one material is required")
+	}
+	if len(req.SupplierIDs) == 0 {
+		return nil, errors.New("at least one supplier is required")
+	}
+
+	requirements, err := u.poRepo.ListBOQMaterials(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if len(requirements) == 0 {
+		return nil, errors.New("project BOQ has no materials")
+	}
+	materials := make(map[string]models.BOQMaterialRequirement, len(requirements))
+	for _, requirement := range requirements {
+		materials[requirement.MaterialID] = requirement
+	}
+
+	issueDate := time.Now().Truncate(24 * time.Hour)
+	if req.IssueDate != "" {
+		issueDate, err = time.Parse("2006-01-02", req.IssueDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid issue date format: %w", err)
+		}
+	}
+
+	dueDate, err := parseOptionalDate(req.ResponseDueDate, "response due date")
+	if err != nil {
+		return nil, err
+	}
+	if dueDate.Valid && dueDate.Time.Before(issueDate) {
+		return nil, errors.New("response due date cannot be before the issue date")
+	}
+
+	rfq := &models.RFQ{
+		ProjectID:       projectID,
+		BOQID:           requirements[0].BOQID,
+		IssueDate:       issueDate,
+		ResponseDueDate: dueDate,
+		Notes:           sql.NullString{String: req.Notes, Valid: req.Notes != ""},
+	}
+
+	for _, supplierID := range req.SupplierIDs {
+		for _, existing := range rfq.Suppliers {
+			if existing.SupplierID == supplierID {
+				return nil, errors.New("supplier is listed more than once")
+			}
+		}
+
+		supplier, err := u.supplierRepo.GetByID(ctx, supplierID)
+		if err != nil {
+			return nil, err
+		}
+		rfq.Suppliers = append(rfq.Suppliers, models.RFQSupplier{
+			SupplierID:   supplier.SupplierID,
+			SupplierName: supplier.Name,
+		})
+	}
+
+	for _, lineReq := range req.Lines {
+		material, ok := materials[strings.TrimSpace(lineReq.MaterialID)]
+		if !ok {
+			return nil, fmt.Errorf("material %s is not in the project BOQ", lineReq.MaterialID)
+		}
+		for _, existing := range rfq.Lines {
+			if existing.MaterialID == material.MaterialID {
+				return nil, fmt.Errorf("material %s is listed more than once", material.Name)
+			}
+		}
+
+		quantity := lineReq.Quantity
+		if quantity < 0 {
+			return nil, errors.New("quantity must be greater than 0")
+		}
+		if quantity == 0 {
+			quantity = material.RequiredQuantity - material.OrderedQuantity
+			if quantity <= 0 {
+				return nil, fmt.Errorf("material %s is already fully ordered", material.Name)
+			}
+		}
+
+		rfq.Lines = append(rfq.Lines, models.RFQLine{
+			MaterialID:   material.MaterialID,
+			Quantity:     quantity,
+			MaterialName: material.Name,
+			Unit:         material.Unit,
+		})
+	}
+
+	if err := u.rfqRepo.Create(ctx, rfq); err != nil {
+		return nil, err
+	}
+
+	return toRFQResponse(rfq), nil
+}
+
+func (u *rfqUsecase) GetRFQ(ctx context.Context, rfqID uuid.UUID) (*responses.RFQResponse, error) {
+	rfq, err := u.rfqRepo.GetByID(ctx, rfqID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toRFQResponse(rfq), nil
+}
+
+func (u *rfqUsecase) ListRFQs(ctx context.Context, projectID uuid.UUID) ([]responses.RFQResponse, error) {
+	rfqs, err := u.rfqRepo.ListByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]responses.RFQResponse, len(rfqs))
+	for i := range rfqs {
+		result[i] = *toRFQResponse(&rfqs[i])
+	}
+
+	return result, nil
+}
+
+// UpdateStatus sends a draft RFQ to its suppliers or cancels it.
+func (u *rfqUsecase) UpdateStatus(ctx context.Context, rfqID uuid.UUID, req requests.UpdateRFQStatusRequest) (*responses.RFQResponse, error) {
+	next := models.RFQStatus(req.Status)
+	if next != models.RFQStatusSent && next != models.RFQStatusCancelled {
+		return nil, fmt.Errorf("invalid RFQ status: %s", req.Status)
+	}
+
+	rfq, err := u.rfqRepo.GetByID(ctx, rfqID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !rfq.Status.CanTransitionTo(next) {
+		return nil, fmt.Errorf("cannot change RFQ status from %s to %s", rfq.Status, next)
+	}
+
+	from := rfq.Status
+	rfq.Status = next
+	if err := u.rfqRepo.ChangeStatus(ctx, rfq, from); err != nil {
+		return nil, err
+	}
+
+	return u.GetRFQ(ctx, rfqID)
+}
+
+// RecordQuote records an invited supplier's unit prices and lead times,
+// replacing any earlier reply from the supplier.
+func (u *rfqUsecase) RecordQuote(ctx context.Context, rfqID, supplierID uuid.UUID, req requests.RecordRFQQuoteRequest) (*responses.RFQComparisonResponse, error) {
+	if len(req.Lines) == 0 {
+		return nil, errors.New("at least one quoted line is required")
+	}
+	if req.LeadTimeDays < 0 {
+		return nil, errors.New("lead time cannot be negative")
+	}
+
+	rfq, err := u.rfqRepo.GetByID(ctx, rfqID)
+	if err != nil {
+		return nil, err
+	}
+
+	if rfq.Status != models.RFQStatusSent {
+		return nil, errors.New("quotes can only be recorded for a sent RFQ")
+	}
+
+	invited := false
+	for _, supplier := range rfq.Suppliers {
+		if supplier.SupplierID == supplierID {
+			invited = true
+			break
+		}
+	}
+	if !invited {
+		return nil, errors.New("supplier was not invited to this RFQ")
+	}
+
+	quotes := make([]models.RFQQuote, 0, len(req.Lines))
+	for _, lineReq := range req.Lines {
+		if findRFQLine(rfq, lineReq.RFQLineID) == nil {
+			return nil, errors.New("RFQ line not found")
+		}
+		for _, existing := range quotes {
+			if existing.RFQLineID == lineReq.RFQLineID {
+				return nil, errors.New("RFQ line is quoted more than once")
+			}
+		}
+		if lineReq.UnitPrice <= 0 {
+			return nil, errors.New("unit price must be greater than 0")
+		}
+
+		leadTime := req.LeadTimeDays
+		if lineReq.LeadTimeDays != nil {
+			if *lineReq.LeadTimeDays < 0 {
+				return nil, errors.New("lead time cannot be negative")
+			}
+			leadTime = *lineReq.LeadTimeDays
+		}
+
+		quotes = append(quotes, models.RFQQuote{
+			RFQLineID:    lineReq.RFQLineID,
+			UnitPrice:    lineReq.UnitPrice,
+			LeadTimeDays: leadTime,
+			Notes:        sql.NullString{String: lineReq.Notes, Valid: lineReq.Notes != ""},
+		})
+	}
+
+	if err := u.rfqRepo.SaveQuotes(ctx, rfqID, supplierID, quotes); err != nil {
+		return nil, err
+	}
+
+	return u.GetComparison(ctx, rfqID)
+}
+
+func (u *rfqUsecase) GetComparison(ctx context.Context, rfqID uuid.UUID) (*responses.RFQComparisonResponse, error) {
+	rfq, err := u.rfqRepo.GetByID(ctx, rfqID)
+	if err != nil {
+		return nil, err
+	}
+
+	return compareRFQQuotes(rfq), nil
+}
+
+// Award chooses a supplier for every line of a sent RFQ, then creates draft
+// purchase orders for the awarded prices or sets them as the BOQ actual
+// prices. The award and its orders or prices are stored together, so an RFQ
+// awarded twice at the same time yields one set of orders.
+func (u *rfqUsecase) Award(ctx context.Context, rfqID uuid.UUID, req requests.AwardRFQRequest) (*responses.RFQAwardResponse, error) {
+	if req.Target != "purchase_orders" && req.Target != "actual_prices" {
+		return nil, fmt.Errorf("invalid award target: %s", req.Target)
+	}
+
+	rfq, err := u.rfqRepo.GetByID(ctx, rfqID)
+	if err != nil {
+		return nil, err
+	}
+
+	if rfq.Status != models.RFQStatusSent {
+		return nil, errors.New("only a sent RFQ can be awarded")
+	}
+
+	awards, err := resolveRFQAward(rfq, req)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &responses.RFQAwardResponse{
+		Target:         req.Target,
+		Lines:          awards,
+		PurchaseOrders: []responses.PurchaseOrderResponse{},
+	}
+
+	var orders []*models.PurchaseOrder
+	var actualPrices []requests.UpdateMaterialActualPriceRequest
+	switch req.Target {
+	case "purchase_orders":
+		orderDate := time.Now().Truncate(24 * time.Hour)
+		if req.OrderDate != "" {
+			orderDate, err = time.Parse("2006-01-02", req.OrderDate)
+			if err != nil {
+				return nil, fmt.Errorf("invalid order date format: %w", err)
+			}
+		}
+
+		remarks := req.Remarks
+		if remarks == "" {
+			remarks = "Awarded from " + rfq.RFQNumber
+		}
+
+		poReq := requests.CreatePurchaseOrdersRequest{
+			OrderDate: orderDate.Format("2006-01-02"),
+			Remarks:   remarks,
+		}
+		for _, award := range awards {
+			line := requests.CreatePurchaseOrderLineRequest{
+				PurchaseOrderLineRequest: requests.PurchaseOrderLineRequest{
+					MaterialID: award.MaterialID,
+					Quantity:   award.Quantity,
+					UnitPrice:  award.UnitPrice,
+				},
+				SupplierID: award.SupplierID,
+			}
+			if award.LeadTimeDays > 0 {
+				line.DeliveryDate = orderDate.AddDate(0, 0, award.LeadTimeDays).Format("2006-01-02")
+			}
+			poReq.Lines = append(poReq.Lines, line)
+		}
+
+		orders, err = u.poUsecase.PrepareFromBOQ(ctx, rfq.ProjectID, poReq)
+		if err != nil {
+			return nil, err
+		}
+	case "actual_prices":
+		if err := u.materialUsecase.CheckActualPriceUpdate(ctx, rfq.BOQID); err != nil {
+			return nil, err
+		}
+		for _, award := range awards {
+			if award.UnitPrice <= 0 {
+				return nil, errors.New("actual price must be greater than 0")
+			}
+			actualPrices = append(actualPrices, requests.UpdateMaterialActualPriceRequest{
+				MaterialID:  award.MaterialID,
+				ActualPrice: award.UnitPrice,
+				SupplierID:  award.SupplierID,
+			})
+		}
+	}
+
+	for _, award := range awards {
+		line := findRFQLine(rfq, award.RFQLineID)
+		line.AwardedSupplierID = uuid.NullUUID{UUID: award.SupplierID, Valid: true}
+		line.AwardedUnitPrice = sql.NullFloat64{Float64: award.UnitPrice, Valid: true}
+	}
+
+	if err := u.rfqRepo.Award(ctx, rfq, orders, actualPrices); err != nil {
+		return nil, err
+	}
+	for _, order := range orders {
+		response.PurchaseOrders = append(response.PurchaseOrders, *toPurchaseOrderResponse(order))
+	}
+
+	updated, err := u.GetRFQ(ctx, rfqID)
+	if err != nil {
+		return nil, err
+	}
+	response.RFQ = *updated
+
+	return response, nil
+}
+
+// resolveRFQAward starts from the recommended award, or from a full award
+// to req.SupplierID, and applies the per-line choices in req.Lines. Every
+// line must end up with a quoted supplier.
+func resolveRFQAward(rfq *models.RFQ, req requests.AwardRFQRequest) ([]responses.RFQAwardLineResponse, error) {
+	quotes := map[uuid.UUID]map[uuid.UUID]models.RFQQuote{}
+	for _, quote := range rfq.Quotes {
+		if quotes[quote.RFQLineID] == nil {
+			quotes[quote.RFQLineID] = map[uuid.UUID]models.RFQQuote{}
+		}
+		quotes[quote.RFQLineID][quote.SupplierID] = quote
+	}
+
+	chosen := map[uuid.UUID]uuid.UUID{}
+	if req.SupplierID != nil {
+		for _, line := range rfq.Lines {
+			if _, ok := quotes[line.RFQLineID][*req.SupplierID]; !ok {
+				return nil, fmt.Errorf("supplier did not quote material %s", line.MaterialName)
+			}
+			chosen[line.RFQLineID] = *req.SupplierID
+		}
+	} else if recommendation := compareRFQQuotes(rfq).Recommendation; recommendation != nil {
+		for _, award := range recommendation.Lines {
+			chosen[award.RFQLineID] = award.SupplierID
+		}
+	}
+
+	for _, lineReq := range req.Lines {
+		line := findRFQLine(rfq, lineReq.RFQLineID)
+		if line == nil {
+			return nil, errors.New("RFQ line not found")
+		}
+		if _, ok := quotes[line.RFQLineID][lineReq.SupplierID]; !ok {
+			return nil, fmt.Errorf("supplier did not quote material %s", line.MaterialName)
+		}
+		chosen[line.RFQLineID] = lineReq.SupplierID
+	}
+
+	names := supplierNames(rfq)
+	awards := make([]responses.RFQAwardLineResponse, 0, len(rfq.Lines))
+	for _, line := range rfq.Lines {
+		supplierID, ok := chosen[line.RFQLineID]
+		if !ok {
+			return nil, fmt.Errorf("material %s has no quote to award", line.MaterialName)
+		}
+		quote := quotes[line.RFQLineID][supplierID]
+		awards = append(awards, toRFQAwardLine(line, quote, names[supplierID]))
+	}
+
+	return awards, nil
+}
+
+// compareRFQQuotes builds the quote matrix of an RFQ and recommends the
+// cheapest award. A split award takes the lowest quote of every line (the
+// shorter lead time on equal prices); it is only recommended over a full
+// award to the cheapest supplier that quoted every line when it costs less.
+func compareRFQQuotes(rfq *models.RFQ) *responses.RFQComparisonResponse {
+	names := supplierNames(rfq)
+
+	comparison := &responses.RFQComparisonResponse{
+		RFQID:     rfq.RFQID,
+		RFQNumber: rfq.RFQNumber,
+		Status:    string(rfq.Status),
+		Lines:     make([]responses.RFQComparisonLineResponse, len(rfq.Lines)),
+		Suppliers: make([]responses.RFQSupplierTotalResponse, len(rfq.Suppliers)),
+	}
+
+	supplierIndex := make(map[uuid.UUID]int, len(rfq.Suppliers))
+	for i, supplier := range rfq.Suppliers {
+		supplierIndex[supplier.SupplierID] = i
+		comparison.Suppliers[i] = responses.RFQSupplierTotalResponse{
+			SupplierID:   supplier.SupplierID,
+			SupplierName: supplier.SupplierName,
+		}
+	}
+
+	split := &responses.RFQRecommendationResponse{
+		AwardType:           "split",
+		Lines:               []responses.RFQAwardLineResponse{},
+		UnquotedMaterialIDs: []string{},
+	}
+
+	for i, line := range rfq.Lines {
+		row := responses.RFQComparisonLineResponse{
+			RFQLineID:    line.RFQLineID,
+			MaterialID:   line.MaterialID,
+			MaterialName: line.MaterialName,
+			Unit:         line.Unit,
+			Quantity:     line.Quantity,
+			Quotes:       []responses.RFQQuoteResponse{},
+		}
+
+		var lowest *models.RFQQuote
+		for j := range rfq.Quotes {
+			quote := &rfq.Quotes[j]
+			if quote.RFQLineID != line.RFQLineID {
+				continue
+			}
+
+			amount := roundMoney(quote.UnitPrice * line.Quantity)
+			row.Quotes = append(row.Quotes, responses.RFQQuoteResponse{
+				SupplierID:   quote.SupplierID,
+				SupplierName: names[quote.SupplierID],
+				UnitPrice:    quote.UnitPrice,
+				Amount:       amount,
+				LeadTimeDays: quote.LeadTimeDays,
+				Notes:        quote.Notes.String,
+			})
+
+			if k, ok := supplierIndex[quote.SupplierID]; ok {
+				total := &comparison.Suppliers[k]
+				total.QuotedLines++
+				total.TotalAmount = roundMoney(total.TotalAmount + amount)
+				if quote.LeadTimeDays > total.MaxLeadTimeDays {
+					total.MaxLeadTimeDays = quote.LeadTimeDays
+				}
+			}
+
+			if lowest == nil || quote.UnitPrice < lowest.UnitPrice ||
+				(quote.UnitPrice == lowest.UnitPrice && quote.LeadTimeDays < lowest.LeadTimeDays) {
+				lowest = quote
+			}
+		}
+
+		if lowest == nil {
+			split.UnquotedMaterialIDs = append(split.UnquotedMaterialIDs, line.MaterialID)
+		} else {
+			for j := range row.Quotes {
+				row.Quotes[j].IsLowest = row.Quotes[j].SupplierID == lowest.SupplierID
+			}
+			award := toRFQAwardLine(line, *lowest, names[lowest.SupplierID])
+			split.Lines = append(split.Lines, award)
+			split.TotalAmount = roundMoney(split.TotalAmount + award.Amount)
+		}
+
+		comparison.Lines[i] = row
+	}
+
+	var full *responses.RFQSupplierTotalResponse
+	for i := range comparison.Suppliers {
+		total := &comparison.Suppliers[i]
+		total.Complete = len(rfq.Lines) > 0 && total.QuotedLines == len(rfq.Lines)
+		if !total.Complete {
+			continue
+		}
+		if full == nil || total.TotalAmount < full.TotalAmount ||
+			(total.TotalAmount == full.TotalAmount && total.MaxLeadTimeDays < full.MaxLeadTimeDays) {
+			full = total
+		}
+	}
+
+	switch {
+	case full != nil && full.TotalAmount <= split.TotalAmount:
+		recommendation := &responses.RFQRecommendationResponse{
+			AwardType:           "full",
+			SupplierID:          &full.SupplierID,
+			TotalAmount:         full.TotalAmount,
+			Lines:               []responses.RFQAwardLineResponse{},
+			UnquotedMaterialIDs: []string{},
+		}
+		for _, line := range rfq.Lines {
+			for _, quote := range rfq.Quotes {
+				if quote.RFQLineID == line.RFQLineID && quote.SupplierID == full.SupplierID {
+					recommendation.Lines = append(recommendation.Lines, toRFQAwardLine(line, quote, full.SupplierName))
+				}
+			}
+		}
+		comparison.Recommendation = recommendation
+	case len(split.Lines) > 0:
+		if full != nil {
+			split.Savings = roundMoney(full.TotalAmount - split.TotalAmount)
+		}
+		comparison.Recommendation = split
+	}
+
+	return comparison
+}
+
+func toRFQAwardLine(line models.RFQLine, quote models.RFQQuote, supplierName string) responses.RFQAwardLineResponse {
+	return responses.RFQAwardLineResponse{
+		RFQLineID:    line.RFQLineID,
+		MaterialID:   line.MaterialID,
+		SupplierID:   quote.SupplierID,
+		SupplierName: supplierName,
+		Quantity:     line.Quantity,
+		UnitPrice:    quote.UnitPrice,
+		Amount:       roundMoney(quote.UnitPrice * line.Quantity),
+		LeadTimeDays: quote.LeadTimeDays,
+	}
+}
+
+func supplierNames(rfq *models.RFQ) map[uuid.UUID]string {
+	names := make(map[uuid.UUID]string, len(rfq.Suppliers))
+	for _, supplier := range rfq.Suppliers {
+		names[supplier.SupplierID] = supplier.SupplierName
+	}
+	return names
+}
+
+func findRFQLine(rfq *models.RFQ, lineID uuid.UUID) *models.RFQLine {
+	for i := range rfq.Lines {
+		if rfq.Lines[i].RFQLineID == lineID {
+			return &rfq.Lines[i]
+		}
+	}
+	return nil
+}
+
+func toRFQResponse(rfq *models.RFQ) *responses.RFQResponse {
+	response := &responses.RFQResponse{
+		RFQID:     rfq.RFQID,
+		RFQNumber: rfq.RFQNumber,
+		ProjectID: rfq.ProjectID,
+		BOQID:     rfq.BOQID,
+		Status:    string(rfq.Status),
+		IssueDate: rfq.IssueDate,
+		Notes:     rfq.Notes.String,
+		Suppliers: make([]responses.RFQSupplierResponse, len(rfq.Suppliers)),
+		Lines:     make([]responses.RFQLineResponse, len(rfq.Lines)),
+		CreatedAt: rfq.CreatedAt,
+	}
+
+	if rfq.ResponseDueDate.Valid {
+		response.ResponseDueDate = &rfq.ResponseDueDate.Time
+	}
+	if rfq.SentAt.Valid {
+		response.SentAt = &rfq.SentAt.Time
+	}
+	if rfq.AwardedAt.Valid {
+		response.AwardedAt = &rfq.AwardedAt.Time
+	}
+	if rfq.UpdatedAt.Valid {
+		response.UpdatedAt = &rfq.UpdatedAt.Time
+	}
+
+	for i, supplier := range rfq.Suppliers {
+		response.Suppliers[i] = responses.RFQSupplierResponse{
+			SupplierID:   supplier.SupplierID,
+			SupplierName: supplier.SupplierName,
+		}
+	}
+
+	for i, line := range rfq.Lines {
+		response.Lines[i] = responses.RFQLineResponse{
+			RFQLineID:         line.RFQLineID,
+			MaterialID:        line.MaterialID,
+			MaterialName:      line.MaterialName,
+			Unit:              line.Unit,
+			Quantity:          line.Quantity,
+			AwardedSupplierID: nullUUIDPtr(line.AwardedSupplierID),
+		}
+		if line.AwardedUnitPrice.Valid {
+			price := line.AwardedUnitPrice.Float64
+			response.Lines[i].AwardedUnitPrice = &price
+		}
+	}
+
+	return response
+}
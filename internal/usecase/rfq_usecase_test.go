@@ -0,0 +1,253 @@
+package usecase_test
+
+import (
+	"boonkosang/internal/domain/models"
+	"boonkosang/internal/requests"
+	"boonkosang/internal/responses"
+	"boonkosang/internal/usecase"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	rfqCement = models.RFQLine{RFQLineID: uuid.New(), MaterialID: "CEM-01", MaterialName: "Cement", Unit: "bag", Quantity: 10}
+	rfqSand   = models.RFQLine{RFQLineID: uuid.New(), MaterialID: "SND-01", MaterialName: "Sand", Unit: "m3", Quantity: 5}
+
+	rfqSupplierA = models.RFQSupplier{SupplierID: uuid.New(), SupplierName: "Siam Supply"}
+	rfqSupplierB = models.RFQSupplier{SupplierID: uuid.New(), SupplierName: "Chao Phraya Materials"}
+)
+
+func rfqQuote(line models.RFQLine, supplier models.RFQSupplier, unitPrice float64, leadTimeDays int) models.RFQQuote {
+	return models.RFQQuote{RFQLineID: line.RFQLineID, SupplierID: supplier.SupplierID, UnitPrice: unitPrice, LeadTimeDays: leadTimeDays}
+}
+
+func testRFQ(lines []models.RFQLine, quotes ...models.RFQQuote) *models.RFQ {
+	return &models.RFQ{
+		RFQID:     uuid.New(),
+		Lines:     lines,
+		Suppliers: []models.RFQSupplier{rfqSupplierA, rfqSupplierB},
+		Quotes:    quotes,
+	}
+}
+
+// awardedTo lists the supplier awarded each line, in line order.
+func awardedTo(t *testing.T, lines []models.RFQLine, awards []responses.RFQAwardLineResponse) []uuid.UUID {
+	t.Helper()
+	require.Len(t, awards, len(lines))
+	suppliers := make([]uuid.UUID, len(awards))
+	for i, award := range awards {
+		assert.Equal(t, lines[i].RFQLineID, award.RFQLineID)
+		suppliers[i] = award.SupplierID
+	}
+	return suppliers
+}
+
+func TestCompareRFQQuotes(t *testing.T) {
+	both := []models.RFQLine{rfqCement, rfqSand}
+
+	tests := []struct {
+		name         string
+		rfq          *models.RFQ
+		wantType     string
+		wantSupplier *uuid.UUID
+		wantLines    []uuid.UUID
+		wantTotal    float64
+		wantSavings  float64
+		wantUnquoted []string
+		wantLowest   map[uuid.UUID]uuid.UUID
+		wantNone     bool
+	}{
+		{
+			name: "full award when it costs the same as a split",
+			rfq: testRFQ(both,
+				rfqQuote(rfqCement, rfqSupplierA, 100, 7),
+				rfqQuote(rfqSand, rfqSupplierA, 50, 7),
+				rfqQuote(rfqCement, rfqSupplierB, 100, 7),
+				rfqQuote(rfqSand, rfqSupplierB, 60, 3),
+			),
+			wantType:     "full",
+			wantSupplier: &rfqSupplierA.SupplierID,
+			wantLines:    []uuid.UUID{rfqSupplierA.SupplierID, rfqSupplierA.SupplierID},
+			wantTotal:    1250,
+			wantUnquoted: []string{},
+		},
+		{
+			name: "split award when it saves over the cheapest full award",
+			rfq: testRFQ(both,
+				rfqQuote(rfqCement, rfqSupplierA, 100, 7),
+				rfqQuote(rfqSand, rfqSupplierA, 50, 7),
+				rfqQuote(rfqCement, rfqSupplierB, 90, 7),
+			),
+			wantType:     "split",
+			wantLines:    []uuid.UUID{rfqSupplierB.SupplierID, rfqSupplierA.SupplierID},
+			wantTotal:    1150,
+			wantSavings:  100,
+			wantUnquoted: []string{},
+			wantLowest:   map[uuid.UUID]uuid.UUID{rfqCement.RFQLineID: rfqSupplierB.SupplierID, rfqSand.RFQLineID: rfqSupplierA.SupplierID},
+		},
+		{
+			name: "split award when no supplier quoted every line",
+			rfq: testRFQ(both,
+				rfqQuote(rfqCement, rfqSupplierA, 100, 7),
+				rfqQuote(rfqSand, rfqSupplierB, 60, 3),
+			),
+			wantType:     "split",
+			wantLines:    []uuid.UUID{rfqSupplierA.SupplierID, rfqSupplierB.SupplierID},
+			wantTotal:    1300,
+			wantUnquoted: []string{},
+		},
+		{
+			name: "equal prices go to the shorter lead time",
+			rfq: testRFQ([]models.RFQLine{rfqCement},
+				rfqQuote(rfqCement, rfqSupplierA, 100, 7),
+				rfqQuote(rfqCement, rfqSupplierB, 100, 3),
+			),
+			wantType:     "full",
+			wantSupplier: &rfqSupplierB.SupplierID,
+			wantLines:    []uuid.UUID{rfqSupplierB.SupplierID},
+			wantTotal:    1000,
+			wantUnquoted: []string{},
+			wantLowest:   map[uuid.UUID]uuid.UUID{rfqCement.RFQLineID: rfqSupplierB.SupplierID},
+		},
+		{
+			name: "unquoted lines are left out of the split award",
+			rfq: testRFQ(both,
+				rfqQuote(rfqCement, rfqSupplierA, 100, 7),
+			),
+			wantType:     "split",
+			wantTotal:    1000,
+			wantUnquoted: []string{"SND-01"},
+		},
+		{
+			name:     "no recommendation without quotes",
+			rfq:      testRFQ(both),
+			wantNone: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			comparison := usecase.CompareRFQQuotes(tt.rfq)
+			require.Len(t, comparison.Lines, len(tt.rfq.Lines))
+
+			if tt.wantNone {
+				assert.Nil(t, comparison.Recommendation)
+				return
+			}
+
+			recommendation := comparison.Recommendation
+			require.NotNil(t, recommendation)
+			assert.Equal(t, tt.wantType, recommendation.AwardType)
+			assert.Equal(t, tt.wantSupplier, recommendation.SupplierID)
+			assert.Equal(t, tt.wantTotal, recommendation.TotalAmount)
+			assert.Equal(t, tt.wantSavings, recommendation.Savings)
+			assert.Equal(t, tt.wantUnquoted, recommendation.UnquotedMaterialIDs)
+			if tt.wantLines != nil {
+				assert.Equal(t, tt.wantLines, awardedTo(t, tt.rfq.Lines, recommendation.Lines))
+			}
+
+			for _, line := range comparison.Lines {
+				lowest, ok := tt.wantLowest[line.RFQLineID]
+				if !ok {
+					continue
+				}
+				for _, quote := range line.Quotes {
+					assert.Equal(t, quote.SupplierID == lowest, quote.IsLowest, "line %s supplier %s", line.MaterialID, quote.SupplierName)
+				}
+			}
+		})
+	}
+}
+
+func TestResolveRFQAward(t *testing.T) {
+	both := []models.RFQLine{rfqCement, rfqSand}
+	// The recommendation splits: cement from B, sand from A
+	quoted := testRFQ(both,
+		rfqQuote(rfqCement, rfqSupplierA, 100, 7),
+		rfqQuote(rfqSand, rfqSupplierA, 50, 7),
+		rfqQuote(rfqCement, rfqSupplierB, 90, 7),
+	)
+	unquotedSand := testRFQ(both,
+		rfqQuote(rfqCement, rfqSupplierA, 100, 7),
+	)
+
+	tests := []struct {
+		name    string
+		rfq     *models.RFQ
+		req     requests.AwardRFQRequest
+		want    []uuid.UUID
+		wantErr string
+	}{
+		{
+			name: "recommended award",
+			rfq:  quoted,
+			want: []uuid.UUID{rfqSupplierB.SupplierID, rfqSupplierA.SupplierID},
+		},
+		{
+			name: "full award to one supplier",
+			rfq:  quoted,
+			req:  requests.AwardRFQRequest{SupplierID: &rfqSupplierA.SupplierID},
+			want: []uuid.UUID{rfqSupplierA.SupplierID, rfqSupplierA.SupplierID},
+		},
+		{
+			name:    "full award to a supplier missing a line",
+			rfq:     quoted,
+			req:     requests.AwardRFQRequest{SupplierID: &rfqSupplierB.SupplierID},
+			wantErr: "supplier did not quote material Sand",
+		},
+		{
+			name: "line override on the recommended award",
+			rfq:  quoted,
+			req: requests.AwardRFQRequest{Lines: []requests.RFQAwardLineRequest{
+				{RFQLineID: rfqCement.RFQLineID, SupplierID: rfqSupplierA.SupplierID},
+			}},
+			want: []uuid.UUID{rfqSupplierA.SupplierID, rfqSupplierA.SupplierID},
+		},
+		{
+			name: "line override on a full award",
+			rfq:  quoted,
+			req: requests.AwardRFQRequest{
+				SupplierID: &rfqSupplierA.SupplierID,
+				Lines:      []requests.RFQAwardLineRequest{{RFQLineID: rfqCement.RFQLineID, SupplierID: rfqSupplierB.SupplierID}},
+			},
+			want: []uuid.UUID{rfqSupplierB.SupplierID, rfqSupplierA.SupplierID},
+		},
+		{
+			name: "line override to a supplier that did not quote it",
+			rfq:  quoted,
+			req: requests.AwardRFQRequest{Lines: []requests.RFQAwardLineRequest{
+				{RFQLineID: rfqSand.RFQLineID, SupplierID: rfqSupplierB.SupplierID},
+			}},
+			wantErr: "supplier did not quote material Sand",
+		},
+		{
+			name: "line override for another RFQ's line",
+			rfq:  quoted,
+			req: requests.AwardRFQRequest{Lines: []requests.RFQAwardLineRequest{
+				{RFQLineID: uuid.New(), SupplierID: rfqSupplierA.SupplierID},
+			}},
+			wantErr: "RFQ line not found",
+		},
+		{
+			name:    "unquoted line",
+			rfq:     unquotedSand,
+			wantErr: "material Sand has no quote to award",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			awards, err := usecase.ResolveRFQAward(tt.rfq, tt.req)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, awardedTo(t, tt.rfq.Lines, awards))
+		})
+	}
+}
@@ -0,0 +1,377 @@
+package usecase
+
+import (
+	"boonkosang/internal/domain/models"
+	"boonkosang/internal/repositories"
+	"boonkosang/internal/requests"
+	"boonkosang/internal/responses"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SupplierPriceUsecase interface {
+	CreatePrice(ctx context.Context, supplierID uuid.UUID, req requests.SupplierPriceRequest) (*responses.SupplierPriceResponse, error)
+	UpdatePrice(ctx context.Context, priceID uuid.UUID, req requests.SupplierPriceRequest) (*responses.SupplierPriceResponse, error)
+	DeletePrice(ctx context.Context, priceID uuid.UUID) error
+	ListPrices(ctx context.Context, supplierID uuid.UUID) ([]responses.SupplierPriceResponse, error)
+	ImportPrices(ctx context.Context, supplierID uuid.UUID, data []byte) (*responses.SupplierPriceImportResponse, error)
+	GetBestPrices(ctx context.Context, materialID, date string, quantity float64) ([]responses.SupplierPriceResponse, error)
+}
+
+type supplierPriceUsecase struct {
+	priceRepo    repositories.SupplierPriceRepository
+	supplierRepo repositories.SupplierRepository
+	materialRepo repositories.MaterialRepository
+	unitUsecase  UnitUsecase
+}
+
+func NewSupplierPriceUsecase(
+	priceRepo repositories.SupplierPriceRepository,
+	supplierRepo repositories.SupplierRepository,
+	materialRepo repositories.MaterialRepository,
+	unitUsecase UnitUsecase,
+) SupplierPriceUsecase {
+	return &supplierPriceUsecase{
+		priceRepo:    priceRepo,
+		supplierRepo: supplierRepo,
+		materialRepo: materialRepo,
+		unitUsecase:  unitUsecase,
+	}
+}
+
+func (u *supplierPriceUsecase) CreatePrice(ctx context.Context, supplierID uuid.UUID, req requests.SupplierPriceRequest) (*responses.SupplierPriceResponse, error) {
+	supplier, err := u.supplierRepo.GetByID(ctx, supplierID)
+	if err != nil {
+		return nil, err
+	}
+
+	price, err := u.toSupplierPrice(ctx, supplier, req, map[string]*models.Material{})
+	if err != nil {
+		return nil, err
+	}
+
+	prices := []models.SupplierPrice{*price}
+	if err := u.priceRepo.Create(ctx, prices); err != nil {
+		return nil, err
+	}
+
+	return toSupplierPriceResponse(&prices[0]), nil
+}
+
+func (u *supplierPriceUsecase) UpdatePrice(ctx context.Context, priceID uuid.UUID, req requests.SupplierPriceRequest) (*responses.SupplierPriceResponse, error) {
+	existing, err := u.priceRepo.GetByID(ctx, priceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.MaterialID != "" && strings.TrimSpace(req.MaterialID) != existing.MaterialID {
+		return nil, errors.New("material of a price list entry cannot be changed")
+	}
+	req.MaterialID = existing.MaterialID
+
+	supplier := &models.Supplier{SupplierID: existing.SupplierID, Name: existing.SupplierName}
+	materials := map[string]*models.Material{
+		existing.MaterialID: {MaterialID: existing.MaterialID, Name: existing.MaterialName, Unit: existing.Unit},
+	}
+	price, err := u.toSupplierPrice(ctx, supplier, req, materials)
+	if err != nil {
+		return nil, err
+	}
+	price.PriceID = existing.PriceID
+	price.CreatedAt = existing.CreatedAt
+
+	if err := u.priceRepo.Update(ctx, price); err != nil {
+		return nil, err
+	}
+
+	updated, err := u.priceRepo.GetByID(ctx, priceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toSupplierPriceResponse(updated), nil
+}
+
+func (u *supplierPriceUsecase) DeletePrice(ctx context.Context, priceID uuid.UUID) error {
+	return u.priceRepo.Delete(ctx, priceID)
+}
+
+func (u *supplierPriceUsecase) ListPrices(ctx context.Context, supplierID uuid.UUID) ([]responses.SupplierPriceResponse, error) {
+	if _, err := u.supplierRepo.GetByID(ctx, supplierID); err != nil {
+		return nil, err
+	}
+
+	prices, err := u.priceRepo.ListBySupplierID(ctx, supplierID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]responses.SupplierPriceResponse, len(prices))
+	for i := range prices {
+		result[i] = *toSupplierPriceResponse(&prices[i])
+	}
+
+	return result, nil
+}
+
+// ImportPrices adds a supplier's price list from CSV. The first row names
+// the columns: material_id, unit_price and valid_from are required,
+// min_quantity, valid_to and unit are optional. Nothing is imported when any row
+// is invalid.
+func (u *supplierPriceUsecase) ImportPrices(ctx context.Context, supplierID uuid.UUID, data []byte) (*responses.SupplierPriceImportResponse, error) {
+	supplier, err := u.supplierRepo.GetByID(ctx, supplierID)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("price list is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid price list: %w", err)
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"material_id", "unit_price", "valid_from"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("price list is missing the %s column", name)
+		}
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var prices []models.SupplierPrice
+	var problems []string
+	materials := map[string]*models.Material{}
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid price list: %w", err)
+		}
+
+		req := requests.SupplierPriceRequest{
+			MaterialID: field(record, "material_id"),
+			ValidFrom:  field(record, "valid_from"),
+			ValidTo:    field(record, "valid_to"),
+			Unit:       field(record, "unit"),
+		}
+		if req.MaterialID == "" && req.ValidFrom == "" && field(record, "unit_price") == "" {
+			continue
+		}
+
+		if req.UnitPrice, err = strconv.ParseFloat(field(record, "unit_price"), 64); err != nil {
+			problems = append(problems, fmt.Sprintf("row %d: invalid unit price", row))
+			continue
+		}
+		if value := field(record, "min_quantity"); value != "" {
+			if req.MinQuantity, err = strconv.ParseFloat(value, 64); err != nil {
+				problems = append(problems, fmt.Sprintf("row %d: invalid minimum quantity", row))
+				continue
+			}
+		}
+
+		price, err := u.toSupplierPrice(ctx, supplier, req, materials)
+		if err != nil {
+			if strings.HasPrefix(err.Error(), "failed to") {
+				return nil, err
+			}
+			problems = append(problems, fmt.Sprintf("row %d: %s", row, err))
+			continue
+		}
+		prices = append(prices, *price)
+	}
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid price list: %s", strings.Join(problems, "; "))
+	}
+	if len(prices) == 0 {
+		return nil, errors.New("price list has no prices")
+	}
+
+	if err := u.priceRepo.Create(ctx, prices); err != nil {
+		return nil, err
+	}
+
+	response := &responses.SupplierPriceImportResponse{
+		Imported: len(prices),
+		Prices:   make([]responses.SupplierPriceResponse, len(prices)),
+	}
+	for i := range prices {
+		response.Prices[i] = *toSupplierPriceResponse(&prices[i])
+	}
+
+	return response, nil
+}
+
+// GetBestPrices returns the cheapest supplier price valid on date for each
+// material, or for materialID only. When quantity is given, prices with a
+// higher minimum quantity are left out.
+func (u *supplierPriceUsecase) GetBestPrices(ctx context.Context, materialID, date string, quantity float64) ([]responses.SupplierPriceResponse, error) {
+	asOf, err := parsePriceDate(date)
+	if err != nil {
+		return nil, err
+	}
+	if quantity < 0 {
+		return nil, errors.New("quantity cannot be negative")
+	}
+
+	var materialIDs []string
+	if materialID != "" {
+		materialIDs = []string{materialID}
+	}
+
+	prices, err := u.priceRepo.ListValidPrices(ctx, asOf, materialIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	best := bestSupplierPrices(prices, func(string) float64 { return quantity })
+	result := make([]responses.SupplierPriceResponse, len(best))
+	for i := range best {
+		result[i] = *toSupplierPriceResponse(&best[i])
+	}
+
+	return result, nil
+}
+
+// toSupplierPrice validates a price list entry. materials caches the
+// materials already looked up.
+func (u *supplierPriceUsecase) toSupplierPrice(ctx context.Context, supplier *models.Supplier, req requests.SupplierPriceRequest, materials map[string]*models.Material) (*models.SupplierPrice, error) {
+	materialID := strings.TrimSpace(req.MaterialID)
+	if materialID == "" {
+		return nil, errors.New("material is required")
+	}
+
+	material, ok := materials[materialID]
+	if !ok {
+		var err error
+		material, err = u.materialRepo.GetByID(ctx, materialID)
+		if err != nil {
+			if err.Error() == "material not found" {
+				return nil, fmt.Errorf("material %s not found", materialID)
+			}
+			return nil, err
+		}
+		materials[materialID] = material
+	}
+
+	if req.UnitPrice <= 0 {
+		return nil, errors.New("unit price must be greater than 0")
+	}
+	if req.MinQuantity < 0 {
+		return nil, errors.New("minimum quantity cannot be negative")
+	}
+
+	factor, err := u.unitUsecase.MaterialUnitFactor(ctx, material.MaterialID, req.Unit)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.ValidFrom == "" {
+		return nil, errors.New("valid from date is required")
+	}
+	validFrom, err := time.Parse("2006-01-02", req.ValidFrom)
+	if err != nil {
+		return nil, fmt.Errorf("invalid valid from date format: %w", err)
+	}
+	validTo, err := parseOptionalDate(req.ValidTo, "valid to date")
+	if err != nil {
+		return nil, err
+	}
+	if validTo.Valid && validTo.Time.Before(validFrom) {
+		return nil, errors.New("valid to date cannot be before the valid from date")
+	}
+
+	return &models.SupplierPrice{
+		SupplierID:   supplier.SupplierID,
+		MaterialID:   material.MaterialID,
+		UnitPrice:    req.UnitPrice / factor,
+		MinQuantity:  req.MinQuantity * factor,
+		ValidFrom:    validFrom,
+		ValidTo:      validTo,
+		SupplierName: supplier.Name,
+		MaterialName: material.Name,
+		Unit:         material.Unit,
+	}, nil
+}
+
+// bestSupplierPrices picks the cheapest price of each material whose minimum
+// quantity is met by quantity(materialID). A quantity of 0 means it is not
+// known, and minimum quantities are ignored. prices must be sorted by
+// material and unit price.
+func bestSupplierPrices(prices []models.SupplierPrice, quantity func(materialID string) float64) []models.SupplierPrice {
+	var best []models.SupplierPrice
+	found := map[string]bool{}
+	for _, price := range prices {
+		if found[price.MaterialID] {
+			continue
+		}
+		if needed := quantity(price.MaterialID); needed > 0 && price.MinQuantity > needed {
+			continue
+		}
+		found[price.MaterialID] = true
+		best = append(best, price)
+	}
+	return best
+}
+
+// parsePriceDate parses a YYYY-MM-DD date, defaulting to today.
+func parsePriceDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Now().Truncate(24 * time.Hour), nil
+	}
+
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date format: %w", err)
+	}
+
+	return date, nil
+}
+
+func toSupplierPriceResponse(price *models.SupplierPrice) *responses.SupplierPriceResponse {
+	response := &responses.SupplierPriceResponse{
+		PriceID:      price.PriceID,
+		SupplierID:   price.SupplierID,
+		SupplierName: price.SupplierName,
+		MaterialID:   price.MaterialID,
+		MaterialName: price.MaterialName,
+		Unit:         price.Unit,
+		UnitPrice:    price.UnitPrice,
+		MinQuantity:  price.MinQuantity,
+		ValidFrom:    price.ValidFrom,
+		CreatedAt:    price.CreatedAt,
+	}
+
+	if price.ValidTo.Valid {
+		response.ValidTo = &price.ValidTo.Time
+	}
+	if price.UpdatedAt.Valid {
+		response.UpdatedAt = &price.UpdatedAt.Time
+	}
+
+	return response
+}
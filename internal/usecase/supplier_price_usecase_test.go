@@ -0,0 +1,181 @@
+package usecase_test
+
+import (
+	"boonkosang/internal/domain/models"
+	mocks "boonkosang/internal/repositories/mock"
+	"boonkosang/internal/usecase"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBestSupplierPrices(t *testing.T) {
+	// Sorted by material and unit price, as ListValidPrices returns them
+	prices := []models.SupplierPrice{
+		{MaterialID: "CEM-01", SupplierName: "Bulk", UnitPrice: 120, MinQuantity: 100},
+		{MaterialID: "CEM-01", SupplierName: "Wholesale", UnitPrice: 128, MinQuantity: 20},
+		{MaterialID: "CEM-01", SupplierName: "Retail", UnitPrice: 135},
+		{MaterialID: "STL-12", SupplierName: "Bulk", UnitPrice: 24, MinQuantity: 500},
+		{MaterialID: "STL-12", SupplierName: "Retail", UnitPrice: 26},
+	}
+
+	tests := []struct {
+		name     string
+		quantity map[string]float64
+		want     map[string]string
+	}{
+		{
+			name:     "unknown quantity ignores minimum quantities",
+			quantity: map[string]float64{},
+			want:     map[string]string{"CEM-01": "Bulk", "STL-12": "Bulk"},
+		},
+		{
+			name:     "minimum quantity met",
+			quantity: map[string]float64{"CEM-01": 100, "STL-12": 600},
+			want:     map[string]string{"CEM-01": "Bulk", "STL-12": "Bulk"},
+		},
+		{
+			name:     "next cheapest price whose minimum is met",
+			quantity: map[string]float64{"CEM-01": 50, "STL-12": 10},
+			want:     map[string]string{"CEM-01": "Wholesale", "STL-12": "Retail"},
+		},
+		{
+			name:     "only the price without a minimum is left",
+			quantity: map[string]float64{"CEM-01": 5},
+			want:     map[string]string{"CEM-01": "Retail", "STL-12": "Bulk"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			best := usecase.BestSupplierPrices(prices, func(materialID string) float64 { return tt.quantity[materialID] })
+
+			got := map[string]string{}
+			for _, price := range best {
+				_, duplicate := got[price.MaterialID]
+				assert.False(t, duplicate, "material %s picked twice", price.MaterialID)
+				got[price.MaterialID] = price.SupplierName
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("material without a qualifying price is left out", func(t *testing.T) {
+		best := usecase.BestSupplierPrices(prices[3:4], func(string) float64 { return 10 })
+		assert.Empty(t, best)
+	})
+}
+
+func TestImportPrices(t *testing.T) {
+	supplierID := uuid.New()
+	supplier := &models.Supplier{SupplierID: supplierID, Name: "Siam Cement Trading"}
+	materials := map[string]*models.Material{
+		"CEM-01": {MaterialID: "CEM-01", Name: "Portland cement", Unit: "bag"},
+		"STL-12": {MaterialID: "STL-12", Name: "Rebar 12 mm", Unit: "kg"},
+	}
+
+	tests := []struct {
+		name      string
+		csv       string
+		createErr error
+		wantErr   string
+		want      []models.SupplierPrice
+	}{
+		{
+			name: "valid price list",
+			csv: "\xef\xbb\xbfmaterial_id,unit_price,valid_from,valid_to,min_quantity\n" +
+				"CEM-01,135.50,2026-10-01,2026-12-31,\n" +
+				"\n" +
+				"STL-12, 24,2026-10-01,,500\n",
+			want: []models.SupplierPrice{
+				{MaterialID: "CEM-01", UnitPrice: 135.5},
+				{MaterialID: "STL-12", UnitPrice: 24, MinQuantity: 500},
+			},
+		},
+		{
+			name: "one invalid row imports nothing",
+			csv: "material_id,unit_price,valid_from\n" +
+				"CEM-01,135.50,2026-10-01\n" +
+				"STL-12,abc,2026-10-01\n",
+			wantErr: "invalid price list: row 3: invalid unit price",
+		},
+		{
+			name: "every invalid row is reported",
+			csv: "material_id,unit_price,valid_from,valid_to\n" +
+				"CEM-01,0,2026-10-01,\n" +
+				"XXX-99,10,2026-10-01,\n" +
+				"STL-12,24,2026-10-01,2026-09-01\n",
+			wantErr: "invalid price list: row 2: unit price must be greater than 0; " +
+				"row 3: material XXX-99 not found; " +
+				"row 4: valid to date cannot be before the valid from date",
+		},
+		{
+			name:    "missing required column",
+			csv:     "material_id,unit_price\nCEM-01,135.50\n",
+			wantErr: "price list is missing the valid_from column",
+		},
+		{
+			name:    "empty file",
+			csv:     "",
+			wantErr: "price list is empty",
+		},
+		{
+			name:    "header only",
+			csv:     "material_id,unit_price,valid_from\n",
+			wantErr: "price list has no prices",
+		},
+		{
+			name:      "repository failure",
+			csv:       "material_id,unit_price,valid_from\nCEM-01,135.50,2026-10-01\n",
+			createErr: errors.New("failed to create supplier prices: connection lost"),
+			wantErr:   "failed to create supplier prices: connection lost",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			priceRepo := new(mocks.MockSupplierPriceRepository)
+			supplierRepo := new(mocks.MockSupplierRepository)
+			materialRepo := new(mocks.MockMaterialRepository)
+			unitUsecase := usecase.NewUnitUsecase(new(mocks.MockUnitRepository), materialRepo)
+			uc := usecase.NewSupplierPriceUsecase(priceRepo, supplierRepo, materialRepo, unitUsecase)
+
+			supplierRepo.On("GetByID", ctx, supplierID).Return(supplier, nil)
+			for materialID, material := range materials {
+				materialRepo.On("GetByID", ctx, materialID).Return(material, nil)
+			}
+			materialRepo.On("GetByID", ctx, "XXX-99").Return(nil, errors.New("material not found"))
+			priceRepo.On("Create", ctx, mock.Anything).Return(tt.createErr)
+
+			response, err := uc.ImportPrices(ctx, supplierID, []byte(tt.csv))
+
+			if tt.want == nil {
+				require.EqualError(t, err, tt.wantErr)
+				assert.Nil(t, response)
+				if tt.createErr == nil {
+					priceRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				}
+				return
+			}
+
+			require.NoError(t, err)
+			priceRepo.AssertNumberOfCalls(t, "Create", 1)
+			created := priceRepo.Calls[0].Arguments.Get(1).([]models.SupplierPrice)
+			require.Len(t, created, len(tt.want))
+			assert.Equal(t, len(tt.want), response.Imported)
+			for i, want := range tt.want {
+				assert.Equal(t, supplierID, created[i].SupplierID)
+				assert.Equal(t, want.MaterialID, created[i].MaterialID)
+				assert.Equal(t, want.UnitPrice, created[i].UnitPrice)
+				assert.Equal(t, want.MinQuantity, created[i].MinQuantity)
+				assert.Equal(t, materials[want.MaterialID].Unit, created[i].Unit)
+			}
+		})
+	}
+}
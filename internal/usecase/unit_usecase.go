@@ -0,0 +1,281 @@
+package usecase
+
+import (
+	"boonkosang/internal/domain/models"
+	"boonkosang/internal/repositories"
+	"boonkosang/internal/requests"
+	"boonkosang/internal/responses"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type UnitUsecase interface {
+	ListUnits() []responses.UnitResponse
+	ListConversions(ctx context.Context, materialID string) ([]responses.UnitConversionResponse, error)
+	CreateConversion(ctx context.Context, req requests.CreateUnitConversionRequest) (*responses.UnitConversionResponse, error)
+	DeleteConversion(ctx context.Context, conversionID uuid.UUID) error
+	Convert(ctx context.Context, materialID string, quantity float64, fromUnit, toUnit string) (*responses.UnitConversionResultResponse, error)
+
+	// MaterialUnitFactor returns how many of the material's own unit make
+	// one unit. An empty unit means the material's unit.
+	MaterialUnitFactor(ctx context.Context, materialID, unit string) (float64, error)
+}
+
+type unitUsecase struct {
+	unitRepo     repositories.UnitRepository
+	materialRepo repositories.MaterialRepository
+}
+
+func NewUnitUsecase(unitRepo repositories.UnitRepository, materialRepo repositories.MaterialRepository) UnitUsecase {
+	return &unitUsecase{
+		unitRepo:     unitRepo,
+		materialRepo: materialRepo,
+	}
+}
+
+func (u *unitUsecase) ListUnits() []responses.UnitResponse {
+	result := make([]responses.UnitResponse, len(models.Units))
+	for i, unit := range models.Units {
+		result[i] = responses.UnitResponse{
+			Code:      unit.Code,
+			Name:      unit.Name,
+			Dimension: string(unit.Dimension),
+			ToBase:    unit.ToBase,
+			Aliases:   unit.Aliases,
+		}
+	}
+	return result
+}
+
+func (u *unitUsecase) ListConversions(ctx context.Context, materialID string) ([]responses.UnitConversionResponse, error) {
+	conversions, err := u.unitRepo.ListConversions(ctx, strings.TrimSpace(materialID))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]responses.UnitConversionResponse, len(conversions))
+	for i := range conversions {
+		result[i] = toUnitConversionResponse(&conversions[i])
+	}
+	return result, nil
+}
+
+func (u *unitUsecase) CreateConversion(ctx context.Context, req requests.CreateUnitConversionRequest) (*responses.UnitConversionResponse, error) {
+	fromUnit, err := normalizeUnitCode(req.FromUnit)
+	if err != nil {
+		return nil, err
+	}
+	toUnit, err := normalizeUnitCode(req.ToUnit)
+	if err != nil {
+		return nil, err
+	}
+	if fromUnit == toUnit {
+		return nil, errors.New("cannot convert a unit to itself")
+	}
+	if req.Factor <= 0 {
+		return nil, errors.New("factor must be greater than 0")
+	}
+
+	from, _ := models.LookupUnit(fromUnit)
+	to, _ := models.LookupUnit(toUnit)
+	if from.Dimension == to.Dimension && from.ToBase > 0 && to.ToBase > 0 {
+		return nil, fmt.Errorf("%s and %s already convert as units of %s", fromUnit, toUnit, from.Dimension)
+	}
+
+	conversion := &models.UnitConversion{
+		FromUnit: fromUnit,
+		ToUnit:   toUnit,
+		Factor:   req.Factor,
+	}
+	if materialID := strings.TrimSpace(req.MaterialID); materialID != "" {
+		material, err := u.materialRepo.GetByID(ctx, materialID)
+		if err != nil {
+			return nil, err
+		}
+		conversion.MaterialID = sql.NullString{String: material.MaterialID, Valid: true}
+		conversion.MaterialName = sql.NullString{String: material.Name, Valid: true}
+	}
+
+	// A conversion also applies in reverse, so one between the same units in
+	// either direction would conflict with it.
+	existing, err := u.unitRepo.ListConversions(ctx, conversion.MaterialID.String)
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range existing {
+		if other.MaterialID != conversion.MaterialID {
+			continue
+		}
+		a, b := unitKey(other.FromUnit), unitKey(other.ToUnit)
+		if (a == fromUnit && b == toUnit) || (a == toUnit && b == fromUnit) {
+			return nil, fmt.Errorf("unit conversion between %s and %s already exists", a, b)
+		}
+	}
+
+	if err := u.unitRepo.CreateConversion(ctx, conversion); err != nil {
+		return nil, err
+	}
+
+	response := toUnitConversionResponse(conversion)
+	return &response, nil
+}
+
+func (u *unitUsecase) DeleteConversion(ctx context.Context, conversionID uuid.UUID) error {
+	return u.unitRepo.DeleteConversion(ctx, conversionID)
+}
+
+func (u *unitUsecase) Convert(ctx context.Context, materialID string, quantity float64, fromUnit, toUnit string) (*responses.UnitConversionResultResponse, error) {
+	if strings.TrimSpace(fromUnit) == "" || strings.TrimSpace(toUnit) == "" {
+		return nil, errors.New("from and to units are required")
+	}
+
+	conversions, err := u.unitRepo.ListConversions(ctx, strings.TrimSpace(materialID))
+	if err != nil {
+		return nil, err
+	}
+
+	factor, ok := unitFactor(fromUnit, toUnit, conversions)
+	if !ok {
+		return nil, fmt.Errorf("cannot convert %s to %s", fromUnit, toUnit)
+	}
+
+	return &responses.UnitConversionResultResponse{
+		MaterialID: strings.TrimSpace(materialID),
+		Quantity:   quantity,
+		FromUnit:   unitKey(fromUnit),
+		ToUnit:     unitKey(toUnit),
+		Factor:     factor,
+		Result:     quantity * factor,
+	}, nil
+}
+
+func (u *unitUsecase) MaterialUnitFactor(ctx context.Context, materialID, unit string) (float64, error) {
+	if strings.TrimSpace(unit) == "" {
+		return 1, nil
+	}
+
+	material, err := u.materialRepo.GetByID(ctx, materialID)
+	if err != nil {
+		return 0, err
+	}
+	if unitKey(unit) == unitKey(material.Unit) {
+		return 1, nil
+	}
+
+	conversions, err := u.unitRepo.ListConversions(ctx, material.MaterialID)
+	if err != nil {
+		return 0, err
+	}
+
+	factor, ok := unitFactor(unit, material.Unit, conversions)
+	if !ok {
+		return 0, fmt.Errorf("cannot convert %s to %s for material %s", unit, material.Unit, material.Name)
+	}
+	return factor, nil
+}
+
+// normalizeUnitCode returns the canonical code of a unit given by its code
+// or one of its aliases.
+func normalizeUnitCode(value string) (string, error) {
+	unit, ok := models.LookupUnit(value)
+	if !ok {
+		return "", fmt.Errorf("unknown unit: %s", strings.TrimSpace(value))
+	}
+	return unit.Code, nil
+}
+
+// unitKey is the canonical code of a known unit, or the trimmed, lower-cased
+// value of a unit recorded before codes were validated.
+func unitKey(value string) string {
+	if unit, ok := models.LookupUnit(value); ok {
+		return unit.Code
+	}
+	return strings.ToLower(strings.TrimSpace(value))
+}
+
+// unitFactor returns how many toUnit make one fromUnit, following units of
+// the same dimension and the given conversions in either direction. A
+// material's own conversion hides a global one between the same units.
+func unitFactor(fromUnit, toUnit string, conversions []models.UnitConversion) (float64, bool) {
+	from, to := unitKey(fromUnit), unitKey(toUnit)
+	if from == to {
+		return 1, true
+	}
+
+	type edge struct {
+		unit   string
+		factor float64
+	}
+	edges := map[string][]edge{}
+
+	pair := func(a, b string) string {
+		if a > b {
+			a, b = b, a
+		}
+		return a + "|" + b
+	}
+	specific := map[string]bool{}
+	for _, conversion := range conversions {
+		if conversion.MaterialID.Valid {
+			specific[pair(unitKey(conversion.FromUnit), unitKey(conversion.ToUnit))] = true
+		}
+	}
+	for _, conversion := range conversions {
+		a, b := unitKey(conversion.FromUnit), unitKey(conversion.ToUnit)
+		if !conversion.MaterialID.Valid && specific[pair(a, b)] {
+			continue
+		}
+		if conversion.Factor <= 0 {
+			continue
+		}
+		edges[a] = append(edges[a], edge{unit: b, factor: conversion.Factor})
+		edges[b] = append(edges[b], edge{unit: a, factor: 1 / conversion.Factor})
+	}
+
+	// Breadth-first search, so the shortest chain of conversions wins.
+	amounts := map[string]float64{from: 1}
+	queue := []string{from}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == to {
+			return amounts[current], true
+		}
+
+		next := edges[current]
+		if unit, ok := models.LookupUnit(current); ok && unit.ToBase > 0 {
+			for _, other := range models.Units {
+				if other.Dimension == unit.Dimension && other.ToBase > 0 && other.Code != unit.Code {
+					next = append(next, edge{unit: other.Code, factor: unit.ToBase / other.ToBase})
+				}
+			}
+		}
+
+		for _, e := range next {
+			if _, seen := amounts[e.unit]; seen {
+				continue
+			}
+			amounts[e.unit] = amounts[current] * e.factor
+			queue = append(queue, e.unit)
+		}
+	}
+
+	return 0, false
+}
+
+func toUnitConversionResponse(conversion *models.UnitConversion) responses.UnitConversionResponse {
+	return responses.UnitConversionResponse{
+		ConversionID: conversion.ConversionID,
+		MaterialID:   conversion.MaterialID.String,
+		MaterialName: conversion.MaterialName.String,
+		FromUnit:     conversion.FromUnit,
+		ToUnit:       conversion.ToUnit,
+		Factor:       conversion.Factor,
+		CreatedAt:    conversion.CreatedAt,
+	}
+}
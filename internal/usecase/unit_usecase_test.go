@@ -0,0 +1,198 @@
+package usecase_test
+
+import (
+	"boonkosang/internal/domain/models"
+	mocks "boonkosang/internal/repositories/mock"
+	"boonkosang/internal/requests"
+	"boonkosang/internal/usecase"
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitFactor(t *testing.T) {
+	cement := sql.NullString{String: "CEM-01", Valid: true}
+
+	tests := []struct {
+		name        string
+		from        string
+		to          string
+		conversions []models.UnitConversion
+		want        float64
+		ok          bool
+	}{
+		{
+			name: "same unit by alias",
+			from: "กก.",
+			to:   "kg",
+			want: 1,
+			ok:   true,
+		},
+		{
+			name: "units of the same dimension",
+			from: "t",
+			to:   "kg",
+			want: 1000,
+			ok:   true,
+		},
+		{
+			name:        "bag to kg through the material's factor",
+			from:        "bag",
+			to:          "kg",
+			conversions: []models.UnitConversion{{MaterialID: cement, FromUnit: "bag", ToUnit: "kg", Factor: 50}},
+			want:        50,
+			ok:          true,
+		},
+		{
+			name:        "reverse of the material's factor",
+			from:        "kg",
+			to:          "bag",
+			conversions: []models.UnitConversion{{MaterialID: cement, FromUnit: "bag", ToUnit: "kg", Factor: 50}},
+			want:        0.02,
+			ok:          true,
+		},
+		{
+			name:        "conversion chained with units of the same dimension",
+			from:        "t",
+			to:          "ถุง",
+			conversions: []models.UnitConversion{{MaterialID: cement, FromUnit: "bag", ToUnit: "kg", Factor: 50}},
+			want:        20,
+			ok:          true,
+		},
+		{
+			name: "material factor hides the global one",
+			from: "bag",
+			to:   "kg",
+			conversions: []models.UnitConversion{
+				{FromUnit: "bag", ToUnit: "kg", Factor: 25},
+				{MaterialID: cement, FromUnit: "bag", ToUnit: "kg", Factor: 50},
+			},
+			want: 50,
+			ok:   true,
+		},
+		{
+			name: "material factor hides a global one stated in reverse",
+			from: "kg",
+			to:   "bag",
+			conversions: []models.UnitConversion{
+				{FromUnit: "kg", ToUnit: "bag", Factor: 0.04},
+				{MaterialID: cement, FromUnit: "bag", ToUnit: "kg", Factor: 50},
+			},
+			want: 0.02,
+			ok:   true,
+		},
+		{
+			name:        "global factor without a material one",
+			from:        "box",
+			to:          "pcs",
+			conversions: []models.UnitConversion{{FromUnit: "box", ToUnit: "pcs", Factor: 100}},
+			want:        100,
+			ok:          true,
+		},
+		{
+			name: "square wa to square metres",
+			from: "ตร.ว.",
+			to:   "m2",
+			want: 4,
+			ok:   true,
+		},
+		{
+			name: "rai to square metres",
+			from: "rai",
+			to:   "m2",
+			want: 1600,
+			ok:   true,
+		},
+		{
+			name: "square metres to rai",
+			from: "m2",
+			to:   "ไร่",
+			want: 1.0 / 1600,
+			ok:   true,
+		},
+		{
+			name: "no conversion between dimensions",
+			from: "kg",
+			to:   "m",
+			ok:   false,
+		},
+		{
+			name:        "conversion without a factor is ignored",
+			from:        "bag",
+			to:          "kg",
+			conversions: []models.UnitConversion{{MaterialID: cement, FromUnit: "bag", ToUnit: "kg", Factor: 0}},
+			ok:          false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := usecase.UnitFactor(tt.from, tt.to, tt.conversions)
+			assert.Equal(t, tt.ok, ok)
+			assert.InDelta(t, tt.want, got, 1e-9)
+		})
+	}
+}
+
+func TestCreateConversionRejectsDuplicatePairs(t *testing.T) {
+	ctx := context.Background()
+	cement := &models.Material{MaterialID: "CEM-01", Name: "Portland cement", Unit: "bag"}
+	global := models.UnitConversion{FromUnit: "box", ToUnit: "pcs", Factor: 100}
+	specific := models.UnitConversion{MaterialID: sql.NullString{String: "CEM-01", Valid: true}, FromUnit: "bag", ToUnit: "kg", Factor: 50}
+
+	tests := []struct {
+		name    string
+		req     requests.CreateUnitConversionRequest
+		wantErr string
+	}{
+		{
+			name:    "same pair for the material",
+			req:     requests.CreateUnitConversionRequest{MaterialID: "CEM-01", FromUnit: "ถุง", ToUnit: "kg", Factor: 40},
+			wantErr: "unit conversion between bag and kg already exists",
+		},
+		{
+			name:    "reverse pair for the material",
+			req:     requests.CreateUnitConversionRequest{MaterialID: "CEM-01", FromUnit: "kg", ToUnit: "bag", Factor: 0.02},
+			wantErr: "unit conversion between bag and kg already exists",
+		},
+		{
+			name:    "reverse global pair",
+			req:     requests.CreateUnitConversionRequest{FromUnit: "pcs", ToUnit: "box", Factor: 0.01},
+			wantErr: "unit conversion between box and pcs already exists",
+		},
+		{
+			name: "material pair over a global one",
+			req:  requests.CreateUnitConversionRequest{MaterialID: "CEM-01", FromUnit: "box", ToUnit: "pcs", Factor: 50},
+		},
+		{
+			name: "global pair that only a material has",
+			req:  requests.CreateUnitConversionRequest{FromUnit: "kg", ToUnit: "bag", Factor: 0.04},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unitRepo := new(mocks.MockUnitRepository)
+			materialRepo := new(mocks.MockMaterialRepository)
+			materialRepo.On("GetByID", ctx, "CEM-01").Return(cement, nil)
+			unitRepo.On("ListConversions", ctx, "CEM-01").Return([]models.UnitConversion{global, specific}, nil)
+			unitRepo.On("ListConversions", ctx, "").Return([]models.UnitConversion{global}, nil)
+			unitRepo.On("CreateConversion", ctx, mock.AnythingOfType("*models.UnitConversion")).Return(nil)
+
+			response, err := usecase.NewUnitUsecase(unitRepo, materialRepo).CreateConversion(ctx, tt.req)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				unitRepo.AssertNotCalled(t, "CreateConversion", mock.Anything, mock.Anything)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.req.MaterialID, response.MaterialID)
+			unitRepo.AssertCalled(t, "CreateConversion", ctx, mock.AnythingOfType("*models.UnitConversion"))
+		})
+	}
+}